@@ -0,0 +1,137 @@
+// Command dlq inspects dead-letter topics and redrives their messages back
+// to the source topic.
+//
+//	go run ./cmd/dlq inspect -topic order.events.dlq
+//	go run ./cmd/dlq redrive -topic order.events.dlq
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"product-app/shared/kafka"
+)
+
+const usage = `usage: dlq <command> [flags]
+
+commands:
+  inspect   print the messages currently in a dead-letter topic
+  redrive   publish dead-lettered messages back to their source topic`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "inspect":
+		err = inspect(ctx, os.Args[2:])
+	case "redrive":
+		err = redrive(ctx, os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func defaultBrokers() string {
+	if brokers := os.Getenv("KAFKA_BROKERS"); brokers != "" {
+		return brokers
+	}
+	return "localhost:9094"
+}
+
+type inspectedMessage struct {
+	Offset          int64           `json:"offset"`
+	Partition       int             `json:"partition"`
+	Key             string          `json:"key"`
+	SourceTopic     string          `json:"source_topic"`
+	SourcePartition int             `json:"source_partition"`
+	SourceOffset    int64           `json:"source_offset"`
+	ConsumerGroup   string          `json:"consumer_group"`
+	Attempts        int             `json:"attempts"`
+	Error           string          `json:"error"`
+	FailedAt        time.Time       `json:"failed_at"`
+	Value           json.RawMessage `json:"value"`
+}
+
+func inspect(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	brokers := flags.String("brokers", defaultBrokers(), "comma separated broker list")
+	topic := flags.String("topic", "", "dead-letter topic, e.g. order.events.dlq")
+	limit := flags.Int("limit", 50, "maximum number of messages to print, 0 for all")
+	flags.Parse(args)
+	if *topic == "" {
+		return fmt.Errorf("-topic is required")
+	}
+
+	deadLetters, err := kafka.ReadDeadLetters(ctx, strings.Split(*brokers, ","), *topic, *limit)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	for _, deadLetter := range deadLetters {
+		value := json.RawMessage(deadLetter.Message.Value)
+		if !json.Valid(value) {
+			value, _ = json.Marshal(string(deadLetter.Message.Value))
+		}
+		if err := encoder.Encode(inspectedMessage{
+			Offset:          deadLetter.Message.Offset,
+			Partition:       deadLetter.Message.Partition,
+			Key:             string(deadLetter.Message.Key),
+			SourceTopic:     deadLetter.SourceTopic,
+			SourcePartition: deadLetter.SourcePartition,
+			SourceOffset:    deadLetter.SourceOffset,
+			ConsumerGroup:   deadLetter.ConsumerGroup,
+			Attempts:        deadLetter.Attempts,
+			Error:           deadLetter.Error,
+			FailedAt:        deadLetter.FailedAt,
+			Value:           value,
+		}); err != nil {
+			return err
+		}
+	}
+	log.Printf("%d message(s) in %s", len(deadLetters), *topic)
+	return nil
+}
+
+func redrive(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("redrive", flag.ExitOnError)
+	brokers := flags.String("brokers", defaultBrokers(), "comma separated broker list")
+	topic := flags.String("topic", "", "dead-letter topic, e.g. order.events.dlq")
+	groupID := flags.String("group", "dlq-redrive", "consumer group that records redriven offsets")
+	limit := flags.Int("limit", 0, "maximum number of messages to redrive, 0 for all")
+	idle := flags.Duration("idle", 5*time.Second, "stop after no message arrived for this long")
+	flags.Parse(args)
+	if *topic == "" {
+		return fmt.Errorf("-topic is required")
+	}
+
+	redriven, err := kafka.Redrive(ctx, kafka.RedriveConfig{
+		Brokers:     strings.Split(*brokers, ","),
+		Topic:       *topic,
+		GroupID:     *groupID,
+		Limit:       *limit,
+		IdleTimeout: *idle,
+	})
+	log.Printf("redriven %d message(s) from %s", redriven, *topic)
+	return err
+}
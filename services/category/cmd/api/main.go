@@ -13,7 +13,8 @@ import (
 	pgcommon "product-app/services/category/internal/adapters/postgresql/common"
 	"product-app/services/category/internal/config"
 	"product-app/services/category/internal/usecase"
-	sharedkafka "product-app/shared/kafka"
+	"product-app/shared/auth"
+	"product-app/shared/inbox"
 
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/labstack/echo/v4"
@@ -22,21 +23,28 @@ import (
 
 func main() {
 	ctx := context.Background()
-	e := buildServer(ctx)
-	e.Start(":8082")
+
+	configurationManager, err := config.NewConfigurationManager()
+	if err != nil {
+		log.Fatalf("category-service not started: %v", err)
+	}
+	log.Printf("effective configuration:\n%s", configurationManager)
+	auth.SetSecret(configurationManager.Auth.JWTSecret)
+
+	e := buildServer(ctx, configurationManager)
+	e.Logger.Fatal(e.Start(configurationManager.HttpAddress))
 }
 
-func buildServer(ctx context.Context) *echo.Echo {
+func buildServer(ctx context.Context, configurationManager *config.ConfigurationManager) *echo.Echo {
 	e := echo.New()
 
-	configurationManager := config.NewConfigurationManager()
 	dbPool := pgcommon.GetConnectionPool(ctx, configurationManager.PostgreSqlConfig)
 
-	registerRoutes(e, dbPool)
+	registerRoutes(e, dbPool, configurationManager)
 	return e
 }
 
-func registerRoutes(e *echo.Echo, dbPool *pgxpool.Pool) {
+func registerRoutes(e *echo.Echo, dbPool *pgxpool.Pool, configurationManager *config.ConfigurationManager) {
 	categoryRepository := postgresql.NewCategoryRepository(dbPool)
 	categoryService := usecase.NewCategoryService(categoryRepository)
 	categoryController := controller.NewCategoryController(categoryService)
@@ -44,21 +52,21 @@ func registerRoutes(e *echo.Echo, dbPool *pgxpool.Pool) {
 	categoryController.RegisterRoutes(e)
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
-	go startKafkaConsumer()
+	go startKafkaConsumer(dbPool, configurationManager)
+	go startProcessedMessagePruner(dbPool)
 }
 
-func startKafkaConsumer() {
+func startKafkaConsumer(dbPool *pgxpool.Pool, configurationManager *config.ConfigurationManager) {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	consumerGroup := configurationManager.ConsumerGroup
 	consumer := kafkaconsumer.NewConsumerAdapter(
-		[]string{"kafka:9092"},
-		"product.events",
-		"category-service",
-		func(ctx context.Context, message sharedkafka.Message) error {
-			log.Printf("category-service received: %s", string(message.Value))
-			return nil
-		},
+		configurationManager.Kafka,
+		configurationManager.ProductEventsTopic,
+		consumerGroup,
+		inbox.NewIdempotentHandler(dbPool, consumerGroup,
+			kafkaconsumer.NewProductEventHandler(postgresql.NewCategoryProductProjection(dbPool))),
 	)
 	defer consumer.Close()
 
@@ -66,3 +74,10 @@ func startKafkaConsumer() {
 		log.Printf("kafka consumer stopped: %v", err)
 	}
 }
+
+func startProcessedMessagePruner(dbPool *pgxpool.Pool) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	inbox.NewPruner(dbPool, inbox.DefaultPrunerConfig).Start(ctx)
+}
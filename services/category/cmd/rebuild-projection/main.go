@@ -0,0 +1,70 @@
+// Command rebuild-projection empties the category_products projection and
+// replays product.events from the oldest retained offset to rebuild it.
+//
+//	go run ./services/category/cmd/rebuild-projection
+//
+// It reads the same configuration as the category service. The live
+// consumer may keep running: every change is guarded by the event time, so
+// events it applies meanwhile are never overwritten by older replayed ones.
+// Products whose events have already expired from the topic are lost.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	kafkaconsumer "product-app/services/category/internal/adapters/kafka"
+	"product-app/services/category/internal/adapters/postgresql"
+	pgcommon "product-app/services/category/internal/adapters/postgresql/common"
+	"product-app/services/category/internal/config"
+	"product-app/shared/inbox"
+	"product-app/shared/kafka"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	configurationManager, err := config.NewConfigurationManager()
+	if err != nil {
+		log.Fatalf("rebuild-projection not started: %v", err)
+	}
+
+	dbPool := pgcommon.GetConnectionPool(ctx, configurationManager.PostgreSqlConfig)
+	defer dbPool.Close()
+
+	projection := postgresql.NewCategoryProductProjection(dbPool)
+	if err := projection.Truncate(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	// The replay bypasses processed_messages: the live consumer has already
+	// recorded these events, and the inbox would skip every one of them.
+	replayed, err := kafka.Replay(ctx, configurationManager.Kafka.Brokers, configurationManager.ProductEventsTopic,
+		inTransaction(dbPool, kafkaconsumer.NewProductEventHandler(projection)))
+	log.Printf("replayed %d message(s) from %s", replayed, configurationManager.ProductEventsTopic)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func inTransaction(dbPool *pgxpool.Pool, handler inbox.TxHandler) kafka.MessageHandler {
+	return func(ctx context.Context, message kafka.Message) error {
+		tx, err := dbPool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to start transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback(ctx) }()
+
+		if err := handler(ctx, tx, message); err != nil {
+			return err
+		}
+		return tx.Commit(ctx)
+	}
+}
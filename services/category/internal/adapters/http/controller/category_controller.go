@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"fmt"
 	"net/http"
 	"product-app/services/category/internal/adapters/http/controller/response"
 	"product-app/services/category/internal/adapters/http/middleware"
@@ -21,6 +22,7 @@ func NewCategoryController(categoryService usecase.ICategoryService) *CategoryCo
 func (categoryController *CategoryController) RegisterRoutes(e *echo.Echo) {
 	e.GET("/api/v1/categories", categoryController.GetAllCategories)
 	e.GET("/api/v1/categories/:id", categoryController.GetCategoryById)
+	e.GET("/api/v1/categories/:id/products", categoryController.GetCategoryProducts)
 	protected := e.Group("/api/v1/categories", middleware.JWTMiddleware())
 	protected.POST("", categoryController.AddCategory)
 	protected.PUT("/:id", categoryController.UpdateCategory)
@@ -50,6 +52,24 @@ func (categoryController *CategoryController) GetCategoryById(c echo.Context) er
 	return c.JSON(http.StatusOK, category)
 }
 
+func (categoryController *CategoryController) GetCategoryProducts(c echo.Context) error {
+	categoryId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid category ID",
+		})
+	}
+
+	products, err := categoryController.categoryService.GetProducts(categoryId)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, products)
+}
+
 func (categoryController *CategoryController) AddCategory(c echo.Context) error {
 	var category domain.Category
 	if err := c.Bind(&category); err != nil {
@@ -58,15 +78,15 @@ func (categoryController *CategoryController) AddCategory(c echo.Context) error
 		})
 	}
 
-	if err := categoryController.categoryService.AddCategory(category); err != nil {
+	created, err := categoryController.categoryService.AddCategory(category)
+	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, response.ErrorResponse{
 			Error: err.Error(),
 		})
 	}
 
-	return c.JSON(http.StatusCreated, map[string]string{
-		"message": "Category created successfully",
-	})
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/categories/%d", created.Id))
+	return c.JSON(http.StatusCreated, created)
 }
 
 func (categoryController *CategoryController) UpdateCategory(c echo.Context) error {
@@ -3,6 +3,7 @@ package kafka
 import (
 	"context"
 
+	sharedconfig "product-app/shared/config"
 	"product-app/shared/kafka"
 )
 
@@ -10,12 +11,16 @@ type ConsumerAdapter struct {
 	consumer *kafka.Consumer
 }
 
-func NewConsumerAdapter(brokers []string, topic, groupID string, handler kafka.MessageHandler) *ConsumerAdapter {
+// NewConsumerAdapter consumes topic as groupID with the brokers, concurrency
+// and ordering of settings.
+func NewConsumerAdapter(settings sharedconfig.Kafka, topic, groupID string, handler kafka.MessageHandler) *ConsumerAdapter {
 	return &ConsumerAdapter{
 		consumer: kafka.NewConsumer(kafka.ConsumerConfig{
-			Brokers: brokers,
-			Topic:   topic,
-			GroupID: groupID,
+			Brokers:     settings.Brokers,
+			Topic:       topic,
+			GroupID:     groupID,
+			Concurrency: settings.ConsumerConcurrency,
+			OrderBy:     kafka.Ordering(settings.ConsumerOrderBy),
 		}, handler),
 	}
 }
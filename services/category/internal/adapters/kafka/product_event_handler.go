@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"product-app/services/category/internal/domain"
+	"product-app/services/category/internal/ports"
+	"product-app/shared/inbox"
+	"product-app/shared/kafka"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// NewProductEventHandler returns a handler for product.events that keeps the
+// category product projection up to date. Malformed envelopes and envelopes
+// with an unsupported major schema version are rejected, so they end up in
+// the dead-letter topic. It is meant to be wrapped with
+// inbox.NewIdempotentHandler; all writes go through tx.
+func NewProductEventHandler(projection ports.CategoryProductProjection) inbox.TxHandler {
+	return func(ctx context.Context, tx pgx.Tx, message kafka.Message) error {
+		envelope, err := kafka.DecodeEnvelope(message.Value)
+		if err != nil {
+			return fmt.Errorf("malformed product event at offset %d: %w", message.Offset, err)
+		}
+
+		switch envelope.Type {
+		case kafka.EventTypeProductCreated:
+			var event kafka.ProductCreated
+			if err := envelope.DecodeData(&event); err != nil {
+				return err
+			}
+			return projection.Upsert(ctx, tx, domain.CategoryProduct{
+				Id:         event.ID,
+				Name:       event.Name,
+				Price:      event.Price,
+				StoreID:    event.StoreID,
+				StoreSlug:  event.StoreSlug,
+				Store:      event.Store,
+				CategoryID: event.CategoryID,
+			}, envelope.OccurredAt)
+		case kafka.EventTypeProductUpdated:
+			var event kafka.ProductUpdated
+			if err := envelope.DecodeData(&event); err != nil {
+				return err
+			}
+			return projection.Upsert(ctx, tx, domain.CategoryProduct{
+				Id:         event.ID,
+				Name:       event.Name,
+				Price:      event.Price,
+				StoreID:    event.StoreID,
+				StoreSlug:  event.StoreSlug,
+				Store:      event.Store,
+				CategoryID: event.CategoryID,
+			}, envelope.OccurredAt)
+		case kafka.EventTypeProductDeleted:
+			var event kafka.ProductDeleted
+			if err := envelope.DecodeData(&event); err != nil {
+				return err
+			}
+			return projection.Delete(ctx, tx, event.ID, event.CategoryID, envelope.OccurredAt)
+		default:
+			log.Printf("category-service ignoring event type %s", envelope.Type)
+		}
+		return nil
+	}
+}
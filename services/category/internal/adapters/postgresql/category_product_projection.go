@@ -0,0 +1,79 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"product-app/services/category/internal/domain"
+	"product-app/services/category/internal/ports"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type CategoryProductProjection struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewCategoryProductProjection(dbPool *pgxpool.Pool) ports.CategoryProductProjection {
+	return &CategoryProductProjection{dbPool: dbPool}
+}
+
+// Upsert stores the product unless a newer event, including a delete, has
+// already been applied. On equal versions a delete wins.
+func (projection *CategoryProductProjection) Upsert(ctx context.Context, tx pgx.Tx, product domain.CategoryProduct, version time.Time) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO category_products (product_id, category_id, name, price, store_id, store_slug, store, deleted, last_event_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, FALSE, $8)
+		ON CONFLICT (product_id) DO UPDATE SET
+			category_id = EXCLUDED.category_id,
+			name = EXCLUDED.name,
+			price = EXCLUDED.price,
+			store_id = EXCLUDED.store_id,
+			store_slug = EXCLUDED.store_slug,
+			store = EXCLUDED.store,
+			deleted = FALSE,
+			last_event_at = EXCLUDED.last_event_at
+		WHERE category_products.last_event_at < EXCLUDED.last_event_at
+		   OR (category_products.last_event_at = EXCLUDED.last_event_at AND NOT category_products.deleted)
+	`,
+		product.Id,
+		product.CategoryID,
+		product.Name,
+		product.Price,
+		product.StoreID,
+		product.StoreSlug,
+		product.Store,
+		version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to project product %d: %w", product.Id, err)
+	}
+	return nil
+}
+
+// Delete turns the product into a tombstone, inserting one when the product
+// was never seen so a late created event cannot resurrect it.
+func (projection *CategoryProductProjection) Delete(ctx context.Context, tx pgx.Tx, productId int64, categoryId int64, version time.Time) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO category_products (product_id, category_id, deleted, last_event_at)
+		VALUES ($1, $2, TRUE, $3)
+		ON CONFLICT (product_id) DO UPDATE SET
+			deleted = TRUE,
+			last_event_at = EXCLUDED.last_event_at
+		WHERE category_products.last_event_at <= EXCLUDED.last_event_at
+	`, productId, categoryId, version)
+	if err != nil {
+		return fmt.Errorf("failed to project deletion of product %d: %w", productId, err)
+	}
+	return nil
+}
+
+// Truncate empties the projection before it is rebuilt from the topic.
+func (projection *CategoryProductProjection) Truncate(ctx context.Context) error {
+	if _, err := projection.dbPool.Exec(ctx, `TRUNCATE category_products`); err != nil {
+		return fmt.Errorf("failed to truncate category_products: %w", err)
+	}
+	return nil
+}
@@ -12,6 +12,13 @@ import (
 	"github.com/labstack/gommon/log"
 )
 
+// selectCategoryColumns counts the live products of each category from the
+// category_products projection.
+const selectCategoryColumns = `
+	SELECT c.id, c.name, c.description,
+		(SELECT COUNT(*) FROM category_products cp WHERE cp.category_id = c.id AND NOT cp.deleted)
+	FROM categories c`
+
 type CategoryRepository struct {
 	dbPool *pgxpool.Pool
 }
@@ -24,7 +31,7 @@ func NewCategoryRepository(dbPool *pgxpool.Pool) ports.CategoryRepository {
 
 func (categoryRepository *CategoryRepository) GetAllCategories() []domain.Category {
 	ctx := context.Background()
-	categoryRows, err := categoryRepository.dbPool.Query(ctx, "SELECT id, name, description FROM categories")
+	categoryRows, err := categoryRepository.dbPool.Query(ctx, selectCategoryColumns)
 
 	if err != nil {
 		log.Errorf("Error while getting all categories %v", err)
@@ -36,7 +43,7 @@ func (categoryRepository *CategoryRepository) GetAllCategories() []domain.Catego
 
 	for categoryRows.Next() {
 		var c domain.Category
-		err := categoryRows.Scan(&c.Id, &c.Name, &c.Description)
+		err := categoryRows.Scan(&c.Id, &c.Name, &c.Description, &c.ProductCount)
 		if err != nil {
 			log.Errorf("Error while scanning category: %v", err)
 			continue
@@ -50,11 +57,11 @@ func (categoryRepository *CategoryRepository) GetAllCategories() []domain.Catego
 func (categoryRepository *CategoryRepository) GetById(categoryId int64) (domain.Category, error) {
 	ctx := context.Background()
 
-	getByIdSql := `SELECT id, name, description FROM categories WHERE id = $1`
+	getByIdSql := selectCategoryColumns + ` WHERE c.id = $1`
 	queryRow := categoryRepository.dbPool.QueryRow(ctx, getByIdSql, categoryId)
 
 	var category domain.Category
-	scanErr := queryRow.Scan(&category.Id, &category.Name, &category.Description)
+	scanErr := queryRow.Scan(&category.Id, &category.Name, &category.Description, &category.ProductCount)
 
 	if errors.Is(scanErr, pgx.ErrNoRows) {
 		return domain.Category{}, fmt.Errorf("category not found with id %d: %w", categoryId, scanErr)
@@ -67,7 +74,7 @@ func (categoryRepository *CategoryRepository) GetById(categoryId int64) (domain.
 	return category, nil
 }
 
-func (categoryRepository *CategoryRepository) AddCategory(category domain.Category) error {
+func (categoryRepository *CategoryRepository) AddCategory(category domain.Category) (domain.Category, error) {
 	ctx := context.Background()
 
 	insertCategorySQL := `
@@ -82,11 +89,12 @@ func (categoryRepository *CategoryRepository) AddCategory(category domain.Catego
 
 	if err != nil {
 		log.Printf("❌ Error inserting category: %v", err)
-		return fmt.Errorf("failed to insert category: %w", err)
+		return domain.Category{}, fmt.Errorf("failed to insert category: %w", err)
 	}
 
 	log.Printf("✅ Category inserted with ID: %d", categoryId)
-	return nil
+	category.Id = categoryId
+	return category, nil
 }
 
 func (categoryRepository *CategoryRepository) UpdateCategory(category domain.Category) error {
@@ -128,3 +136,28 @@ func (categoryRepository *CategoryRepository) DeleteById(categoryId int64) error
 	log.Printf("INFO: Category deleted with id %d", categoryId)
 	return nil
 }
+
+func (categoryRepository *CategoryRepository) GetProductsByCategoryId(categoryId int64) ([]domain.CategoryProduct, error) {
+	ctx := context.Background()
+
+	rows, err := categoryRepository.dbPool.Query(ctx, `
+		SELECT product_id, name, price, store_id, store_slug, store, category_id
+		FROM category_products
+		WHERE category_id = $1 AND NOT deleted
+		ORDER BY product_id
+	`, categoryId)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting products of category %d: %w", categoryId, err)
+	}
+	defer rows.Close()
+
+	products := []domain.CategoryProduct{}
+	for rows.Next() {
+		var p domain.CategoryProduct
+		if err := rows.Scan(&p.Id, &p.Name, &p.Price, &p.StoreID, &p.StoreSlug, &p.Store, &p.CategoryID); err != nil {
+			return nil, fmt.Errorf("error while scanning product of category %d: %w", categoryId, err)
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
@@ -1,7 +1,8 @@
 package domain
 
 type Category struct {
-	Id          int64  `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Id           int64  `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	ProductCount int64  `json:"product_count"`
 }
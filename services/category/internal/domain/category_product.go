@@ -0,0 +1,13 @@
+package domain
+
+// CategoryProduct is the category service's copy of a product, kept up to
+// date from product.events.
+type CategoryProduct struct {
+	Id         int64   `json:"id"`
+	Name       string  `json:"name"`
+	Price      float32 `json:"price"`
+	StoreID    int64   `json:"store_id"`
+	StoreSlug  string  `json:"store_slug"`
+	Store      string  `json:"store"`
+	CategoryID int64   `json:"category_id"`
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"product-app/services/category/internal/domain"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// CategoryProductProjection applies product events to the category_products
+// table inside the caller's transaction. version is the event's occurred_at;
+// a change older than the stored one is ignored, so events may arrive out of
+// order or more than once.
+type CategoryProductProjection interface {
+	Upsert(ctx context.Context, tx pgx.Tx, product domain.CategoryProduct, version time.Time) error
+	Delete(ctx context.Context, tx pgx.Tx, productId int64, categoryId int64, version time.Time) error
+	Truncate(ctx context.Context) error
+}
@@ -5,7 +5,8 @@ import "product-app/services/category/internal/domain"
 type CategoryRepository interface {
 	GetAllCategories() []domain.Category
 	GetById(categoryId int64) (domain.Category, error)
-	AddCategory(category domain.Category) error
+	AddCategory(category domain.Category) (domain.Category, error)
 	UpdateCategory(category domain.Category) error
 	DeleteById(categoryId int64) error
+	GetProductsByCategoryId(categoryId int64) ([]domain.CategoryProduct, error)
 }
@@ -10,9 +10,10 @@ import (
 type ICategoryService interface {
 	GetAllCategories() []domain.Category
 	GetById(categoryId int64) (domain.Category, error)
-	AddCategory(category domain.Category) error
+	AddCategory(category domain.Category) (domain.Category, error)
 	UpdateCategory(category domain.Category) error
 	DeleteById(categoryId int64) error
+	GetProducts(categoryId int64) ([]domain.CategoryProduct, error)
 }
 
 type CategoryService struct {
@@ -33,9 +34,9 @@ func (categoryService *CategoryService) GetById(categoryId int64) (domain.Catego
 	return categoryService.categoryRepository.GetById(categoryId)
 }
 
-func (categoryService *CategoryService) AddCategory(category domain.Category) error {
+func (categoryService *CategoryService) AddCategory(category domain.Category) (domain.Category, error) {
 	if err := validateCategory(category); err != nil {
-		return err
+		return domain.Category{}, err
 	}
 	return categoryService.categoryRepository.AddCategory(category)
 }
@@ -51,6 +52,15 @@ func (categoryService *CategoryService) DeleteById(categoryId int64) error {
 	return categoryService.categoryRepository.DeleteById(categoryId)
 }
 
+// GetProducts lists the products of an existing category from the
+// projection maintained by the product event consumer.
+func (categoryService *CategoryService) GetProducts(categoryId int64) ([]domain.CategoryProduct, error) {
+	if _, err := categoryService.categoryRepository.GetById(categoryId); err != nil {
+		return nil, err
+	}
+	return categoryService.categoryRepository.GetProductsByCategoryId(categoryId)
+}
+
 func validateCategory(category domain.Category) error {
 	if err := validateNameWithRegex(category.Name, "category name is required"); err != nil {
 		return err
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS processed_messages (
+  consumer_group TEXT NOT NULL,
+  message_id TEXT NOT NULL,
+  processed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (consumer_group, message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_messages_processed_at ON processed_messages (processed_at);
@@ -0,0 +1,15 @@
+-- Projection of product.events. Deleted products stay as tombstones so an
+-- older created/updated event delivered late cannot bring them back.
+CREATE TABLE IF NOT EXISTS category_products (
+  product_id BIGINT PRIMARY KEY,
+  category_id BIGINT NOT NULL,
+  name VARCHAR(255) NOT NULL DEFAULT '',
+  price DOUBLE PRECISION NOT NULL DEFAULT 0,
+  store_id BIGINT NOT NULL DEFAULT 0,
+  store_slug VARCHAR(255) NOT NULL DEFAULT '',
+  store VARCHAR(255) NOT NULL DEFAULT '',
+  deleted BOOLEAN NOT NULL DEFAULT FALSE,
+  last_event_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_products_category_id ON category_products (category_id) WHERE NOT deleted;
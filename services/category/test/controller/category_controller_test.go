@@ -83,6 +83,12 @@ func Test_ShouldAddCategory(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/api/v1/categories/3", rec.Header().Get(echo.HeaderLocation))
+
+	var response map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, float64(3), response["id"])
+	assert.Equal(t, "Clothing", response["name"])
 }
 
 func Test_ShouldUpdateCategory(t *testing.T) {
@@ -121,3 +127,45 @@ func Test_ShouldDeleteCategory(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+func Test_ShouldGetCategoryProducts(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/categories/1/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	categoryService := usecase.NewCategoryService(&FakeCategoryRepository{
+		categories: []domain.Category{{Id: 1, Name: "Electronics", Description: "Electronic items", ProductCount: 1}},
+		products:   []domain.CategoryProduct{{Id: 10, Name: "AirFryer", Price: 1000, StoreSlug: "abc-tech", CategoryID: 1}},
+	})
+	categoryController := httpcontroller.NewCategoryController(categoryService)
+
+	err := categoryController.GetCategoryProducts(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var products []domain.CategoryProduct
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &products))
+	assert.Len(t, products, 1)
+	assert.Equal(t, "AirFryer", products[0].Name)
+	assert.Equal(t, "abc-tech", products[0].StoreSlug)
+}
+
+func Test_ShouldReturnNotFound_WhenListingProductsOfMissingCategory(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/categories/99/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("99")
+
+	categoryController := setupCategoryController()
+
+	err := categoryController.GetCategoryProducts(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
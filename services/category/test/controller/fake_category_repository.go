@@ -9,6 +9,7 @@ import (
 
 type FakeCategoryRepository struct {
 	categories []domain.Category
+	products   []domain.CategoryProduct
 }
 
 func NewFakeCategoryRepository(initial []domain.Category) ports.CategoryRepository {
@@ -28,13 +29,14 @@ func (repo *FakeCategoryRepository) GetById(categoryId int64) (domain.Category,
 	return domain.Category{}, errors.New(fmt.Sprintf("Category not found with id %d", categoryId))
 }
 
-func (repo *FakeCategoryRepository) AddCategory(category domain.Category) error {
-	repo.categories = append(repo.categories, domain.Category{
+func (repo *FakeCategoryRepository) AddCategory(category domain.Category) (domain.Category, error) {
+	created := domain.Category{
 		Id:          int64(len(repo.categories)) + 1,
 		Name:        category.Name,
 		Description: category.Description,
-	})
-	return nil
+	}
+	repo.categories = append(repo.categories, created)
+	return created, nil
 }
 
 func (repo *FakeCategoryRepository) UpdateCategory(category domain.Category) error {
@@ -61,3 +63,13 @@ func (repo *FakeCategoryRepository) DeleteById(categoryId int64) error {
 	repo.categories = append(repo.categories[:foundIndex], repo.categories[foundIndex+1:]...)
 	return nil
 }
+
+func (repo *FakeCategoryRepository) GetProductsByCategoryId(categoryId int64) ([]domain.CategoryProduct, error) {
+	products := []domain.CategoryProduct{}
+	for _, product := range repo.products {
+		if product.CategoryID == categoryId {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
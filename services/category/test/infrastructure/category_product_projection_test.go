@@ -0,0 +1,101 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	kafkaconsumer "product-app/services/category/internal/adapters/kafka"
+	"product-app/shared/inbox"
+	"product-app/shared/kafka"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var projectionEpoch = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func productEvent(t *testing.T, event kafka.Event, offset int64, occurredAt time.Time) kafka.Message {
+	value, err := kafka.EncodeEvent("product-service", event, kafka.WithOccurredAt(occurredAt))
+	assert.NoError(t, err)
+	return kafka.Message{Topic: "product.events", Offset: offset, Value: value}
+}
+
+func applyProductEvents(t *testing.T, messages ...kafka.Message) {
+	handler := inbox.NewIdempotentHandler(dbPool, "category-service", kafkaconsumer.NewProductEventHandler(projection))
+	for _, message := range messages {
+		assert.NoError(t, handler(ctx, message))
+	}
+}
+
+func TestProjection_CreatedAndUpdatedEventsBackCategoryListing(t *testing.T) {
+	setupFullTestData()
+
+	applyProductEvents(t,
+		productEvent(t, kafka.ProductCreated{ID: 10, Name: "Telefon", Price: 100, StoreSlug: "abc-tech", CategoryID: 1}, 0, projectionEpoch),
+		productEvent(t, kafka.ProductCreated{ID: 11, Name: "Buzdolabı", Price: 900, CategoryID: 2}, 1, projectionEpoch),
+		productEvent(t, kafka.ProductUpdated{ID: 10, Name: "Telefon", Price: 80, StoreSlug: "abc-tech", CategoryID: 1}, 2, projectionEpoch.Add(time.Minute)),
+	)
+
+	products, err := categoryRepository.GetProductsByCategoryId(1)
+	assert.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, int64(10), products[0].Id)
+	assert.Equal(t, float32(80), products[0].Price)
+	assert.Equal(t, "abc-tech", products[0].StoreSlug)
+
+	category, err := categoryRepository.GetById(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), category.ProductCount)
+}
+
+func TestProjection_IgnoresOlderEvents(t *testing.T) {
+	setupFullTestData()
+
+	applyProductEvents(t,
+		productEvent(t, kafka.ProductUpdated{ID: 10, Name: "Telefon", Price: 80, CategoryID: 1}, 0, projectionEpoch.Add(time.Minute)),
+		productEvent(t, kafka.ProductCreated{ID: 10, Name: "Telefon", Price: 100, CategoryID: 1}, 1, projectionEpoch),
+	)
+
+	products, err := categoryRepository.GetProductsByCategoryId(1)
+	assert.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, float32(80), products[0].Price)
+}
+
+func TestProjection_DeleteLeavesTombstoneThatBlocksLateCreate(t *testing.T) {
+	setupFullTestData()
+
+	applyProductEvents(t,
+		productEvent(t, kafka.ProductDeleted{ID: 10, CategoryID: 1}, 0, projectionEpoch.Add(time.Minute)),
+		productEvent(t, kafka.ProductCreated{ID: 10, Name: "Telefon", Price: 100, CategoryID: 1}, 1, projectionEpoch),
+	)
+
+	products, err := categoryRepository.GetProductsByCategoryId(1)
+	assert.NoError(t, err)
+	assert.Empty(t, products)
+
+	category, err := categoryRepository.GetById(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), category.ProductCount)
+}
+
+func TestProjection_TruncateEmptiesProjection(t *testing.T) {
+	setupFullTestData()
+
+	applyProductEvents(t,
+		productEvent(t, kafka.ProductCreated{ID: 10, Name: "Telefon", Price: 100, CategoryID: 1}, 0, projectionEpoch),
+	)
+	assert.NoError(t, projection.Truncate(ctx))
+
+	products, err := categoryRepository.GetProductsByCategoryId(1)
+	assert.NoError(t, err)
+	assert.Empty(t, products)
+}
+
+func TestProjection_RejectsMalformedEnvelope(t *testing.T) {
+	setupFullTestData()
+	handler := kafkaconsumer.NewProductEventHandler(projection)
+
+	err := handler(ctx, nil, kafka.Message{Topic: "product.events", Offset: 7, Value: []byte(`{"id": 10}`)})
+
+	assert.ErrorIs(t, err, kafka.ErrInvalidEnvelope)
+}
@@ -43,8 +43,10 @@ func TestCategoryRepository_Add(t *testing.T) {
 		Description: "Teknoloji ürünleri",
 	}
 
-	err := categoryRepository.AddCategory(newCategory)
+	created, err := categoryRepository.AddCategory(newCategory)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(1), created.Id)
+	assert.Equal(t, "Teknoloji", created.Name)
 
 	categories := categoryRepository.GetAllCategories()
 	assert.Len(t, categories, 1)
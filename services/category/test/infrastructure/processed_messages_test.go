@@ -0,0 +1,91 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"product-app/shared/inbox"
+	"product-app/shared/kafka"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func countingHandler(calls *int, result error) inbox.TxHandler {
+	return func(ctx context.Context, tx pgx.Tx, message kafka.Message) error {
+		*calls++
+		if _, err := tx.Exec(ctx, `INSERT INTO categories (name, description) VALUES ($1, '')`, string(message.Value)); err != nil {
+			return err
+		}
+		return result
+	}
+}
+
+func eventMessage(eventId, value string, offset int64) kafka.Message {
+	return kafka.Message{
+		Topic:   "product.events",
+		Offset:  offset,
+		Value:   []byte(value),
+		Headers: []kafka.Header{{Key: kafka.HeaderEventID, Value: []byte(eventId)}},
+	}
+}
+
+func TestIdempotentHandler_SkipsDuplicateEventId(t *testing.T) {
+	clearTestData()
+
+	calls := 0
+	handler := inbox.NewIdempotentHandler(dbPool, "category-service", countingHandler(&calls, nil))
+
+	assert.NoError(t, handler(ctx, eventMessage("evt-1", "Oyuncak", 10)))
+	assert.NoError(t, handler(ctx, eventMessage("evt-1", "Oyuncak", 42)))
+
+	assert.Equal(t, 1, calls)
+	assert.Len(t, categoryRepository.GetAllCategories(), 1)
+}
+
+func TestIdempotentHandler_FallsBackToOffset(t *testing.T) {
+	clearTestData()
+
+	calls := 0
+	handler := inbox.NewIdempotentHandler(dbPool, "category-service", countingHandler(&calls, nil))
+	message := kafka.Message{Topic: "product.events", Partition: 0, Offset: 7, Value: []byte("Oyuncak")}
+
+	assert.Equal(t, "product.events/0/7", inbox.MessageID(message))
+	assert.NoError(t, handler(ctx, message))
+	assert.NoError(t, handler(ctx, message))
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotentHandler_RollsBackMarkerWithSideEffects(t *testing.T) {
+	clearTestData()
+
+	calls := 0
+	failing := inbox.NewIdempotentHandler(dbPool, "category-service", countingHandler(&calls, errors.New("boom")))
+	assert.Error(t, failing(ctx, eventMessage("evt-2", "Oyuncak", 1)))
+	assert.Empty(t, categoryRepository.GetAllCategories())
+
+	succeeding := inbox.NewIdempotentHandler(dbPool, "category-service", countingHandler(&calls, nil))
+	assert.NoError(t, succeeding(ctx, eventMessage("evt-2", "Oyuncak", 1)))
+
+	assert.Equal(t, 2, calls)
+	assert.Len(t, categoryRepository.GetAllCategories(), 1)
+}
+
+func TestProcessedMessagesPruner_DeletesExpiredMarkers(t *testing.T) {
+	clearTestData()
+
+	_, err := dbPool.Exec(ctx, `
+		INSERT INTO processed_messages (consumer_group, message_id, processed_at)
+		VALUES ('category-service', 'old', NOW() - INTERVAL '2 hours'),
+		       ('category-service', 'new', NOW())
+	`)
+	assert.NoError(t, err)
+
+	deleted, err := inbox.NewPruner(dbPool, inbox.PrunerConfig{Retention: time.Hour}).Prune(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
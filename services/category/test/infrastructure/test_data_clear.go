@@ -10,6 +10,8 @@ import (
 func TruncateTestData(ctx context.Context, dbPool *pgxpool.Pool) {
 	_, err := dbPool.Exec(ctx, `
 		TRUNCATE TABLE categories RESTART IDENTITY CASCADE;
+		TRUNCATE TABLE processed_messages;
+		TRUNCATE TABLE category_products;
 	`)
 	if err != nil {
 		log.Fatalf("❌ Failed to truncate test data: %v", err)
@@ -18,6 +18,7 @@ var (
 	ctx                context.Context
 	dbPool             *pgxpool.Pool
 	categoryRepository ports.CategoryRepository
+	projection         ports.CategoryProductProjection
 )
 
 func TestMain(m *testing.M) {
@@ -40,6 +41,7 @@ func TestMain(m *testing.M) {
 	createSchema(ctx, dbPool)
 
 	categoryRepository = postgresql.NewCategoryRepository(dbPool)
+	projection = postgresql.NewCategoryProductProjection(dbPool)
 
 	code := m.Run()
 
@@ -66,12 +68,33 @@ func createTestDatabase(ctx context.Context, host, port, dbName string) {
 func createSchema(ctx context.Context, pool *pgxpool.Pool) {
 	_, err := pool.Exec(ctx, `
 		DROP TABLE IF EXISTS categories;
+		DROP TABLE IF EXISTS processed_messages;
+		DROP TABLE IF EXISTS category_products;
 
 		CREATE TABLE categories (
 			id BIGSERIAL PRIMARY KEY,
 			name TEXT NOT NULL,
 			description TEXT
 		);
+
+		CREATE TABLE processed_messages (
+			consumer_group TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			processed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (consumer_group, message_id)
+		);
+
+		CREATE TABLE category_products (
+			product_id BIGINT PRIMARY KEY,
+			category_id BIGINT NOT NULL,
+			name VARCHAR(255) NOT NULL DEFAULT '',
+			price DOUBLE PRECISION NOT NULL DEFAULT 0,
+			store_id BIGINT NOT NULL DEFAULT 0,
+			store_slug VARCHAR(255) NOT NULL DEFAULT '',
+			store VARCHAR(255) NOT NULL DEFAULT '',
+			deleted BOOLEAN NOT NULL DEFAULT FALSE,
+			last_event_at TIMESTAMPTZ NOT NULL
+		);
 	`)
 	if err != nil {
 		panic(err)
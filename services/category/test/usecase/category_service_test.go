@@ -73,8 +73,9 @@ func Test_ShouldAddCategory_Success(t *testing.T) {
 		Name:        "Clothing",
 		Description: "Apparel and accessories",
 	}
-	err := categoryService.AddCategory(newCategory)
+	created, err := categoryService.AddCategory(newCategory)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(3), created.Id)
 
 	afterCategories := categoryService.GetAllCategories()
 	assert.Equal(t, 3, len(afterCategories))
@@ -84,3 +85,27 @@ func Test_ShouldAddCategory_Success(t *testing.T) {
 	assert.Equal(t, "Clothing", addedCategory.Name)
 	assert.Equal(t, "Apparel and accessories", addedCategory.Description)
 }
+
+func Test_ShouldGetCategoryProducts(t *testing.T) {
+	categoryService := usecase.NewCategoryService(&FakeCategoryRepository{
+		categories: []domain.Category{{Id: 1, Name: "Electronics", Description: "Electronic items"}},
+		products: []domain.CategoryProduct{
+			{Id: 10, Name: "AirFryer", Price: 1000, CategoryID: 1},
+			{Id: 11, Name: "Novel", Price: 20, CategoryID: 2},
+		},
+	})
+
+	products, err := categoryService.GetProducts(1)
+
+	assert.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, int64(10), products[0].Id)
+}
+
+func Test_ShouldNotGetProducts_WhenCategoryMissing(t *testing.T) {
+	categoryService := setupCategoryService()
+
+	_, err := categoryService.GetProducts(99)
+
+	assert.Error(t, err)
+}
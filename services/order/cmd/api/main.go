@@ -2,11 +2,26 @@ package main
 
 import (
 	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"product-app/services/order/internal/adapters/fakepayment"
 	"product-app/services/order/internal/adapters/http/controller"
 	postgresql "product-app/services/order/internal/adapters/postgresql/common"
+	"product-app/services/order/internal/adapters/productclient"
 	"product-app/services/order/internal/config"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
 	"product-app/services/order/internal/usecase"
+	"product-app/shared/auth"
+	sharedconfig "product-app/shared/config"
+	"product-app/shared/idempotency"
+	sharedkafka "product-app/shared/kafka"
+	"product-app/shared/outbox"
 
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/labstack/echo/v4"
@@ -15,25 +30,99 @@ import (
 
 func main() {
 	ctx := context.Background()
-	e := buildServer(ctx)
-	e.Start(":8084")
+
+	configurationManager, err := config.NewConfigurationManager()
+	if err != nil {
+		log.Fatalf("order-service not started: %v", err)
+	}
+	log.Printf("effective configuration:\n%s", configurationManager)
+	auth.SetSecret(configurationManager.Auth.JWTSecret)
+
+	e := buildServer(ctx, configurationManager)
+	e.Logger.Fatal(e.Start(configurationManager.HttpAddress))
 }
 
-func buildServer(ctx context.Context) *echo.Echo {
+func buildServer(ctx context.Context, configurationManager *config.ConfigurationManager) *echo.Echo {
 	e := echo.New()
 
-	configurationManager := config.NewConfigurationManager()
 	dbPool := postgresql.GetConnectionPool(ctx, configurationManager.PostgreSqlConfig)
 
-	registerRoutes(e, dbPool)
+	registerRoutes(e, dbPool, configurationManager)
 	return e
 }
 
-func registerRoutes(e *echo.Echo, dbPool *pgxpool.Pool) {
-	orderRepository := postgresql.NewOrderRepository(dbPool)
-	orderService := usecase.NewOrderService(orderRepository)
+func registerRoutes(e *echo.Echo, dbPool *pgxpool.Pool, configurationManager *config.ConfigurationManager) {
+	eventsTopic := configurationManager.OrderEventsTopic
+	orderRepository := postgresql.NewOrderRepository(dbPool, eventsTopic)
+	productCatalog := productclient.NewClient(configurationManager.ProductServiceURL, productclient.Options{
+		Timeout:    configurationManager.ProductServiceTimeout,
+		MaxRetries: configurationManager.ProductServiceRetries,
+		CacheTTL:   configurationManager.ProductCacheTTL,
+	})
+	paymentRepository := postgresql.NewPaymentRepository(dbPool)
+	paymentGateway := newPaymentGateway(configurationManager)
+	orderService := usecase.NewOrderService(orderRepository, productCatalog, paymentGateway, paymentRepository)
 	orderController := controller.NewOrderController(orderService)
+	paymentService := usecase.NewPaymentService(orderService, paymentRepository, paymentGateway)
+	paymentController := controller.NewPaymentController(paymentService)
+	cartService := usecase.NewCartService(postgresql.NewCartRepository(dbPool, eventsTopic), productCatalog, configurationManager.CartTTL)
+	go cartService.StartPruning(context.Background(), time.Hour)
+	cartController := controller.NewCartController(cartService)
+	stockController := controller.NewStockController(usecase.NewStockService(postgresql.NewStockRepository(dbPool)))
+	invoiceController := controller.NewInvoiceController(usecase.NewInvoiceService(orderService, postgresql.NewInvoiceRepository(dbPool), productCatalog, usecase.InvoiceSettings{
+		Seller: domain.InvoiceParty{
+			Name:    configurationManager.SellerName,
+			Address: configurationManager.SellerAddress,
+			TaxId:   configurationManager.SellerTaxId,
+			Email:   configurationManager.SellerEmail,
+		},
+		Currency: configurationManager.InvoiceCurrency,
+		TaxRate:  configurationManager.InvoiceTaxRate,
+	}))
+	shipmentController := controller.NewShipmentController(usecase.NewShipmentService(orderService, postgresql.NewShipmentRepository(dbPool, eventsTopic)))
 
-	orderController.RegisterRoutes(e)
+	idempotencyStore := idempotency.NewPostgresStore(dbPool)
+	go idempotencyStore.StartPruning(context.Background(), time.Hour)
+	idempotent := idempotency.Middleware(idempotencyStore, configurationManager.IdempotencyTTL)
+	orderController.RegisterRoutes(e, idempotent)
+	paymentController.RegisterRoutes(e, idempotent)
+	cartController.RegisterRoutes(e, idempotent)
+	stockController.RegisterRoutes(e)
+	invoiceController.RegisterRoutes(e, idempotent)
+	shipmentController.RegisterRoutes(e, idempotent)
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+
+	go startOutboxRelay(dbPool, configurationManager.Kafka.Brokers, eventsTopic)
+}
+
+// newPaymentGateway builds the adapter selected by PAYMENT_PROVIDER.
+// Validate has already refused the fake provider outside development and
+// test; this refuses it again so it can never take real orders.
+func newPaymentGateway(configurationManager *config.ConfigurationManager) ports.PaymentGateway {
+	switch configurationManager.PaymentProvider {
+	case "fake":
+		if !sharedconfig.IsDevelopment(strings.ToLower(configurationManager.Environment)) {
+			log.Fatalf("order-service not started: the fake payment provider is not allowed with APP_ENV %q", configurationManager.Environment)
+		}
+		return fakepayment.NewProvider(fakepayment.Options{
+			WebhookURL:    configurationManager.PaymentWebhookURL,
+			WebhookSecret: configurationManager.PaymentWebhookSecret,
+			Delay:         configurationManager.PaymentFakeDelay,
+		})
+	}
+	log.Fatalf("order-service not started: PAYMENT_PROVIDER %q is not supported", configurationManager.PaymentProvider)
+	return nil
+}
+
+func startOutboxRelay(dbPool *pgxpool.Pool, brokers []string, topic string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	producer := sharedkafka.NewProducer(brokers, topic)
+	defer producer.Close()
+
+	relay := outbox.NewRelay(dbPool, producer, outbox.RelayConfig{Topic: topic})
+	if err := relay.Start(ctx); err != nil {
+		log.Printf("outbox relay stopped: %v", err)
+	}
 }
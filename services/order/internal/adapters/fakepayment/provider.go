@@ -0,0 +1,295 @@
+package fakepayment
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+)
+
+// ProviderName is what the fake provider records as the payments' provider.
+const ProviderName = "fake"
+
+// Payment methods with a scripted outcome, after the test cards of real
+// providers. Any other payment method is authorized at once.
+const (
+	// MethodDecline is declined, which sends payment.failed
+	MethodDecline = "pm_card_declined"
+	// MethodDelay is authorized after Options.Delay
+	MethodDelay = "pm_card_delayed"
+)
+
+// Options configures the fake provider.
+type Options struct {
+	// WebhookURL receives the provider's events
+	WebhookURL string
+	// WebhookSecret signs the events, see Sign
+	WebhookSecret string
+	// Delay is how long MethodDelay payments wait for authorization
+	Delay time.Duration
+	// RetryBackoff is the wait before redelivering an event that was not
+	// acknowledged with a 2xx; it doubles for each of MaxDeliveries
+	RetryBackoff time.Duration
+	// MaxDeliveries bounds the delivery attempts of an event
+	MaxDeliveries int
+	// HttpClient sends the webhook calls
+	HttpClient *http.Client
+}
+
+// DefaultOptions fill every zero value of the options passed to NewProvider.
+func DefaultOptions() Options {
+	return Options{
+		Delay:         5 * time.Second,
+		RetryBackoff:  200 * time.Millisecond,
+		MaxDeliveries: 5,
+		HttpClient:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// intent is the provider's side of a payment.
+type intent struct {
+	amount   float64
+	status   string
+	refunded float64
+}
+
+// webhookEvent is the wire format of the fake provider's events.
+type webhookEvent struct {
+	Id            string  `json:"id"`
+	Type          string  `json:"type"`
+	PaymentId     string  `json:"payment_id"`
+	Amount        float64 `json:"amount"`
+	FailureReason string  `json:"failure_reason,omitempty"`
+	Created       int64   `json:"created"`
+}
+
+// Provider is an in-memory payment provider for development and tests. It
+// keeps its intents in memory and reports their outcome through signed
+// webhook calls, like a real provider would, so the whole payment flow can
+// run without one.
+type Provider struct {
+	options  Options
+	mu       sync.Mutex
+	intents  map[string]*intent
+	sequence int64
+}
+
+func NewProvider(options Options) ports.PaymentGateway {
+	return &Provider{
+		options: withDefaults(options),
+		intents: map[string]*intent{},
+	}
+}
+
+// Provider implements ports.PaymentGateway.
+func (provider *Provider) Provider() string {
+	return ProviderName
+}
+
+// CreatePaymentIntent implements ports.PaymentGateway. The outcome of the
+// payment is sent to the webhook once the intent has been returned.
+func (provider *Provider) CreatePaymentIntent(_ context.Context, request domain.PaymentIntentRequest) (domain.PaymentIntent, error) {
+	if request.Amount <= 0 {
+		return domain.PaymentIntent{}, fmt.Errorf("%w: amount must be positive", domain.ErrPaymentDeclined)
+	}
+	secret, err := randomHex(12)
+	if err != nil {
+		return domain.PaymentIntent{}, fmt.Errorf("%w: %v", domain.ErrPaymentUnavailable, err)
+	}
+
+	provider.mu.Lock()
+	id := provider.nextId("pi_fake")
+	provider.intents[id] = &intent{amount: request.Amount, status: domain.PaymentStatusPending}
+	provider.mu.Unlock()
+
+	switch request.PaymentMethod {
+	case MethodDecline:
+		go provider.settle(id, domain.PaymentStatusFailed, "card declined", 0)
+	case MethodDelay:
+		go provider.settle(id, domain.PaymentStatusAuthorized, "", provider.options.Delay)
+	default:
+		go provider.settle(id, domain.PaymentStatusAuthorized, "", 0)
+	}
+	return domain.PaymentIntent{ProviderPaymentId: id, ClientSecret: id + "_secret_" + secret}, nil
+}
+
+// Capture implements ports.PaymentGateway. Only an authorized intent can be
+// captured, for at most its amount; payment.captured follows.
+func (provider *Provider) Capture(_ context.Context, providerPaymentId string, amount float64) error {
+	provider.mu.Lock()
+	payment, ok := provider.intents[providerPaymentId]
+	if !ok {
+		provider.mu.Unlock()
+		return fmt.Errorf("%w: unknown payment %s", domain.ErrPaymentNotFound, providerPaymentId)
+	}
+	switch {
+	case payment.status == domain.PaymentStatusCaptured:
+		provider.mu.Unlock()
+		return nil
+	case payment.status != domain.PaymentStatusAuthorized:
+		status := payment.status
+		provider.mu.Unlock()
+		return fmt.Errorf("%w: payment %s is %s", domain.ErrPaymentDeclined, providerPaymentId, status)
+	case amount > payment.amount:
+		provider.mu.Unlock()
+		return fmt.Errorf("%w: capture of %.2f exceeds %.2f", domain.ErrPaymentDeclined, amount, payment.amount)
+	}
+	payment.status = domain.PaymentStatusCaptured
+	payment.amount = amount
+	provider.mu.Unlock()
+
+	go provider.deliver(provider.event(domain.PaymentEventCaptured, providerPaymentId, amount, ""))
+	return nil
+}
+
+// Refund implements ports.PaymentGateway. Refunds are synchronous and may
+// not exceed what was captured.
+func (provider *Provider) Refund(_ context.Context, request domain.RefundRequest) (string, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	payment, ok := provider.intents[request.ProviderPaymentId]
+	if !ok || payment.status != domain.PaymentStatusCaptured {
+		return "", fmt.Errorf("%w: payment %s is not captured", domain.ErrRefundFailed, request.ProviderPaymentId)
+	}
+	if payment.refunded+request.Amount > payment.amount+0.005 {
+		return "", fmt.Errorf("%w: refund exceeds the captured amount", domain.ErrRefundFailed)
+	}
+	payment.refunded += request.Amount
+	return provider.nextId("re_fake"), nil
+}
+
+// ParseWebhook implements ports.PaymentGateway.
+func (provider *Provider) ParseWebhook(payload []byte, header http.Header) (domain.PaymentEvent, error) {
+	if err := verify(provider.options.WebhookSecret, payload, header.Get(SignatureHeader), time.Now()); err != nil {
+		return domain.PaymentEvent{}, err
+	}
+	var event webhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return domain.PaymentEvent{}, fmt.Errorf("decoding webhook event: %w", err)
+	}
+	if event.Id == "" || event.PaymentId == "" {
+		return domain.PaymentEvent{}, fmt.Errorf("webhook event without id or payment_id")
+	}
+	return domain.PaymentEvent{
+		Id:                event.Id,
+		Type:              event.Type,
+		ProviderPaymentId: event.PaymentId,
+		Amount:            event.Amount,
+		FailureReason:     event.FailureReason,
+		OccurredAt:        time.Unix(event.Created, 0).UTC(),
+	}, nil
+}
+
+// settle moves a pending intent to status after delay and reports it.
+func (provider *Provider) settle(id, status, failureReason string, delay time.Duration) {
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	provider.mu.Lock()
+	payment := provider.intents[id]
+	payment.status = status
+	amount := payment.amount
+	provider.mu.Unlock()
+
+	eventType := domain.PaymentEventAuthorized
+	if status == domain.PaymentStatusFailed {
+		eventType = domain.PaymentEventFailed
+	}
+	provider.deliver(provider.event(eventType, id, amount, failureReason))
+}
+
+func (provider *Provider) event(eventType, paymentId string, amount float64, failureReason string) webhookEvent {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	return webhookEvent{
+		Id:            provider.nextId("evt_fake"),
+		Type:          eventType,
+		PaymentId:     paymentId,
+		Amount:        amount,
+		FailureReason: failureReason,
+		Created:       time.Now().Unix(),
+	}
+}
+
+// deliver posts event to the webhook, redelivering it with backoff until it
+// is acknowledged with a 2xx. Every attempt is signed anew.
+func (provider *Provider) deliver(event webhookEvent) {
+	if provider.options.WebhookURL == "" {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("fake payment provider: encoding %s: %v", event.Id, err)
+		return
+	}
+	backoff := provider.options.RetryBackoff
+	for attempt := 1; attempt <= provider.options.MaxDeliveries; attempt++ {
+		if attempt > 1 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		err = provider.post(payload)
+		if err == nil {
+			return
+		}
+	}
+	log.Printf("fake payment provider: giving up on %s %s: %v", event.Type, event.Id, err)
+}
+
+func (provider *Provider) post(payload []byte) error {
+	request, err := http.NewRequest(http.MethodPost, provider.options.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, Sign(provider.options.WebhookSecret, payload, time.Now()))
+	response, err := provider.options.HttpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %d", response.StatusCode)
+	}
+	return nil
+}
+
+// nextId must be called with mu held.
+func (provider *Provider) nextId(prefix string) string {
+	provider.sequence++
+	return fmt.Sprintf("%s_%d", prefix, provider.sequence)
+}
+
+func randomHex(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
+
+func withDefaults(options Options) Options {
+	defaults := DefaultOptions()
+	if options.Delay <= 0 {
+		options.Delay = defaults.Delay
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = defaults.RetryBackoff
+	}
+	if options.MaxDeliveries <= 0 {
+		options.MaxDeliveries = defaults.MaxDeliveries
+	}
+	if options.HttpClient == nil {
+		options.HttpClient = defaults.HttpClient
+	}
+	return options
+}
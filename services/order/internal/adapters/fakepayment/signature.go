@@ -0,0 +1,63 @@
+package fakepayment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"product-app/services/order/internal/domain"
+)
+
+// SignatureHeader carries the signature of a webhook call as
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+const SignatureHeader = "Payment-Signature"
+
+// SignatureTolerance is how old a signed timestamp may be, which bounds how
+// long a captured call can be replayed.
+const SignatureTolerance = 5 * time.Minute
+
+// Sign returns the SignatureHeader value for payload signed at signedAt.
+func Sign(secret string, payload []byte, signedAt time.Time) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + signature(secret, timestamp, payload)
+}
+
+// verify checks a SignatureHeader value against payload.
+func verify(secret string, payload []byte, header string, now time.Time) error {
+	var timestamp, signed string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signed = value
+		}
+	}
+	if timestamp == "" || signed == "" {
+		return fmt.Errorf("%w: missing %s", domain.ErrInvalidWebhookSignature, SignatureHeader)
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", domain.ErrInvalidWebhookSignature)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", domain.ErrInvalidWebhookSignature)
+	}
+	if !hmac.Equal([]byte(signed), []byte(signature(secret, timestamp, payload))) {
+		return fmt.Errorf("%w: signature mismatch", domain.ErrInvalidWebhookSignature)
+	}
+	return nil
+}
+
+func signature(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"product-app/services/order/internal/adapters/http/controller/response"
+	"product-app/services/order/internal/adapters/http/middleware"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CartTokenHeader carries the token of an anonymous cart, both ways.
+const CartTokenHeader = "X-Cart-Token"
+
+type CartController struct {
+	cartService usecase.ICartService
+}
+
+func NewCartController(cartService usecase.ICartService) *CartController {
+	return &CartController{cartService: cartService}
+}
+
+// RegisterRoutes registers the cart routes. The cart works with or without
+// a token: anonymous visitors keep the X-Cart-Token returned with their
+// cart, and a signed-in request that still sends it merges that cart into
+// the user's. Checkout needs a token. idempotent is applied to the POST
+// routes.
+func (cartController *CartController) RegisterRoutes(e *echo.Echo, idempotent ...echo.MiddlewareFunc) {
+	cart := e.Group("/api/v1/cart", middleware.OptionalJWTMiddleware())
+	cart.GET("", cartController.GetCart)
+	cart.POST("/items", cartController.AddItem, idempotent...)
+	cart.PUT("/items/:productId", cartController.UpdateItem)
+	cart.DELETE("/items/:productId", cartController.RemoveItem)
+	cart.POST("/checkout", cartController.Checkout, append([]echo.MiddlewareFunc{middleware.JWTMiddleware()}, idempotent...)...)
+}
+
+func (cartController *CartController) GetCart(c echo.Context) error {
+	cart, err := cartController.cartService.Get(cartOwner(c))
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return cartResponse(c, http.StatusOK, cart)
+}
+
+func (cartController *CartController) AddItem(c echo.Context) error {
+	var item model.CartItemCreate
+	if err := c.Bind(&item); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	cart, err := cartController.cartService.AddItem(cartOwner(c), item)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return cartResponse(c, http.StatusOK, cart)
+}
+
+func (cartController *CartController) UpdateItem(c echo.Context) error {
+	productId, err := parsePositiveIDParam(c, "productId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid product ID",
+		})
+	}
+	var update model.CartItemUpdate
+	if err := c.Bind(&update); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	cart, err := cartController.cartService.UpdateItem(cartOwner(c), productId, update)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return cartResponse(c, http.StatusOK, cart)
+}
+
+func (cartController *CartController) RemoveItem(c echo.Context) error {
+	productId, err := parsePositiveIDParam(c, "productId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid product ID",
+		})
+	}
+
+	cart, err := cartController.cartService.RemoveItem(cartOwner(c), productId, c.QueryParam("variant"))
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return cartResponse(c, http.StatusOK, cart)
+}
+
+func (cartController *CartController) Checkout(c echo.Context) error {
+	owner := cartOwner(c)
+	if owner.UserId <= 0 {
+		return missingUser(c)
+	}
+
+	order, err := cartController.cartService.Checkout(owner)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/orders/%d", order.Id))
+	return c.JSON(http.StatusCreated, order)
+}
+
+// cartOwner identifies the caller's cart from the optional token and the
+// X-Cart-Token header.
+func cartOwner(c echo.Context) model.CartOwner {
+	userId, _ := currentUserId(c)
+	return model.CartOwner{UserId: userId, Token: c.Request().Header.Get(CartTokenHeader)}
+}
+
+// cartResponse answers with cart, handing an anonymous cart's token back in
+// X-Cart-Token.
+func cartResponse(c echo.Context, status int, cart domain.Cart) error {
+	if cart.Token != "" {
+		c.Response().Header().Set(CartTokenHeader, cart.Token)
+	}
+	return c.JSON(status, cart)
+}
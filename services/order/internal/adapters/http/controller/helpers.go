@@ -2,7 +2,10 @@ package controller
 
 import (
 	"fmt"
+	"product-app/services/order/internal/usecase/model"
+	"product-app/shared/auth"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -15,3 +18,79 @@ func parsePositiveIDParam(c echo.Context, name string) (int64, error) {
 	}
 	return int64(id), nil
 }
+
+// currentUserId returns the id of the authenticated user set by the JWT
+// middleware.
+func currentUserId(c echo.Context) (int64, bool) {
+	userId, ok := c.Get("user_id").(int64)
+	return userId, ok && userId > 0
+}
+
+// currentRequester describes the authenticated caller for read checks.
+func currentRequester(c echo.Context) (model.Requester, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return model.Requester{}, false
+	}
+	roles, _ := c.Get("roles").([]string)
+	requester := model.Requester{UserId: userId}
+	for _, role := range roles {
+		if role == auth.RoleAdmin {
+			requester.Admin = true
+		}
+	}
+	return requester, true
+}
+
+// orderSearchFromQuery reads the list filters, sort and page from the query
+// string. from and to take RFC 3339 timestamps or dates; a date in to
+// includes that whole day.
+func orderSearchFromQuery(c echo.Context) (model.OrderSearch, error) {
+	search := model.OrderSearch{
+		Status:         c.QueryParam("status"),
+		CustomerNumber: c.QueryParam("customer_number"),
+		Sort:           c.QueryParam("sort"),
+		Cursor:         c.QueryParam("cursor"),
+	}
+
+	var err error
+	if search.From, err = parseTimeQuery(c, "from", false); err != nil {
+		return model.OrderSearch{}, err
+	}
+	if search.To, err = parseTimeQuery(c, "to", true); err != nil {
+		return model.OrderSearch{}, err
+	}
+	if raw := c.QueryParam("product_id"); raw != "" {
+		productId, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || productId <= 0 {
+			return model.OrderSearch{}, fmt.Errorf("invalid product_id")
+		}
+		search.ProductId = productId
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return model.OrderSearch{}, fmt.Errorf("invalid limit")
+		}
+		search.Limit = limit
+	}
+	return search, nil
+}
+
+func parseTimeQuery(c echo.Context, name string, endOfDay bool) (time.Time, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: use RFC 3339 or YYYY-MM-DD", name)
+	}
+	if endOfDay {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return parsed, nil
+}
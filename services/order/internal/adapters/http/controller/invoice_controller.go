@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"product-app/services/order/internal/adapters/http/controller/response"
+	"product-app/services/order/internal/adapters/http/middleware"
+	"product-app/services/order/internal/adapters/invoice"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InvoiceController struct {
+	invoiceService usecase.IInvoiceService
+}
+
+func NewInvoiceController(invoiceService usecase.IInvoiceService) *InvoiceController {
+	return &InvoiceController{invoiceService: invoiceService}
+}
+
+// RegisterRoutes registers the invoice and credit-note routes. Documents
+// are rendered as PDF, or as HTML with ?format=html or an Accept header
+// preferring text/html. idempotent is applied to issuing credit notes.
+func (invoiceController *InvoiceController) RegisterRoutes(e *echo.Echo, idempotent ...echo.MiddlewareFunc) {
+	protected := e.Group("/api/v1/orders", middleware.JWTMiddleware())
+	protected.GET("/:id/invoice", invoiceController.GetInvoice)
+	protected.POST("/:id/credit-notes", invoiceController.IssueCreditNote, idempotent...)
+	protected.GET("/:id/credit-notes", invoiceController.GetCreditNotes)
+	protected.GET("/:id/credit-notes/:number", invoiceController.GetCreditNote)
+}
+
+func (invoiceController *InvoiceController) GetInvoice(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	document, err := invoiceController.invoiceService.GetInvoice(orderId, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return renderInvoice(c, document)
+}
+
+func (invoiceController *InvoiceController) IssueCreditNote(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	var creditNote model.CreditNoteCreate
+	if err := c.Bind(&creditNote); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	issued, err := invoiceController.invoiceService.IssueCreditNote(orderId, creditNote, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/orders/%d/credit-notes/%s", orderId, url.PathEscape(issued.Number)))
+	return c.JSON(http.StatusCreated, issued)
+}
+
+func (invoiceController *InvoiceController) GetCreditNotes(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	creditNotes, err := invoiceController.invoiceService.GetCreditNotes(orderId, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, creditNotes)
+}
+
+func (invoiceController *InvoiceController) GetCreditNote(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	document, err := invoiceController.invoiceService.GetCreditNote(orderId, c.Param("number"), requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return renderInvoice(c, document)
+}
+
+// renderInvoice answers with document as HTML when the request asks for it
+// and as PDF otherwise.
+func renderInvoice(c echo.Context, document domain.Invoice) error {
+	format := c.QueryParam("format")
+	if format == "" && strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMETextHTML) {
+		format = "html"
+	}
+
+	switch format {
+	case "html":
+		body, err := invoice.RenderHTML(document)
+		if err != nil {
+			return orderErrorResponse(c, err)
+		}
+		return c.Blob(http.StatusOK, echo.MIMETextHTMLCharsetUTF8, body)
+	case "", "pdf":
+		body, err := invoice.RenderPDF(document)
+		if err != nil {
+			return orderErrorResponse(c, err)
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", invoice.Filename(document, "pdf")))
+		return c.Blob(http.StatusOK, "application/pdf", body)
+	default:
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "format must be pdf or html",
+		})
+	}
+}
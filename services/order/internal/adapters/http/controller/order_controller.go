@@ -1,11 +1,14 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"product-app/services/order/internal/adapters/http/controller/response"
 	"product-app/services/order/internal/adapters/http/middleware"
 	"product-app/services/order/internal/domain"
 	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
 
 	"github.com/labstack/echo/v4"
 )
@@ -18,24 +21,61 @@ func NewOrderController(orderService usecase.IOrderService) *OrderController {
 	return &OrderController{orderService: orderService}
 }
 
-func (orderController *OrderController) RegisterRoutes(e *echo.Echo) {
-	e.GET("/api/v1/orders", orderController.GetAllOrders)
-	e.GET("/api/v1/orders/:id", orderController.GetOrderById)
-	e.GET("/api/v1/orders/customer/:customerNumber", orderController.GetByCustomerNumber)
-
+// RegisterRoutes registers the order routes. Every route needs a token;
+// orders are only shown to their owner and to admins, and only admins may
+// delete or refund them. idempotent is applied to the POST routes after
+// authentication.
+func (orderController *OrderController) RegisterRoutes(e *echo.Echo, idempotent ...echo.MiddlewareFunc) {
 	protected := e.Group("/api/v1/orders", middleware.JWTMiddleware())
-	protected.POST("", orderController.CreateOrder)
+	protected.GET("", orderController.GetAllOrders)
+	protected.GET("/me", orderController.GetMyOrders)
+	protected.GET("/:id", orderController.GetOrderById)
+	protected.GET("/customer/:customerNumber", orderController.GetByCustomerNumber)
+	protected.POST("", orderController.CreateOrder, idempotent...)
 	protected.DELETE("/:id", orderController.DeleteOrder)
+	protected.POST("/:id/transitions", orderController.TransitionOrder, idempotent...)
+	protected.GET("/:id/transitions", orderController.GetStatusHistory)
+	protected.POST("/:id/cancel", orderController.CancelOrder, idempotent...)
+	protected.POST("/:id/refunds", orderController.RefundOrder, idempotent...)
+	protected.GET("/:id/refunds", orderController.GetRefunds)
 }
 
 func (orderController *OrderController) GetAllOrders(c echo.Context) error {
-	orders, err := orderController.orderService.GetAll()
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+	search, err := orderSearchFromQuery(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
 			Error: err.Error(),
 		})
 	}
-	return c.JSON(http.StatusOK, orders)
+
+	page, err := orderController.orderService.GetAll(search, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+func (orderController *OrderController) GetMyOrders(c echo.Context) error {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return missingUser(c)
+	}
+	search, err := orderSearchFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	page, err := orderController.orderService.GetByUserId(userId, search)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, page)
 }
 
 func (orderController *OrderController) GetOrderById(c echo.Context) error {
@@ -45,12 +85,14 @@ func (orderController *OrderController) GetOrderById(c echo.Context) error {
 			Error: "Invalid order ID",
 		})
 	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
 
-	order, err := orderController.orderService.GetById(orderId)
+	order, err := orderController.orderService.GetById(orderId, requester)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, response.ErrorResponse{
-			Error: err.Error(),
-		})
+		return orderErrorResponse(c, err)
 	}
 	return c.JSON(http.StatusOK, order)
 }
@@ -62,30 +104,42 @@ func (orderController *OrderController) GetByCustomerNumber(c echo.Context) erro
 			Error: "Invalid customer number",
 		})
 	}
-
-	orders, err := orderController.orderService.GetByCustomerNumber(customerNumber)
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+	search, err := orderSearchFromQuery(c)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, response.ErrorResponse{
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
 			Error: err.Error(),
 		})
 	}
-	return c.JSON(http.StatusOK, orders)
+
+	page, err := orderController.orderService.GetByCustomerNumber(customerNumber, search, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, page)
 }
 
 func (orderController *OrderController) CreateOrder(c echo.Context) error {
-	var order domain.Order
+	userId, ok := currentUserId(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	var order model.OrderCreate
 	if err := c.Bind(&order); err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
 			Error: "Invalid request body",
 		})
 	}
 
-	created, err := orderController.orderService.Create(order)
+	created, err := orderController.orderService.Create(order, userId)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, response.ErrorResponse{
-			Error: err.Error(),
-		})
+		return orderErrorResponse(c, err)
 	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/orders/%d", created.Id))
 	return c.JSON(http.StatusCreated, created)
 }
 
@@ -97,12 +151,184 @@ func (orderController *OrderController) DeleteOrder(c echo.Context) error {
 		})
 	}
 
-	if err := orderController.orderService.Delete(orderId); err != nil {
-		return c.JSON(http.StatusNotFound, response.ErrorResponse{
-			Error: err.Error(),
-		})
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	if err := orderController.orderService.Delete(orderId, requester); err != nil {
+		return orderErrorResponse(c, err)
 	}
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Order deleted successfully",
 	})
 }
+
+func (orderController *OrderController) TransitionOrder(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	var transition model.OrderTransition
+	if err := c.Bind(&transition); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	order, err := orderController.orderService.Transition(orderId, transition, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, order)
+}
+
+func (orderController *OrderController) GetStatusHistory(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	history, err := orderController.orderService.GetStatusHistory(orderId, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, history)
+}
+
+func (orderController *OrderController) CancelOrder(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	var cancellation model.OrderCancellation
+	if err := c.Bind(&cancellation); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	order, err := orderController.orderService.Cancel(orderId, cancellation, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, order)
+}
+
+func (orderController *OrderController) RefundOrder(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	var refundCreate model.RefundCreate
+	if err := c.Bind(&refundCreate); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	refund, err := orderController.orderService.Refund(orderId, refundCreate, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, refund)
+}
+
+func (orderController *OrderController) GetRefunds(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	refunds, err := orderController.orderService.GetRefunds(orderId, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, refunds)
+}
+
+// orderErrorResponse maps domain errors to HTTP status codes.
+func orderErrorResponse(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound),
+		errors.Is(err, domain.ErrPaymentNotFound),
+		errors.Is(err, domain.ErrCartNotFound),
+		errors.Is(err, domain.ErrCartItemNotFound),
+		errors.Is(err, domain.ErrInvoiceNotFound),
+		errors.Is(err, domain.ErrShipmentNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrOrderForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrIllegalTransition),
+		errors.Is(err, domain.ErrPaymentAlreadyInProgress),
+		errors.Is(err, domain.ErrOutOfStock),
+		errors.Is(err, domain.ErrCartChanged),
+		errors.Is(err, domain.ErrInvoiceNotAvailable),
+		errors.Is(err, domain.ErrInvoiceAlreadyIssued),
+		errors.Is(err, domain.ErrDuplicateTrackingNumber):
+		status = http.StatusConflict
+	case errors.Is(err, domain.ErrPaymentDeclined):
+		status = http.StatusPaymentRequired
+	case errors.Is(err, domain.ErrInvalidOrder),
+		errors.Is(err, domain.ErrInvalidOrderStatus),
+		errors.Is(err, domain.ErrInvalidCancelReason),
+		errors.Is(err, domain.ErrInvalidRefund),
+		errors.Is(err, domain.ErrInvalidCartItem),
+		errors.Is(err, domain.ErrCartEmpty),
+		errors.Is(err, domain.ErrProductNotFound),
+		errors.Is(err, domain.ErrInvalidStockLevel),
+		errors.Is(err, domain.ErrInvalidCreditNote),
+		errors.Is(err, domain.ErrInvalidShipment),
+		errors.Is(err, domain.ErrInvalidTrackingEvent):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrInvalidOrderQuery):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrRefundFailed),
+		errors.Is(err, domain.ErrPaymentUnavailable):
+		status = http.StatusBadGateway
+	case errors.Is(err, domain.ErrCatalogUnavailable):
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, response.ErrorResponse{Error: err.Error()})
+}
+
+func missingUser(c echo.Context) error {
+	return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+		Error: "Missing user in token",
+	})
+}
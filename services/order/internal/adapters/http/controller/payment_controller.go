@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"product-app/services/order/internal/adapters/http/controller/response"
+	"product-app/services/order/internal/adapters/http/middleware"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxWebhookBody bounds the size of a webhook call read into memory.
+const maxWebhookBody = 1 << 20
+
+type PaymentController struct {
+	paymentService usecase.IPaymentService
+}
+
+func NewPaymentController(paymentService usecase.IPaymentService) *PaymentController {
+	return &PaymentController{paymentService: paymentService}
+}
+
+// RegisterRoutes registers the payment routes. Payments of an order need a
+// token like the order itself; the webhook is called by the payment provider
+// and is authenticated by its signature instead. idempotent is applied to
+// starting a payment.
+func (paymentController *PaymentController) RegisterRoutes(e *echo.Echo, idempotent ...echo.MiddlewareFunc) {
+	protected := e.Group("/api/v1/orders", middleware.JWTMiddleware())
+	protected.POST("/:id/payments", paymentController.StartPayment, idempotent...)
+	protected.GET("/:id/payments", paymentController.GetPayments)
+
+	e.POST("/api/v1/payments/webhook", paymentController.HandleWebhook)
+}
+
+func (paymentController *PaymentController) StartPayment(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	var start model.PaymentStart
+	if err := c.Bind(&start); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	payment, err := paymentController.paymentService.StartPayment(orderId, start, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/orders/%d/payments", orderId))
+	return c.JSON(http.StatusCreated, payment)
+}
+
+func (paymentController *PaymentController) GetPayments(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	payments, err := paymentController.paymentService.GetPayments(orderId, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, payments)
+}
+
+// HandleWebhook answers the provider with a 2xx once an event has been
+// applied or was applied before, and with an error status otherwise, which
+// makes the provider deliver the event again.
+func (paymentController *PaymentController) HandleWebhook(c echo.Context) error {
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBody))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	err = paymentController.paymentService.HandleWebhook(payload, c.Request().Header)
+	switch {
+	case err == nil:
+		return c.JSON(http.StatusOK, map[string]string{"status": "processed"})
+	case errors.Is(err, domain.ErrDuplicateWebhookEvent):
+		return c.JSON(http.StatusOK, map[string]string{"status": "duplicate"})
+	case errors.Is(err, domain.ErrInvalidWebhookSignature):
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, domain.ErrPaymentNotFound),
+		errors.Is(err, domain.ErrOrderNotFound):
+		// The provider may call before the payment has been stored; its
+		// retry will find it.
+		return c.JSON(http.StatusNotFound, response.ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("payment webhook failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"product-app/services/order/internal/adapters/http/controller/response"
+	"product-app/services/order/internal/adapters/http/middleware"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ShipmentController struct {
+	shipmentService usecase.IShipmentService
+}
+
+func NewShipmentController(shipmentService usecase.IShipmentService) *ShipmentController {
+	return &ShipmentController{shipmentService: shipmentService}
+}
+
+// RegisterRoutes registers the shipment routes. idempotent is applied to
+// creating shipments and appending tracking events.
+func (shipmentController *ShipmentController) RegisterRoutes(e *echo.Echo, idempotent ...echo.MiddlewareFunc) {
+	protected := e.Group("/api/v1/orders", middleware.JWTMiddleware())
+	protected.POST("/:id/shipments", shipmentController.CreateShipment, idempotent...)
+	protected.GET("/:id/shipments", shipmentController.GetShipments)
+	protected.GET("/:id/shipments/:shipmentId", shipmentController.GetShipment)
+	protected.POST("/:id/shipments/:shipmentId/events", shipmentController.AddTrackingEvent, idempotent...)
+}
+
+func (shipmentController *ShipmentController) CreateShipment(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	var create model.ShipmentCreate
+	if err := c.Bind(&create); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	shipment, err := shipmentController.shipmentService.CreateShipment(orderId, create, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/orders/%d/shipments/%d", orderId, shipment.Id))
+	return c.JSON(http.StatusCreated, shipment)
+}
+
+func (shipmentController *ShipmentController) GetShipments(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	shipments, err := shipmentController.shipmentService.GetShipments(orderId, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, shipments)
+}
+
+func (shipmentController *ShipmentController) GetShipment(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	shipmentId, err := parsePositiveIDParam(c, "shipmentId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid shipment ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	shipment, err := shipmentController.shipmentService.GetShipment(orderId, shipmentId, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, shipment)
+}
+
+// AddTrackingEvent answers 201 with the shipment including the new event.
+func (shipmentController *ShipmentController) AddTrackingEvent(c echo.Context) error {
+	orderId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid order ID",
+		})
+	}
+	shipmentId, err := parsePositiveIDParam(c, "shipmentId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid shipment ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	var event model.TrackingEventCreate
+	if err := c.Bind(&event); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	shipment, err := shipmentController.shipmentService.AddTrackingEvent(orderId, shipmentId, event, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/orders/%d/shipments/%d", orderId, shipmentId))
+	return c.JSON(http.StatusCreated, shipment)
+}
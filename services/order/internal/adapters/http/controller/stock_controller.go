@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"net/http"
+	"product-app/services/order/internal/adapters/http/controller/response"
+	"product-app/services/order/internal/adapters/http/middleware"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+type StockController struct {
+	stockService usecase.IStockService
+}
+
+func NewStockController(stockService usecase.IStockService) *StockController {
+	return &StockController{stockService: stockService}
+}
+
+// RegisterRoutes registers the admin-only stock routes.
+func (stockController *StockController) RegisterRoutes(e *echo.Echo) {
+	protected := e.Group("/api/v1/stock", middleware.JWTMiddleware())
+	protected.GET("/:productId", stockController.GetStock)
+	protected.PUT("/:productId", stockController.SetStock)
+}
+
+func (stockController *StockController) GetStock(c echo.Context) error {
+	productId, err := parsePositiveIDParam(c, "productId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid product ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+
+	levels, err := stockController.stockService.GetStock(productId, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, levels)
+}
+
+func (stockController *StockController) SetStock(c echo.Context) error {
+	productId, err := parsePositiveIDParam(c, "productId")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid product ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return missingUser(c)
+	}
+	var update model.StockLevelUpdate
+	if err := c.Bind(&update); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	level, err := stockController.stockService.SetStock(productId, update, requester)
+	if err != nil {
+		return orderErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, level)
+}
@@ -9,3 +9,7 @@ import (
 func JWTMiddleware() echo.MiddlewareFunc {
 	return auth.JWTMiddleware()
 }
+
+func OptionalJWTMiddleware() echo.MiddlewareFunc {
+	return auth.OptionalJWTMiddleware()
+}
@@ -0,0 +1,70 @@
+package invoice
+
+import (
+	"bytes"
+	"html/template"
+	"product-app/services/order/internal/domain"
+)
+
+var htmlTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"title":      Title,
+	"money":      money,
+	"rate":       rate,
+	"describe":   describe,
+	"stores":     stores,
+	"partyLines": partyLines,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{title .}} {{.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 14px; color: #222; margin: 40px; }
+h1 { margin: 0 0 4px; }
+.parties { display: flex; justify-content: space-between; margin: 24px 0; }
+.parties h2 { font-size: 12px; text-transform: uppercase; color: #777; margin: 0 0 4px; }
+table { width: 100%; border-collapse: collapse; margin: 16px 0; }
+th, td { padding: 6px 8px; border-bottom: 1px solid #ddd; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.totals { width: 40%; margin-left: auto; }
+.grand td { font-weight: bold; border-top: 2px solid #222; }
+.muted { color: #777; }
+</style>
+</head>
+<body>
+<h1>{{title .}} {{.Number}}</h1>
+<div class="muted">Issued {{.IssuedAt.Format "2006-01-02"}} · Order #{{.OrderId}} · Amounts in {{.Currency}}</div>
+{{if .CorrectsNumber}}<p>Corrects invoice {{.CorrectsNumber}}. Reason: {{.Reason}}</p>{{end}}
+<div class="parties">
+<div><h2>Seller</h2>{{range partyLines .Seller}}{{.}}<br>{{end}}</div>
+<div><h2>Customer</h2>{{range partyLines .Customer}}{{.}}<br>{{end}}</div>
+</div>
+{{with stores .}}<p>Sold by: {{.}}</p>{{end}}
+<table>
+<thead><tr><th>Description</th><th>Qty</th><th>Unit price</th><th>Discount</th><th>Net</th><th>Tax</th><th>Total</th></tr></thead>
+<tbody>
+{{range .Lines}}<tr><td>{{describe .}}{{if .Store}}<br><span class="muted">{{.Store}}</span>{{end}}</td><td>{{.Quantity}}</td><td>{{money .UnitPrice}}</td><td>{{money .Discount}}</td><td>{{money .NetAmount}}</td><td>{{rate .TaxRate}}</td><td>{{money .Total}}</td></tr>
+{{end}}</tbody>
+</table>
+<table class="totals">
+<thead><tr><th>Tax rate</th><th>Net</th><th>Tax</th><th>Total</th></tr></thead>
+<tbody>
+{{range .Taxes}}<tr><td>{{rate .Rate}}</td><td>{{money .NetAmount}}</td><td>{{money .TaxAmount}}</td><td>{{money .Total}}</td></tr>
+{{end}}<tr><td>Net total</td><td></td><td></td><td>{{money .NetTotal}}</td></tr>
+<tr><td>Tax total</td><td></td><td></td><td>{{money .TaxTotal}}</td></tr>
+<tr class="grand"><td>Total</td><td></td><td></td><td>{{money .GrandTotal}} {{.Currency}}</td></tr>
+</tbody>
+</table>
+<p class="muted">Prices include tax.</p>
+</body>
+</html>
+`))
+
+// RenderHTML renders invoice as a standalone HTML page.
+func RenderHTML(invoice domain.Invoice) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := htmlTemplate.Execute(&buffer, invoice); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
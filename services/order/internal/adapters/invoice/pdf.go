@@ -0,0 +1,359 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"product-app/services/order/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// A4 in points, with the margin kept free on every side.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+	rightEdge  = pageWidth - margin
+	// bottom leaves room for the page footer.
+	bottom = margin + 30
+)
+
+// Right edges of the numeric columns of the line table.
+const (
+	quantityColumn  = 300.0
+	unitPriceColumn = 360.0
+	discountColumn  = 420.0
+	netColumn       = 475.0
+	taxRateColumn   = 505.0
+	totalColumn     = rightEdge
+)
+
+// The two standard Type 1 fonts every PDF reader has, so nothing needs to
+// be embedded.
+const (
+	regular = "F1"
+	bold    = "F2"
+)
+
+// RenderPDF renders invoice as a PDF document. The output depends only on
+// invoice, so an issued document renders to the same bytes every time.
+func RenderPDF(invoice domain.Invoice) ([]byte, error) {
+	doc := &pdfDocument{}
+	doc.newPage()
+
+	doc.text(margin, doc.y, 20, bold, 0, Title(invoice)+" "+invoice.Number)
+	doc.y -= 20
+	doc.text(margin, doc.y, 10, regular, 0.4, fmt.Sprintf("Issued %s    Order #%d    Amounts in %s",
+		invoice.IssuedAt.Format("2006-01-02"), invoice.OrderId, invoice.Currency))
+	doc.y -= 32
+
+	doc.text(margin, doc.y, 8, bold, 0.4, "SELLER")
+	doc.text(320, doc.y, 8, bold, 0.4, "CUSTOMER")
+	doc.y -= 14
+	seller, customer := partyLines(invoice.Seller), partyLines(invoice.Customer)
+	for i := 0; i < max(len(seller), len(customer)); i++ {
+		if i < len(seller) {
+			doc.text(margin, doc.y, 10, regular, 0, seller[i])
+		}
+		if i < len(customer) {
+			doc.text(320, doc.y, 10, regular, 0, customer[i])
+		}
+		doc.y -= 13
+	}
+	doc.y -= 8
+
+	if names := stores(invoice); names != "" {
+		doc.paragraph(10, "Sold by: "+names)
+	}
+	if invoice.CorrectsNumber != "" {
+		doc.paragraph(10, fmt.Sprintf("Corrects invoice %s. Reason: %s", invoice.CorrectsNumber, invoice.Reason))
+	}
+	doc.y -= 12
+
+	lineHeader(doc)
+	for _, line := range invoice.Lines {
+		height := 16.0
+		if line.Store != "" {
+			height = 26
+		}
+		if doc.breakPage(height) {
+			lineHeader(doc)
+		}
+		doc.text(margin, doc.y, 10, regular, 0, fit(describe(line), 10, quantityColumn-margin-30))
+		doc.textRight(quantityColumn, doc.y, 10, regular, strconv.Itoa(int(line.Quantity)))
+		doc.textRight(unitPriceColumn, doc.y, 10, regular, money(line.UnitPrice))
+		doc.textRight(discountColumn, doc.y, 10, regular, money(line.Discount))
+		doc.textRight(netColumn, doc.y, 10, regular, money(line.NetAmount))
+		doc.textRight(taxRateColumn, doc.y, 10, regular, rate(line.TaxRate))
+		doc.textRight(totalColumn, doc.y, 10, regular, money(line.Total))
+		if line.Store != "" {
+			doc.text(margin, doc.y-11, 8, regular, 0.4, fit(line.Store, 8, quantityColumn-margin-30))
+		}
+		doc.y -= height
+		doc.rule(margin, rightEdge, doc.y+11, 0.85)
+	}
+	doc.y -= 16
+
+	const labelColumn = 330.0
+	doc.breakPage(float64(len(invoice.Taxes))*14 + 80)
+	doc.text(labelColumn, doc.y, 9, bold, 0, "Tax rate")
+	doc.textRight(netColumn-60, doc.y, 9, bold, "Net")
+	doc.textRight(netColumn, doc.y, 9, bold, "Tax")
+	doc.textRight(totalColumn, doc.y, 9, bold, "Total")
+	doc.y -= 14
+	for _, tax := range invoice.Taxes {
+		doc.text(labelColumn, doc.y, 10, regular, 0, rate(tax.Rate))
+		doc.textRight(netColumn-60, doc.y, 10, regular, money(tax.NetAmount))
+		doc.textRight(netColumn, doc.y, 10, regular, money(tax.TaxAmount))
+		doc.textRight(totalColumn, doc.y, 10, regular, money(tax.Total))
+		doc.y -= 14
+	}
+	doc.rule(labelColumn, rightEdge, doc.y+10, 0.85)
+	doc.y -= 6
+	for _, total := range []struct {
+		label  string
+		amount float64
+	}{{"Net total", invoice.NetTotal}, {"Tax total", invoice.TaxTotal}} {
+		doc.text(labelColumn, doc.y, 10, regular, 0, total.label)
+		doc.textRight(totalColumn, doc.y, 10, regular, money(total.amount))
+		doc.y -= 14
+	}
+	doc.rule(labelColumn, rightEdge, doc.y+10, 0)
+	doc.y -= 4
+	doc.text(labelColumn, doc.y, 12, bold, 0, "Total")
+	doc.textRight(totalColumn, doc.y, 12, bold, money(invoice.GrandTotal)+" "+invoice.Currency)
+	doc.y -= 28
+	doc.text(margin, doc.y, 9, regular, 0.4, "Prices include tax.")
+
+	for i, page := range doc.pages {
+		footer := fmt.Sprintf("%s    Page %d of %d", invoice.Number, i+1, len(doc.pages))
+		writeText(page, rightEdge-textWidth(footer, 8), margin, 8, regular, 0.4, footer)
+	}
+	return doc.bytes(invoice), nil
+}
+
+func lineHeader(doc *pdfDocument) {
+	doc.text(margin, doc.y, 9, bold, 0, "Description")
+	doc.textRight(quantityColumn, doc.y, 9, bold, "Qty")
+	doc.textRight(unitPriceColumn, doc.y, 9, bold, "Unit price")
+	doc.textRight(discountColumn, doc.y, 9, bold, "Discount")
+	doc.textRight(netColumn, doc.y, 9, bold, "Net")
+	doc.textRight(taxRateColumn, doc.y, 9, bold, "Tax")
+	doc.textRight(totalColumn, doc.y, 9, bold, "Total")
+	doc.y -= 5
+	doc.rule(margin, rightEdge, doc.y, 0)
+	doc.y -= 14
+}
+
+// pdfDocument lays out text top to bottom over as many pages as it needs.
+// y is the baseline of the next line on the current page.
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func (doc *pdfDocument) newPage() {
+	doc.pages = append(doc.pages, &bytes.Buffer{})
+	doc.y = pageHeight - margin - 14
+}
+
+// breakPage starts a new page when less than height is left on this one
+// and reports whether it did.
+func (doc *pdfDocument) breakPage(height float64) bool {
+	if doc.y-height >= bottom {
+		return false
+	}
+	doc.newPage()
+	return true
+}
+
+func (doc *pdfDocument) page() *bytes.Buffer {
+	return doc.pages[len(doc.pages)-1]
+}
+
+// text writes s with its left edge at x; gray is 0 for black up to 1 for
+// white.
+func (doc *pdfDocument) text(x, y, size float64, font string, gray float64, s string) {
+	writeText(doc.page(), x, y, size, font, gray, s)
+}
+
+// textRight writes s in black with its right edge at x.
+func (doc *pdfDocument) textRight(x, y, size float64, font string, s string) {
+	writeText(doc.page(), x-textWidth(s, size), y, size, font, 0, s)
+}
+
+// paragraph writes s at the left margin, wrapped to the page width.
+func (doc *pdfDocument) paragraph(size float64, s string) {
+	for _, line := range wrap(s, size, rightEdge-margin) {
+		doc.breakPage(size + 4)
+		doc.text(margin, doc.y, size, regular, 0, line)
+		doc.y -= size + 4
+	}
+}
+
+func (doc *pdfDocument) rule(x1, x2, y, gray float64) {
+	fmt.Fprintf(doc.page(), "%s G 0.5 w %s %s m %s %s l S\n", num(gray), num(x1), num(y), num(x2), num(y))
+}
+
+func writeText(page *bytes.Buffer, x, y, size float64, font string, gray float64, s string) {
+	fmt.Fprintf(page, "BT /%s %s Tf %s g %s %s Td (%s) Tj ET\n", font, num(size), num(gray), num(x), num(y), escape(s))
+}
+
+// bytes assembles the pages into a PDF file: catalog, page tree, the two
+// fonts, a page and content stream per page, the document info and the
+// cross-reference table.
+func (doc *pdfDocument) bytes(invoice domain.Invoice) []byte {
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	const firstPage = 5
+	kids := make([]string, len(doc.pages))
+	for i := range doc.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(doc.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range doc.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			num(pageWidth), num(pageHeight), regular, bold, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes()))
+	}
+	object(fmt.Sprintf("<< /Title (%s) /Producer (product-app order service) /CreationDate (D:%s) >>",
+		escape(Title(invoice)+" "+invoice.Number), invoice.IssuedAt.UTC().Format("20060102150405Z")))
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, len(offsets), xref)
+	return out.Bytes()
+}
+
+// num formats a coordinate or size, to a hundredth of a point.
+func num(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// winAnsi maps the characters outside Latin-1 that WinAnsiEncoding has.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+	'Š': 0x8a, 'Œ': 0x8c, 'Ž': 0x8e, 'š': 0x9a, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// transliterations map Latin letters WinAnsiEncoding lacks to the nearest
+// ASCII letter, so names such as "Dekorasyon Sarayı" stay readable.
+var transliterations = map[rune]rune{
+	// Turkish
+	'ı': 'i', 'İ': 'I', 'ş': 's', 'Ş': 'S', 'ğ': 'g', 'Ğ': 'G',
+	// Central European
+	'ą': 'a', 'Ą': 'A', 'ć': 'c', 'Ć': 'C', 'č': 'c', 'Č': 'C', 'ď': 'd', 'Ď': 'D',
+	'ę': 'e', 'Ę': 'E', 'ě': 'e', 'Ě': 'E', 'ł': 'l', 'Ł': 'L', 'ń': 'n', 'Ń': 'N',
+	'ň': 'n', 'Ň': 'N', 'ő': 'o', 'Ő': 'O', 'ř': 'r', 'Ř': 'R', 'ś': 's', 'Ś': 'S',
+	'ť': 't', 'Ť': 'T', 'ů': 'u', 'Ů': 'U', 'ű': 'u', 'Ű': 'U', 'ź': 'z', 'Ź': 'Z',
+	'ż': 'z', 'Ż': 'Z',
+	// Romanian and Baltic
+	'ă': 'a', 'Ă': 'A', 'ș': 's', 'Ș': 'S', 'ț': 't', 'Ț': 'T', 'ā': 'a', 'Ā': 'A',
+	'ē': 'e', 'Ē': 'E', 'ī': 'i', 'Ī': 'I', 'ū': 'u', 'Ū': 'U', 'ģ': 'g', 'Ģ': 'G',
+	'ķ': 'k', 'Ķ': 'K', 'ļ': 'l', 'Ļ': 'L', 'ņ': 'n', 'Ņ': 'N', 'ė': 'e', 'Ė': 'E',
+	'į': 'i', 'Į': 'I', 'ų': 'u', 'Ų': 'U',
+}
+
+// escape encodes s as the body of a PDF literal string in WinAnsiEncoding.
+// Letters the encoding lacks are transliterated; other characters it lacks
+// become '?'.
+func escape(s string) string {
+	var out strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			out.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f:
+			out.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&out, "\\%03o", r)
+		default:
+			if b, ok := winAnsi[r]; ok {
+				fmt.Fprintf(&out, "\\%03o", b)
+			} else if ascii, ok := transliterations[r]; ok {
+				out.WriteRune(ascii)
+			} else {
+				out.WriteByte('?')
+			}
+		}
+	}
+	return out.String()
+}
+
+// helveticaWidths are the advance widths of Helvetica's printable ASCII
+// characters, from space to tilde, in thousandths of the font size.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth is the width of s set in Helvetica at size. Bold text runs a
+// little wider; it is only measured for short headings.
+func textWidth(s string, size float64) float64 {
+	width := 0
+	for _, r := range s {
+		if ascii, ok := transliterations[r]; ok {
+			r = ascii
+		}
+		if r >= 0x20 && r < 0x7f {
+			width += helveticaWidths[r-0x20]
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// fit shortens s with an ellipsis until it is at most width wide.
+func fit(s string, size, width float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// wrap breaks s into lines at most width wide, between words where it can.
+func wrap(s string, size, width float64) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && textWidth(candidate, size) > width {
+			lines = append(lines, fit(line, size, width))
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, fit(line, size, width))
+	}
+	return lines
+}
@@ -0,0 +1,69 @@
+// Package invoice renders issued invoices and credit notes as HTML and PDF
+// documents. Both are produced in-process with the standard library only.
+package invoice
+
+import (
+	"fmt"
+	"product-app/services/order/internal/domain"
+	"strings"
+)
+
+// Title is the document heading of invoice.
+func Title(invoice domain.Invoice) string {
+	if invoice.IsCreditNote() {
+		return "Credit note"
+	}
+	return "Invoice"
+}
+
+// Filename is the download name of invoice's document with extension ext.
+func Filename(invoice domain.Invoice, ext string) string {
+	return invoice.Number + "." + ext
+}
+
+func money(amount float64) string {
+	if amount == 0 {
+		amount = 0 // avoid printing -0.00
+	}
+	return fmt.Sprintf("%.2f", amount)
+}
+
+func rate(rate float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", rate), "0"), ".") + "%"
+}
+
+func describe(line domain.InvoiceLine) string {
+	if line.Variant == "" {
+		return line.Description
+	}
+	return line.Description + " (" + line.Variant + ")"
+}
+
+func stores(invoice domain.Invoice) string {
+	names := []string{}
+	for _, store := range invoice.Stores() {
+		if store.Slug != "" {
+			names = append(names, store.Name+" ("+store.Slug+")")
+		} else {
+			names = append(names, store.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// partyLines are the lines of a seller or customer address block.
+func partyLines(party domain.InvoiceParty) []string {
+	lines := []string{party.Name}
+	for _, line := range strings.Split(party.Address, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if party.TaxId != "" {
+		lines = append(lines, "Tax ID: "+party.TaxId)
+	}
+	if party.Email != "" {
+		lines = append(lines, party.Email)
+	}
+	return lines
+}
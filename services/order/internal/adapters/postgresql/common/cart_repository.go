@@ -0,0 +1,291 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const cartColumns = `id, COALESCE(user_id, 0), COALESCE(token, ''), updated_at, expires_at`
+
+type CartRepository struct {
+	dbPool *pgxpool.Pool
+	outbox eventOutbox
+}
+
+// NewCartRepository stores carts and writes the events of checked-out
+// orders to the outbox for eventsTopic.
+func NewCartRepository(dbPool *pgxpool.Pool, eventsTopic string) ports.CartRepository {
+	return &CartRepository{dbPool: dbPool, outbox: eventOutbox{topic: eventsTopic}}
+}
+
+// GetOrCreateForUser implements ports.CartRepository.
+func (r *CartRepository) GetOrCreateForUser(userId int64) (domain.Cart, error) {
+	ctx := context.Background()
+
+	cart, err := scanCart(r.dbPool.QueryRow(ctx, `
+		INSERT INTO carts (user_id) VALUES ($1)
+		ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING `+cartColumns, userId))
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("error while getting cart of user %d: %w", userId, err)
+	}
+	return r.withItems(ctx, r.dbPool, cart)
+}
+
+// GetByToken implements ports.CartRepository.
+func (r *CartRepository) GetByToken(token string) (domain.Cart, error) {
+	ctx := context.Background()
+
+	cart, err := scanCart(r.dbPool.QueryRow(ctx, `SELECT `+cartColumns+` FROM carts WHERE token = $1`, token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Cart{}, domain.ErrCartNotFound
+	}
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("error while getting anonymous cart: %w", err)
+	}
+	return r.withItems(ctx, r.dbPool, cart)
+}
+
+// CreateAnonymous implements ports.CartRepository.
+func (r *CartRepository) CreateAnonymous(token string, expiresAt time.Time) (domain.Cart, error) {
+	ctx := context.Background()
+
+	cart, err := scanCart(r.dbPool.QueryRow(ctx, `
+		INSERT INTO carts (token, expires_at) VALUES ($1, $2)
+		RETURNING `+cartColumns, token, expiresAt))
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("error while creating anonymous cart: %w", err)
+	}
+	cart.Items = []domain.CartItem{}
+	return cart, nil
+}
+
+// AddItem implements ports.CartRepository.
+func (r *CartRepository) AddItem(cart domain.Cart, item domain.CartItem) error {
+	ctx := context.Background()
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO cart_items (cart_id, product_id, variant, quantity)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cart_id, product_id, variant) DO UPDATE
+		SET quantity = cart_items.quantity + EXCLUDED.quantity
+	`, cart.Id, item.ProductID, item.Variant, item.Quantity)
+	if err != nil {
+		return fmt.Errorf("error while adding product %d to cart %d: %w", item.ProductID, cart.Id, err)
+	}
+	if err := touchCart(ctx, tx, cart); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetItemQuantity implements ports.CartRepository.
+func (r *CartRepository) SetItemQuantity(cart domain.Cart, productId int64, variant string, quantity int32) error {
+	ctx := context.Background()
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var commandSQL string
+	args := []interface{}{cart.Id, productId, variant}
+	if quantity == 0 {
+		commandSQL = `DELETE FROM cart_items WHERE cart_id = $1 AND product_id = $2 AND variant = $3`
+	} else {
+		commandSQL = `UPDATE cart_items SET quantity = $4 WHERE cart_id = $1 AND product_id = $2 AND variant = $3`
+		args = append(args, quantity)
+	}
+	commandTag, err := tx.Exec(ctx, commandSQL, args...)
+	if err != nil {
+		return fmt.Errorf("error while updating product %d in cart %d: %w", productId, cart.Id, err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: product %d %q", domain.ErrCartItemNotFound, productId, variant)
+	}
+	if err := touchCart(ctx, tx, cart); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Merge implements ports.CartRepository.
+func (r *CartRepository) Merge(fromCartId, intoCartId int64) error {
+	ctx := context.Background()
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var lockedId int64
+	err = tx.QueryRow(ctx, `SELECT id FROM carts WHERE id = $1 AND id <> $2 FOR UPDATE`, fromCartId, intoCartId).Scan(&lockedId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w with id %d", domain.ErrCartNotFound, fromCartId)
+	}
+	if err != nil {
+		return fmt.Errorf("error while merging cart %d: %w", fromCartId, err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO cart_items (cart_id, product_id, variant, quantity, added_at)
+		SELECT $2, product_id, variant, quantity, added_at FROM cart_items WHERE cart_id = $1
+		ON CONFLICT (cart_id, product_id, variant) DO UPDATE
+		SET quantity = cart_items.quantity + EXCLUDED.quantity
+	`, fromCartId, intoCartId)
+	if err != nil {
+		return fmt.Errorf("error while merging cart %d into %d: %w", fromCartId, intoCartId, err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM carts WHERE id = $1`, fromCartId); err != nil {
+		return fmt.Errorf("error while merging cart %d: %w", fromCartId, err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE carts SET updated_at = NOW() WHERE id = $1`, intoCartId); err != nil {
+		return fmt.Errorf("error while merging cart %d into %d: %w", fromCartId, intoCartId, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Printf("INFO: cart %d merged into cart %d", fromCartId, intoCartId)
+	return nil
+}
+
+// Checkout implements ports.CartRepository.
+func (r *CartRepository) Checkout(cart domain.Cart, order domain.Order, events ports.OrderEvents) (domain.Order, error) {
+	ctx := context.Background()
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	locked, err := scanCart(tx.QueryRow(ctx, `SELECT `+cartColumns+` FROM carts WHERE id = $1 FOR UPDATE`, cart.Id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Order{}, fmt.Errorf("%w with id %d", domain.ErrCartNotFound, cart.Id)
+	}
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("error while locking cart %d: %w", cart.Id, err)
+	}
+	locked, err = r.withItems(ctx, tx, locked)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if !sameLines(locked.Items, cart.Items) {
+		return domain.Order{}, fmt.Errorf("%w: cart %d", domain.ErrCartChanged, cart.Id)
+	}
+
+	order, err = insertOrder(ctx, tx, order)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM cart_items WHERE cart_id = $1`, cart.Id); err != nil {
+		return domain.Order{}, fmt.Errorf("error while clearing cart %d: %w", cart.Id, err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE carts SET updated_at = NOW() WHERE id = $1`, cart.Id); err != nil {
+		return domain.Order{}, fmt.Errorf("error while clearing cart %d: %w", cart.Id, err)
+	}
+	if err := r.outbox.enqueueOrder(ctx, tx, events, order); err != nil {
+		return domain.Order{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Order{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Printf("✅ Cart %d checked out as order %d", cart.Id, order.Id)
+	return order, nil
+}
+
+// DeleteExpired implements ports.CartRepository.
+func (r *CartRepository) DeleteExpired(before time.Time) (int64, error) {
+	ctx := context.Background()
+
+	commandTag, err := r.dbPool.Exec(ctx, `DELETE FROM carts WHERE token IS NOT NULL AND expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("error while deleting expired carts: %w", err)
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+// withItems loads the cart's lines, oldest first.
+func (r *CartRepository) withItems(ctx context.Context, querier pgxQuerier, cart domain.Cart) (domain.Cart, error) {
+	rows, err := querier.Query(ctx, `
+		SELECT product_id, variant, quantity FROM cart_items
+		WHERE cart_id = $1
+		ORDER BY added_at, product_id, variant
+	`, cart.Id)
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("error while getting items of cart %d: %w", cart.Id, err)
+	}
+	defer rows.Close()
+
+	cart.Items = []domain.CartItem{}
+	for rows.Next() {
+		var item domain.CartItem
+		if err := rows.Scan(&item.ProductID, &item.Variant, &item.Quantity); err != nil {
+			return domain.Cart{}, fmt.Errorf("error while scanning item of cart %d: %w", cart.Id, err)
+		}
+		cart.Items = append(cart.Items, item)
+	}
+	return cart, rows.Err()
+}
+
+// touchCart records a change of the cart and moves the expiry of an
+// anonymous cart to cart.ExpiresAt.
+func touchCart(ctx context.Context, tx pgx.Tx, cart domain.Cart) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE carts SET updated_at = NOW(), expires_at = CASE WHEN token IS NULL THEN NULL ELSE $2 END
+		WHERE id = $1
+	`, cart.Id, cart.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("error while updating cart %d: %w", cart.Id, err)
+	}
+	return nil
+}
+
+func scanCart(row pgx.Row) (domain.Cart, error) {
+	var cart domain.Cart
+	err := row.Scan(&cart.Id, &cart.UserId, &cart.Token, &cart.UpdatedAt, &cart.ExpiresAt)
+	return cart, err
+}
+
+// sameLines reports whether two carts hold the same quantities of the same
+// product variants.
+func sameLines(a, b []domain.CartItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	type line struct {
+		productId int64
+		variant   string
+	}
+	quantities := make(map[line]int32, len(a))
+	for _, item := range a {
+		quantities[line{item.ProductID, item.Variant}] = item.Quantity
+	}
+	for _, item := range b {
+		if quantities[line{item.ProductID, item.Variant}] != item.Quantity {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const selectInvoiceColumns = `SELECT id, kind, number, year, sequence, order_id, corrects_number, reason, currency,
+	seller, customer, lines, taxes, net_total, tax_total, grand_total, issued_at FROM invoices`
+
+type InvoiceRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewInvoiceRepository(dbPool *pgxpool.Pool) ports.InvoiceRepository {
+	return &InvoiceRepository{dbPool: dbPool}
+}
+
+// Issue implements ports.InvoiceRepository.
+func (r *InvoiceRepository) Issue(invoice domain.Invoice) (domain.Invoice, error) {
+	ctx := context.Background()
+	seller, customer, lines, taxes, err := encodeInvoiceParts(invoice)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if invoice.Kind == domain.InvoiceKindCreditNote {
+		if err := checkCreditable(ctx, tx, invoice); err != nil {
+			return domain.Invoice{}, err
+		}
+	}
+
+	invoice.Year = invoice.IssuedAt.Year()
+	err = tx.QueryRow(ctx, `
+		INSERT INTO invoice_sequences (kind, year, last_number)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (kind, year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number
+	`, invoice.Kind, invoice.Year).Scan(&invoice.Sequence)
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("error while numbering %s of order %d: %w", invoice.Kind, invoice.OrderId, err)
+	}
+	invoice.Number = domain.InvoiceNumber(invoice.Kind, invoice.Year, invoice.Sequence)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO invoices (kind, number, year, sequence, order_id, corrects_number, reason, currency,
+			seller, customer, lines, taxes, net_total, tax_total, grand_total, issued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id
+	`, invoice.Kind, invoice.Number, invoice.Year, invoice.Sequence, invoice.OrderId, invoice.CorrectsNumber,
+		invoice.Reason, invoice.Currency, seller, customer, lines, taxes, invoice.NetTotal, invoice.TaxTotal,
+		invoice.GrandTotal, invoice.IssuedAt).Scan(&invoice.Id)
+	if isPgError(err, uniqueViolationCode) {
+		return domain.Invoice{}, fmt.Errorf("%w: order %d", domain.ErrInvoiceAlreadyIssued, invoice.OrderId)
+	}
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to insert %s of order %d: %w", invoice.Kind, invoice.OrderId, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Printf("INFO: %s %s issued for order %d", invoice.Kind, invoice.Number, invoice.OrderId)
+	return invoice, nil
+}
+
+// GetInvoice implements ports.InvoiceRepository.
+func (r *InvoiceRepository) GetInvoice(orderId int64) (domain.Invoice, error) {
+	ctx := context.Background()
+	invoice, err := scanInvoice(r.dbPool.QueryRow(ctx,
+		selectInvoiceColumns+` WHERE order_id = $1 AND kind = $2`, orderId, domain.InvoiceKindInvoice))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Invoice{}, fmt.Errorf("%w for order %d", domain.ErrInvoiceNotFound, orderId)
+	}
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("error while getting invoice of order %d: %w", orderId, err)
+	}
+	return invoice, nil
+}
+
+// GetCreditNotes implements ports.InvoiceRepository.
+func (r *InvoiceRepository) GetCreditNotes(orderId int64) ([]domain.Invoice, error) {
+	ctx := context.Background()
+	rows, err := r.dbPool.Query(ctx,
+		selectInvoiceColumns+` WHERE order_id = $1 AND kind = $2 ORDER BY id`, orderId, domain.InvoiceKindCreditNote)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting credit notes of order %d: %w", orderId, err)
+	}
+	defer rows.Close()
+
+	creditNotes := []domain.Invoice{}
+	for rows.Next() {
+		creditNote, err := scanInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error while scanning credit notes of order %d: %w", orderId, err)
+		}
+		creditNotes = append(creditNotes, creditNote)
+	}
+	return creditNotes, rows.Err()
+}
+
+// invoiceLineKey identifies a line of an invoice by its product variant.
+type invoiceLineKey struct {
+	productId int64
+	variant   string
+}
+
+// checkCreditable locks the invoice that creditNote corrects and fails with
+// domain.ErrInvalidCreditNote if creditNote credits more units of a line
+// than the invoice's earlier credit notes left. A concurrent credit note of
+// the same order waits for the lock, so both cannot credit the same units.
+func checkCreditable(ctx context.Context, tx pgx.Tx, creditNote domain.Invoice) error {
+	var invoiced []byte
+	err := tx.QueryRow(ctx, `SELECT lines FROM invoices WHERE order_id = $1 AND kind = $2 FOR UPDATE`,
+		creditNote.OrderId, domain.InvoiceKindInvoice).Scan(&invoiced)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w for order %d", domain.ErrInvoiceNotFound, creditNote.OrderId)
+	}
+	if err != nil {
+		return fmt.Errorf("error while locking invoice of order %d: %w", creditNote.OrderId, err)
+	}
+	documents := [][]byte{invoiced}
+
+	rows, err := tx.Query(ctx, `SELECT lines FROM invoices WHERE order_id = $1 AND kind = $2`,
+		creditNote.OrderId, domain.InvoiceKindCreditNote)
+	if err != nil {
+		return fmt.Errorf("error while getting credit notes of order %d: %w", creditNote.OrderId, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var credited []byte
+		if err := rows.Scan(&credited); err != nil {
+			return fmt.Errorf("error while scanning credit notes of order %d: %w", creditNote.OrderId, err)
+		}
+		documents = append(documents, credited)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error while getting credit notes of order %d: %w", creditNote.OrderId, err)
+	}
+
+	uncredited := map[invoiceLineKey]int32{}
+	for _, document := range documents {
+		var lines []domain.InvoiceLine
+		if err := json.Unmarshal(document, &lines); err != nil {
+			return fmt.Errorf("failed to decode invoice lines of order %d: %w", creditNote.OrderId, err)
+		}
+		for _, line := range lines {
+			uncredited[invoiceLineKey{line.ProductID, line.Variant}] += line.Quantity
+		}
+	}
+	for _, line := range creditNote.Lines {
+		key := invoiceLineKey{line.ProductID, line.Variant}
+		if uncredited[key]+line.Quantity < 0 {
+			return fmt.Errorf("%w: only %d of product %d %q are left to credit",
+				domain.ErrInvalidCreditNote, uncredited[key], line.ProductID, line.Variant)
+		}
+		uncredited[key] += line.Quantity
+	}
+	return nil
+}
+
+func encodeInvoiceParts(invoice domain.Invoice) (seller, customer, lines, taxes []byte, err error) {
+	if seller, err = json.Marshal(invoice.Seller); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to encode invoice seller: %w", err)
+	}
+	if customer, err = json.Marshal(invoice.Customer); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to encode invoice customer: %w", err)
+	}
+	if lines, err = json.Marshal(invoice.Lines); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to encode invoice lines: %w", err)
+	}
+	if taxes, err = json.Marshal(invoice.Taxes); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to encode invoice taxes: %w", err)
+	}
+	return seller, customer, lines, taxes, nil
+}
+
+func scanInvoice(row pgx.Row) (domain.Invoice, error) {
+	var invoice domain.Invoice
+	var seller, customer, lines, taxes []byte
+	err := row.Scan(&invoice.Id, &invoice.Kind, &invoice.Number, &invoice.Year, &invoice.Sequence, &invoice.OrderId,
+		&invoice.CorrectsNumber, &invoice.Reason, &invoice.Currency, &seller, &customer, &lines, &taxes,
+		&invoice.NetTotal, &invoice.TaxTotal, &invoice.GrandTotal, &invoice.IssuedAt)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	for _, part := range []struct {
+		data   []byte
+		target any
+	}{
+		{seller, &invoice.Seller},
+		{customer, &invoice.Customer},
+		{lines, &invoice.Lines},
+		{taxes, &invoice.Taxes},
+	} {
+		if err := json.Unmarshal(part.data, part.target); err != nil {
+			return domain.Invoice{}, fmt.Errorf("failed to decode invoice %s: %w", invoice.Number, err)
+		}
+	}
+	return invoice, nil
+}
@@ -2,115 +2,539 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"product-app/services/order/internal/domain"
 	"product-app/services/order/internal/ports"
+	"strings"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+const orderColumns = `id, COALESCE(user_id, 0), customer_number, status, subtotal, discount_total, grand_total, refunded_total, order_time`
+
+const selectOrderColumns = `SELECT ` + orderColumns + ` FROM orders`
+
+// pgxQuerier is satisfied by both the pool and a transaction, so items can
+// be loaded either way.
+type pgxQuerier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
 type OrderRepository struct {
 	dbPool *pgxpool.Pool
+	outbox eventOutbox
 }
 
-func NewOrderRepository(dbPool *pgxpool.Pool) ports.OrderRepository {
-	return &OrderRepository{dbPool: dbPool}
+// NewOrderRepository stores orders and writes their events to the outbox
+// for eventsTopic.
+func NewOrderRepository(dbPool *pgxpool.Pool, eventsTopic string) ports.OrderRepository {
+	return &OrderRepository{dbPool: dbPool, outbox: eventOutbox{topic: eventsTopic}}
 }
 
-// Create implements ports.OrderRepository
-func (o *OrderRepository) Create(order domain.Order) (domain.Order, error) {
+// Create implements ports.OrderRepository. The order and all of its items
+// are inserted, their stock reserved and its events enqueued in one
+// transaction.
+func (o *OrderRepository) Create(order domain.Order, events ports.OrderEvents) (domain.Order, error) {
 	ctx := context.Background()
+
+	tx, err := o.dbPool.Begin(ctx)
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	order, err = insertOrder(ctx, tx, order)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if err := o.outbox.enqueueOrder(ctx, tx, events, order); err != nil {
+		return domain.Order{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Order{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Printf("✅ Order inserted with ID: %d (%d items)", order.Id, len(order.Items))
+	return order, nil
+}
+
+// insertOrder inserts order with its items and first status change and
+// reserves its stock within tx.
+func insertOrder(ctx context.Context, tx pgx.Tx, order domain.Order) (domain.Order, error) {
 	insertOrderSQL := `
-		INSERT INTO orders (customer_number, product_id, quantity, order_time)
-		VALUES ($1, $2, $3, NOW())
+		INSERT INTO orders (user_id, customer_number, status, subtotal, discount_total, grand_total, order_time)
+		VALUES (NULLIF($1, 0), $2, $3, $4, $5, $6, NOW())
 		RETURNING id, order_time
 	`
-	err := o.dbPool.QueryRow(ctx, insertOrderSQL,
+	err := tx.QueryRow(ctx, insertOrderSQL,
+		order.UserId,
 		order.CustomerNumber,
-		order.ProductID,
-		order.Quantity,
+		order.Status,
+		order.Subtotal,
+		order.DiscountTotal,
+		order.GrandTotal,
 	).Scan(&order.Id, &order.OrderTime)
 	if err != nil {
 		log.Printf("❌ Error inserting order: %v", err)
 		return domain.Order{}, fmt.Errorf("failed to insert order: %w", err)
 	}
-	log.Printf("✅ Order inserted with ID: %d", order.Id)
+
+	insertItemSQL := `
+		INSERT INTO order_items (order_id, product_id, variant, quantity, unit_price, discount, line_total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	for i := range order.Items {
+		item := &order.Items[i]
+		err := tx.QueryRow(ctx, insertItemSQL,
+			order.Id,
+			item.ProductID,
+			item.Variant,
+			item.Quantity,
+			item.UnitPrice,
+			item.Discount,
+			item.LineTotal,
+		).Scan(&item.Id)
+		if err != nil {
+			log.Printf("❌ Error inserting item of order %d: %v", order.Id, err)
+			return domain.Order{}, fmt.Errorf("failed to insert order item: %w", err)
+		}
+	}
+
+	err = insertStatusChange(ctx, tx, domain.OrderStatusChange{
+		OrderId:   order.Id,
+		ToStatus:  order.Status,
+		ChangedAt: order.OrderTime,
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if err := reserveStock(ctx, tx, order); err != nil {
+		return domain.Order{}, err
+	}
 	return order, nil
 }
 
-// Delete implements ports.OrderRepository
-func (o *OrderRepository) Delete(id int64) error {
+// Delete implements ports.OrderRepository and returns the deleted order
+// with its items. Only an order that could still be cancelled gives its
+// reserved stock back; a shipped order's stock has left the warehouse.
+func (o *OrderRepository) Delete(id int64, events ports.OrderEvents) (domain.Order, error) {
 	ctx := context.Background()
-	deleteSql := `DELETE FROM orders WHERE id = $1`
-	commandTag, err := o.dbPool.Exec(ctx, deleteSql, id)
+
+	tx, err := o.dbPool.Begin(ctx)
 	if err != nil {
-		log.Printf("ERROR: Error while deleting order with id %d: %v", id, err)
-		return fmt.Errorf("error while deleting order with id %d: %w", id, err)
+		return domain.Order{}, fmt.Errorf("failed to start transaction: %w", err)
 	}
-	if commandTag.RowsAffected() == 0 {
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	order, err := scanOrder(tx.QueryRow(ctx, selectOrderColumns+` WHERE id = $1 FOR UPDATE`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
 		log.Printf("WARNING: order with id %d not found for deletion", id)
-		return fmt.Errorf("order with id %d not found", id)
+		return domain.Order{}, fmt.Errorf("%w with id %d", domain.ErrOrderNotFound, id)
+	}
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("error while deleting order with id %d: %w", id, err)
+	}
+	orders := []domain.Order{order}
+	if err := o.loadItems(ctx, tx, orders); err != nil {
+		return domain.Order{}, err
+	}
+	if domain.CanTransition(order.Status, domain.OrderStatusCancelled) {
+		if err := releaseStock(ctx, tx, id); err != nil {
+			return domain.Order{}, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM orders WHERE id = $1`, id); err != nil {
+		log.Printf("ERROR: Error while deleting order with id %d: %v", id, err)
+		return domain.Order{}, fmt.Errorf("error while deleting order with id %d: %w", id, err)
+	}
+	if err := o.outbox.enqueueOrder(ctx, tx, events, orders[0]); err != nil {
+		return domain.Order{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Order{}, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	log.Printf("INFO: order deleted with id %d", id)
-	return nil
+	return orders[0], nil
+}
+
+// Find implements ports.OrderRepository. Pages are read by keyset: the
+// cursor is compared with the (sort key, id) pair, which the order indexes
+// cover.
+func (o *OrderRepository) Find(query domain.OrderQuery) ([]domain.Order, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !query.From.IsZero() {
+		conditions = append(conditions, "order_time >= "+arg(query.From))
+	}
+	if !query.To.IsZero() {
+		conditions = append(conditions, "order_time < "+arg(query.To))
+	}
+	if query.Status != "" {
+		conditions = append(conditions, "status = "+arg(query.Status))
+	}
+	if query.CustomerNumber != "" {
+		conditions = append(conditions, "customer_number = "+arg(query.CustomerNumber))
+	}
+	if query.UserId != 0 {
+		conditions = append(conditions, "user_id = "+arg(query.UserId))
+	}
+	if query.ProductId != 0 {
+		conditions = append(conditions,
+			"EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = orders.id AND i.product_id = "+arg(query.ProductId)+")")
+	}
+
+	sortColumn, direction, comparison := "order_time", "ASC", ">"
+	if query.SortBy == domain.OrderSortTotal {
+		sortColumn = "grand_total"
+	}
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	if query.After != nil {
+		var position interface{} = query.After.OrderTime
+		if sortColumn == "grand_total" {
+			position = query.After.GrandTotal
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s, %s)",
+			sortColumn, comparison, arg(position), arg(query.After.Id)))
+	}
+
+	sql := selectOrderColumns
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sql += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT %s", sortColumn, direction, direction, arg(query.Limit))
+
+	orders, err := o.queryOrders(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding orders: %w", err)
+	}
+	return orders, nil
+}
+
+// GetById implements ports.OrderRepository
+func (o *OrderRepository) GetById(id int64) (domain.Order, error) {
+	ctx := context.Background()
+	order, err := scanOrder(o.dbPool.QueryRow(ctx, selectOrderColumns+` WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Order{}, fmt.Errorf("%w with id %d", domain.ErrOrderNotFound, id)
+	}
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("error while getting order with id %d: %w", id, err)
+	}
+	orders := []domain.Order{order}
+	if err := o.loadItems(ctx, o.dbPool, orders); err != nil {
+		return domain.Order{}, err
+	}
+	return orders[0], nil
+}
+
+// UpdateStatus implements ports.OrderRepository. The status only changes
+// when it still equals change.FromStatus, so two concurrent transitions
+// cannot both succeed. Cancelling gives the order's reserved stock back and
+// shipping consumes it.
+func (o *OrderRepository) UpdateStatus(id int64, change domain.OrderStatusChange, events ports.OrderEvents) (domain.Order, error) {
+	ctx := context.Background()
+
+	tx, err := o.dbPool.Begin(ctx)
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	order, err := scanOrder(tx.QueryRow(ctx, `
+		UPDATE orders SET status = $1
+		WHERE id = $2 AND status = $3
+		RETURNING `+orderColumns, change.ToStatus, id, change.FromStatus))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var current string
+		if err := tx.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1`, id).Scan(&current); err != nil {
+			return domain.Order{}, fmt.Errorf("%w with id %d", domain.ErrOrderNotFound, id)
+		}
+		return domain.Order{}, fmt.Errorf("%w: order %d is %s, not %s", domain.ErrIllegalTransition, id, current, change.FromStatus)
+	}
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("error while updating status of order %d: %w", id, err)
+	}
+
+	change.OrderId = id
+	if err := insertStatusChange(ctx, tx, change); err != nil {
+		return domain.Order{}, err
+	}
+	switch change.ToStatus {
+	case domain.OrderStatusCancelled:
+		if err := releaseStock(ctx, tx, id); err != nil {
+			return domain.Order{}, err
+		}
+	case domain.OrderStatusShipped:
+		if err := consumeStock(ctx, tx, id); err != nil {
+			return domain.Order{}, err
+		}
+	}
+	orders := []domain.Order{order}
+	if err := o.loadItems(ctx, tx, orders); err != nil {
+		return domain.Order{}, err
+	}
+	if err := o.outbox.enqueueOrder(ctx, tx, events, orders[0]); err != nil {
+		return domain.Order{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Order{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Printf("INFO: order %d moved from %s to %s", id, change.FromStatus, change.ToStatus)
+	return orders[0], nil
+}
+
+// GetStatusHistory implements ports.OrderRepository, oldest change first.
+func (o *OrderRepository) GetStatusHistory(id int64) ([]domain.OrderStatusChange, error) {
+	ctx := context.Background()
+	rows, err := o.dbPool.Query(ctx, `
+		SELECT id, order_id, COALESCE(from_status, ''), to_status, COALESCE(actor_user_id, 0), COALESCE(reason_code, ''), reason, changed_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting status history of order %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	history := []domain.OrderStatusChange{}
+	for rows.Next() {
+		var change domain.OrderStatusChange
+		if err := rows.Scan(&change.Id, &change.OrderId, &change.FromStatus, &change.ToStatus,
+			&change.ActorUserId, &change.ReasonCode, &change.Reason, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("error while scanning status history of order %d: %w", id, err)
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
 }
 
-// GetAll implements ports.OrderRepository
-func (o *OrderRepository) GetAll() ([]domain.Order, error) {
+// ReserveRefund implements ports.OrderRepository. The refunded total is
+// only raised while it stays within the grand total, so concurrent refunds
+// cannot together exceed what was paid.
+func (o *OrderRepository) ReserveRefund(refund domain.Refund) (domain.Refund, error) {
 	ctx := context.Background()
-	orderRows, err := o.dbPool.Query(ctx,
-		`SELECT id, customer_number, product_id, quantity, order_time FROM orders`)
+
+	tx, err := o.dbPool.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error while getting all orders: %w", err)
+		return domain.Refund{}, fmt.Errorf("failed to start transaction: %w", err)
 	}
-	defer orderRows.Close()
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	var orders []domain.Order
-	for orderRows.Next() {
-		var order domain.Order
-		err := orderRows.Scan(&order.Id, &order.CustomerNumber, &order.ProductID, &order.Quantity, &order.OrderTime)
+	commandTag, err := tx.Exec(ctx, `
+		UPDATE orders SET refunded_total = refunded_total + $1
+		WHERE id = $2 AND refunded_total + $1 <= grand_total
+	`, refund.Amount, refund.OrderId)
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("error while refunding order %d: %w", refund.OrderId, err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		var remaining float64
+		err := tx.QueryRow(ctx, `SELECT grand_total - refunded_total FROM orders WHERE id = $1`, refund.OrderId).Scan(&remaining)
 		if err != nil {
-			log.Printf("ERROR: Error while scanning order: %v", err)
-			continue
+			return domain.Refund{}, fmt.Errorf("%w with id %d", domain.ErrOrderNotFound, refund.OrderId)
 		}
-		orders = append(orders, order)
+		return domain.Refund{}, fmt.Errorf("%w: only %.2f of order %d is left to refund", domain.ErrInvalidRefund, remaining, refund.OrderId)
 	}
-	return orders, nil
+
+	refund.Status = domain.RefundStatusPending
+	err = tx.QueryRow(ctx, `
+		INSERT INTO order_refunds (order_id, amount, status, reason, actor_user_id, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6)
+		RETURNING id
+	`, refund.OrderId, refund.Amount, refund.Status, refund.Reason, refund.ActorUserId, refund.CreatedAt).Scan(&refund.Id)
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("failed to record refund of order %d: %w", refund.OrderId, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Refund{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return refund, nil
+}
+
+// CompleteRefund implements ports.OrderRepository.
+func (o *OrderRepository) CompleteRefund(refund domain.Refund, events ports.RefundEvents) (domain.Refund, error) {
+	ctx := context.Background()
+
+	tx, err := o.dbPool.Begin(ctx)
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	commandTag, err := tx.Exec(ctx, `
+		UPDATE order_refunds SET status = $1, provider_reference = NULLIF($2, '')
+		WHERE id = $3 AND status = $4
+	`, domain.RefundStatusCompleted, refund.ProviderReference, refund.Id, domain.RefundStatusPending)
+	if err != nil {
+		return domain.Refund{}, fmt.Errorf("failed to complete refund %d of order %d: %w", refund.Id, refund.OrderId, err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return domain.Refund{}, fmt.Errorf("%w: refund %d of order %d is not pending", domain.ErrInvalidRefund, refund.Id, refund.OrderId)
+	}
+	refund.Status = domain.RefundStatusCompleted
+	if events != nil {
+		if err := o.outbox.enqueue(ctx, tx, events(refund)); err != nil {
+			return domain.Refund{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Refund{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Printf("INFO: refunded %.2f of order %d", refund.Amount, refund.OrderId)
+	return refund, nil
+}
+
+// ReleaseRefund implements ports.OrderRepository. Only pending refunds are
+// released, so a completed refund is never taken off the refunded total.
+func (o *OrderRepository) ReleaseRefund(refund domain.Refund) error {
+	ctx := context.Background()
+
+	tx, err := o.dbPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var amount float64
+	err = tx.QueryRow(ctx, `
+		DELETE FROM order_refunds WHERE id = $1 AND status = $2
+		RETURNING amount
+	`, refund.Id, domain.RefundStatusPending).Scan(&amount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: refund %d of order %d is not pending", domain.ErrInvalidRefund, refund.Id, refund.OrderId)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release refund %d of order %d: %w", refund.Id, refund.OrderId, err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE orders SET refunded_total = refunded_total - $1 WHERE id = $2`, amount, refund.OrderId); err != nil {
+		return fmt.Errorf("failed to release refund %d of order %d: %w", refund.Id, refund.OrderId, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
-// GetByCustomerNumber implements ports.OrderRepository
-func (o *OrderRepository) GetByCustomerNumber(customerNumber string) ([]domain.Order, error) {
+// GetRefunds implements ports.OrderRepository, oldest refund first.
+func (o *OrderRepository) GetRefunds(orderId int64) ([]domain.Refund, error) {
 	ctx := context.Background()
-	sql := `SELECT id, customer_number, product_id, quantity, order_time FROM orders WHERE customer_number = $1`
-	rows, err := o.dbPool.Query(ctx, sql, customerNumber)
+	rows, err := o.dbPool.Query(ctx, `
+		SELECT id, order_id, amount, status, reason, COALESCE(provider_reference, ''), COALESCE(actor_user_id, 0), created_at
+		FROM order_refunds
+		WHERE order_id = $1
+		ORDER BY id
+	`, orderId)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting refunds of order %d: %w", orderId, err)
+	}
+	defer rows.Close()
+
+	refunds := []domain.Refund{}
+	for rows.Next() {
+		var refund domain.Refund
+		if err := rows.Scan(&refund.Id, &refund.OrderId, &refund.Amount, &refund.Status, &refund.Reason,
+			&refund.ProviderReference, &refund.ActorUserId, &refund.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error while scanning refunds of order %d: %w", orderId, err)
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, rows.Err()
+}
+
+func insertStatusChange(ctx context.Context, tx pgx.Tx, change domain.OrderStatusChange) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO order_status_history (order_id, from_status, to_status, actor_user_id, reason_code, reason, changed_at)
+		VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, 0), NULLIF($5, ''), $6, $7)
+	`, change.OrderId, change.FromStatus, change.ToStatus, change.ActorUserId, change.ReasonCode, change.Reason, change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record status change of order %d: %w", change.OrderId, err)
+	}
+	return nil
+}
+
+func (o *OrderRepository) queryOrders(ctx context.Context, sql string, args ...interface{}) ([]domain.Order, error) {
+	rows, err := o.dbPool.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error while getting orders for customer %s: %w", customerNumber, err)
+		return nil, err
 	}
 	defer rows.Close()
 
-	var orders []domain.Order
+	orders := []domain.Order{}
 	for rows.Next() {
-		var order domain.Order
-		err := rows.Scan(&order.Id, &order.CustomerNumber, &order.ProductID, &order.Quantity, &order.OrderTime)
+		order, err := scanOrder(rows)
 		if err != nil {
-			log.Printf("ERROR: Error while scanning order: %v", err)
-			continue
+			return nil, fmt.Errorf("error while scanning order: %w", err)
 		}
 		orders = append(orders, order)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := o.loadItems(ctx, o.dbPool, orders); err != nil {
+		return nil, err
+	}
 	return orders, nil
 }
 
-// GetById implements ports.OrderRepository
-func (o *OrderRepository) GetById(id int64) (domain.Order, error) {
-	ctx := context.Background()
-	sql := `SELECT id, customer_number, product_id, quantity, order_time FROM orders WHERE id = $1`
-	var order domain.Order
-	err := o.dbPool.QueryRow(ctx, sql, id).
-		Scan(&order.Id, &order.CustomerNumber, &order.ProductID, &order.Quantity, &order.OrderTime)
+// loadItems fills the items of orders with a single query.
+func (o *OrderRepository) loadItems(ctx context.Context, querier pgxQuerier, orders []domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	orderIds := make([]int64, 0, len(orders))
+	positions := make(map[int64]int, len(orders))
+	for i, order := range orders {
+		orderIds = append(orderIds, order.Id)
+		positions[order.Id] = i
+		orders[i].Items = []domain.OrderItem{}
+	}
+
+	rows, err := querier.Query(ctx, `
+		SELECT id, order_id, product_id, variant, quantity, unit_price, discount, line_total
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, id
+	`, orderIds)
 	if err != nil {
-		return domain.Order{}, fmt.Errorf("error while getting order with id %d: %w", id, err)
+		return fmt.Errorf("error while getting order items: %w", err)
 	}
-	return order, nil
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.OrderItem
+		var orderId int64
+		if err := rows.Scan(&item.Id, &orderId, &item.ProductID, &item.Variant, &item.Quantity,
+			&item.UnitPrice, &item.Discount, &item.LineTotal); err != nil {
+			return fmt.Errorf("error while scanning order item: %w", err)
+		}
+		position := positions[orderId]
+		orders[position].Items = append(orders[position].Items, item)
+	}
+	return rows.Err()
+}
+
+func scanOrder(row pgx.Row) (domain.Order, error) {
+	var order domain.Order
+	err := row.Scan(&order.Id, &order.UserId, &order.CustomerNumber, &order.Status, &order.Subtotal, &order.DiscountTotal, &order.GrandTotal, &order.RefundedTotal, &order.OrderTime)
+	return order, err
 }
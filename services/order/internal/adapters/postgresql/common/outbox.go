@@ -0,0 +1,52 @@
+package postgresql
+
+import (
+	"context"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"product-app/shared/kafka"
+	"product-app/shared/outbox"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// eventSource identifies this service in event envelopes.
+const eventSource = "order-service"
+
+// eventOutbox writes events to the outbox table of the transaction that
+// stores the change they describe; the relay publishes them to topic.
+type eventOutbox struct {
+	topic string
+}
+
+// enqueue wraps each event in an envelope keyed by its aggregate id and
+// stores it in tx.
+func (o eventOutbox) enqueue(ctx context.Context, tx pgx.Tx, events []kafka.Event) error {
+	for _, event := range events {
+		message, err := outbox.NewEventMessage(o.topic, eventSource, event)
+		if err != nil {
+			return err
+		}
+		if err := outbox.Enqueue(ctx, tx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueOrder stores the events of a stored order change, if any.
+func (o eventOutbox) enqueueOrder(ctx context.Context, tx pgx.Tx, events ports.OrderEvents, order domain.Order) error {
+	if events == nil {
+		return nil
+	}
+	return o.enqueue(ctx, tx, events(order))
+}
+
+// enqueueShipment stores the events of a stored shipment change, if any.
+func (o eventOutbox) enqueueShipment(ctx context.Context, tx pgx.Tx, events ports.ShipmentEvents, shipment domain.Shipment) error {
+	if events == nil {
+		return nil
+	}
+	return o.enqueue(ctx, tx, events(shipment))
+}
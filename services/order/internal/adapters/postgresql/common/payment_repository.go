@@ -0,0 +1,141 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const uniqueViolationCode = "23505"
+
+const selectPaymentColumns = `SELECT id, order_id, provider, provider_payment_id, amount, status, failure_reason, created_at, updated_at FROM payments`
+
+type PaymentRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewPaymentRepository(dbPool *pgxpool.Pool) ports.PaymentRepository {
+	return &PaymentRepository{dbPool: dbPool}
+}
+
+// Create implements ports.PaymentRepository. It fails with
+// domain.ErrPaymentAlreadyInProgress when the order already has a pending
+// or authorized payment.
+func (p *PaymentRepository) Create(payment domain.Payment) (domain.Payment, error) {
+	ctx := context.Background()
+	err := p.dbPool.QueryRow(ctx, `
+		INSERT INTO payments (order_id, provider, provider_payment_id, amount, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, payment.OrderId, payment.Provider, payment.ProviderPaymentId, payment.Amount, payment.Status).
+		Scan(&payment.Id, &payment.CreatedAt, &payment.UpdatedAt)
+	if isPgError(err, uniqueViolationCode) {
+		return domain.Payment{}, fmt.Errorf("%w: order %d", domain.ErrPaymentAlreadyInProgress, payment.OrderId)
+	}
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("failed to insert payment of order %d: %w", payment.OrderId, err)
+	}
+	log.Printf("INFO: payment %s started for order %d", payment.ProviderPaymentId, payment.OrderId)
+	return payment, nil
+}
+
+// GetByProviderPaymentId implements ports.PaymentRepository
+func (p *PaymentRepository) GetByProviderPaymentId(provider, providerPaymentId string) (domain.Payment, error) {
+	ctx := context.Background()
+	payment, err := scanPayment(p.dbPool.QueryRow(ctx,
+		selectPaymentColumns+` WHERE provider = $1 AND provider_payment_id = $2`, provider, providerPaymentId))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Payment{}, fmt.Errorf("%w: %s payment %s", domain.ErrPaymentNotFound, provider, providerPaymentId)
+	}
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("error while getting payment %s: %w", providerPaymentId, err)
+	}
+	return payment, nil
+}
+
+// GetByOrderId implements ports.PaymentRepository, oldest payment first.
+func (p *PaymentRepository) GetByOrderId(orderId int64) ([]domain.Payment, error) {
+	ctx := context.Background()
+	rows, err := p.dbPool.Query(ctx, selectPaymentColumns+` WHERE order_id = $1 ORDER BY id`, orderId)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting payments of order %d: %w", orderId, err)
+	}
+	defer rows.Close()
+
+	payments := []domain.Payment{}
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error while scanning payments of order %d: %w", orderId, err)
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+// UpdateStatus implements ports.PaymentRepository
+func (p *PaymentRepository) UpdateStatus(id int64, from []string, status, failureReason string) (domain.Payment, error) {
+	ctx := context.Background()
+	payment, err := scanPayment(p.dbPool.QueryRow(ctx, `
+		UPDATE payments SET status = $1, failure_reason = $2, updated_at = NOW()
+		WHERE id = $3 AND status = ANY($4)
+		RETURNING id, order_id, provider, provider_payment_id, amount, status, failure_reason, created_at, updated_at
+	`, status, failureReason, id, from))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var current string
+		if err := p.dbPool.QueryRow(ctx, `SELECT status FROM payments WHERE id = $1`, id).Scan(&current); err != nil {
+			return domain.Payment{}, fmt.Errorf("%w with id %d", domain.ErrPaymentNotFound, id)
+		}
+		return domain.Payment{}, fmt.Errorf("%w: payment %d is %s", domain.ErrIllegalTransition, id, current)
+	}
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("error while updating payment %d: %w", id, err)
+	}
+	return payment, nil
+}
+
+// ReserveWebhookEvent implements ports.PaymentRepository
+func (p *PaymentRepository) ReserveWebhookEvent(provider string, event domain.PaymentEvent) error {
+	ctx := context.Background()
+	commandTag, err := p.dbPool.Exec(ctx, `
+		INSERT INTO payment_webhook_events (provider, event_id, event_type, received_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (provider, event_id) DO NOTHING
+	`, provider, event.Id, event.Type)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook event %s: %w", event.Id, err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrDuplicateWebhookEvent, event.Id)
+	}
+	return nil
+}
+
+// ReleaseWebhookEvent implements ports.PaymentRepository
+func (p *PaymentRepository) ReleaseWebhookEvent(provider, eventId string) error {
+	ctx := context.Background()
+	_, err := p.dbPool.Exec(ctx, `DELETE FROM payment_webhook_events WHERE provider = $1 AND event_id = $2`, provider, eventId)
+	if err != nil {
+		return fmt.Errorf("failed to release webhook event %s: %w", eventId, err)
+	}
+	return nil
+}
+
+func scanPayment(row pgx.Row) (domain.Payment, error) {
+	var payment domain.Payment
+	err := row.Scan(&payment.Id, &payment.OrderId, &payment.Provider, &payment.ProviderPaymentId, &payment.Amount,
+		&payment.Status, &payment.FailureReason, &payment.CreatedAt, &payment.UpdatedAt)
+	return payment, err
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,287 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const selectShipmentColumns = `SELECT id, order_id, carrier, tracking_number, status, created_at, updated_at FROM shipments`
+
+type ShipmentRepository struct {
+	dbPool *pgxpool.Pool
+	outbox eventOutbox
+}
+
+// NewShipmentRepository stores shipments and writes their events to the
+// outbox for eventsTopic.
+func NewShipmentRepository(dbPool *pgxpool.Pool, eventsTopic string) ports.ShipmentRepository {
+	return &ShipmentRepository{dbPool: dbPool, outbox: eventOutbox{topic: eventsTopic}}
+}
+
+// Create implements ports.ShipmentRepository.
+func (r *ShipmentRepository) Create(shipment domain.Shipment, events ports.ShipmentEvents) (domain.Shipment, error) {
+	ctx := context.Background()
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var status string
+	err = tx.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, shipment.OrderId).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Shipment{}, fmt.Errorf("%w with id %d", domain.ErrOrderNotFound, shipment.OrderId)
+	}
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("error while locking order %d: %w", shipment.OrderId, err)
+	}
+	if status != domain.OrderStatusPaid {
+		return domain.Shipment{}, fmt.Errorf("%w: order %d is %s", domain.ErrIllegalTransition, shipment.OrderId, status)
+	}
+	unshipped, err := unshippedQuantities(ctx, tx, shipment.OrderId)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	for _, item := range shipment.Items {
+		if item.Quantity > unshipped[shipmentLine{item.ProductID, item.Variant}] {
+			return domain.Shipment{}, fmt.Errorf("%w: only %d of product %d %q are left to ship",
+				domain.ErrInvalidShipment, unshipped[shipmentLine{item.ProductID, item.Variant}], item.ProductID, item.Variant)
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO shipments (order_id, carrier, tracking_number, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, shipment.OrderId, shipment.Carrier, shipment.TrackingNumber, shipment.Status).
+		Scan(&shipment.Id, &shipment.CreatedAt, &shipment.UpdatedAt)
+	if isPgError(err, uniqueViolationCode) {
+		return domain.Shipment{}, fmt.Errorf("%w: %s %s", domain.ErrDuplicateTrackingNumber, shipment.Carrier, shipment.TrackingNumber)
+	}
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("failed to insert shipment of order %d: %w", shipment.OrderId, err)
+	}
+	for _, item := range shipment.Items {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO shipment_items (shipment_id, product_id, variant, quantity)
+			VALUES ($1, $2, $3, $4)
+		`, shipment.Id, item.ProductID, item.Variant, item.Quantity)
+		if err != nil {
+			return domain.Shipment{}, fmt.Errorf("failed to insert product %d of shipment %d: %w", item.ProductID, shipment.Id, err)
+		}
+	}
+	for i := range shipment.Events {
+		event := &shipment.Events[i]
+		event.ShipmentId = shipment.Id
+		if err := insertTrackingEvent(ctx, tx, event); err != nil {
+			return domain.Shipment{}, err
+		}
+	}
+	if err := r.outbox.enqueueShipment(ctx, tx, events, shipment); err != nil {
+		return domain.Shipment{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Shipment{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Printf("INFO: shipment %d (%s %s) created for order %d", shipment.Id, shipment.Carrier, shipment.TrackingNumber, shipment.OrderId)
+	return shipment, nil
+}
+
+// GetById implements ports.ShipmentRepository.
+func (r *ShipmentRepository) GetById(id int64) (domain.Shipment, error) {
+	ctx := context.Background()
+
+	shipment, err := scanShipment(r.dbPool.QueryRow(ctx, selectShipmentColumns+` WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Shipment{}, fmt.Errorf("%w with id %d", domain.ErrShipmentNotFound, id)
+	}
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("error while getting shipment %d: %w", id, err)
+	}
+	return r.withDetails(ctx, r.dbPool, shipment)
+}
+
+// GetByOrderId implements ports.ShipmentRepository.
+func (r *ShipmentRepository) GetByOrderId(orderId int64) ([]domain.Shipment, error) {
+	ctx := context.Background()
+
+	rows, err := r.dbPool.Query(ctx, selectShipmentColumns+` WHERE order_id = $1 ORDER BY id`, orderId)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting shipments of order %d: %w", orderId, err)
+	}
+	shipments := []domain.Shipment{}
+	for rows.Next() {
+		shipment, err := scanShipment(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error while scanning shipments of order %d: %w", orderId, err)
+		}
+		shipments = append(shipments, shipment)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting shipments of order %d: %w", orderId, err)
+	}
+
+	for i := range shipments {
+		if shipments[i], err = r.withDetails(ctx, r.dbPool, shipments[i]); err != nil {
+			return nil, err
+		}
+	}
+	return shipments, nil
+}
+
+// AddEvent implements ports.ShipmentRepository.
+func (r *ShipmentRepository) AddEvent(event domain.TrackingEvent, events ports.ShipmentEvents) (domain.Shipment, error) {
+	ctx := context.Background()
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var current string
+	err = tx.QueryRow(ctx, `SELECT status FROM shipments WHERE id = $1 FOR UPDATE`, event.ShipmentId).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Shipment{}, fmt.Errorf("%w with id %d", domain.ErrShipmentNotFound, event.ShipmentId)
+	}
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("error while locking shipment %d: %w", event.ShipmentId, err)
+	}
+	if current == domain.ShipmentStatusDelivered {
+		return domain.Shipment{}, fmt.Errorf("%w: shipment %d is delivered", domain.ErrIllegalTransition, event.ShipmentId)
+	}
+
+	if err := insertTrackingEvent(ctx, tx, &event); err != nil {
+		return domain.Shipment{}, err
+	}
+	_, err = tx.Exec(ctx, `UPDATE shipments SET status = $1, updated_at = NOW() WHERE id = $2`, event.Status, event.ShipmentId)
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("error while updating shipment %d: %w", event.ShipmentId, err)
+	}
+	shipment, err := scanShipment(tx.QueryRow(ctx, selectShipmentColumns+` WHERE id = $1`, event.ShipmentId))
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("error while getting shipment %d: %w", event.ShipmentId, err)
+	}
+	if shipment, err = r.withDetails(ctx, tx, shipment); err != nil {
+		return domain.Shipment{}, err
+	}
+	if err := r.outbox.enqueueShipment(ctx, tx, events, shipment); err != nil {
+		return domain.Shipment{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Shipment{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return shipment, nil
+}
+
+// withDetails loads the shipment's items and tracking events.
+func (r *ShipmentRepository) withDetails(ctx context.Context, querier pgxQuerier, shipment domain.Shipment) (domain.Shipment, error) {
+	rows, err := querier.Query(ctx, `
+		SELECT product_id, variant, quantity FROM shipment_items
+		WHERE shipment_id = $1
+		ORDER BY product_id, variant
+	`, shipment.Id)
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("error while getting items of shipment %d: %w", shipment.Id, err)
+	}
+	shipment.Items = []domain.ShipmentItem{}
+	for rows.Next() {
+		var item domain.ShipmentItem
+		if err := rows.Scan(&item.ProductID, &item.Variant, &item.Quantity); err != nil {
+			rows.Close()
+			return domain.Shipment{}, fmt.Errorf("error while scanning item of shipment %d: %w", shipment.Id, err)
+		}
+		shipment.Items = append(shipment.Items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return domain.Shipment{}, fmt.Errorf("error while getting items of shipment %d: %w", shipment.Id, err)
+	}
+
+	rows, err = querier.Query(ctx, `
+		SELECT id, shipment_id, status, location, description, occurred_at, recorded_at FROM shipment_events
+		WHERE shipment_id = $1
+		ORDER BY id
+	`, shipment.Id)
+	if err != nil {
+		return domain.Shipment{}, fmt.Errorf("error while getting events of shipment %d: %w", shipment.Id, err)
+	}
+	defer rows.Close()
+	shipment.Events = []domain.TrackingEvent{}
+	for rows.Next() {
+		var event domain.TrackingEvent
+		if err := rows.Scan(&event.Id, &event.ShipmentId, &event.Status, &event.Location, &event.Description,
+			&event.OccurredAt, &event.RecordedAt); err != nil {
+			return domain.Shipment{}, fmt.Errorf("error while scanning event of shipment %d: %w", shipment.Id, err)
+		}
+		shipment.Events = append(shipment.Events, event)
+	}
+	return shipment, rows.Err()
+}
+
+// shipmentLine identifies an order line by product and variant.
+type shipmentLine struct {
+	productId int64
+	variant   string
+}
+
+// unshippedQuantities returns the units of each line of the order that no
+// shipment carries yet.
+func unshippedQuantities(ctx context.Context, tx pgx.Tx, orderId int64) (map[shipmentLine]int32, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT product_id, variant, SUM(quantity)::int FROM (
+			SELECT product_id, variant, quantity FROM order_items WHERE order_id = $1
+			UNION ALL
+			SELECT si.product_id, si.variant, -si.quantity FROM shipment_items si
+			JOIN shipments s ON s.id = si.shipment_id
+			WHERE s.order_id = $1
+		) lines
+		GROUP BY product_id, variant
+	`, orderId)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting shipped items of order %d: %w", orderId, err)
+	}
+	defer rows.Close()
+
+	unshipped := map[shipmentLine]int32{}
+	for rows.Next() {
+		var line shipmentLine
+		var quantity int32
+		if err := rows.Scan(&line.productId, &line.variant, &quantity); err != nil {
+			return nil, fmt.Errorf("error while scanning shipped items of order %d: %w", orderId, err)
+		}
+		unshipped[line] = quantity
+	}
+	return unshipped, rows.Err()
+}
+
+func insertTrackingEvent(ctx context.Context, tx pgx.Tx, event *domain.TrackingEvent) error {
+	err := tx.QueryRow(ctx, `
+		INSERT INTO shipment_events (shipment_id, status, location, description, occurred_at, recorded_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING id, recorded_at
+	`, event.ShipmentId, event.Status, event.Location, event.Description, event.OccurredAt).
+		Scan(&event.Id, &event.RecordedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert tracking event of shipment %d: %w", event.ShipmentId, err)
+	}
+	return nil
+}
+
+func scanShipment(row pgx.Row) (domain.Shipment, error) {
+	var shipment domain.Shipment
+	err := row.Scan(&shipment.Id, &shipment.OrderId, &shipment.Carrier, &shipment.TrackingNumber, &shipment.Status,
+		&shipment.CreatedAt, &shipment.UpdatedAt)
+	return shipment, err
+}
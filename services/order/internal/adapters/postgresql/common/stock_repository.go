@@ -0,0 +1,132 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type StockRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewStockRepository(dbPool *pgxpool.Pool) ports.StockRepository {
+	return &StockRepository{dbPool: dbPool}
+}
+
+// Set implements ports.StockRepository.
+func (s *StockRepository) Set(level domain.StockLevel) (domain.StockLevel, error) {
+	ctx := context.Background()
+
+	err := s.dbPool.QueryRow(ctx, `
+		INSERT INTO stock_levels (product_id, variant, available, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (product_id, variant) DO UPDATE
+		SET available = EXCLUDED.available, updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`, level.ProductID, level.Variant, level.Available).Scan(&level.UpdatedAt)
+	if err != nil {
+		return domain.StockLevel{}, fmt.Errorf("error while setting stock of product %d: %w", level.ProductID, err)
+	}
+	log.Printf("INFO: stock of product %d %q set to %d", level.ProductID, level.Variant, level.Available)
+	return level, nil
+}
+
+// GetByProductId implements ports.StockRepository, ordered by variant.
+func (s *StockRepository) GetByProductId(productId int64) ([]domain.StockLevel, error) {
+	ctx := context.Background()
+
+	rows, err := s.dbPool.Query(ctx, `
+		SELECT product_id, variant, available, updated_at
+		FROM stock_levels WHERE product_id = $1
+		ORDER BY variant
+	`, productId)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting stock of product %d: %w", productId, err)
+	}
+	defer rows.Close()
+
+	levels := []domain.StockLevel{}
+	for rows.Next() {
+		var level domain.StockLevel
+		if err := rows.Scan(&level.ProductID, &level.Variant, &level.Available, &level.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error while scanning stock level: %w", err)
+		}
+		levels = append(levels, level)
+	}
+	return levels, rows.Err()
+}
+
+// reserveStock takes the units of order's items from their stock levels and
+// records the reservation, failing with domain.ErrOutOfStock when a tracked
+// product has too few units. Untracked products are skipped.
+func reserveStock(ctx context.Context, tx pgx.Tx, order domain.Order) error {
+	for _, item := range order.Items {
+		var available int32
+		err := tx.QueryRow(ctx, `
+			SELECT available FROM stock_levels
+			WHERE product_id = $1 AND variant = $2
+			FOR UPDATE
+		`, item.ProductID, item.Variant).Scan(&available)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error while reserving stock of product %d: %w", item.ProductID, err)
+		}
+		if available < item.Quantity {
+			return fmt.Errorf("%w: product %d has %d left", domain.ErrOutOfStock, item.ProductID, available)
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE stock_levels SET available = available - $3, updated_at = NOW()
+			WHERE product_id = $1 AND variant = $2
+		`, item.ProductID, item.Variant, item.Quantity)
+		if err != nil {
+			return fmt.Errorf("error while reserving stock of product %d: %w", item.ProductID, err)
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO stock_reservations (order_id, product_id, variant, quantity)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (order_id, product_id, variant) DO UPDATE
+			SET quantity = stock_reservations.quantity + EXCLUDED.quantity
+		`, order.Id, item.ProductID, item.Variant, item.Quantity)
+		if err != nil {
+			return fmt.Errorf("error while recording stock reservation of order %d: %w", order.Id, err)
+		}
+	}
+	return nil
+}
+
+// releaseStock gives the units reserved by an order back to their stock
+// levels and forgets the reservation.
+func releaseStock(ctx context.Context, tx pgx.Tx, orderId int64) error {
+	_, err := tx.Exec(ctx, `
+		WITH released AS (
+			DELETE FROM stock_reservations WHERE order_id = $1
+			RETURNING product_id, variant, quantity
+		)
+		UPDATE stock_levels SET available = stock_levels.available + released.quantity, updated_at = NOW()
+		FROM released
+		WHERE stock_levels.product_id = released.product_id AND stock_levels.variant = released.variant
+	`, orderId)
+	if err != nil {
+		return fmt.Errorf("error while releasing stock of order %d: %w", orderId, err)
+	}
+	return nil
+}
+
+// consumeStock forgets the units reserved by an order once they have been
+// shipped, so that they are not given back to stock later.
+func consumeStock(ctx context.Context, tx pgx.Tx, orderId int64) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM stock_reservations WHERE order_id = $1`, orderId); err != nil {
+		return fmt.Errorf("error while consuming stock of order %d: %w", orderId, err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package productclient
+
+import (
+	"product-app/services/order/internal/domain"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	product   domain.CatalogProduct
+	expiresAt time.Time
+}
+
+// cache keeps found products for a short time so that an order with
+// repeated lines, or a burst of orders, does not hit the product service
+// for every line.
+type cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[int64]cacheEntry
+}
+
+func newCache(ttl time.Duration) *cache {
+	return &cache{ttl: ttl, entries: map[int64]cacheEntry{}}
+}
+
+func (c *cache) get(id int64) (domain.CatalogProduct, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[id]
+	if !ok {
+		return domain.CatalogProduct{}, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, id)
+		return domain.CatalogProduct{}, false
+	}
+	return entry.product, true
+}
+
+func (c *cache) put(product domain.CatalogProduct) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[product.Id] = cacheEntry{product: product, expiresAt: time.Now().Add(c.ttl)}
+}
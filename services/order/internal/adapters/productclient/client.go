@@ -0,0 +1,152 @@
+package productclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"strings"
+	"time"
+)
+
+// Options tunes the resilience of the client.
+type Options struct {
+	// Timeout bounds a single request attempt
+	Timeout time.Duration
+	// MaxRetries is the number of extra attempts after a failed one
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry; it doubles each time
+	RetryBackoff time.Duration
+	// BreakerThreshold consecutive failures open the circuit
+	BreakerThreshold int
+	// BreakerCooldown is how long the circuit stays open before a trial call
+	BreakerCooldown time.Duration
+	// CacheTTL is how long a found product is served from memory
+	CacheTTL time.Duration
+}
+
+// DefaultOptions fill every zero duration and threshold of the options
+// passed to NewClient. A MaxRetries of zero means a single attempt.
+func DefaultOptions() Options {
+	return Options{
+		Timeout:          2 * time.Second,
+		MaxRetries:       2,
+		RetryBackoff:     100 * time.Millisecond,
+		BreakerThreshold: 5,
+		BreakerCooldown:  10 * time.Second,
+		CacheTTL:         30 * time.Second,
+	}
+}
+
+// Client reads products from the product service's public REST API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	options    Options
+	breaker    *breaker
+	cache      *cache
+}
+
+func NewClient(baseURL string, options Options) ports.ProductCatalog {
+	options = withDefaults(options)
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: options.Timeout},
+		options:    options,
+		breaker:    newBreaker(options.BreakerThreshold, options.BreakerCooldown),
+		cache:      newCache(options.CacheTTL),
+	}
+}
+
+// GetProduct implements ports.ProductCatalog. Server errors and network
+// failures are retried with exponential backoff; a 404 is not.
+func (client *Client) GetProduct(ctx context.Context, id int64) (domain.CatalogProduct, error) {
+	if product, ok := client.cache.get(id); ok {
+		return product, nil
+	}
+	if !client.breaker.allow() {
+		return domain.CatalogProduct{}, fmt.Errorf("%w: circuit open", domain.ErrCatalogUnavailable)
+	}
+
+	var lastErr error
+	backoff := client.options.RetryBackoff
+	for attempt := 0; attempt <= client.options.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				client.breaker.abandon()
+				return domain.CatalogProduct{}, fmt.Errorf("%w: %v", domain.ErrCatalogUnavailable, ctx.Err())
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		product, err := client.fetch(ctx, id)
+		if err == nil {
+			client.breaker.success()
+			client.cache.put(product)
+			return product, nil
+		}
+		if errors.Is(err, domain.ErrProductNotFound) {
+			client.breaker.success()
+			return domain.CatalogProduct{}, err
+		}
+		lastErr = err
+		log.Printf("WARNING: product service lookup of product %d failed (attempt %d): %v", id, attempt+1, err)
+	}
+
+	client.breaker.failure()
+	return domain.CatalogProduct{}, fmt.Errorf("%w: %v", domain.ErrCatalogUnavailable, lastErr)
+}
+
+func (client *Client) fetch(ctx context.Context, id int64) (domain.CatalogProduct, error) {
+	path := fmt.Sprintf("/api/v1/products/%d", id)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, client.baseURL+path, nil)
+	if err != nil {
+		return domain.CatalogProduct{}, err
+	}
+	resp, err := client.httpClient.Do(request)
+	if err != nil {
+		return domain.CatalogProduct{}, fmt.Errorf("product service request %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return domain.CatalogProduct{}, fmt.Errorf("%w with id %d", domain.ErrProductNotFound, id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return domain.CatalogProduct{}, fmt.Errorf("product service request %s returned status %d", path, resp.StatusCode)
+	}
+	var product domain.CatalogProduct
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		return domain.CatalogProduct{}, fmt.Errorf("failed to decode product service response: %w", err)
+	}
+	return product, nil
+}
+
+func withDefaults(options Options) Options {
+	defaults := DefaultOptions()
+	if options.Timeout <= 0 {
+		options.Timeout = defaults.Timeout
+	}
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = defaults.RetryBackoff
+	}
+	if options.BreakerThreshold <= 0 {
+		options.BreakerThreshold = defaults.BreakerThreshold
+	}
+	if options.BreakerCooldown <= 0 {
+		options.BreakerCooldown = defaults.BreakerCooldown
+	}
+	if options.CacheTTL <= 0 {
+		options.CacheTTL = defaults.CacheTTL
+	}
+	return options
+}
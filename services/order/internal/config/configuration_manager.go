@@ -1,51 +1,113 @@
 package config
 
 import (
-	"os"
-	postgresql "product-app/services/order/internal/adapters/postgresql/common"
-	"strconv"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	postgresql "product-app/services/order/internal/adapters/postgresql/common"
+	sharedconfig "product-app/shared/config"
 )
 
+// ConfigurationManager holds application-level configurations.
 type ConfigurationManager struct {
-	PostgreSqlConfig postgresql.Config
-}
+	// Environment is APP_ENV; secrets may only use defaults in development
+	Environment string `env:"APP_ENV" yaml:"environment" default:"production"`
+	// HttpAddress is the listen address of the REST server
+	HttpAddress string `env:"HTTP_ADDRESS" yaml:"http_address" default:":8084" required:"true"`
+	// OrderEventsTopic receives the order lifecycle events
+	OrderEventsTopic string `env:"KAFKA_ORDER_EVENTS_TOPIC" yaml:"order_events_topic" default:"order.events" required:"true"`
+	// ProductServiceURL is the base URL of the product service used to price orders
+	ProductServiceURL string `env:"PRODUCT_SERVICE_URL" yaml:"product_service_url" default:"http://product:8081" required:"true"`
+	// ProductServiceTimeout bounds a single product lookup attempt
+	ProductServiceTimeout time.Duration `env:"PRODUCT_SERVICE_TIMEOUT" yaml:"product_service_timeout" default:"2s"`
+	// ProductServiceRetries is the number of retries of a failed product lookup
+	ProductServiceRetries int `env:"PRODUCT_SERVICE_RETRIES" yaml:"product_service_retries" default:"2"`
+	// ProductCacheTTL is how long looked-up products are reused
+	ProductCacheTTL time.Duration `env:"PRODUCT_CACHE_TTL" yaml:"product_cache_ttl" default:"30s"`
+	// IdempotencyTTL is how long Idempotency-Key responses are replayed
+	IdempotencyTTL time.Duration `env:"IDEMPOTENCY_TTL" yaml:"idempotency_ttl" default:"24h"`
+	// PaymentProvider selects the payment gateway adapter; only "fake" exists
+	// yet, and it is refused outside development and test
+	PaymentProvider string `env:"PAYMENT_PROVIDER" yaml:"payment_provider" default:"fake" required:"true"`
+	// PaymentWebhookSecret verifies the signature of the provider's webhook calls
+	PaymentWebhookSecret string `env:"PAYMENT_WEBHOOK_SECRET" yaml:"payment_webhook_secret" default:"dev-only-webhook-secret" secret:"true"`
+	// PaymentWebhookURL is where the fake provider delivers its webhook calls
+	PaymentWebhookURL string `env:"PAYMENT_WEBHOOK_URL" yaml:"payment_webhook_url" default:"http://localhost:8084/api/v1/payments/webhook"`
+	// PaymentFakeDelay is how long the fake provider holds delayed payments
+	PaymentFakeDelay time.Duration `env:"PAYMENT_FAKE_DELAY" yaml:"payment_fake_delay" default:"5s"`
+	// CartTTL is how long an anonymous cart lives after its last change
+	CartTTL time.Duration `env:"CART_TTL" yaml:"cart_ttl" default:"168h"`
+	// SellerName and the other seller details are printed on new invoices
+	SellerName    string `env:"SELLER_NAME" yaml:"seller_name" default:"Product Platform" required:"true"`
+	SellerAddress string `env:"SELLER_ADDRESS" yaml:"seller_address"`
+	SellerTaxId   string `env:"SELLER_TAX_ID" yaml:"seller_tax_id"`
+	SellerEmail   string `env:"SELLER_EMAIL" yaml:"seller_email"`
+	// InvoiceCurrency is the ISO 4217 code of the order amounts
+	InvoiceCurrency string `env:"INVOICE_CURRENCY" yaml:"invoice_currency" default:"EUR" required:"true"`
+	// InvoiceTaxRate is the tax percentage included in the order prices
+	InvoiceTaxRate float64 `env:"INVOICE_TAX_RATE" yaml:"invoice_tax_rate" default:"20"`
 
-func NewConfigurationManager() *ConfigurationManager {
-	postgreSqlConfig := getPostgreSqlConfig()
+	Database sharedconfig.Postgres `yaml:"database"`
+	Kafka    sharedconfig.Kafka    `yaml:"kafka"`
+	Auth     sharedconfig.Auth     `yaml:"auth"`
 
-	return &ConfigurationManager{
-		PostgreSqlConfig: postgreSqlConfig,
-	}
+	// PostgreSqlConfig contains PostgreSQL related configuration values
+	PostgreSqlConfig postgresql.Config `yaml:"-"`
 }
-func getPostgreSqlConfig() postgresql.Config {
-	return postgresql.Config{
-		Host:                  getEnvString("DB_HOST", "localhost"),
-		Port:                  getEnvString("DB_PORT", "6432"),
-		UserName:              getEnvString("DB_USER", "postgres"),
-		Password:              getEnvString("DB_PASSWORD", "postgres"),
-		DbName:                getEnvString("DB_NAME", "productapp"),
-		MaxConnections:        int32(getEnvInt("DB_MAX_CONNECTIONS", 10)),
-		MaxConnectionIdleTime: time.Duration(getEnvInt("DB_MAX_IDLE_SECONDS", 30)) * time.Second,
+
+// NewConfigurationManager loads the configuration from defaults, the
+// optional CONFIG_FILE, environment variables and *_FILE secrets, and
+// returns an error describing every invalid or missing value.
+func NewConfigurationManager() (*ConfigurationManager, error) {
+	configurationManager := &ConfigurationManager{}
+	if err := sharedconfig.Load(configurationManager); err != nil {
+		return nil, err
 	}
+	configurationManager.PostgreSqlConfig = getPostgreSqlConfig(configurationManager.Database)
+	return configurationManager, nil
 }
 
-func getEnvString(key, fallback string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return fallback
+// Validate rejects unknown payment providers, the fake provider outside
+// development and test, webhook secrets too short to sign with and
+// impossible invoice settings.
+func (configurationManager *ConfigurationManager) Validate() error {
+	var problems []error
+	switch configurationManager.PaymentProvider {
+	case "fake":
+		if !sharedconfig.IsDevelopment(strings.ToLower(configurationManager.Environment)) {
+			problems = append(problems, fmt.Errorf("PAYMENT_PROVIDER %q only takes payments in development and test, not with APP_ENV %q", configurationManager.PaymentProvider, configurationManager.Environment))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("PAYMENT_PROVIDER %q is not supported", configurationManager.PaymentProvider))
 	}
-	return value
+	if len(configurationManager.PaymentWebhookSecret) < 16 {
+		problems = append(problems, errors.New("PAYMENT_WEBHOOK_SECRET must be at least 16 characters"))
+	}
+	if len(configurationManager.InvoiceCurrency) != 3 {
+		problems = append(problems, fmt.Errorf("INVOICE_CURRENCY %q is not a three-letter currency code", configurationManager.InvoiceCurrency))
+	}
+	if configurationManager.InvoiceTaxRate < 0 || configurationManager.InvoiceTaxRate >= 100 {
+		problems = append(problems, fmt.Errorf("INVOICE_TAX_RATE %v must be from 0 up to 100", configurationManager.InvoiceTaxRate))
+	}
+	return errors.Join(problems...)
 }
 
-func getEnvInt(key string, fallback int) int {
-	raw := os.Getenv(key)
-	if raw == "" {
-		return fallback
-	}
-	parsed, err := strconv.Atoi(raw)
-	if err != nil {
-		return fallback
+// String prints the effective configuration with secrets redacted.
+func (configurationManager *ConfigurationManager) String() string {
+	return sharedconfig.Describe(configurationManager)
+}
+
+// getPostgreSqlConfig maps the loaded database settings to the pool config.
+func getPostgreSqlConfig(database sharedconfig.Postgres) postgresql.Config {
+	return postgresql.Config{
+		Host:                  database.Host,
+		Port:                  database.Port,
+		UserName:              database.User,
+		Password:              database.Password,
+		DbName:                database.Name,
+		MaxConnections:        database.MaxConnections,
+		MaxConnectionIdleTime: database.MaxIdleTime,
 	}
-	return parsed
 }
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrCartNotFound     = errors.New("cart not found")
+	ErrCartItemNotFound = errors.New("cart item not found")
+	ErrInvalidCartItem  = errors.New("invalid cart item")
+	ErrCartEmpty        = errors.New("cart is empty")
+	ErrCartChanged      = errors.New("cart changed during checkout")
+)
+
+// Cart holds the items a customer intends to order. A signed-in user has
+// one cart; an anonymous visitor's cart is found by its Token and expires
+// after a period without changes.
+//
+// Only ProductID, Variant and Quantity of the items are stored. Names,
+// prices and totals are filled in from the product catalog whenever the
+// cart is read, so a cart always shows current prices.
+type Cart struct {
+	Id            int64      `json:"id"`
+	UserId        int64      `json:"user_id,omitempty"`
+	Token         string     `json:"token,omitempty"`
+	Items         []CartItem `json:"items"`
+	Subtotal      float64    `json:"subtotal"`
+	DiscountTotal float64    `json:"discount_total"`
+	GrandTotal    float64    `json:"grand_total"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+}
+
+// IsAnonymous reports whether the cart belongs to a visitor who has not
+// signed in.
+func (cart Cart) IsAnonymous() bool {
+	return cart.UserId == 0
+}
+
+// CartItem is one line of a cart. Unavailable marks a product the catalog
+// no longer has; such lines are kept but left out of the totals and must be
+// removed before checkout.
+type CartItem struct {
+	ProductID   int64   `json:"product_id"`
+	Variant     string  `json:"variant"`
+	Quantity    int32   `json:"quantity"`
+	Name        string  `json:"name,omitempty"`
+	UnitPrice   float64 `json:"unit_price"`
+	Discount    float64 `json:"discount"`
+	LineTotal   float64 `json:"line_total"`
+	Unavailable bool    `json:"unavailable,omitempty"`
+}
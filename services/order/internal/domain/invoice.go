@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrInvoiceNotAvailable  = errors.New("order cannot be invoiced")
+	ErrInvoiceAlreadyIssued = errors.New("invoice already issued")
+	ErrInvalidCreditNote    = errors.New("invalid credit note")
+)
+
+// Invoice kinds. An order gets at most one invoice; corrections are credit
+// notes against it.
+const (
+	InvoiceKindInvoice    = "invoice"
+	InvoiceKindCreditNote = "credit_note"
+)
+
+// Invoice is an issued invoice or credit note. It is a snapshot of
+// everything it shows, taken at issue time, and is never changed
+// afterwards.
+//
+// Numbers run per kind and calendar year without gaps: INV-2026-000001,
+// INV-2026-000002, … and CN-2026-000001, … The amounts of a credit note,
+// including its line quantities, are negative.
+type Invoice struct {
+	Id       int64  `json:"id"`
+	Kind     string `json:"kind"`
+	Number   string `json:"number"`
+	Year     int    `json:"year"`
+	Sequence int64  `json:"sequence"`
+	OrderId  int64  `json:"order_id"`
+	// CorrectsNumber is the number of the invoice a credit note corrects.
+	CorrectsNumber string `json:"corrects_number,omitempty"`
+	// Reason says why a credit note was issued.
+	Reason     string        `json:"reason,omitempty"`
+	Currency   string        `json:"currency"`
+	Seller     InvoiceParty  `json:"seller"`
+	Customer   InvoiceParty  `json:"customer"`
+	Lines      []InvoiceLine `json:"lines"`
+	Taxes      []InvoiceTax  `json:"taxes"`
+	NetTotal   float64       `json:"net_total"`
+	TaxTotal   float64       `json:"tax_total"`
+	GrandTotal float64       `json:"grand_total"`
+	IssuedAt   time.Time     `json:"issued_at"`
+}
+
+// InvoiceParty is the seller or the customer of an invoice.
+type InvoiceParty struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+	TaxId   string `json:"tax_id,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+// InvoiceLine is one invoiced order item. Total is the gross line amount
+// after the discount, split into NetAmount and TaxAmount at TaxRate.
+type InvoiceLine struct {
+	ProductID   int64   `json:"product_id"`
+	Variant     string  `json:"variant,omitempty"`
+	Description string  `json:"description"`
+	Store       string  `json:"store,omitempty"`
+	StoreSlug   string  `json:"store_slug,omitempty"`
+	Quantity    int32   `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Discount    float64 `json:"discount"`
+	TaxRate     float64 `json:"tax_rate"`
+	NetAmount   float64 `json:"net_amount"`
+	TaxAmount   float64 `json:"tax_amount"`
+	Total       float64 `json:"total"`
+}
+
+// InvoiceStore is a store selling invoiced items.
+type InvoiceStore struct {
+	Name string `json:"name"`
+	Slug string `json:"slug,omitempty"`
+}
+
+// InvoiceTax sums the lines taxed at one rate.
+type InvoiceTax struct {
+	Rate      float64 `json:"rate"`
+	NetAmount float64 `json:"net_amount"`
+	TaxAmount float64 `json:"tax_amount"`
+	Total     float64 `json:"total"`
+}
+
+// IsCreditNote reports whether the document is a credit note.
+func (invoice Invoice) IsCreditNote() bool {
+	return invoice.Kind == InvoiceKindCreditNote
+}
+
+// Stores returns the stores selling the invoiced items, in line order and
+// without repeats.
+func (invoice Invoice) Stores() []InvoiceStore {
+	stores := []InvoiceStore{}
+	seen := map[InvoiceStore]bool{}
+	for _, line := range invoice.Lines {
+		store := InvoiceStore{Name: line.Store, Slug: line.StoreSlug}
+		if store.Name == "" || seen[store] {
+			continue
+		}
+		seen[store] = true
+		stores = append(stores, store)
+	}
+	return stores
+}
+
+// InvoiceNumber formats the number of the sequence-th document of a kind
+// issued in year.
+func InvoiceNumber(kind string, year int, sequence int64) string {
+	prefix := "INV"
+	if kind == InvoiceKindCreditNote {
+		prefix = "CN"
+	}
+	return fmt.Sprintf("%s-%d-%06d", prefix, year, sequence)
+}
@@ -1,11 +1,130 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
+// Order is a customer's order of one or more items. Amounts are in the
+// store currency and rounded to cents.
 type Order struct {
-	Id             int64     `json:"id"`
-	CustomerNumber string    `json:"customer_number"`
-	ProductID      string    `json:"product_id"`
-	Quantity       int32     `json:"quantity"`
-	OrderTime      time.Time `json:"order_time"`
+	Id int64 `json:"id"`
+	// UserId is the user who placed the order; 0 for orders placed before
+	// owners were recorded.
+	UserId         int64       `json:"user_id"`
+	CustomerNumber string      `json:"customer_number"`
+	Status         string      `json:"status"`
+	Items          []OrderItem `json:"items"`
+	// Subtotal is the sum of quantity × unit price over all items.
+	Subtotal float64 `json:"subtotal"`
+	// DiscountTotal is the sum of the item discounts.
+	DiscountTotal float64 `json:"discount_total"`
+	// GrandTotal is Subtotal minus DiscountTotal.
+	GrandTotal float64 `json:"grand_total"`
+	// RefundedTotal is the sum of the refunds issued for the order; it never
+	// exceeds GrandTotal.
+	RefundedTotal float64   `json:"refunded_total"`
+	OrderTime     time.Time `json:"order_time"`
+}
+
+// OrderItem is one line of an order. UnitPrice is a snapshot taken when the
+// order was placed, so later price changes do not alter the order.
+type OrderItem struct {
+	Id        int64   `json:"id"`
+	ProductID int64   `json:"product_id"`
+	Variant   string  `json:"variant,omitempty"`
+	Quantity  int32   `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	// Discount is the amount taken off the whole line.
+	Discount  float64 `json:"discount"`
+	LineTotal float64 `json:"line_total"`
+}
+
+// Order statuses. An order is placed as pending and moves along
+// pending → paid → shipped → delivered; it can be cancelled while pending or
+// paid.
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+)
+
+var (
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrInvalidOrder        = errors.New("invalid order")
+	ErrInvalidOrderStatus  = errors.New("unknown order status")
+	ErrIllegalTransition   = errors.New("illegal order status transition")
+	ErrOrderForbidden      = errors.New("order belongs to another user")
+	ErrInvalidCancelReason = errors.New("unknown cancellation reason")
+)
+
+// Cancellation reason codes, recorded with the status change that cancels
+// an order.
+const (
+	CancelReasonCustomerRequest = "customer_request"
+	CancelReasonDuplicateOrder  = "duplicate_order"
+	CancelReasonOutOfStock      = "out_of_stock"
+	CancelReasonPaymentFailed   = "payment_failed"
+	CancelReasonFraudSuspected  = "fraud_suspected"
+	CancelReasonOther           = "other"
+)
+
+// IsValidCancelReason reports whether code is one of the known cancellation
+// reason codes.
+func IsValidCancelReason(code string) bool {
+	switch code {
+	case CancelReasonCustomerRequest, CancelReasonDuplicateOrder, CancelReasonOutOfStock,
+		CancelReasonPaymentFailed, CancelReasonFraudSuspected, CancelReasonOther:
+		return true
+	}
+	return false
+}
+
+// CustomerNumberFor returns the customer number of the user with userId.
+func CustomerNumberFor(userId int64) string {
+	return fmt.Sprintf("CUST-%03d", userId)
+}
+
+// orderTransitions lists the statuses each status may move to.
+var orderTransitions = map[string][]string{
+	OrderStatusPending: {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:    {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped: {OrderStatusDelivered},
+}
+
+// IsValidOrderStatus reports whether status is one of the known order statuses.
+func IsValidOrderStatus(status string) bool {
+	switch status {
+	case OrderStatusPending, OrderStatusPaid, OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// CanTransition reports whether an order may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, allowed := range orderTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderStatusChange is one entry of an order's status history. FromStatus is
+// empty for the entry recorded when the order was placed, and ActorUserId is
+// zero for changes made by the system. ReasonCode is only set on
+// cancellations.
+type OrderStatusChange struct {
+	Id          int64     `json:"id"`
+	OrderId     int64     `json:"order_id"`
+	FromStatus  string    `json:"from_status,omitempty"`
+	ToStatus    string    `json:"to_status"`
+	ActorUserId int64     `json:"actor_user_id,omitempty"`
+	ReasonCode  string    `json:"reason_code,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	ChangedAt   time.Time `json:"changed_at"`
 }
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidOrderQuery = errors.New("invalid order query")
+
+// Order sort keys. Ties are broken by id in the same direction.
+const (
+	OrderSortTime  = "order_time"
+	OrderSortTotal = "grand_total"
+)
+
+// OrderQuery selects a page of orders. Zero-valued filters match every
+// order; From is inclusive and To exclusive.
+type OrderQuery struct {
+	From           time.Time
+	To             time.Time
+	Status         string
+	ProductId      int64
+	CustomerNumber string
+	UserId         int64
+	SortBy         string
+	Descending     bool
+	// After continues a previous page: only orders sorting after it are
+	// returned.
+	After *OrderCursor
+	Limit int
+}
+
+// OrderCursor is the sort position of the last order of a page.
+type OrderCursor struct {
+	Id         int64
+	OrderTime  time.Time
+	GrandTotal float64
+}
+
+// OrderPage is one page of a query. NextCursor is empty on the last page.
+type OrderPage struct {
+	Orders     []Order `json:"orders"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
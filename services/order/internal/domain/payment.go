@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPaymentNotFound          = errors.New("payment not found")
+	ErrPaymentDeclined          = errors.New("payment declined")
+	ErrPaymentUnavailable       = errors.New("payment provider unavailable")
+	ErrInvalidWebhookSignature  = errors.New("invalid webhook signature")
+	ErrDuplicateWebhookEvent    = errors.New("webhook event already processed")
+	ErrPaymentAlreadyInProgress = errors.New("order already has a payment in progress")
+)
+
+// Payment statuses. A payment is created when the provider opens an intent
+// and follows pending → authorized → captured, or ends as failed.
+const (
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusCaptured   = "captured"
+	PaymentStatusFailed     = "failed"
+)
+
+// Payment is an attempt to collect an order's grand total through the
+// payment provider. ClientSecret lets the client confirm the payment with
+// the provider and is only returned when the payment is started.
+type Payment struct {
+	Id                int64     `json:"id"`
+	OrderId           int64     `json:"order_id"`
+	Provider          string    `json:"provider"`
+	ProviderPaymentId string    `json:"provider_payment_id"`
+	Amount            float64   `json:"amount"`
+	Status            string    `json:"status"`
+	FailureReason     string    `json:"failure_reason,omitempty"`
+	ClientSecret      string    `json:"client_secret,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// PaymentIntentRequest asks the provider to prepare the collection of
+// Amount for an order. PaymentMethod is the provider's token for the card
+// or wallet the customer chose.
+type PaymentIntentRequest struct {
+	OrderId       int64
+	Amount        float64
+	PaymentMethod string
+}
+
+// PaymentIntent is the provider's answer to a PaymentIntentRequest.
+type PaymentIntent struct {
+	ProviderPaymentId string
+	ClientSecret      string
+}
+
+// Payment webhook event types, in the provider-neutral form adapters
+// translate their events to.
+const (
+	PaymentEventAuthorized = "payment.authorized"
+	PaymentEventCaptured   = "payment.captured"
+	PaymentEventFailed     = "payment.failed"
+)
+
+// PaymentEvent is a verified webhook call of the payment provider. Id is
+// the provider's event id and is unique per provider.
+type PaymentEvent struct {
+	Id                string
+	Type              string
+	ProviderPaymentId string
+	Amount            float64
+	FailureReason     string
+	OccurredAt        time.Time
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "errors"
+
+var (
+	ErrProductNotFound    = errors.New("product not found")
+	ErrCatalogUnavailable = errors.New("product catalog unavailable")
+)
+
+// CatalogProduct is the part of a product-service product an order needs.
+// Discount is a percentage of Price. Store and StoreSlug name the store
+// selling the product.
+type CatalogProduct struct {
+	Id        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Price     float64 `json:"price"`
+	Discount  float64 `json:"discount"`
+	Store     string  `json:"store"`
+	StoreSlug string  `json:"store_slug"`
+}
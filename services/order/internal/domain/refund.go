@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRefund = errors.New("invalid refund")
+	ErrRefundFailed  = errors.New("refund failed")
+)
+
+// Refund statuses. A refund is pending from the moment its amount is
+// reserved until the payment provider has returned the money.
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusCompleted = "completed"
+)
+
+// Refund is money returned for an order, in full or in part.
+// ProviderReference identifies the refund at the payment provider and is
+// empty for refunds recorded without one.
+type Refund struct {
+	Id                int64     `json:"id"`
+	OrderId           int64     `json:"order_id"`
+	Amount            float64   `json:"amount"`
+	Status            string    `json:"status"`
+	Reason            string    `json:"reason,omitempty"`
+	ProviderReference string    `json:"provider_reference,omitempty"`
+	ActorUserId       int64     `json:"actor_user_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// RefundRequest asks the payment provider to return Amount of the order's
+// captured payment, identified by ProviderPaymentId.
+type RefundRequest struct {
+	OrderId           int64
+	ProviderPaymentId string
+	Amount            float64
+	Reason            string
+}
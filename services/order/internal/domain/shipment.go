@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrShipmentNotFound        = errors.New("shipment not found")
+	ErrInvalidShipment         = errors.New("invalid shipment")
+	ErrInvalidTrackingEvent    = errors.New("invalid tracking event")
+	ErrDuplicateTrackingNumber = errors.New("tracking number already used")
+)
+
+// Shipment statuses. A shipment is recorded as shipped when its parcel is
+// handed to the carrier and then follows the carrier's tracking events until
+// it is delivered. Exception covers delays, failed delivery attempts and
+// returns; a later event can move the shipment on again.
+const (
+	ShipmentStatusShipped        = "shipped"
+	ShipmentStatusInTransit      = "in_transit"
+	ShipmentStatusOutForDelivery = "out_for_delivery"
+	ShipmentStatusDelivered      = "delivered"
+	ShipmentStatusException      = "exception"
+)
+
+// IsValidShipmentStatus reports whether status is one of the known shipment
+// statuses.
+func IsValidShipmentStatus(status string) bool {
+	switch status {
+	case ShipmentStatusShipped, ShipmentStatusInTransit, ShipmentStatusOutForDelivery,
+		ShipmentStatusDelivered, ShipmentStatusException:
+		return true
+	}
+	return false
+}
+
+// Shipment is a parcel sent for part or all of an order. Events are its
+// tracking history, oldest first; Status is the status of the latest one.
+type Shipment struct {
+	Id             int64           `json:"id"`
+	OrderId        int64           `json:"order_id"`
+	Carrier        string          `json:"carrier"`
+	TrackingNumber string          `json:"tracking_number"`
+	Status         string          `json:"status"`
+	Items          []ShipmentItem  `json:"items"`
+	Events         []TrackingEvent `json:"events"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// ShipmentItem is the part of an order line a shipment carries.
+type ShipmentItem struct {
+	ProductID int64  `json:"product_id"`
+	Variant   string `json:"variant,omitempty"`
+	Quantity  int32  `json:"quantity"`
+}
+
+// TrackingEvent is one step of a shipment's journey as reported by the
+// carrier. OccurredAt is when the carrier saw it happen; RecordedAt is when
+// it was stored.
+type TrackingEvent struct {
+	Id          int64     `json:"id"`
+	ShipmentId  int64     `json:"shipment_id"`
+	Status      string    `json:"status"`
+	Location    string    `json:"location,omitempty"`
+	Description string    `json:"description,omitempty"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	RecordedAt  time.Time `json:"recorded_at"`
+}
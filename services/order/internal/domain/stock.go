@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrOutOfStock        = errors.New("out of stock")
+	ErrInvalidStockLevel = errors.New("invalid stock level")
+)
+
+// StockLevel is the number of units of a product variant that can still be
+// ordered. Placing an order reserves units and cancelling or deleting it
+// releases them. A product without a stock level is not stock-tracked and
+// can always be ordered.
+type StockLevel struct {
+	ProductID int64     `json:"product_id"`
+	Variant   string    `json:"variant"`
+	Available int32     `json:"available"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"time"
+
+	"product-app/services/order/internal/domain"
+)
+
+// CartRepository stores carts with their item quantities. Carts are
+// returned unpriced; see domain.Cart.
+type CartRepository interface {
+	// GetOrCreateForUser returns the user's cart, creating an empty one
+	// the first time.
+	GetOrCreateForUser(userId int64) (domain.Cart, error)
+	// GetByToken returns an anonymous cart, expired or not, and fails with
+	// domain.ErrCartNotFound once it has been deleted.
+	GetByToken(token string) (domain.Cart, error)
+	CreateAnonymous(token string, expiresAt time.Time) (domain.Cart, error)
+	// AddItem adds the item's quantity to the cart's line for the same
+	// product and variant, or adds the line. The cart's expiry is set to
+	// cart.ExpiresAt.
+	AddItem(cart domain.Cart, item domain.CartItem) error
+	// SetItemQuantity replaces the quantity of a line, removing it at zero,
+	// and fails with domain.ErrCartItemNotFound for a missing line.
+	SetItemQuantity(cart domain.Cart, productId int64, variant string, quantity int32) error
+	// Merge moves the lines of the cart fromCartId into intoCartId, adding
+	// up the quantities of lines both have, and deletes fromCartId.
+	Merge(fromCartId, intoCartId int64) error
+	// Checkout stores order, reserving its stock, and empties the cart in
+	// one transaction. It fails with domain.ErrCartChanged when the cart's
+	// lines differ from cart.Items and with domain.ErrOutOfStock when a
+	// product has too few units left.
+	Checkout(cart domain.Cart, order domain.Order, events OrderEvents) (domain.Order, error)
+	// DeleteExpired deletes the anonymous carts that expired before before.
+	DeleteExpired(before time.Time) (int64, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"product-app/services/order/internal/domain"
+	"product-app/shared/kafka"
+)
+
+// OrderEvents returns the events describing an order change as stored.
+// Repositories write them to the outbox in the transaction of the change,
+// so they are published if and only if the change commits. A nil factory
+// writes no events.
+type OrderEvents func(order domain.Order) []kafka.Event
+
+// RefundEvents returns the events describing a stored refund; see
+// OrderEvents.
+type RefundEvents func(refund domain.Refund) []kafka.Event
+
+// ShipmentEvents returns the events describing a shipment change as stored;
+// see OrderEvents.
+type ShipmentEvents func(shipment domain.Shipment) []kafka.Event
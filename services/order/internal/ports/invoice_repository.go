@@ -0,0 +1,22 @@
+package ports
+
+import "product-app/services/order/internal/domain"
+
+// InvoiceRepository stores issued invoices and credit notes. There is no
+// way to change or delete one.
+type InvoiceRepository interface {
+	// Issue numbers and stores a document, filling in its Id, Year,
+	// Sequence and Number. The number is taken from the kind's sequence of
+	// the year of invoice.IssuedAt in the same transaction, so a failed
+	// issue leaves no gap. It fails with domain.ErrInvoiceAlreadyIssued
+	// when the order already has an invoice. A credit note is checked
+	// against the invoice and earlier credit notes with the invoice locked,
+	// and fails with domain.ErrInvalidCreditNote if it credits more units
+	// of a line than are left.
+	Issue(invoice domain.Invoice) (domain.Invoice, error)
+	// GetInvoice returns the invoice of an order, or
+	// domain.ErrInvoiceNotFound.
+	GetInvoice(orderId int64) (domain.Invoice, error)
+	// GetCreditNotes returns the credit notes of an order, oldest first.
+	GetCreditNotes(orderId int64) ([]domain.Invoice, error)
+}
@@ -3,9 +3,29 @@ package ports
 import "product-app/services/order/internal/domain"
 
 type OrderRepository interface {
-	GetAll() ([]domain.Order, error)
+	// Find returns up to query.Limit orders matching query, in its sort
+	// order.
+	Find(query domain.OrderQuery) ([]domain.Order, error)
 	GetById(id int64) (domain.Order, error)
-	GetByCustomerNumber(customerNumber string) ([]domain.Order, error)
-	Create(order domain.Order) (domain.Order, error)
-	Delete(id int64) error
+	Create(order domain.Order, events OrderEvents) (domain.Order, error)
+	// Delete removes the order and writes the events for the order as it
+	// was before.
+	Delete(id int64, events OrderEvents) (domain.Order, error)
+	// UpdateStatus moves the order to change.ToStatus only if it is still in
+	// change.FromStatus, records change in its history and returns the
+	// updated order. It fails with domain.ErrIllegalTransition when the
+	// status changed in the meantime.
+	UpdateStatus(id int64, change domain.OrderStatusChange, events OrderEvents) (domain.Order, error)
+	GetStatusHistory(id int64) ([]domain.OrderStatusChange, error)
+	// ReserveRefund records refund as pending and adds its amount to the
+	// order's refunded total. It fails with domain.ErrInvalidRefund when the
+	// refund would take the refunded total above the grand total.
+	ReserveRefund(refund domain.Refund) (domain.Refund, error)
+	// CompleteRefund marks a pending refund completed with its provider
+	// reference and writes its events.
+	CompleteRefund(refund domain.Refund, events RefundEvents) (domain.Refund, error)
+	// ReleaseRefund removes a pending refund and takes its amount off the
+	// order's refunded total again.
+	ReleaseRefund(refund domain.Refund) error
+	GetRefunds(orderId int64) ([]domain.Refund, error)
 }
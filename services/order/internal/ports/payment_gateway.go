@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	"product-app/services/order/internal/domain"
+)
+
+// PaymentGateway moves money at a payment provider. Each provider has its
+// own adapter; the service only sees these calls and the events returned by
+// ParseWebhook.
+//
+// CreatePaymentIntent and Capture fail with domain.ErrPaymentDeclined when
+// the provider refuses and with domain.ErrPaymentUnavailable when it cannot
+// be reached. Refund returns the provider's reference for the refund and
+// fails with domain.ErrRefundFailed.
+type PaymentGateway interface {
+	// Provider names the provider, e.g. "fake".
+	Provider() string
+	CreatePaymentIntent(ctx context.Context, request domain.PaymentIntentRequest) (domain.PaymentIntent, error)
+	Capture(ctx context.Context, providerPaymentId string, amount float64) error
+	Refund(ctx context.Context, request domain.RefundRequest) (string, error)
+	// ParseWebhook verifies the signature of a webhook call from its body
+	// and headers and decodes it. It fails with
+	// domain.ErrInvalidWebhookSignature when the call was not signed by the
+	// provider.
+	ParseWebhook(payload []byte, header http.Header) (domain.PaymentEvent, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import "product-app/services/order/internal/domain"
+
+type PaymentRepository interface {
+	Create(payment domain.Payment) (domain.Payment, error)
+	GetByProviderPaymentId(provider, providerPaymentId string) (domain.Payment, error)
+	GetByOrderId(orderId int64) ([]domain.Payment, error)
+	// UpdateStatus moves the payment to status only if it is still in one of
+	// from, and fails with domain.ErrIllegalTransition otherwise.
+	UpdateStatus(id int64, from []string, status, failureReason string) (domain.Payment, error)
+	// ReserveWebhookEvent records that the provider's event id is being
+	// processed. It fails with domain.ErrDuplicateWebhookEvent when the
+	// event was recorded before.
+	ReserveWebhookEvent(provider string, event domain.PaymentEvent) error
+	// ReleaseWebhookEvent forgets an event whose processing failed, so the
+	// provider's retry is processed again.
+	ReleaseWebhookEvent(provider, eventId string) error
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"product-app/services/order/internal/domain"
+)
+
+// ProductCatalog looks up the products being ordered. GetProduct fails with
+// domain.ErrProductNotFound for unknown products and with
+// domain.ErrCatalogUnavailable when the catalog cannot answer.
+type ProductCatalog interface {
+	GetProduct(ctx context.Context, id int64) (domain.CatalogProduct, error)
+}
@@ -0,0 +1,25 @@
+package ports
+
+import "product-app/services/order/internal/domain"
+
+type ShipmentRepository interface {
+	// Create stores a shipment with its items and first tracking event,
+	// filling in the ids and timestamps. The order is locked while its
+	// status and shipped quantities are checked, and Create fails with
+	// domain.ErrIllegalTransition when the order is not paid, with
+	// domain.ErrInvalidShipment when an item exceeds what is left unshipped
+	// of its order line, or with domain.ErrDuplicateTrackingNumber when the
+	// carrier's tracking number is already recorded. This check is the
+	// authoritative one; the service checks the quantities beforehand only
+	// to answer early, and a concurrent shipment can slip past that.
+	Create(shipment domain.Shipment, events ShipmentEvents) (domain.Shipment, error)
+	// GetById returns a shipment with its items and events, or
+	// domain.ErrShipmentNotFound.
+	GetById(id int64) (domain.Shipment, error)
+	// GetByOrderId returns the shipments of an order, oldest first.
+	GetByOrderId(orderId int64) ([]domain.Shipment, error)
+	// AddEvent appends a tracking event and moves the shipment to its
+	// status. A delivered shipment takes no more events; AddEvent then fails
+	// with domain.ErrIllegalTransition.
+	AddEvent(event domain.TrackingEvent, events ShipmentEvents) (domain.Shipment, error)
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "product-app/services/order/internal/domain"
+
+// StockRepository sets and reads stock levels. Reserving and releasing
+// stock is part of storing and cancelling orders.
+type StockRepository interface {
+	Set(level domain.StockLevel) (domain.StockLevel, error)
+	GetByProductId(productId int64) ([]domain.StockLevel, error)
+}
@@ -0,0 +1,306 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"product-app/services/order/internal/usecase/model"
+	"time"
+)
+
+// MaxCartQuantity caps the quantity of a single cart line.
+const MaxCartQuantity = 999
+
+type ICartService interface {
+	Get(owner model.CartOwner) (domain.Cart, error)
+	AddItem(owner model.CartOwner, item model.CartItemCreate) (domain.Cart, error)
+	UpdateItem(owner model.CartOwner, productId int64, update model.CartItemUpdate) (domain.Cart, error)
+	RemoveItem(owner model.CartOwner, productId int64, variant string) (domain.Cart, error)
+	// Checkout turns the signed-in user's cart into an order, reserving its
+	// stock and emptying the cart.
+	Checkout(owner model.CartOwner) (domain.Order, error)
+	// PruneExpired deletes anonymous carts that expired one TTL ago or
+	// earlier; until then they can still be merged at sign-in.
+	PruneExpired() (int64, error)
+	// StartPruning prunes every interval until ctx is cancelled.
+	StartPruning(ctx context.Context, interval time.Duration)
+}
+
+// CartService keeps carts and prices them from the product catalog on every
+// read. A request carrying both a user and an anonymous cart token merges
+// the anonymous cart into the user's, which is how a visitor's cart
+// survives signing in.
+type CartService struct {
+	cartRepository ports.CartRepository
+	productCatalog ports.ProductCatalog
+	anonymousTTL   time.Duration
+}
+
+// NewCartService wires the service. anonymousTTL is how long an anonymous
+// cart lives after its last change. productCatalog may be nil; without a
+// catalog carts are not priced.
+func NewCartService(cartRepository ports.CartRepository, productCatalog ports.ProductCatalog, anonymousTTL time.Duration) ICartService {
+	return &CartService{
+		cartRepository: cartRepository,
+		productCatalog: productCatalog,
+		anonymousTTL:   anonymousTTL,
+	}
+}
+
+// Get implements [ICartService]. A visitor without a cart gets an empty,
+// unsaved one.
+func (s *CartService) Get(owner model.CartOwner) (domain.Cart, error) {
+	cart, err := s.find(owner, false)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return s.price(cart)
+}
+
+// AddItem implements [ICartService]. A visitor without a cart gets a new
+// anonymous cart, whose token is returned with it.
+func (s *CartService) AddItem(owner model.CartOwner, item model.CartItemCreate) (domain.Cart, error) {
+	if item.ProductID <= 0 {
+		return domain.Cart{}, fmt.Errorf("%w: product id must be a positive integer", domain.ErrInvalidCartItem)
+	}
+	if item.Quantity <= 0 || item.Quantity > MaxCartQuantity {
+		return domain.Cart{}, fmt.Errorf("%w: quantity must be between 1 and %d", domain.ErrInvalidCartItem, MaxCartQuantity)
+	}
+	if s.productCatalog != nil {
+		if _, err := s.productCatalog.GetProduct(context.Background(), item.ProductID); err != nil {
+			return domain.Cart{}, err
+		}
+	}
+
+	cart, err := s.find(owner, true)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	existing, found := findCartItem(cart, item.ProductID, item.Variant)
+	if !found && len(cart.Items) >= MaxOrderItems {
+		return domain.Cart{}, fmt.Errorf("%w: a cart cannot hold more than %d lines", domain.ErrInvalidCartItem, MaxOrderItems)
+	}
+	if existing.Quantity+item.Quantity > MaxCartQuantity {
+		return domain.Cart{}, fmt.Errorf("%w: quantity must be between 1 and %d", domain.ErrInvalidCartItem, MaxCartQuantity)
+	}
+
+	err = s.cartRepository.AddItem(s.touched(cart), domain.CartItem{
+		ProductID: item.ProductID,
+		Variant:   item.Variant,
+		Quantity:  item.Quantity,
+	})
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return s.reload(cart)
+}
+
+// UpdateItem implements [ICartService].
+func (s *CartService) UpdateItem(owner model.CartOwner, productId int64, update model.CartItemUpdate) (domain.Cart, error) {
+	if update.Quantity < 0 || update.Quantity > MaxCartQuantity {
+		return domain.Cart{}, fmt.Errorf("%w: quantity must be between 0 and %d", domain.ErrInvalidCartItem, MaxCartQuantity)
+	}
+	cart, err := s.find(owner, false)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	if cart.Id == 0 {
+		return domain.Cart{}, fmt.Errorf("%w: product %d", domain.ErrCartItemNotFound, productId)
+	}
+	if err := s.cartRepository.SetItemQuantity(s.touched(cart), productId, update.Variant, update.Quantity); err != nil {
+		return domain.Cart{}, err
+	}
+	return s.reload(cart)
+}
+
+// RemoveItem implements [ICartService].
+func (s *CartService) RemoveItem(owner model.CartOwner, productId int64, variant string) (domain.Cart, error) {
+	return s.UpdateItem(owner, productId, model.CartItemUpdate{Variant: variant})
+}
+
+// Checkout implements [ICartService]. The cart is priced as the customer
+// last saw it; if it changes before the order is stored the checkout fails
+// with domain.ErrCartChanged and can be retried.
+func (s *CartService) Checkout(owner model.CartOwner) (domain.Order, error) {
+	if owner.UserId <= 0 {
+		return domain.Order{}, errors.New("orders can only be placed by a signed-in user")
+	}
+	cart, err := s.find(owner, false)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	priced, err := s.price(cart)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if len(priced.Items) == 0 {
+		return domain.Order{}, domain.ErrCartEmpty
+	}
+
+	orderCreate := model.OrderCreate{Items: make([]model.OrderItemCreate, 0, len(priced.Items))}
+	for _, item := range priced.Items {
+		if item.Unavailable {
+			return domain.Order{}, fmt.Errorf("%w: product %d is no longer available", domain.ErrInvalidCartItem, item.ProductID)
+		}
+		orderCreate.Items = append(orderCreate.Items, model.OrderItemCreate{
+			ProductID: item.ProductID,
+			Variant:   item.Variant,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Discount:  item.Discount,
+		})
+	}
+	if err := validateOrder(orderCreate); err != nil {
+		return domain.Order{}, fmt.Errorf("%w: %v", domain.ErrInvalidCartItem, err)
+	}
+
+	return s.cartRepository.Checkout(cart, buildOrder(orderCreate, owner.UserId), orderCreatedEvents)
+}
+
+// PruneExpired implements [ICartService].
+func (s *CartService) PruneExpired() (int64, error) {
+	return s.cartRepository.DeleteExpired(time.Now().UTC().Add(-s.anonymousTTL))
+}
+
+// StartPruning implements [ICartService].
+func (s *CartService) StartPruning(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if deleted, err := s.PruneExpired(); err != nil {
+			log.Printf("cart prune failed: %v", err)
+		} else if deleted > 0 {
+			log.Printf("pruned %d expired cart(s)", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// find returns owner's cart. A signed-in owner's anonymous cart is merged
+// into the user's cart first, expired or not. An anonymous owner whose cart
+// is gone or expired gets a new cart if create is set and an empty,
+// unsaved one otherwise.
+func (s *CartService) find(owner model.CartOwner, create bool) (domain.Cart, error) {
+	if owner.UserId > 0 {
+		cart, err := s.cartRepository.GetOrCreateForUser(owner.UserId)
+		if err != nil || owner.Token == "" {
+			return cart, err
+		}
+		anonymous, err := s.cartRepository.GetByToken(owner.Token)
+		if errors.Is(err, domain.ErrCartNotFound) {
+			return cart, nil
+		}
+		if err != nil {
+			return domain.Cart{}, err
+		}
+		if err := s.cartRepository.Merge(anonymous.Id, cart.Id); err != nil && !errors.Is(err, domain.ErrCartNotFound) {
+			return domain.Cart{}, err
+		}
+		return s.cartRepository.GetOrCreateForUser(owner.UserId)
+	}
+
+	if owner.Token != "" {
+		cart, err := s.cartRepository.GetByToken(owner.Token)
+		if err == nil && (cart.ExpiresAt == nil || cart.ExpiresAt.After(time.Now())) {
+			return cart, nil
+		}
+		if err != nil && !errors.Is(err, domain.ErrCartNotFound) {
+			return domain.Cart{}, err
+		}
+	}
+	if !create {
+		return domain.Cart{Items: []domain.CartItem{}}, nil
+	}
+	token, err := newCartToken()
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return s.cartRepository.CreateAnonymous(token, time.Now().UTC().Add(s.anonymousTTL))
+}
+
+// reload reads cart again after a change and prices it.
+func (s *CartService) reload(cart domain.Cart) (domain.Cart, error) {
+	var err error
+	if cart.IsAnonymous() {
+		cart, err = s.cartRepository.GetByToken(cart.Token)
+	} else {
+		cart, err = s.cartRepository.GetOrCreateForUser(cart.UserId)
+	}
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return s.price(cart)
+}
+
+// touched moves the expiry of an anonymous cart that is being changed.
+func (s *CartService) touched(cart domain.Cart) domain.Cart {
+	if cart.IsAnonymous() {
+		expiresAt := time.Now().UTC().Add(s.anonymousTTL)
+		cart.ExpiresAt = &expiresAt
+	}
+	return cart
+}
+
+// price fills in the current name, price and discount of every line and
+// the cart totals. Lines of products the catalog no longer has are marked
+// unavailable and left out of the totals.
+func (s *CartService) price(cart domain.Cart) (domain.Cart, error) {
+	if s.productCatalog == nil {
+		return cart, nil
+	}
+	ctx := context.Background()
+	items := make([]domain.CartItem, len(cart.Items))
+	var subtotal, discountTotal float64
+	for i, item := range cart.Items {
+		product, err := s.productCatalog.GetProduct(ctx, item.ProductID)
+		if errors.Is(err, domain.ErrProductNotFound) {
+			item.Unavailable = true
+			items[i] = item
+			continue
+		}
+		if err != nil {
+			return domain.Cart{}, err
+		}
+		item.Name = product.Name
+		item.UnitPrice = roundMoney(product.Price)
+		gross := roundMoney(float64(item.Quantity) * item.UnitPrice)
+		item.Discount = roundMoney(float64(item.Quantity) * item.UnitPrice * product.Discount / 100)
+		item.LineTotal = roundMoney(gross - item.Discount)
+		items[i] = item
+		subtotal += gross
+		discountTotal += item.Discount
+	}
+	cart.Items = items
+	cart.Subtotal = roundMoney(subtotal)
+	cart.DiscountTotal = roundMoney(discountTotal)
+	cart.GrandTotal = roundMoney(cart.Subtotal - cart.DiscountTotal)
+	return cart, nil
+}
+
+func findCartItem(cart domain.Cart, productId int64, variant string) (domain.CartItem, bool) {
+	for _, item := range cart.Items {
+		if item.ProductID == productId && item.Variant == variant {
+			return item, true
+		}
+	}
+	return domain.CartItem{}, false
+}
+
+func newCartToken() (string, error) {
+	buffer := make([]byte, 24)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", fmt.Errorf("failed to generate cart token: %w", err)
+	}
+	return hex.EncodeToString(buffer), nil
+}
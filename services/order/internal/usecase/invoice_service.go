@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"product-app/services/order/internal/usecase/model"
+	"strings"
+	"time"
+)
+
+type IInvoiceService interface {
+	// GetInvoice returns the invoice of an order, issuing it the first time
+	// it is asked for. Orders are invoiced once they are paid.
+	GetInvoice(orderId int64, requester model.Requester) (domain.Invoice, error)
+	// IssueCreditNote credits invoiced items. Only admins may issue credit
+	// notes.
+	IssueCreditNote(orderId int64, creditNote model.CreditNoteCreate, requester model.Requester) (domain.Invoice, error)
+	GetCreditNotes(orderId int64, requester model.Requester) ([]domain.Invoice, error)
+	GetCreditNote(orderId int64, number string, requester model.Requester) (domain.Invoice, error)
+}
+
+// InvoiceSettings are the seller details, currency and tax rate printed on
+// new invoices. TaxRate is a percentage and is included in the order
+// prices.
+type InvoiceSettings struct {
+	Seller   domain.InvoiceParty
+	Currency string
+	TaxRate  float64
+}
+
+// InvoiceService issues the invoice of a paid order and the credit notes
+// correcting it. Issued documents are never changed; each one keeps the
+// seller details, product names and tax rate it was issued with.
+type InvoiceService struct {
+	orderService      IOrderService
+	invoiceRepository ports.InvoiceRepository
+	productCatalog    ports.ProductCatalog
+	settings          InvoiceSettings
+}
+
+// NewInvoiceService wires the service. productCatalog may be nil; invoice
+// lines then describe products by id.
+func NewInvoiceService(orderService IOrderService, invoiceRepository ports.InvoiceRepository, productCatalog ports.ProductCatalog, settings InvoiceSettings) IInvoiceService {
+	return &InvoiceService{
+		orderService:      orderService,
+		invoiceRepository: invoiceRepository,
+		productCatalog:    productCatalog,
+		settings:          settings,
+	}
+}
+
+// GetInvoice implements [IInvoiceService].
+func (s *InvoiceService) GetInvoice(orderId int64, requester model.Requester) (domain.Invoice, error) {
+	order, err := s.orderService.GetById(orderId, requester)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	invoice, err := s.invoiceRepository.GetInvoice(orderId)
+	if !errors.Is(err, domain.ErrInvoiceNotFound) {
+		return invoice, err
+	}
+	if !isInvoiceable(order.Status) {
+		return domain.Invoice{}, fmt.Errorf("%w: order %d is %s", domain.ErrInvoiceNotAvailable, orderId, order.Status)
+	}
+
+	invoice, err = s.buildInvoice(order)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	issued, err := s.invoiceRepository.Issue(invoice)
+	if errors.Is(err, domain.ErrInvoiceAlreadyIssued) {
+		return s.invoiceRepository.GetInvoice(orderId)
+	}
+	return issued, err
+}
+
+// IssueCreditNote implements [IInvoiceService]. A line cannot be credited
+// beyond what was invoiced; crediting the last units of a line credits
+// whatever amount of it is left, so rounding never leaves a remainder. The
+// repository checks the quantities again when issuing, so concurrent credit
+// notes cannot credit the same units twice.
+func (s *InvoiceService) IssueCreditNote(orderId int64, creditNote model.CreditNoteCreate, requester model.Requester) (domain.Invoice, error) {
+	if !requester.Admin {
+		return domain.Invoice{}, fmt.Errorf("%w: only admins can issue credit notes", domain.ErrOrderForbidden)
+	}
+	reason := strings.TrimSpace(creditNote.Reason)
+	if reason == "" {
+		return domain.Invoice{}, fmt.Errorf("%w: reason is required", domain.ErrInvalidCreditNote)
+	}
+	invoice, err := s.invoiceRepository.GetInvoice(orderId)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	creditNotes, err := s.invoiceRepository.GetCreditNotes(orderId)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+
+	remaining := remainingLines(invoice, creditNotes)
+	items := creditNote.Items
+	if len(items) == 0 {
+		for _, line := range remaining {
+			if line.Quantity > 0 {
+				items = append(items, model.CreditNoteItem{ProductID: line.ProductID, Variant: line.Variant, Quantity: line.Quantity})
+			}
+		}
+		if len(items) == 0 {
+			return domain.Invoice{}, fmt.Errorf("%w: invoice %s is fully credited", domain.ErrInvalidCreditNote, invoice.Number)
+		}
+	}
+
+	lines := make([]domain.InvoiceLine, 0, len(items))
+	for _, item := range items {
+		i := findInvoiceLine(invoice.Lines, item.ProductID, item.Variant)
+		if i < 0 {
+			return domain.Invoice{}, fmt.Errorf("%w: product %d is not on invoice %s", domain.ErrInvalidCreditNote, item.ProductID, invoice.Number)
+		}
+		if item.Quantity <= 0 || item.Quantity > remaining[i].Quantity {
+			return domain.Invoice{}, fmt.Errorf("%w: product %d can be credited 1 to %d times", domain.ErrInvalidCreditNote, item.ProductID, remaining[i].Quantity)
+		}
+		credit := creditLine(invoice.Lines[i], remaining[i], item.Quantity)
+		remaining[i] = addInvoiceLine(remaining[i], credit)
+		lines = append(lines, credit)
+	}
+
+	return s.invoiceRepository.Issue(withInvoiceTotals(domain.Invoice{
+		Kind:           domain.InvoiceKindCreditNote,
+		OrderId:        orderId,
+		CorrectsNumber: invoice.Number,
+		Reason:         reason,
+		Currency:       invoice.Currency,
+		Seller:         invoice.Seller,
+		Customer:       invoice.Customer,
+		Lines:          lines,
+		IssuedAt:       time.Now().UTC(),
+	}))
+}
+
+// GetCreditNotes implements [IInvoiceService].
+func (s *InvoiceService) GetCreditNotes(orderId int64, requester model.Requester) ([]domain.Invoice, error) {
+	if _, err := s.orderService.GetById(orderId, requester); err != nil {
+		return nil, err
+	}
+	return s.invoiceRepository.GetCreditNotes(orderId)
+}
+
+// GetCreditNote implements [IInvoiceService].
+func (s *InvoiceService) GetCreditNote(orderId int64, number string, requester model.Requester) (domain.Invoice, error) {
+	creditNotes, err := s.GetCreditNotes(orderId, requester)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	for _, creditNote := range creditNotes {
+		if creditNote.Number == number {
+			return creditNote, nil
+		}
+	}
+	return domain.Invoice{}, fmt.Errorf("%w: credit note %s of order %d", domain.ErrInvoiceNotFound, number, orderId)
+}
+
+// buildInvoice describes order's items from the catalog and splits their
+// totals into net amount and tax.
+func (s *InvoiceService) buildInvoice(order domain.Order) (domain.Invoice, error) {
+	lines := make([]domain.InvoiceLine, 0, len(order.Items))
+	for _, item := range order.Items {
+		line := domain.InvoiceLine{
+			ProductID:   item.ProductID,
+			Variant:     item.Variant,
+			Description: fmt.Sprintf("Product #%d", item.ProductID),
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Discount:    item.Discount,
+			TaxRate:     s.settings.TaxRate,
+			Total:       item.LineTotal,
+		}
+		if s.productCatalog != nil {
+			product, err := s.productCatalog.GetProduct(context.Background(), item.ProductID)
+			if err != nil && !errors.Is(err, domain.ErrProductNotFound) {
+				return domain.Invoice{}, err
+			}
+			if err == nil {
+				line.Description = product.Name
+				line.Store = product.Store
+				line.StoreSlug = product.StoreSlug
+			}
+		}
+		line.NetAmount = roundMoney(line.Total / (1 + line.TaxRate/100))
+		line.TaxAmount = roundMoney(line.Total - line.NetAmount)
+		lines = append(lines, line)
+	}
+
+	return withInvoiceTotals(domain.Invoice{
+		Kind:     domain.InvoiceKindInvoice,
+		OrderId:  order.Id,
+		Currency: s.settings.Currency,
+		Seller:   s.settings.Seller,
+		Customer: domain.InvoiceParty{Name: order.CustomerNumber},
+		Lines:    lines,
+		IssuedAt: time.Now().UTC(),
+	}), nil
+}
+
+func isInvoiceable(status string) bool {
+	return status == domain.OrderStatusPaid || status == domain.OrderStatusShipped || status == domain.OrderStatusDelivered
+}
+
+// withInvoiceTotals fills in the tax breakdown, one entry per rate in line
+// order, and the totals of invoice's lines.
+func withInvoiceTotals(invoice domain.Invoice) domain.Invoice {
+	invoice.Taxes = []domain.InvoiceTax{}
+	var netTotal, taxTotal, grandTotal float64
+	for _, line := range invoice.Lines {
+		i := 0
+		for i < len(invoice.Taxes) && invoice.Taxes[i].Rate != line.TaxRate {
+			i++
+		}
+		if i == len(invoice.Taxes) {
+			invoice.Taxes = append(invoice.Taxes, domain.InvoiceTax{Rate: line.TaxRate})
+		}
+		invoice.Taxes[i].NetAmount = roundMoney(invoice.Taxes[i].NetAmount + line.NetAmount)
+		invoice.Taxes[i].TaxAmount = roundMoney(invoice.Taxes[i].TaxAmount + line.TaxAmount)
+		invoice.Taxes[i].Total = roundMoney(invoice.Taxes[i].Total + line.Total)
+		netTotal += line.NetAmount
+		taxTotal += line.TaxAmount
+		grandTotal += line.Total
+	}
+	invoice.NetTotal = roundMoney(netTotal)
+	invoice.TaxTotal = roundMoney(taxTotal)
+	invoice.GrandTotal = roundMoney(grandTotal)
+	return invoice
+}
+
+// remainingLines returns invoice's lines less what creditNotes credited,
+// in the same order.
+func remainingLines(invoice domain.Invoice, creditNotes []domain.Invoice) []domain.InvoiceLine {
+	remaining := append([]domain.InvoiceLine{}, invoice.Lines...)
+	for _, creditNote := range creditNotes {
+		for _, credit := range creditNote.Lines {
+			if i := findInvoiceLine(remaining, credit.ProductID, credit.Variant); i >= 0 {
+				remaining[i] = addInvoiceLine(remaining[i], credit)
+			}
+		}
+	}
+	return remaining
+}
+
+// creditLine credits quantity units of invoiced, of which remaining are
+// still uncredited.
+func creditLine(invoiced, remaining domain.InvoiceLine, quantity int32) domain.InvoiceLine {
+	credit := invoiced
+	if quantity == remaining.Quantity {
+		credit.Discount, credit.NetAmount, credit.TaxAmount, credit.Total =
+			remaining.Discount, remaining.NetAmount, remaining.TaxAmount, remaining.Total
+	} else {
+		share := float64(quantity) / float64(invoiced.Quantity)
+		credit.Discount = roundMoney(invoiced.Discount * share)
+		credit.Total = roundMoney(invoiced.Total * share)
+		credit.NetAmount = roundMoney(credit.Total / (1 + invoiced.TaxRate/100))
+		credit.TaxAmount = roundMoney(credit.Total - credit.NetAmount)
+	}
+	credit.Quantity = -quantity
+	credit.Discount = -credit.Discount
+	credit.NetAmount = -credit.NetAmount
+	credit.TaxAmount = -credit.TaxAmount
+	credit.Total = -credit.Total
+	return credit
+}
+
+func addInvoiceLine(line, other domain.InvoiceLine) domain.InvoiceLine {
+	line.Quantity += other.Quantity
+	line.Discount = roundMoney(line.Discount + other.Discount)
+	line.NetAmount = roundMoney(line.NetAmount + other.NetAmount)
+	line.TaxAmount = roundMoney(line.TaxAmount + other.TaxAmount)
+	line.Total = roundMoney(line.Total + other.Total)
+	return line
+}
+
+func findInvoiceLine(lines []domain.InvoiceLine, productId int64, variant string) int {
+	for i, line := range lines {
+		if line.ProductID == productId && line.Variant == variant {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,124 @@
+package model
+
+import "time"
+
+// OrderCreate is the body of a create request. The owner and customer
+// number come from the caller's token.
+type OrderCreate struct {
+	Items []OrderItemCreate `json:"items"`
+}
+
+// OrderSearch holds the filters, sort and page of an order list request.
+// Sort is a domain.OrderSort* key, prefixed with "-" for descending order;
+// Cursor is the NextCursor of the previous page.
+type OrderSearch struct {
+	From           time.Time
+	To             time.Time
+	Status         string
+	ProductId      int64
+	CustomerNumber string
+	Sort           string
+	Limit          int
+	Cursor         string
+}
+
+// Requester is the authenticated caller of a read.
+type Requester struct {
+	UserId int64
+	Admin  bool
+}
+
+type OrderTransition struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// OrderCancellation is the body of a cancel request. ReasonCode is one of
+// the domain.CancelReason* codes; Note is free text.
+type OrderCancellation struct {
+	ReasonCode string `json:"reason_code"`
+	Note       string `json:"note"`
+}
+
+// RefundCreate is the body of a refund request. A zero Amount refunds
+// whatever has not been refunded yet.
+type RefundCreate struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+
+type OrderItemCreate struct {
+	ProductID int64   `json:"product_id"`
+	Variant   string  `json:"variant"`
+	Quantity  int32   `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Discount  float64 `json:"discount"`
+}
+
+// PaymentStart is the body of a start-payment request. PaymentMethod is the
+// provider's token for the customer's card or wallet.
+type PaymentStart struct {
+	PaymentMethod string `json:"payment_method"`
+}
+
+// CartOwner identifies a cart: the signed-in user's, the anonymous cart
+// whose token the client holds, or both right after sign-in, when the
+// anonymous cart is merged into the user's.
+type CartOwner struct {
+	UserId int64
+	Token  string
+}
+
+type CartItemCreate struct {
+	ProductID int64  `json:"product_id"`
+	Variant   string `json:"variant"`
+	Quantity  int32  `json:"quantity"`
+}
+
+// CartItemUpdate sets the quantity of a cart line; zero removes it.
+type CartItemUpdate struct {
+	Variant  string `json:"variant"`
+	Quantity int32  `json:"quantity"`
+}
+
+type StockLevelUpdate struct {
+	Variant   string `json:"variant"`
+	Available int32  `json:"available"`
+}
+
+// CreditNoteCreate is the body of a credit-note request. Without Items the
+// credit note covers everything not credited yet.
+type CreditNoteCreate struct {
+	Reason string           `json:"reason"`
+	Items  []CreditNoteItem `json:"items"`
+}
+
+// CreditNoteItem credits Quantity units of an invoice line.
+type CreditNoteItem struct {
+	ProductID int64  `json:"product_id"`
+	Variant   string `json:"variant"`
+	Quantity  int32  `json:"quantity"`
+}
+
+// ShipmentCreate is the body of a create-shipment request. Without Items the
+// shipment carries everything not shipped yet.
+type ShipmentCreate struct {
+	Carrier        string               `json:"carrier"`
+	TrackingNumber string               `json:"tracking_number"`
+	Items          []ShipmentItemCreate `json:"items"`
+}
+
+type ShipmentItemCreate struct {
+	ProductID int64  `json:"product_id"`
+	Variant   string `json:"variant"`
+	Quantity  int32  `json:"quantity"`
+}
+
+// TrackingEventCreate is the body of a tracking-event request. Status is
+// one of the domain.ShipmentStatus* values; a zero OccurredAt means now.
+type TrackingEventCreate struct {
+	Status      string    `json:"status"`
+	Location    string    `json:"location"`
+	Description string    `json:"description"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
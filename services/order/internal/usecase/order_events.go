@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"time"
+
+	"product-app/services/order/internal/domain"
+	"product-app/shared/kafka"
+)
+
+// orderCreatedEvents is the ports.OrderEvents of a new order.
+func orderCreatedEvents(order domain.Order) []kafka.Event {
+	return []kafka.Event{orderCreatedEvent(order)}
+}
+
+func orderCreatedEvent(order domain.Order) kafka.OrderCreated {
+	return kafka.OrderCreated{
+		ID:             order.Id,
+		CustomerNumber: order.CustomerNumber,
+		Items:          orderLines(order.Items),
+		Subtotal:       order.Subtotal,
+		DiscountTotal:  order.DiscountTotal,
+		GrandTotal:     order.GrandTotal,
+		OrderTime:      order.OrderTime,
+	}
+}
+
+func orderCancelledEvent(order domain.Order, reasonCode string, cancelledAt time.Time) kafka.OrderCancelled {
+	return kafka.OrderCancelled{
+		ID:             order.Id,
+		CustomerNumber: order.CustomerNumber,
+		Items:          orderLines(order.Items),
+		Subtotal:       order.Subtotal,
+		DiscountTotal:  order.DiscountTotal,
+		GrandTotal:     order.GrandTotal,
+		OrderTime:      order.OrderTime,
+		ReasonCode:     reasonCode,
+		CancelledAt:    cancelledAt,
+	}
+}
+
+func orderStatusChangedEvent(order domain.Order, previousStatus, status string, changedAt time.Time) kafka.OrderStatusChanged {
+	return kafka.OrderStatusChanged{
+		ID:             order.Id,
+		CustomerNumber: order.CustomerNumber,
+		Items:          orderLines(order.Items),
+		Subtotal:       order.Subtotal,
+		DiscountTotal:  order.DiscountTotal,
+		GrandTotal:     order.GrandTotal,
+		OrderTime:      order.OrderTime,
+		PreviousStatus: previousStatus,
+		Status:         status,
+		ChangedAt:      changedAt,
+	}
+}
+
+func orderRefundedEvent(order domain.Order, refund domain.Refund) kafka.OrderRefunded {
+	return kafka.OrderRefunded{
+		ID:             order.Id,
+		CustomerNumber: order.CustomerNumber,
+		RefundID:       refund.Id,
+		Amount:         refund.Amount,
+		RefundedTotal:  order.RefundedTotal,
+		GrandTotal:     order.GrandTotal,
+		Reason:         refund.Reason,
+		RefundedAt:     refund.CreatedAt,
+	}
+}
+
+func orderLines(items []domain.OrderItem) []kafka.OrderLine {
+	lines := make([]kafka.OrderLine, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, kafka.OrderLine{
+			ProductID: item.ProductID,
+			Variant:   item.Variant,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Discount:  item.Discount,
+			LineTotal: item.LineTotal,
+		})
+	}
+	return lines
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase/model"
+)
+
+const (
+	// DefaultOrderPageSize is the page size of order lists without a limit.
+	DefaultOrderPageSize = 20
+	// MaxOrderPageSize caps the limit of order lists.
+	MaxOrderPageSize = 100
+	// DefaultOrderSort lists the newest orders first.
+	DefaultOrderSort = "-" + domain.OrderSortTime
+)
+
+// orderCursor is the decoded form of a page's NextCursor. It records the
+// sort it was issued for, so it cannot be replayed against another one.
+type orderCursor struct {
+	Sort       string    `json:"s"`
+	Id         int64     `json:"id"`
+	OrderTime  time.Time `json:"t"`
+	GrandTotal float64   `json:"g"`
+}
+
+// buildOrderQuery validates search and turns it into a repository query
+// that reads one order more than the page size, to tell whether another
+// page follows.
+func buildOrderQuery(search model.OrderSearch) (domain.OrderQuery, error) {
+	query := domain.OrderQuery{
+		From:           search.From.UTC(),
+		To:             search.To.UTC(),
+		Status:         search.Status,
+		ProductId:      search.ProductId,
+		CustomerNumber: search.CustomerNumber,
+		Limit:          search.Limit,
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return domain.OrderQuery{}, fmt.Errorf("%w: from must be before to", domain.ErrInvalidOrderQuery)
+	}
+	if query.Status != "" && !domain.IsValidOrderStatus(query.Status) {
+		return domain.OrderQuery{}, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidOrderQuery, query.Status)
+	}
+	if query.ProductId < 0 {
+		return domain.OrderQuery{}, fmt.Errorf("%w: product id must be a positive integer", domain.ErrInvalidOrderQuery)
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DefaultOrderPageSize
+	}
+	if query.Limit < 0 || query.Limit > MaxOrderPageSize {
+		return domain.OrderQuery{}, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidOrderQuery, MaxOrderPageSize)
+	}
+	query.Limit++
+
+	sort := search.Sort
+	if sort == "" {
+		sort = DefaultOrderSort
+	}
+	query.SortBy = strings.TrimPrefix(sort, "-")
+	query.Descending = strings.HasPrefix(sort, "-")
+	if query.SortBy != domain.OrderSortTime && query.SortBy != domain.OrderSortTotal {
+		return domain.OrderQuery{}, fmt.Errorf("%w: cannot sort by %q", domain.ErrInvalidOrderQuery, search.Sort)
+	}
+
+	if search.Cursor != "" {
+		cursor, err := decodeOrderCursor(search.Cursor)
+		if err != nil || cursor.Sort != sort {
+			return domain.OrderQuery{}, fmt.Errorf("%w: cursor does not belong to this sort", domain.ErrInvalidOrderQuery)
+		}
+		query.After = &domain.OrderCursor{Id: cursor.Id, OrderTime: cursor.OrderTime, GrandTotal: cursor.GrandTotal}
+	}
+	return query, nil
+}
+
+// orderPage trims the extra order read by a query built with
+// buildOrderQuery and issues the cursor of the next page if there is one.
+func orderPage(orders []domain.Order, query domain.OrderQuery) domain.OrderPage {
+	pageSize := query.Limit - 1
+	if len(orders) <= pageSize {
+		return domain.OrderPage{Orders: orders}
+	}
+	orders = orders[:pageSize]
+	last := orders[len(orders)-1]
+	sort := query.SortBy
+	if query.Descending {
+		sort = "-" + sort
+	}
+	return domain.OrderPage{
+		Orders: orders,
+		NextCursor: encodeOrderCursor(orderCursor{
+			Sort:       sort,
+			Id:         last.Id,
+			OrderTime:  last.OrderTime,
+			GrandTotal: last.GrandTotal,
+		}),
+	}
+}
+
+func encodeOrderCursor(cursor orderCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeOrderCursor(encoded string) (orderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return orderCursor{}, err
+	}
+	var cursor orderCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return orderCursor{}, err
+	}
+	return cursor, nil
+}
@@ -1,66 +1,519 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"math"
 	"product-app/services/order/internal/domain"
 	"product-app/services/order/internal/ports"
+	"product-app/services/order/internal/usecase/model"
+	"product-app/shared/kafka"
+	"time"
 )
 
+// MaxOrderItems caps the number of lines a single order may have.
+const MaxOrderItems = 100
+
 type IOrderService interface {
-	GetAll() ([]domain.Order, error)
-	GetById(id int64) (domain.Order, error)
-	GetByCustomerNumber(customerNumber string) ([]domain.Order, error)
-	Create(order domain.Order) (domain.Order, error)
-	Delete(id int64) error
+	GetAll(search model.OrderSearch, requester model.Requester) (domain.OrderPage, error)
+	GetById(id int64, requester model.Requester) (domain.Order, error)
+	GetByCustomerNumber(customerNumber string, search model.OrderSearch, requester model.Requester) (domain.OrderPage, error)
+	GetByUserId(userId int64, search model.OrderSearch) (domain.OrderPage, error)
+	Create(order model.OrderCreate, userId int64) (domain.Order, error)
+	Delete(id int64, requester model.Requester) error
+	// Transition moves an order along the state machine on behalf of
+	// requester. Only admins may transition orders.
+	Transition(id int64, transition model.OrderTransition, requester model.Requester) (domain.Order, error)
+	// ApplyTransition moves an order along the state machine for the
+	// service's own flows, such as payment webhooks and shipment tracking.
+	// It checks no permissions and must not be reachable from the API. It
+	// cannot cancel; that goes through Cancel.
+	ApplyTransition(id int64, transition model.OrderTransition, actorUserId int64) (domain.Order, error)
+	GetStatusHistory(id int64, requester model.Requester) ([]domain.OrderStatusChange, error)
+	Cancel(id int64, cancellation model.OrderCancellation, requester model.Requester) (domain.Order, error)
+	Refund(id int64, refundCreate model.RefundCreate, requester model.Requester) (domain.Refund, error)
+	GetRefunds(id int64, requester model.Requester) ([]domain.Refund, error)
 }
 
+// OrderService manages orders. Every change hands its events to the
+// repository, which writes them to the outbox in the same transaction.
 type OrderService struct {
 	orderRepository ports.OrderRepository
+	productCatalog  ports.ProductCatalog
+	paymentGateway  ports.PaymentGateway
+	payments        ports.PaymentRepository
 }
 
-func NewOrderService(orderRepository ports.OrderRepository) IOrderService {
+// NewOrderService wires the service. productCatalog, paymentGateway and
+// payments may be nil; without a catalog the prices sent by the client are
+// trusted, and without a gateway and its payments refunds are only
+// recorded.
+func NewOrderService(orderRepository ports.OrderRepository, productCatalog ports.ProductCatalog, paymentGateway ports.PaymentGateway, payments ports.PaymentRepository) IOrderService {
 	return &OrderService{
 		orderRepository: orderRepository,
+		productCatalog:  productCatalog,
+		paymentGateway:  paymentGateway,
+		payments:        payments,
 	}
 }
 
-// Create implements [IOrderService].
-func (o *OrderService) Create(order domain.Order) (domain.Order, error) {
-	if err := validateOrder(order); err != nil {
+// Create implements [IOrderService]. It validates the lines, prices them
+// from the product catalog, computes the line and order totals and stores
+// the order with all its items at once.
+func (o *OrderService) Create(orderCreate model.OrderCreate, userId int64) (domain.Order, error) {
+	if userId <= 0 {
+		return domain.Order{}, fmt.Errorf("%w: orders can only be placed by a signed-in user", domain.ErrInvalidOrder)
+	}
+	if err := validateOrder(orderCreate); err != nil {
+		return domain.Order{}, fmt.Errorf("%w: %v", domain.ErrInvalidOrder, err)
+	}
+	orderCreate, err := o.priceItems(orderCreate)
+	if err != nil {
 		return domain.Order{}, err
 	}
-	return o.orderRepository.Create(order)
+	return o.orderRepository.Create(buildOrder(orderCreate, userId), orderCreatedEvents)
 }
 
-// Delete implements [IOrderService].
-func (o *OrderService) Delete(id int64) error {
-	return o.orderRepository.Delete(id)
+// Delete implements [IOrderService]. Only admins may delete orders.
+// Deleting a pending or paid order cancels it, so order.cancelled and
+// order.status_changed are published for it; shipped, delivered and
+// cancelled orders are removed without events.
+func (o *OrderService) Delete(id int64, requester model.Requester) error {
+	if !requester.Admin {
+		return fmt.Errorf("%w: only admins can delete orders", domain.ErrOrderForbidden)
+	}
+	_, err := o.orderRepository.Delete(id, func(deleted domain.Order) []kafka.Event {
+		if !domain.CanTransition(deleted.Status, domain.OrderStatusCancelled) {
+			return nil
+		}
+		cancelledAt := time.Now().UTC()
+		previousStatus := deleted.Status
+		deleted.Status = domain.OrderStatusCancelled
+		return []kafka.Event{
+			orderCancelledEvent(deleted, "", cancelledAt),
+			orderStatusChangedEvent(deleted, previousStatus, domain.OrderStatusCancelled, cancelledAt),
+		}
+	})
+	return err
 }
 
-// GetAll implements [IOrderService].
-func (o *OrderService) GetAll() ([]domain.Order, error) {
-	return o.orderRepository.GetAll()
+// Transition implements [IOrderService]. Moving to cancelled goes through
+// [OrderService.Cancel] with reason code other, so it is checked and
+// refunded like any other cancellation.
+func (o *OrderService) Transition(id int64, transition model.OrderTransition, requester model.Requester) (domain.Order, error) {
+	if !requester.Admin {
+		return domain.Order{}, fmt.Errorf("%w: only admins can transition orders", domain.ErrOrderForbidden)
+	}
+	if transition.Status == domain.OrderStatusCancelled {
+		return o.Cancel(id, model.OrderCancellation{
+			ReasonCode: domain.CancelReasonOther,
+			Note:       transition.Reason,
+		}, requester)
+	}
+	return o.ApplyTransition(id, transition, requester.UserId)
 }
 
-// GetByCustomerNumber implements [IOrderService].
-func (o *OrderService) GetByCustomerNumber(customerNumber string) ([]domain.Order, error) {
-	return o.orderRepository.GetByCustomerNumber(customerNumber)
+// ApplyTransition implements [IOrderService]. It moves the order to the
+// requested status if the state machine allows it from the current one and
+// fails with domain.ErrIllegalTransition otherwise, including for
+// cancelled.
+func (o *OrderService) ApplyTransition(id int64, transition model.OrderTransition, actorUserId int64) (domain.Order, error) {
+	if !domain.IsValidOrderStatus(transition.Status) {
+		return domain.Order{}, fmt.Errorf("%w: %q", domain.ErrInvalidOrderStatus, transition.Status)
+	}
+
+	order, err := o.orderRepository.GetById(id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if transition.Status == domain.OrderStatusCancelled {
+		return domain.Order{}, fmt.Errorf("%w: order %d can only be cancelled through Cancel", domain.ErrIllegalTransition, id)
+	}
+	if !domain.CanTransition(order.Status, transition.Status) {
+		return domain.Order{}, fmt.Errorf("%w: %s → %s", domain.ErrIllegalTransition, order.Status, transition.Status)
+	}
+
+	change := domain.OrderStatusChange{
+		OrderId:     id,
+		FromStatus:  order.Status,
+		ToStatus:    transition.Status,
+		ActorUserId: actorUserId,
+		Reason:      transition.Reason,
+		ChangedAt:   time.Now().UTC(),
+	}
+	return o.orderRepository.UpdateStatus(id, change, func(updated domain.Order) []kafka.Event {
+		return []kafka.Event{orderStatusChangedEvent(updated, change.FromStatus, change.ToStatus, change.ChangedAt)}
+	})
+}
+
+// Cancel implements [IOrderService]. Owners and admins may cancel an order
+// while it is pending or paid; a paid order is refunded in full.
+func (o *OrderService) Cancel(id int64, cancellation model.OrderCancellation, requester model.Requester) (domain.Order, error) {
+	if !domain.IsValidCancelReason(cancellation.ReasonCode) {
+		return domain.Order{}, fmt.Errorf("%w: %q", domain.ErrInvalidCancelReason, cancellation.ReasonCode)
+	}
+	order, err := o.GetById(id, requester)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	return o.cancel(order, cancellation, requester.UserId)
+}
+
+// cancel moves order to cancelled with order.cancelled and
+// order.status_changed events, fails its payments still in flight, and
+// refunds whatever is left of the payment if the order was paid. A failed refund leaves the order cancelled; the returned
+// error then wraps domain.ErrRefundFailed and the refund can be retried with
+// [OrderService.Refund].
+func (o *OrderService) cancel(order domain.Order, cancellation model.OrderCancellation, actorUserId int64) (domain.Order, error) {
+	if !domain.CanTransition(order.Status, domain.OrderStatusCancelled) {
+		return domain.Order{}, fmt.Errorf("%w: %s → %s", domain.ErrIllegalTransition, order.Status, domain.OrderStatusCancelled)
+	}
+
+	change := domain.OrderStatusChange{
+		OrderId:     order.Id,
+		FromStatus:  order.Status,
+		ToStatus:    domain.OrderStatusCancelled,
+		ActorUserId: actorUserId,
+		ReasonCode:  cancellation.ReasonCode,
+		Reason:      cancellation.Note,
+		ChangedAt:   time.Now().UTC(),
+	}
+	cancelled, err := o.orderRepository.UpdateStatus(order.Id, change, func(cancelled domain.Order) []kafka.Event {
+		return []kafka.Event{
+			orderCancelledEvent(cancelled, change.ReasonCode, change.ChangedAt),
+			orderStatusChangedEvent(cancelled, change.FromStatus, change.ToStatus, change.ChangedAt),
+		}
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+	o.failOpenPayments(cancelled.Id, "order cancelled: "+change.ReasonCode)
+
+	remaining := roundMoney(cancelled.GrandTotal - cancelled.RefundedTotal)
+	if change.FromStatus != domain.OrderStatusPaid || remaining <= 0 {
+		return cancelled, nil
+	}
+	_, cancelled, err = o.refund(cancelled, remaining, "cancelled: "+change.ReasonCode, actorUserId)
+	if err != nil {
+		return cancelled, fmt.Errorf("order %d was cancelled but not refunded: %w", order.Id, err)
+	}
+	return cancelled, nil
+}
+
+// Refund implements [IOrderService]. Only admins may refund, and only
+// orders that have been paid. A zero amount refunds the rest of the order.
+func (o *OrderService) Refund(id int64, refundCreate model.RefundCreate, requester model.Requester) (domain.Refund, error) {
+	if !requester.Admin {
+		return domain.Refund{}, fmt.Errorf("%w: only admins can refund orders", domain.ErrOrderForbidden)
+	}
+	order, err := o.orderRepository.GetById(id)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	paid, err := o.wasPaid(order)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if !paid {
+		return domain.Refund{}, fmt.Errorf("%w: order %d has not been paid", domain.ErrInvalidRefund, id)
+	}
+
+	remaining := roundMoney(order.GrandTotal - order.RefundedTotal)
+	if remaining <= 0 {
+		return domain.Refund{}, fmt.Errorf("%w: order %d has been refunded in full", domain.ErrInvalidRefund, id)
+	}
+	amount := roundMoney(refundCreate.Amount)
+	if refundCreate.Amount == 0 {
+		amount = remaining
+	}
+	if amount <= 0 {
+		return domain.Refund{}, fmt.Errorf("%w: amount must be positive", domain.ErrInvalidRefund)
+	}
+	if amount > remaining {
+		return domain.Refund{}, fmt.Errorf("%w: only %.2f of order %d is left to refund", domain.ErrInvalidRefund, remaining, id)
+	}
+
+	refund, _, err := o.refund(order, amount, refundCreate.Reason, requester.UserId)
+	return refund, err
+}
+
+// GetRefunds implements [IOrderService].
+func (o *OrderService) GetRefunds(id int64, requester model.Requester) ([]domain.Refund, error) {
+	if _, err := o.GetById(id, requester); err != nil {
+		return nil, err
+	}
+	return o.orderRepository.GetRefunds(id)
+}
+
+// refund returns amount of the order's captured payment through the payment
+// gateway and records it with an order.refunded event. Without a gateway, or
+// for an order that was not paid through it, the refund is only recorded,
+// for refunds settled outside the service.
+//
+// The amount is reserved before the gateway is called, so concurrent refunds
+// cannot together return more than was paid, and released again if the
+// gateway declines. A refund the gateway issued but that could not be
+// completed stays pending and keeps its amount reserved.
+func (o *OrderService) refund(order domain.Order, amount float64, reason string, actorUserId int64) (domain.Refund, domain.Order, error) {
+	payment, found, err := o.capturedPayment(order.Id)
+	if err != nil {
+		return domain.Refund{}, order, err
+	}
+	refund, err := o.orderRepository.ReserveRefund(domain.Refund{
+		OrderId:     order.Id,
+		Amount:      amount,
+		Reason:      reason,
+		ActorUserId: actorUserId,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return domain.Refund{}, order, err
+	}
+	if found {
+		reference, err := o.paymentGateway.Refund(context.Background(), domain.RefundRequest{
+			OrderId:           order.Id,
+			ProviderPaymentId: payment.ProviderPaymentId,
+			Amount:            amount,
+			Reason:            reason,
+		})
+		if err != nil {
+			if releaseErr := o.orderRepository.ReleaseRefund(refund); releaseErr != nil {
+				log.Printf("refund %d of order %d was declined but not released: %v", refund.Id, order.Id, releaseErr)
+			}
+			return domain.Refund{}, order, err
+		}
+		refund.ProviderReference = reference
+	}
+
+	refunded := order
+	refunded.RefundedTotal = roundMoney(order.RefundedTotal + amount)
+	completed, err := o.orderRepository.CompleteRefund(refund, func(completed domain.Refund) []kafka.Event {
+		return []kafka.Event{orderRefundedEvent(refunded, completed)}
+	})
+	if err != nil {
+		if refund.ProviderReference != "" {
+			log.Printf("refund %s of order %d was issued but not recorded: %v", refund.ProviderReference, order.Id, err)
+		}
+		return domain.Refund{}, order, err
+	}
+	return completed, refunded, nil
+}
+
+// failOpenPayments marks the payments of the order that are still pending or
+// authorized failed, so that none of them is captured for a cancelled order.
+// The order is already cancelled, so failures are logged rather than
+// returned; the authorized webhook checks the order status as well.
+func (o *OrderService) failOpenPayments(orderId int64, reason string) {
+	if o.payments == nil {
+		return
+	}
+	payments, err := o.payments.GetByOrderId(orderId)
+	if err != nil {
+		log.Printf("failed to look up payments of cancelled order %d: %v", orderId, err)
+		return
+	}
+	for _, payment := range payments {
+		if !isOpenPayment(payment) {
+			continue
+		}
+		_, err := o.payments.UpdateStatus(payment.Id,
+			[]string{domain.PaymentStatusPending, domain.PaymentStatusAuthorized}, domain.PaymentStatusFailed, reason)
+		if err != nil && !errors.Is(err, domain.ErrIllegalTransition) {
+			log.Printf("failed to fail payment %d of cancelled order %d: %v", payment.Id, orderId, err)
+		}
+	}
+}
+
+// capturedPayment finds the payment of order that the gateway captured, if
+// the order was paid through the gateway.
+func (o *OrderService) capturedPayment(orderId int64) (domain.Payment, bool, error) {
+	if o.paymentGateway == nil || o.payments == nil {
+		return domain.Payment{}, false, nil
+	}
+	payments, err := o.payments.GetByOrderId(orderId)
+	if err != nil {
+		return domain.Payment{}, false, err
+	}
+	for _, payment := range payments {
+		if payment.Status == domain.PaymentStatusCaptured && payment.Provider == o.paymentGateway.Provider() {
+			return payment, true, nil
+		}
+	}
+	return domain.Payment{}, false, nil
+}
+
+// wasPaid reports whether order has been paid, including cancelled orders
+// that were paid before being cancelled.
+func (o *OrderService) wasPaid(order domain.Order) (bool, error) {
+	switch order.Status {
+	case domain.OrderStatusPaid, domain.OrderStatusShipped, domain.OrderStatusDelivered:
+		return true, nil
+	case domain.OrderStatusCancelled:
+		history, err := o.orderRepository.GetStatusHistory(order.Id)
+		if err != nil {
+			return false, err
+		}
+		for _, change := range history {
+			if change.ToStatus == domain.OrderStatusPaid {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// priceItems replaces the unit price and discount of every line with the
+// product's current price and discount percentage, failing with
+// domain.ErrProductNotFound for a product that does not exist.
+func (o *OrderService) priceItems(orderCreate model.OrderCreate) (model.OrderCreate, error) {
+	if o.productCatalog == nil {
+		return orderCreate, nil
+	}
+	ctx := context.Background()
+	items := make([]model.OrderItemCreate, len(orderCreate.Items))
+	for i, item := range orderCreate.Items {
+		product, err := o.productCatalog.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			return model.OrderCreate{}, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		item.UnitPrice = roundMoney(product.Price)
+		item.Discount = roundMoney(float64(item.Quantity) * item.UnitPrice * product.Discount / 100)
+		items[i] = item
+	}
+	orderCreate.Items = items
+	return orderCreate, nil
+}
+
+// GetStatusHistory implements [IOrderService].
+func (o *OrderService) GetStatusHistory(id int64, requester model.Requester) ([]domain.OrderStatusChange, error) {
+	if _, err := o.GetById(id, requester); err != nil {
+		return nil, err
+	}
+	return o.orderRepository.GetStatusHistory(id)
+}
+
+// GetAll implements [IOrderService]. Only admins may list every order.
+func (o *OrderService) GetAll(search model.OrderSearch, requester model.Requester) (domain.OrderPage, error) {
+	if !requester.Admin {
+		return domain.OrderPage{}, fmt.Errorf("%w: only admins can list all orders", domain.ErrOrderForbidden)
+	}
+	query, err := buildOrderQuery(search)
+	if err != nil {
+		return domain.OrderPage{}, err
+	}
+	return o.findOrders(query)
+}
+
+// GetByCustomerNumber implements [IOrderService]. Users may only look up
+// their own customer number.
+func (o *OrderService) GetByCustomerNumber(customerNumber string, search model.OrderSearch, requester model.Requester) (domain.OrderPage, error) {
+	if !requester.Admin && customerNumber != domain.CustomerNumberFor(requester.UserId) {
+		return domain.OrderPage{}, fmt.Errorf("%w: customer %s", domain.ErrOrderForbidden, customerNumber)
+	}
+	query, err := buildOrderQuery(search)
+	if err != nil {
+		return domain.OrderPage{}, err
+	}
+	query.CustomerNumber = customerNumber
+	return o.findOrders(query)
+}
+
+// GetByUserId implements [IOrderService].
+func (o *OrderService) GetByUserId(userId int64, search model.OrderSearch) (domain.OrderPage, error) {
+	query, err := buildOrderQuery(search)
+	if err != nil {
+		return domain.OrderPage{}, err
+	}
+	query.UserId = userId
+	return o.findOrders(query)
+}
+
+func (o *OrderService) findOrders(query domain.OrderQuery) (domain.OrderPage, error) {
+	orders, err := o.orderRepository.Find(query)
+	if err != nil {
+		return domain.OrderPage{}, err
+	}
+	if orders == nil {
+		orders = []domain.Order{}
+	}
+	return orderPage(orders, query), nil
+}
+
+// GetById implements [IOrderService]. The order is only returned to its
+// owner and to admins.
+func (o *OrderService) GetById(id int64, requester model.Requester) (domain.Order, error) {
+	order, err := o.orderRepository.GetById(id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if !canRead(order, requester) {
+		return domain.Order{}, fmt.Errorf("%w: order %d", domain.ErrOrderForbidden, id)
+	}
+	return order, nil
+}
+
+func canRead(order domain.Order, requester model.Requester) bool {
+	return requester.Admin || (requester.UserId > 0 && order.UserId == requester.UserId)
+}
+
+// buildOrder turns a validated create request into an order of userId with
+// its line totals, subtotal, discount total and grand total filled in.
+func buildOrder(orderCreate model.OrderCreate, userId int64) domain.Order {
+	order := domain.Order{
+		UserId:         userId,
+		CustomerNumber: domain.CustomerNumberFor(userId),
+		Status:         domain.OrderStatusPending,
+		Items:          make([]domain.OrderItem, 0, len(orderCreate.Items)),
+	}
+	for _, item := range orderCreate.Items {
+		gross := roundMoney(float64(item.Quantity) * item.UnitPrice)
+		discount := roundMoney(item.Discount)
+		order.Items = append(order.Items, domain.OrderItem{
+			ProductID: item.ProductID,
+			Variant:   item.Variant,
+			Quantity:  item.Quantity,
+			UnitPrice: roundMoney(item.UnitPrice),
+			Discount:  discount,
+			LineTotal: roundMoney(gross - discount),
+		})
+		order.Subtotal += gross
+		order.DiscountTotal += discount
+	}
+	order.Subtotal = roundMoney(order.Subtotal)
+	order.DiscountTotal = roundMoney(order.DiscountTotal)
+	order.GrandTotal = roundMoney(order.Subtotal - order.DiscountTotal)
+	return order
 }
 
-// GetById implements [IOrderService].
-func (o *OrderService) GetById(id int64) (domain.Order, error) {
-	return o.orderRepository.GetById(id)
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
 }
 
-func validateOrder(order domain.Order) error {
-	if order.CustomerNumber == "" {
-		return errors.New("customer number is required")
+func validateOrder(orderCreate model.OrderCreate) error {
+	if len(orderCreate.Items) == 0 {
+		return errors.New("order must contain at least one item")
 	}
-	if order.ProductID == "" {
-		return errors.New("product id is required")
+	if len(orderCreate.Items) > MaxOrderItems {
+		return fmt.Errorf("order cannot contain more than %d items", MaxOrderItems)
 	}
-	if order.Quantity <= 0 {
-		return errors.New("quantity must be greater than 0")
+	for i, item := range orderCreate.Items {
+		if item.ProductID <= 0 {
+			return fmt.Errorf("item %d: product id must be a positive integer", i+1)
+		}
+		if item.Quantity <= 0 {
+			return fmt.Errorf("item %d: quantity must be greater than 0", i+1)
+		}
+		if item.UnitPrice < 0 {
+			return fmt.Errorf("item %d: unit price cannot be negative", i+1)
+		}
+		if item.Discount < 0 {
+			return fmt.Errorf("item %d: discount cannot be negative", i+1)
+		}
+		if roundMoney(item.Discount) > roundMoney(float64(item.Quantity)*item.UnitPrice) {
+			return fmt.Errorf("item %d: discount cannot exceed the line amount", i+1)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"product-app/services/order/internal/usecase/model"
+)
+
+type IPaymentService interface {
+	StartPayment(orderId int64, start model.PaymentStart, requester model.Requester) (domain.Payment, error)
+	GetPayments(orderId int64, requester model.Requester) ([]domain.Payment, error)
+	// HandleWebhook verifies and applies a webhook call of the payment
+	// provider. It fails with domain.ErrDuplicateWebhookEvent for an event
+	// that was applied before, which the provider must not retry.
+	HandleWebhook(payload []byte, header http.Header) error
+}
+
+// PaymentService collects an order's grand total through the payment
+// gateway. A payment is started by the customer; the provider reports its
+// outcome through webhooks, an authorized payment is captured and a captured
+// payment marks the order paid.
+type PaymentService struct {
+	orderService      IOrderService
+	paymentRepository ports.PaymentRepository
+	paymentGateway    ports.PaymentGateway
+}
+
+// webhookRequester reads orders for the provider's webhook calls, which
+// are not made on behalf of a user.
+var webhookRequester = model.Requester{Admin: true}
+
+func NewPaymentService(orderService IOrderService, paymentRepository ports.PaymentRepository, paymentGateway ports.PaymentGateway) IPaymentService {
+	return &PaymentService{
+		orderService:      orderService,
+		paymentRepository: paymentRepository,
+		paymentGateway:    paymentGateway,
+	}
+}
+
+// StartPayment implements [IPaymentService]. Only a pending order without a
+// payment in progress can be paid, by its owner or an admin.
+func (p *PaymentService) StartPayment(orderId int64, start model.PaymentStart, requester model.Requester) (domain.Payment, error) {
+	order, err := p.orderService.GetById(orderId, requester)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if order.Status != domain.OrderStatusPending {
+		return domain.Payment{}, fmt.Errorf("%w: order %d is %s", domain.ErrIllegalTransition, orderId, order.Status)
+	}
+	if order.GrandTotal <= 0 {
+		return domain.Payment{}, fmt.Errorf("%w: order %d has nothing to pay", domain.ErrIllegalTransition, orderId)
+	}
+	payments, err := p.paymentRepository.GetByOrderId(orderId)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	for _, payment := range payments {
+		if isOpenPayment(payment) {
+			return domain.Payment{}, fmt.Errorf("%w: payment %d is %s", domain.ErrPaymentAlreadyInProgress, payment.Id, payment.Status)
+		}
+	}
+
+	intent, err := p.paymentGateway.CreatePaymentIntent(context.Background(), domain.PaymentIntentRequest{
+		OrderId:       orderId,
+		Amount:        order.GrandTotal,
+		PaymentMethod: start.PaymentMethod,
+	})
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	payment, err := p.paymentRepository.Create(domain.Payment{
+		OrderId:           orderId,
+		Provider:          p.paymentGateway.Provider(),
+		ProviderPaymentId: intent.ProviderPaymentId,
+		Amount:            order.GrandTotal,
+		Status:            domain.PaymentStatusPending,
+	})
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	payment.ClientSecret = intent.ClientSecret
+	return payment, nil
+}
+
+// GetPayments implements [IPaymentService].
+func (p *PaymentService) GetPayments(orderId int64, requester model.Requester) ([]domain.Payment, error) {
+	if _, err := p.orderService.GetById(orderId, requester); err != nil {
+		return nil, err
+	}
+	return p.paymentRepository.GetByOrderId(orderId)
+}
+
+// HandleWebhook implements [IPaymentService]. The event id is reserved
+// before the event is applied and released when applying fails, so that the
+// provider's retry is applied again rather than dropped as a duplicate.
+func (p *PaymentService) HandleWebhook(payload []byte, header http.Header) error {
+	event, err := p.paymentGateway.ParseWebhook(payload, header)
+	if err != nil {
+		return err
+	}
+	provider := p.paymentGateway.Provider()
+	if err := p.paymentRepository.ReserveWebhookEvent(provider, event); err != nil {
+		return err
+	}
+	if err := p.apply(event); err != nil {
+		if releaseErr := p.paymentRepository.ReleaseWebhookEvent(provider, event.Id); releaseErr != nil {
+			log.Printf("failed to release webhook event %s: %v", event.Id, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// apply moves the payment of event along and, once it is captured, marks
+// its order paid. Every step tolerates having been done before, since
+// providers deliver events at least once and not necessarily in order.
+func (p *PaymentService) apply(event domain.PaymentEvent) error {
+	payment, err := p.paymentRepository.GetByProviderPaymentId(p.paymentGateway.Provider(), event.ProviderPaymentId)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case domain.PaymentEventAuthorized:
+		order, err := p.orderService.GetById(payment.OrderId, webhookRequester)
+		if err != nil {
+			return err
+		}
+		if order.Status != domain.OrderStatusPending {
+			// Cancelled while the payment was in flight. The payment fails
+			// instead of being captured, and the provider lets the
+			// uncaptured authorization lapse.
+			_, err = p.paymentRepository.UpdateStatus(payment.Id,
+				[]string{domain.PaymentStatusPending, domain.PaymentStatusAuthorized}, domain.PaymentStatusFailed,
+				fmt.Sprintf("order %d is %s", order.Id, order.Status))
+			if errors.Is(err, domain.ErrIllegalTransition) {
+				return nil
+			}
+			return err
+		}
+		payment, err = p.paymentRepository.UpdateStatus(payment.Id,
+			[]string{domain.PaymentStatusPending, domain.PaymentStatusAuthorized}, domain.PaymentStatusAuthorized, "")
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return p.paymentGateway.Capture(context.Background(), payment.ProviderPaymentId, payment.Amount)
+
+	case domain.PaymentEventCaptured:
+		payment, err = p.paymentRepository.UpdateStatus(payment.Id,
+			[]string{domain.PaymentStatusPending, domain.PaymentStatusAuthorized, domain.PaymentStatusCaptured}, domain.PaymentStatusCaptured, "")
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = p.orderService.ApplyTransition(payment.OrderId, model.OrderTransition{
+			Status: domain.OrderStatusPaid,
+			Reason: fmt.Sprintf("payment %s captured", payment.ProviderPaymentId),
+		}, 0)
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			// Already paid by an earlier delivery, or cancelled while the
+			// payment was in flight; the latter needs a manual refund.
+			log.Printf("payment %s captured for order %d: %v", payment.ProviderPaymentId, payment.OrderId, err)
+			return nil
+		}
+		return err
+
+	case domain.PaymentEventFailed:
+		_, err = p.paymentRepository.UpdateStatus(payment.Id,
+			[]string{domain.PaymentStatusPending, domain.PaymentStatusAuthorized}, domain.PaymentStatusFailed, event.FailureReason)
+		if errors.Is(err, domain.ErrIllegalTransition) {
+			return nil
+		}
+		return err
+
+	default:
+		log.Printf("ignoring payment event %s of type %q", event.Id, event.Type)
+		return nil
+	}
+}
+
+func isOpenPayment(payment domain.Payment) bool {
+	return payment.Status == domain.PaymentStatusPending || payment.Status == domain.PaymentStatusAuthorized
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"product-app/services/order/internal/usecase/model"
+	"product-app/shared/kafka"
+	"strings"
+	"time"
+)
+
+type IShipmentService interface {
+	// CreateShipment records a parcel of a paid order handed to the
+	// carrier. Only admins may create shipments.
+	CreateShipment(orderId int64, create model.ShipmentCreate, requester model.Requester) (domain.Shipment, error)
+	// AddTrackingEvent appends a carrier event to a shipment of the order.
+	// Only admins may add tracking events.
+	AddTrackingEvent(orderId, shipmentId int64, event model.TrackingEventCreate, requester model.Requester) (domain.Shipment, error)
+	GetShipments(orderId int64, requester model.Requester) ([]domain.Shipment, error)
+	GetShipment(orderId, shipmentId int64, requester model.Requester) (domain.Shipment, error)
+}
+
+// ShipmentService records the shipments of paid orders and their tracking
+// events, with shipment.* events for customer notifications. An
+// order becomes shipped once its shipments carry all of its items, and
+// delivered once all of them are delivered.
+type ShipmentService struct {
+	orderService       IOrderService
+	shipmentRepository ports.ShipmentRepository
+}
+
+func NewShipmentService(orderService IOrderService, shipmentRepository ports.ShipmentRepository) IShipmentService {
+	return &ShipmentService{
+		orderService:       orderService,
+		shipmentRepository: shipmentRepository,
+	}
+}
+
+// CreateShipment implements [IShipmentService]. Items cannot exceed what is
+// left unshipped of the order's lines. The repository checks that again
+// with the order locked, which catches concurrent shipments.
+func (s *ShipmentService) CreateShipment(orderId int64, create model.ShipmentCreate, requester model.Requester) (domain.Shipment, error) {
+	if !requester.Admin {
+		return domain.Shipment{}, fmt.Errorf("%w: only admins can create shipments", domain.ErrOrderForbidden)
+	}
+	carrier := strings.TrimSpace(create.Carrier)
+	trackingNumber := strings.TrimSpace(create.TrackingNumber)
+	if carrier == "" || trackingNumber == "" {
+		return domain.Shipment{}, fmt.Errorf("%w: carrier and tracking number are required", domain.ErrInvalidShipment)
+	}
+	order, err := s.orderService.GetById(orderId, requester)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if order.Status != domain.OrderStatusPaid {
+		return domain.Shipment{}, fmt.Errorf("%w: order %d is %s", domain.ErrIllegalTransition, orderId, order.Status)
+	}
+	shipments, err := s.shipmentRepository.GetByOrderId(orderId)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+
+	unshipped := unshippedItems(order, shipments)
+	items := []domain.ShipmentItem{}
+	if len(create.Items) == 0 {
+		for _, item := range unshipped {
+			if item.Quantity > 0 {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			return domain.Shipment{}, fmt.Errorf("%w: order %d is fully shipped", domain.ErrInvalidShipment, orderId)
+		}
+	}
+	for _, requested := range create.Items {
+		if requested.Quantity <= 0 {
+			return domain.Shipment{}, fmt.Errorf("%w: quantity of product %d must be positive", domain.ErrInvalidShipment, requested.ProductID)
+		}
+		i := findShipmentItem(unshipped, requested.ProductID, requested.Variant)
+		if i < 0 {
+			return domain.Shipment{}, fmt.Errorf("%w: product %d %q is not on order %d", domain.ErrInvalidShipment, requested.ProductID, requested.Variant, orderId)
+		}
+		j := findShipmentItem(items, requested.ProductID, requested.Variant)
+		if j < 0 {
+			items = append(items, domain.ShipmentItem{ProductID: requested.ProductID, Variant: requested.Variant})
+			j = len(items) - 1
+		}
+		items[j].Quantity += requested.Quantity
+		if items[j].Quantity > unshipped[i].Quantity {
+			return domain.Shipment{}, fmt.Errorf("%w: only %d of product %d %q are left to ship",
+				domain.ErrInvalidShipment, unshipped[i].Quantity, requested.ProductID, requested.Variant)
+		}
+	}
+
+	now := time.Now().UTC()
+	shipment, err := s.shipmentRepository.Create(domain.Shipment{
+		OrderId:        orderId,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		Status:         domain.ShipmentStatusShipped,
+		Items:          items,
+		Events: []domain.TrackingEvent{{
+			Status:      domain.ShipmentStatusShipped,
+			Description: "Handed to " + carrier,
+			OccurredAt:  now,
+		}},
+	}, func(shipment domain.Shipment) []kafka.Event {
+		return []kafka.Event{kafka.ShipmentCreated{
+			ID:             shipment.Id,
+			OrderID:        orderId,
+			CustomerNumber: order.CustomerNumber,
+			Carrier:        shipment.Carrier,
+			TrackingNumber: shipment.TrackingNumber,
+			Status:         shipment.Status,
+			Items:          shipmentLines(shipment.Items),
+			CreatedAt:      shipment.CreatedAt,
+		}}
+	})
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	s.syncOrderStatus(order, append(shipments, shipment), requester.UserId)
+	return shipment, nil
+}
+
+// AddTrackingEvent implements [IShipmentService]. A delivered shipment takes
+// no more events.
+func (s *ShipmentService) AddTrackingEvent(orderId, shipmentId int64, create model.TrackingEventCreate, requester model.Requester) (domain.Shipment, error) {
+	if !requester.Admin {
+		return domain.Shipment{}, fmt.Errorf("%w: only admins can add tracking events", domain.ErrOrderForbidden)
+	}
+	if !domain.IsValidShipmentStatus(create.Status) {
+		return domain.Shipment{}, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidTrackingEvent, create.Status)
+	}
+	order, err := s.orderService.GetById(orderId, requester)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if _, err := s.getShipment(orderId, shipmentId); err != nil {
+		return domain.Shipment{}, err
+	}
+
+	occurredAt := create.OccurredAt.UTC()
+	if create.OccurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+	event := domain.TrackingEvent{
+		ShipmentId:  shipmentId,
+		Status:      create.Status,
+		Location:    strings.TrimSpace(create.Location),
+		Description: strings.TrimSpace(create.Description),
+		OccurredAt:  occurredAt,
+	}
+	updated, err := s.shipmentRepository.AddEvent(event, func(updated domain.Shipment) []kafka.Event {
+		events := []kafka.Event{kafka.ShipmentUpdated{
+			ID:             updated.Id,
+			OrderID:        orderId,
+			CustomerNumber: order.CustomerNumber,
+			Carrier:        updated.Carrier,
+			TrackingNumber: updated.TrackingNumber,
+			PreviousStatus: previousShipmentStatus(updated),
+			Status:         updated.Status,
+			Location:       event.Location,
+			Description:    event.Description,
+			OccurredAt:     occurredAt,
+		}}
+		if updated.Status == domain.ShipmentStatusDelivered {
+			events = append(events, kafka.ShipmentDelivered{
+				ID:             updated.Id,
+				OrderID:        orderId,
+				CustomerNumber: order.CustomerNumber,
+				Carrier:        updated.Carrier,
+				TrackingNumber: updated.TrackingNumber,
+				Items:          shipmentLines(updated.Items),
+				DeliveredAt:    occurredAt,
+			})
+		}
+		return events
+	})
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+
+	shipments, err := s.shipmentRepository.GetByOrderId(orderId)
+	if err != nil {
+		log.Printf("failed to update status of order %d after tracking event of shipment %d: %v", orderId, shipmentId, err)
+		return updated, nil
+	}
+	s.syncOrderStatus(order, shipments, requester.UserId)
+	return updated, nil
+}
+
+// GetShipments implements [IShipmentService].
+func (s *ShipmentService) GetShipments(orderId int64, requester model.Requester) ([]domain.Shipment, error) {
+	if _, err := s.orderService.GetById(orderId, requester); err != nil {
+		return nil, err
+	}
+	return s.shipmentRepository.GetByOrderId(orderId)
+}
+
+// GetShipment implements [IShipmentService].
+func (s *ShipmentService) GetShipment(orderId, shipmentId int64, requester model.Requester) (domain.Shipment, error) {
+	if _, err := s.orderService.GetById(orderId, requester); err != nil {
+		return domain.Shipment{}, err
+	}
+	return s.getShipment(orderId, shipmentId)
+}
+
+// getShipment returns a shipment of the order; shipments of other orders
+// are not found.
+func (s *ShipmentService) getShipment(orderId, shipmentId int64) (domain.Shipment, error) {
+	shipment, err := s.shipmentRepository.GetById(shipmentId)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if shipment.OrderId != orderId {
+		return domain.Shipment{}, fmt.Errorf("%w: shipment %d of order %d", domain.ErrShipmentNotFound, shipmentId, orderId)
+	}
+	return shipment, nil
+}
+
+// syncOrderStatus moves order to shipped once shipments carry all of its
+// items, and on to delivered once they are all delivered as well. The
+// shipment change is already stored, so a failed transition is logged
+// rather than returned; the order's next tracking event tries again.
+func (s *ShipmentService) syncOrderStatus(order domain.Order, shipments []domain.Shipment, actorUserId int64) {
+	if len(shipments) == 0 {
+		return
+	}
+	for _, item := range unshippedItems(order, shipments) {
+		if item.Quantity > 0 {
+			return
+		}
+	}
+
+	if order.Status == domain.OrderStatusPaid {
+		updated, err := s.orderService.ApplyTransition(order.Id, model.OrderTransition{
+			Status: domain.OrderStatusShipped,
+			Reason: "all items shipped",
+		}, actorUserId)
+		if err != nil {
+			log.Printf("failed to mark order %d shipped: %v", order.Id, err)
+			return
+		}
+		order = updated
+	}
+	if order.Status != domain.OrderStatusShipped {
+		return
+	}
+	for _, shipment := range shipments {
+		if shipment.Status != domain.ShipmentStatusDelivered {
+			return
+		}
+	}
+	_, err := s.orderService.ApplyTransition(order.Id, model.OrderTransition{
+		Status: domain.OrderStatusDelivered,
+		Reason: "all shipments delivered",
+	}, actorUserId)
+	if err != nil {
+		log.Printf("failed to mark order %d delivered: %v", order.Id, err)
+	}
+}
+
+// unshippedItems returns the units of each of order's lines that none of
+// shipments carries, one entry per product variant in line order.
+func unshippedItems(order domain.Order, shipments []domain.Shipment) []domain.ShipmentItem {
+	unshipped := []domain.ShipmentItem{}
+	for _, item := range order.Items {
+		i := findShipmentItem(unshipped, item.ProductID, item.Variant)
+		if i < 0 {
+			unshipped = append(unshipped, domain.ShipmentItem{ProductID: item.ProductID, Variant: item.Variant})
+			i = len(unshipped) - 1
+		}
+		unshipped[i].Quantity += item.Quantity
+	}
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			if i := findShipmentItem(unshipped, item.ProductID, item.Variant); i >= 0 {
+				unshipped[i].Quantity -= item.Quantity
+			}
+		}
+	}
+	return unshipped
+}
+
+// previousShipmentStatus is the status shipment had before its latest
+// tracking event.
+func previousShipmentStatus(shipment domain.Shipment) string {
+	if len(shipment.Events) < 2 {
+		return ""
+	}
+	return shipment.Events[len(shipment.Events)-2].Status
+}
+
+func findShipmentItem(items []domain.ShipmentItem, productId int64, variant string) int {
+	for i, item := range items {
+		if item.ProductID == productId && item.Variant == variant {
+			return i
+		}
+	}
+	return -1
+}
+
+func shipmentLines(items []domain.ShipmentItem) []kafka.ShipmentLine {
+	lines := make([]kafka.ShipmentLine, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, kafka.ShipmentLine{ProductID: item.ProductID, Variant: item.Variant, Quantity: item.Quantity})
+	}
+	return lines
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"fmt"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"product-app/services/order/internal/usecase/model"
+)
+
+type IStockService interface {
+	SetStock(productId int64, update model.StockLevelUpdate, requester model.Requester) (domain.StockLevel, error)
+	GetStock(productId int64, requester model.Requester) ([]domain.StockLevel, error)
+}
+
+// StockService lets admins manage the stock levels that orders reserve
+// from.
+type StockService struct {
+	stockRepository ports.StockRepository
+}
+
+func NewStockService(stockRepository ports.StockRepository) IStockService {
+	return &StockService{stockRepository: stockRepository}
+}
+
+// SetStock implements [IStockService]. Only admins may set stock levels.
+func (s *StockService) SetStock(productId int64, update model.StockLevelUpdate, requester model.Requester) (domain.StockLevel, error) {
+	if !requester.Admin {
+		return domain.StockLevel{}, fmt.Errorf("%w: only admins can set stock", domain.ErrOrderForbidden)
+	}
+	if productId <= 0 {
+		return domain.StockLevel{}, fmt.Errorf("%w: product id must be a positive integer", domain.ErrInvalidStockLevel)
+	}
+	if update.Available < 0 {
+		return domain.StockLevel{}, fmt.Errorf("%w: available cannot be negative", domain.ErrInvalidStockLevel)
+	}
+	return s.stockRepository.Set(domain.StockLevel{
+		ProductID: productId,
+		Variant:   update.Variant,
+		Available: update.Available,
+	})
+}
+
+// GetStock implements [IStockService]. Only admins may read stock levels.
+func (s *StockService) GetStock(productId int64, requester model.Requester) ([]domain.StockLevel, error) {
+	if !requester.Admin {
+		return nil, fmt.Errorf("%w: only admins can read stock", domain.ErrOrderForbidden)
+	}
+	return s.stockRepository.GetByProductId(productId)
+}
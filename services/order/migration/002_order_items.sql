@@ -0,0 +1,35 @@
+ALTER TABLE orders
+  ADD COLUMN IF NOT EXISTS subtotal NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS discount_total NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS grand_total NUMERIC(12, 2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS order_items (
+  id         BIGSERIAL      PRIMARY KEY,
+  order_id   BIGINT         NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+  product_id BIGINT         NOT NULL,
+  variant    VARCHAR(255)   NOT NULL DEFAULT '',
+  quantity   INT            NOT NULL CHECK (quantity > 0),
+  unit_price NUMERIC(12, 2) NOT NULL CHECK (unit_price >= 0),
+  discount   NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (discount >= 0),
+  line_total NUMERIC(12, 2) NOT NULL CHECK (line_total >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items (order_id);
+
+-- Single-line orders become orders with one item. Their price was never
+-- recorded, so the snapshot is 0; lines whose product_id is not numeric
+-- cannot be mapped to a product and are dropped.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM information_schema.columns
+    WHERE table_name = 'orders' AND column_name = 'product_id'
+  ) THEN
+    INSERT INTO order_items (order_id, product_id, quantity, unit_price, line_total)
+    SELECT id, product_id::BIGINT, quantity, 0, 0
+    FROM orders
+    WHERE product_id ~ '^[0-9]+$';
+
+    ALTER TABLE orders DROP COLUMN product_id, DROP COLUMN quantity;
+  END IF;
+END $$;
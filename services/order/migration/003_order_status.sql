@@ -0,0 +1,28 @@
+ALTER TABLE orders
+  ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'orders_status_check') THEN
+    ALTER TABLE orders ADD CONSTRAINT orders_status_check
+      CHECK (status IN ('pending', 'paid', 'shipped', 'delivered', 'cancelled'));
+  END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+  id            BIGSERIAL   PRIMARY KEY,
+  order_id      BIGINT      NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+  from_status   VARCHAR(20),
+  to_status     VARCHAR(20) NOT NULL,
+  actor_user_id BIGINT,
+  reason        TEXT        NOT NULL DEFAULT '',
+  changed_at    TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history (order_id, id);
+
+-- Existing orders start their history at pending.
+INSERT INTO order_status_history (order_id, to_status, reason, changed_at)
+SELECT o.id, o.status, 'migrated', o.order_time
+FROM orders o
+WHERE NOT EXISTS (SELECT 1 FROM order_status_history h WHERE h.order_id = o.id);
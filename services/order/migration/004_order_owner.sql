@@ -0,0 +1,6 @@
+-- Orders belong to the user who placed them. Orders placed before owners
+-- were recorded keep a NULL user_id and are only visible to admins.
+ALTER TABLE orders
+  ADD COLUMN IF NOT EXISTS user_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders (user_id, id);
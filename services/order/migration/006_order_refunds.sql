@@ -0,0 +1,20 @@
+-- Cancellations carry a reason code, and refunds are recorded per order.
+-- refunded_total is kept next to grand_total so a refund can be checked
+-- against the remaining amount in a single UPDATE.
+ALTER TABLE order_status_history
+  ADD COLUMN IF NOT EXISTS reason_code VARCHAR(32);
+
+ALTER TABLE orders
+  ADD COLUMN IF NOT EXISTS refunded_total NUMERIC(12, 2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS order_refunds (
+  id                 BIGSERIAL      PRIMARY KEY,
+  order_id           BIGINT         NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+  amount             NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+  reason             TEXT           NOT NULL DEFAULT '',
+  provider_reference VARCHAR(255),
+  actor_user_id      BIGINT,
+  created_at         TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_refunds_order_id ON order_refunds (order_id, id);
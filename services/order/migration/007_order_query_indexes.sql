@@ -0,0 +1,8 @@
+-- Indexes for the order list filters. Each sort key is paired with id, the
+-- tie-breaker of the keyset cursor.
+CREATE INDEX IF NOT EXISTS idx_orders_order_time ON orders (order_time, id);
+CREATE INDEX IF NOT EXISTS idx_orders_grand_total ON orders (grand_total, id);
+CREATE INDEX IF NOT EXISTS idx_orders_status_order_time ON orders (status, order_time, id);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_number_order_time ON orders (customer_number, order_time, id);
+CREATE INDEX IF NOT EXISTS idx_orders_user_id_order_time ON orders (user_id, order_time, id);
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items (product_id, order_id);
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS payments (
+  id                  BIGSERIAL      PRIMARY KEY,
+  order_id            BIGINT         NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+  provider            VARCHAR(32)    NOT NULL,
+  provider_payment_id VARCHAR(255)   NOT NULL,
+  amount              NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+  status              VARCHAR(20)    NOT NULL
+    CHECK (status IN ('pending', 'authorized', 'captured', 'failed')),
+  failure_reason      TEXT           NOT NULL DEFAULT '',
+  created_at          TIMESTAMP      NOT NULL DEFAULT NOW(),
+  updated_at          TIMESTAMP      NOT NULL DEFAULT NOW(),
+  UNIQUE (provider, provider_payment_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id, id);
+
+-- An order has at most one payment the provider may still collect.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_one_open_per_order
+  ON payments (order_id) WHERE status IN ('pending', 'authorized');
+
+-- Webhook event ids seen per provider, so redelivered events are skipped.
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+  provider    VARCHAR(32)  NOT NULL,
+  event_id    VARCHAR(255) NOT NULL,
+  event_type  VARCHAR(64)  NOT NULL,
+  received_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (provider, event_id)
+);
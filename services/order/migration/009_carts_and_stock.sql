@@ -0,0 +1,42 @@
+-- Carts: one per signed-in user, or one per anonymous token. Anonymous carts
+-- expire after a period without changes and are deleted some time later.
+CREATE TABLE IF NOT EXISTS carts (
+  id         BIGSERIAL   PRIMARY KEY,
+  user_id    BIGINT      UNIQUE,
+  token      VARCHAR(64) UNIQUE,
+  created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMP,
+  CHECK ((user_id IS NULL) <> (token IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_carts_expires_at ON carts (expires_at) WHERE token IS NOT NULL;
+
+-- Prices are not stored; carts are priced from the product catalog on read.
+CREATE TABLE IF NOT EXISTS cart_items (
+  cart_id    BIGINT    NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+  product_id BIGINT    NOT NULL,
+  variant    TEXT      NOT NULL DEFAULT '',
+  quantity   INT       NOT NULL CHECK (quantity > 0),
+  added_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (cart_id, product_id, variant)
+);
+
+-- Units left per product variant. Products without a row are not tracked.
+CREATE TABLE IF NOT EXISTS stock_levels (
+  product_id BIGINT    NOT NULL,
+  variant    TEXT      NOT NULL DEFAULT '',
+  available  INT       NOT NULL CHECK (available >= 0),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (product_id, variant)
+);
+
+-- Units taken from stock_levels by an order, given back when it is
+-- cancelled or deleted.
+CREATE TABLE IF NOT EXISTS stock_reservations (
+  order_id   BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+  product_id BIGINT NOT NULL,
+  variant    TEXT   NOT NULL DEFAULT '',
+  quantity   INT    NOT NULL CHECK (quantity > 0),
+  PRIMARY KEY (order_id, product_id, variant)
+);
@@ -0,0 +1,50 @@
+-- Last number issued per document kind and year. Taking the next number
+-- locks the row until the issuing transaction ends, so numbers are gapless.
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+  kind        VARCHAR(20) NOT NULL,
+  year        INT         NOT NULL,
+  last_number BIGINT      NOT NULL,
+  PRIMARY KEY (kind, year)
+);
+
+-- Issued invoices and credit notes. A document is a snapshot of what it
+-- shows and is kept when its order is deleted, so order_id has no foreign
+-- key.
+CREATE TABLE IF NOT EXISTS invoices (
+  id              BIGSERIAL      PRIMARY KEY,
+  kind            VARCHAR(20)    NOT NULL CHECK (kind IN ('invoice', 'credit_note')),
+  number          VARCHAR(32)    NOT NULL UNIQUE,
+  year            INT            NOT NULL,
+  sequence        BIGINT         NOT NULL,
+  order_id        BIGINT         NOT NULL,
+  corrects_number VARCHAR(32)    NOT NULL DEFAULT '',
+  reason          TEXT           NOT NULL DEFAULT '',
+  currency        VARCHAR(3)     NOT NULL,
+  seller          JSONB          NOT NULL,
+  customer        JSONB          NOT NULL,
+  lines           JSONB          NOT NULL,
+  taxes           JSONB          NOT NULL,
+  net_total       NUMERIC(12, 2) NOT NULL,
+  tax_total       NUMERIC(12, 2) NOT NULL,
+  grand_total     NUMERIC(12, 2) NOT NULL,
+  issued_at       TIMESTAMP      NOT NULL,
+  UNIQUE (kind, year, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_order_id ON invoices (order_id, id);
+
+-- An order has at most one invoice; corrections are credit notes.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoices_one_per_order
+  ON invoices (order_id) WHERE kind = 'invoice';
+
+-- Issued documents are immutable.
+CREATE OR REPLACE FUNCTION reject_invoice_change() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'invoice % is immutable', OLD.number;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS invoices_immutable ON invoices;
+CREATE TRIGGER invoices_immutable
+  BEFORE UPDATE OR DELETE ON invoices
+  FOR EACH ROW EXECUTE FUNCTION reject_invoice_change();
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS shipments (
+  id              BIGSERIAL    PRIMARY KEY,
+  order_id        BIGINT       NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+  carrier         VARCHAR(64)  NOT NULL,
+  tracking_number VARCHAR(128) NOT NULL,
+  status          VARCHAR(20)  NOT NULL
+    CHECK (status IN ('shipped', 'in_transit', 'out_for_delivery', 'delivered', 'exception')),
+  created_at      TIMESTAMP    NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMP    NOT NULL DEFAULT NOW(),
+  UNIQUE (carrier, tracking_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_order_id ON shipments (order_id, id);
+
+-- The units of each order line a shipment carries.
+CREATE TABLE IF NOT EXISTS shipment_items (
+  shipment_id BIGINT       NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+  product_id  BIGINT       NOT NULL,
+  variant     TEXT         NOT NULL DEFAULT '',
+  quantity    INT          NOT NULL CHECK (quantity > 0),
+  PRIMARY KEY (shipment_id, product_id, variant)
+);
+
+-- Tracking history of a shipment, appended to and never changed.
+CREATE TABLE IF NOT EXISTS shipment_events (
+  id          BIGSERIAL    PRIMARY KEY,
+  shipment_id BIGINT       NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+  status      VARCHAR(20)  NOT NULL
+    CHECK (status IN ('shipped', 'in_transit', 'out_for_delivery', 'delivered', 'exception')),
+  location    TEXT         NOT NULL DEFAULT '',
+  description TEXT         NOT NULL DEFAULT '',
+  occurred_at TIMESTAMP    NOT NULL,
+  recorded_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipment_events_shipment_id ON shipment_events (shipment_id, id);
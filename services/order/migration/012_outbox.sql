@@ -0,0 +1,20 @@
+-- Transactional outbox for order, refund and shipment events. Rows are
+-- written in the same transaction as the change they describe and published
+-- by the outbox relay.
+CREATE TABLE IF NOT EXISTS outbox (
+  id BIGSERIAL PRIMARY KEY,
+  topic TEXT NOT NULL,
+  message_key TEXT NOT NULL,
+  payload JSON NOT NULL,
+  headers JSONB NOT NULL DEFAULT '{}',
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  sent_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (topic, id) WHERE sent_at IS NULL;
+-- Lets the relay find earlier pending rows with the same key, which hold
+-- back later ones while they wait for a retry.
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_key ON outbox (topic, message_key, id) WHERE sent_at IS NULL;
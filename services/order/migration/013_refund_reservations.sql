@@ -0,0 +1,6 @@
+-- Refunds are reserved as pending before the payment provider is asked to
+-- return the money, so the amount already counts towards refunded_total
+-- while the provider call is in flight. A pending refund is completed once
+-- the provider accepts it, or deleted again if it declines.
+ALTER TABLE order_refunds
+  ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'completed';
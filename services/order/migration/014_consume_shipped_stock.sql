@@ -0,0 +1,5 @@
+-- Shipping an order now consumes its stock reservation. Reservations of
+-- orders shipped before are consumed here, so deleting such an order no
+-- longer gives their units back to stock.
+DELETE FROM stock_reservations
+WHERE order_id IN (SELECT id FROM orders WHERE status IN ('shipped', 'delivered'));
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpcontroller "product-app/services/order/internal/adapters/http/controller"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+	"product-app/shared/auth"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCartServer(carts ...domain.Cart) *echo.Echo {
+	orders := NewFakeOrderRepository(nil)
+	catalog := NewFakeProductCatalog(
+		domain.CatalogProduct{Id: 1, Name: "Keyboard", Price: 10},
+		domain.CatalogProduct{Id: 2, Name: "Mouse", Price: 5},
+	)
+	cartService := usecase.NewCartService(NewFakeCartRepository(orders, carts...), catalog, time.Hour)
+
+	e := echo.New()
+	httpcontroller.NewCartController(cartService).RegisterRoutes(e)
+	httpcontroller.NewStockController(usecase.NewStockService(&FakeStockRepository{})).RegisterRoutes(e)
+	return e
+}
+
+// apiCall sends a request as userId with role, or anonymously when userId
+// is 0, with the given cart token.
+func apiCall(e *echo.Echo, method, path string, userId int64, role, cartToken, body string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if userId > 0 {
+		token, _ := auth.GenerateToken(userId, "buyer", "buyer@example.com", role)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	if cartToken != "" {
+		req.Header.Set(httpcontroller.CartTokenHeader, cartToken)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeCart(t *testing.T, rec *httptest.ResponseRecorder) domain.Cart {
+	var cart domain.Cart
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cart))
+	return cart
+}
+
+func Test_ShouldKeepAnonymousCart_ByToken(t *testing.T) {
+	e := setupCartServer()
+
+	rec := apiCall(e, http.MethodPost, "/api/v1/cart/items", 0, "", "", `{"product_id": 1, "quantity": 2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	token := rec.Header().Get(httpcontroller.CartTokenHeader)
+	assert.NotEmpty(t, token)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/cart", 0, "", token, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	cart := decodeCart(t, rec)
+	assert.Len(t, cart.Items, 1)
+	assert.Equal(t, 20.0, cart.GrandTotal)
+}
+
+func Test_ShouldMergeAnonymousCartAndCheckout_AfterSignIn(t *testing.T) {
+	e := setupCartServer()
+	rec := apiCall(e, http.MethodPost, "/api/v1/cart/items", 0, "", "", `{"product_id": 2, "quantity": 1}`)
+	token := rec.Header().Get(httpcontroller.CartTokenHeader)
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/cart/items", 1, "buyer", token, `{"product_id": 1, "quantity": 1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(httpcontroller.CartTokenHeader))
+	assert.Len(t, decodeCart(t, rec).Items, 2)
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/cart/checkout", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/api/v1/orders/1", rec.Header().Get(echo.HeaderLocation))
+	var order domain.Order
+	json.Unmarshal(rec.Body.Bytes(), &order)
+	assert.Equal(t, 15.0, order.GrandTotal)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/cart", 1, "buyer", "", "")
+	assert.Empty(t, decodeCart(t, rec).Items)
+}
+
+func Test_ShouldUpdateAndRemoveCartItems(t *testing.T) {
+	e := setupCartServer(domain.Cart{Id: 1, UserId: 1, Items: []domain.CartItem{{ProductID: 1, Variant: "uk", Quantity: 1}}})
+
+	rec := apiCall(e, http.MethodPut, "/api/v1/cart/items/1", 1, "buyer", "", `{"variant": "uk", "quantity": 3}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int32(3), decodeCart(t, rec).Items[0].Quantity)
+
+	rec = apiCall(e, http.MethodDelete, "/api/v1/cart/items/1?variant=uk", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, decodeCart(t, rec).Items)
+
+	rec = apiCall(e, http.MethodDelete, "/api/v1/cart/items/1?variant=uk", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func Test_ShouldMapCartErrors(t *testing.T) {
+	e := setupCartServer()
+
+	rec := apiCall(e, http.MethodPost, "/api/v1/cart/items", 0, "", "", `{"product_id": 3, "quantity": 1}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/cart/items", 0, "", "", `{"product_id": 1, "quantity": 0}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/cart/checkout", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/cart/checkout", 0, "", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/cart", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer not-a-token")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func Test_ShouldLetOnlyAdminsSetStock(t *testing.T) {
+	e := setupCartServer()
+
+	rec := apiCall(e, http.MethodPut, "/api/v1/stock/1", 1, "buyer", "", `{"available": 5}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = apiCall(e, http.MethodPut, "/api/v1/stock/1", 1, "admin", "", `{"available": -1}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = apiCall(e, http.MethodPut, "/api/v1/stock/1", 1, "admin", "", `{"available": 5}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/stock/1", 1, "admin", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"available":5`)
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"fmt"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+)
+
+type FakeInvoiceRepository struct {
+	invoices  []domain.Invoice
+	sequences map[string]int64
+}
+
+func NewFakeInvoiceRepository() *FakeInvoiceRepository {
+	return &FakeInvoiceRepository{sequences: map[string]int64{}}
+}
+
+var _ ports.InvoiceRepository = (*FakeInvoiceRepository)(nil)
+
+func (repo *FakeInvoiceRepository) Issue(invoice domain.Invoice) (domain.Invoice, error) {
+	if invoice.Kind == domain.InvoiceKindInvoice {
+		if _, err := repo.GetInvoice(invoice.OrderId); err == nil {
+			return domain.Invoice{}, fmt.Errorf("%w: order %d", domain.ErrInvoiceAlreadyIssued, invoice.OrderId)
+		}
+	}
+	if invoice.Kind == domain.InvoiceKindCreditNote {
+		if err := repo.checkCreditable(invoice); err != nil {
+			return domain.Invoice{}, err
+		}
+	}
+	invoice.Year = invoice.IssuedAt.Year()
+	key := fmt.Sprintf("%s/%d", invoice.Kind, invoice.Year)
+	repo.sequences[key]++
+	invoice.Sequence = repo.sequences[key]
+	invoice.Number = domain.InvoiceNumber(invoice.Kind, invoice.Year, invoice.Sequence)
+	invoice.Id = int64(len(repo.invoices)) + 1
+	repo.invoices = append(repo.invoices, invoice)
+	return invoice, nil
+}
+
+func (repo *FakeInvoiceRepository) GetInvoice(orderId int64) (domain.Invoice, error) {
+	for _, invoice := range repo.invoices {
+		if invoice.OrderId == orderId && invoice.Kind == domain.InvoiceKindInvoice {
+			return invoice, nil
+		}
+	}
+	return domain.Invoice{}, fmt.Errorf("%w for order %d", domain.ErrInvoiceNotFound, orderId)
+}
+
+func (repo *FakeInvoiceRepository) GetCreditNotes(orderId int64) ([]domain.Invoice, error) {
+	creditNotes := []domain.Invoice{}
+	for _, invoice := range repo.invoices {
+		if invoice.OrderId == orderId && invoice.Kind == domain.InvoiceKindCreditNote {
+			creditNotes = append(creditNotes, invoice)
+		}
+	}
+	return creditNotes, nil
+}
+
+// checkCreditable fails like the Postgres repository when creditNote
+// credits more units of a line than the order's invoice and credit notes
+// leave.
+func (repo *FakeInvoiceRepository) checkCreditable(creditNote domain.Invoice) error {
+	uncredited := map[string]int32{}
+	for _, invoice := range repo.invoices {
+		if invoice.OrderId != creditNote.OrderId {
+			continue
+		}
+		for _, line := range invoice.Lines {
+			uncredited[fmt.Sprintf("%d/%s", line.ProductID, line.Variant)] += line.Quantity
+		}
+	}
+	for _, line := range creditNote.Lines {
+		key := fmt.Sprintf("%d/%s", line.ProductID, line.Variant)
+		if uncredited[key]+line.Quantity < 0 {
+			return fmt.Errorf("%w: only %d of product %d are left to credit", domain.ErrInvalidCreditNote, uncredited[key], line.ProductID)
+		}
+		uncredited[key] += line.Quantity
+	}
+	return nil
+}
@@ -1,8 +1,8 @@
 package controller
 
 import (
-	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"product-app/services/order/internal/domain"
@@ -10,54 +10,210 @@ import (
 )
 
 type FakeOrderRepository struct {
-	orders []domain.Order
+	orders         []domain.Order
+	history        []domain.OrderStatusChange
+	refunds        []domain.Refund
+	refundSequence int64
+	outbox         *FakeOutbox
 }
 
-func NewFakeOrderRepository(initialOrders []domain.Order) ports.OrderRepository {
-	return &FakeOrderRepository{orders: initialOrders}
+func NewFakeOrderRepository(initialOrders []domain.Order) *FakeOrderRepository {
+	return &FakeOrderRepository{orders: initialOrders, outbox: &FakeOutbox{}}
 }
 
-func (repo *FakeOrderRepository) GetAll() ([]domain.Order, error) {
-	return repo.orders, nil
-}
+var _ ports.OrderRepository = (*FakeOrderRepository)(nil)
 
-func (repo *FakeOrderRepository) GetById(id int64) (domain.Order, error) {
+func (repo *FakeOrderRepository) Find(query domain.OrderQuery) ([]domain.Order, error) {
+	var orders []domain.Order
 	for _, order := range repo.orders {
-		if order.Id == id {
-			return order, nil
+		if matchesQuery(order, query) {
+			orders = append(orders, order)
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		return sortsBefore(orders[i], orders[j].Id, orders[j].OrderTime, orders[j].GrandTotal, query)
+	})
+	if query.After != nil {
+		var after []domain.Order
+		for _, order := range orders {
+			if !sortsBefore(order, query.After.Id, query.After.OrderTime, query.After.GrandTotal, query) &&
+				order.Id != query.After.Id {
+				after = append(after, order)
+			}
 		}
+		orders = after
 	}
-	return domain.Order{}, fmt.Errorf("order not found with id %d", id)
+	if len(orders) > query.Limit {
+		orders = orders[:query.Limit]
+	}
+	return orders, nil
 }
 
-func (repo *FakeOrderRepository) GetByCustomerNumber(customerNumber string) ([]domain.Order, error) {
-	var orders []domain.Order
-	for _, order := range repo.orders {
-		if order.CustomerNumber == customerNumber {
-			orders = append(orders, order)
+func matchesQuery(order domain.Order, query domain.OrderQuery) bool {
+	if !query.From.IsZero() && order.OrderTime.Before(query.From) {
+		return false
+	}
+	if !query.To.IsZero() && !order.OrderTime.Before(query.To) {
+		return false
+	}
+	if query.Status != "" && order.Status != query.Status {
+		return false
+	}
+	if query.CustomerNumber != "" && order.CustomerNumber != query.CustomerNumber {
+		return false
+	}
+	if query.UserId != 0 && order.UserId != query.UserId {
+		return false
+	}
+	if query.ProductId != 0 {
+		for _, item := range order.Items {
+			if item.ProductID == query.ProductId {
+				return true
+			}
 		}
+		return false
+	}
+	return true
+}
+
+// sortsBefore reports whether order comes before the position (id,
+// orderTime, grandTotal) in the sort of query.
+func sortsBefore(order domain.Order, id int64, orderTime time.Time, grandTotal float64, query domain.OrderQuery) bool {
+	var less, equal bool
+	if query.SortBy == domain.OrderSortTotal {
+		less, equal = order.GrandTotal < grandTotal, order.GrandTotal == grandTotal
+	} else {
+		less, equal = order.OrderTime.Before(orderTime), order.OrderTime.Equal(orderTime)
 	}
-	if len(orders) == 0 {
-		return nil, errors.New("no orders found for customer")
+	if equal {
+		less = order.Id < id
+		equal = order.Id == id
 	}
-	return orders, nil
+	if query.Descending {
+		return !less && !equal
+	}
+	return less
+}
+
+func (repo *FakeOrderRepository) GetById(id int64) (domain.Order, error) {
+	for _, order := range repo.orders {
+		if order.Id == id {
+			return order, nil
+		}
+	}
+	return domain.Order{}, fmt.Errorf("%w with id %d", domain.ErrOrderNotFound, id)
 }
 
-func (repo *FakeOrderRepository) Create(order domain.Order) (domain.Order, error) {
+func (repo *FakeOrderRepository) Create(order domain.Order, events ports.OrderEvents) (domain.Order, error) {
 	order.Id = int64(len(repo.orders)) + 1
 	if order.OrderTime.IsZero() {
 		order.OrderTime = time.Now()
 	}
 	repo.orders = append(repo.orders, order)
+	repo.history = append(repo.history, domain.OrderStatusChange{
+		Id:        int64(len(repo.history)) + 1,
+		OrderId:   order.Id,
+		ToStatus:  order.Status,
+		ChangedAt: order.OrderTime,
+	})
+	repo.outbox.recordOrder(events, order)
 	return order, nil
 }
 
-func (repo *FakeOrderRepository) Delete(id int64) error {
+func (repo *FakeOrderRepository) Delete(id int64, events ports.OrderEvents) (domain.Order, error) {
 	for i, order := range repo.orders {
 		if order.Id == id {
 			repo.orders = append(repo.orders[:i], repo.orders[i+1:]...)
-			return nil
+			repo.outbox.recordOrder(events, order)
+			return order, nil
+		}
+	}
+	return domain.Order{}, fmt.Errorf("%w with id %d", domain.ErrOrderNotFound, id)
+}
+
+func (repo *FakeOrderRepository) UpdateStatus(id int64, change domain.OrderStatusChange, events ports.OrderEvents) (domain.Order, error) {
+	for i, order := range repo.orders {
+		if order.Id != id {
+			continue
+		}
+		if order.Status != change.FromStatus {
+			return domain.Order{}, fmt.Errorf("%w: order %d is %s", domain.ErrIllegalTransition, id, order.Status)
+		}
+		repo.orders[i].Status = change.ToStatus
+		change.Id = int64(len(repo.history)) + 1
+		change.OrderId = id
+		repo.history = append(repo.history, change)
+		repo.outbox.recordOrder(events, repo.orders[i])
+		return repo.orders[i], nil
+	}
+	return domain.Order{}, fmt.Errorf("%w with id %d", domain.ErrOrderNotFound, id)
+}
+
+func (repo *FakeOrderRepository) GetStatusHistory(id int64) ([]domain.OrderStatusChange, error) {
+	history := []domain.OrderStatusChange{}
+	for _, change := range repo.history {
+		if change.OrderId == id {
+			history = append(history, change)
+		}
+	}
+	return history, nil
+}
+
+func (repo *FakeOrderRepository) ReserveRefund(refund domain.Refund) (domain.Refund, error) {
+	for i, order := range repo.orders {
+		if order.Id != refund.OrderId {
+			continue
+		}
+		if order.RefundedTotal+refund.Amount > order.GrandTotal {
+			return domain.Refund{}, fmt.Errorf("%w: refund exceeds order %d", domain.ErrInvalidRefund, order.Id)
+		}
+		repo.orders[i].RefundedTotal += refund.Amount
+		repo.refundSequence++
+		refund.Id = repo.refundSequence
+		refund.Status = domain.RefundStatusPending
+		repo.refunds = append(repo.refunds, refund)
+		return refund, nil
+	}
+	return domain.Refund{}, fmt.Errorf("%w with id %d", domain.ErrOrderNotFound, refund.OrderId)
+}
+
+func (repo *FakeOrderRepository) CompleteRefund(refund domain.Refund, events ports.RefundEvents) (domain.Refund, error) {
+	for i, stored := range repo.refunds {
+		if stored.Id != refund.Id || stored.Status != domain.RefundStatusPending {
+			continue
+		}
+		refund.Status = domain.RefundStatusCompleted
+		repo.refunds[i] = refund
+		if events != nil {
+			repo.outbox.record(events(refund))
+		}
+		return refund, nil
+	}
+	return domain.Refund{}, fmt.Errorf("%w: refund %d is not pending", domain.ErrInvalidRefund, refund.Id)
+}
+
+func (repo *FakeOrderRepository) ReleaseRefund(refund domain.Refund) error {
+	for i, stored := range repo.refunds {
+		if stored.Id != refund.Id || stored.Status != domain.RefundStatusPending {
+			continue
+		}
+		repo.refunds = append(repo.refunds[:i], repo.refunds[i+1:]...)
+		for j := range repo.orders {
+			if repo.orders[j].Id == stored.OrderId {
+				repo.orders[j].RefundedTotal -= stored.Amount
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: refund %d is not pending", domain.ErrInvalidRefund, refund.Id)
+}
+
+func (repo *FakeOrderRepository) GetRefunds(orderId int64) ([]domain.Refund, error) {
+	refunds := []domain.Refund{}
+	for _, refund := range repo.refunds {
+		if refund.OrderId == orderId {
+			refunds = append(refunds, refund)
 		}
 	}
-	return fmt.Errorf("order not found with id %d", id)
+	return refunds, nil
 }
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+)
+
+type FakeProductCatalog struct {
+	products    map[int64]domain.CatalogProduct
+	unavailable bool
+}
+
+var _ ports.ProductCatalog = (*FakeProductCatalog)(nil)
+
+func NewFakeProductCatalog(products ...domain.CatalogProduct) *FakeProductCatalog {
+	catalog := &FakeProductCatalog{products: map[int64]domain.CatalogProduct{}}
+	for _, product := range products {
+		catalog.products[product.Id] = product
+	}
+	return catalog
+}
+
+func (catalog *FakeProductCatalog) GetProduct(ctx context.Context, id int64) (domain.CatalogProduct, error) {
+	if catalog.unavailable {
+		return domain.CatalogProduct{}, fmt.Errorf("%w: circuit open", domain.ErrCatalogUnavailable)
+	}
+	product, ok := catalog.products[id]
+	if !ok {
+		return domain.CatalogProduct{}, fmt.Errorf("%w with id %d", domain.ErrProductNotFound, id)
+	}
+	return product, nil
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+)
+
+type FakeStockRepository struct {
+	levels []domain.StockLevel
+}
+
+var _ ports.StockRepository = (*FakeStockRepository)(nil)
+
+func (repo *FakeStockRepository) Set(level domain.StockLevel) (domain.StockLevel, error) {
+	for i, existing := range repo.levels {
+		if existing.ProductID == level.ProductID && existing.Variant == level.Variant {
+			repo.levels[i] = level
+			return level, nil
+		}
+	}
+	repo.levels = append(repo.levels, level)
+	return level, nil
+}
+
+func (repo *FakeStockRepository) GetByProductId(productId int64) ([]domain.StockLevel, error) {
+	levels := []domain.StockLevel{}
+	for _, level := range repo.levels {
+		if level.ProductID == productId {
+			levels = append(levels, level)
+		}
+	}
+	return levels, nil
+}
@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpcontroller "product-app/services/order/internal/adapters/http/controller"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"product-app/services/order/internal/usecase"
+	"product-app/shared/auth"
+	"product-app/shared/idempotency"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const orderPayload = `{"items": [{"product_id": 9, "quantity": 3, "unit_price": 12.5}]}`
+
+func setupIdempotentServer() (*echo.Echo, *FakeOrderRepository) {
+	fakeRepo := NewFakeOrderRepository(nil)
+	orderController := httpcontroller.NewOrderController(usecase.NewOrderService(fakeRepo, nil, nil, nil))
+
+	e := echo.New()
+	orderController.RegisterRoutes(e, idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour))
+	return e, fakeRepo
+}
+
+func postOrder(e *echo.Echo, userId int64, key, payload string) *httptest.ResponseRecorder {
+	token, _ := auth.GenerateToken(userId, "buyer", "buyer@example.com")
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(payload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	if key != "" {
+		req.Header.Set(idempotency.HeaderKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_ShouldReplayOrderCreation_WhenIdempotencyKeyRepeated(t *testing.T) {
+	e, fakeRepo := setupIdempotentServer()
+
+	first := postOrder(e, 1, "order-key-1", orderPayload)
+	second := postOrder(e, 1, "order-key-1", orderPayload)
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "/api/v1/orders/1", second.Header().Get(echo.HeaderLocation))
+	assert.Equal(t, "true", second.Header().Get(idempotency.HeaderReplayed))
+	assert.Empty(t, first.Header().Get(idempotency.HeaderReplayed))
+	assert.Len(t, fakeRepo.orders, 1)
+}
+
+func Test_ShouldRejectReusedIdempotencyKey_WithDifferentBody(t *testing.T) {
+	e, fakeRepo := setupIdempotentServer()
+
+	first := postOrder(e, 1, "order-key-1", orderPayload)
+	second := postOrder(e, 1, "order-key-1", `{"items": [{"product_id": 9, "quantity": 4, "unit_price": 12.5}]}`)
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, second.Code)
+	assert.Len(t, fakeRepo.orders, 1)
+}
+
+func Test_ShouldScopeIdempotencyKeysPerUser(t *testing.T) {
+	e, fakeRepo := setupIdempotentServer()
+
+	first := postOrder(e, 1, "shared-key", orderPayload)
+	second := postOrder(e, 2, "shared-key", orderPayload)
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Empty(t, second.Header().Get(idempotency.HeaderReplayed))
+	assert.Len(t, fakeRepo.orders, 2)
+}
+
+func Test_ShouldCreateOrderEachTime_WithoutIdempotencyKey(t *testing.T) {
+	e, fakeRepo := setupIdempotentServer()
+
+	postOrder(e, 1, "", orderPayload)
+	postOrder(e, 1, "", orderPayload)
+
+	assert.Len(t, fakeRepo.orders, 2)
+}
+
+func Test_ShouldReplayValidationErrors_WithIdempotencyKey(t *testing.T) {
+	e, fakeRepo := setupIdempotentServer()
+
+	first := postOrder(e, 1, "bad-order", `{"items": []}`)
+	second := postOrder(e, 1, "bad-order", `{"items": []}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, first.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, second.Code)
+	assert.Equal(t, "true", second.Header().Get(idempotency.HeaderReplayed))
+
+	var response map[string]string
+	json.Unmarshal(second.Body.Bytes(), &response)
+	assert.Equal(t, "invalid order: order must contain at least one item", response["error"])
+	assert.Empty(t, fakeRepo.orders)
+}
+
+// flakyOrderRepository fails the first failures creates like a lost
+// database connection.
+type flakyOrderRepository struct {
+	*FakeOrderRepository
+	failures int
+}
+
+func (repo *flakyOrderRepository) Create(order domain.Order, events ports.OrderEvents) (domain.Order, error) {
+	if repo.failures > 0 {
+		repo.failures--
+		return domain.Order{}, errors.New("connection reset by peer")
+	}
+	return repo.FakeOrderRepository.Create(order, events)
+}
+
+func Test_ShouldNotReplayServerErrors_WithIdempotencyKey(t *testing.T) {
+	fakeRepo := &flakyOrderRepository{FakeOrderRepository: NewFakeOrderRepository(nil), failures: 1}
+	e := echo.New()
+	httpcontroller.NewOrderController(usecase.NewOrderService(fakeRepo, nil, nil, nil)).
+		RegisterRoutes(e, idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour))
+
+	first := postOrder(e, 1, "flaky-order", orderPayload)
+	second := postOrder(e, 1, "flaky-order", orderPayload)
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Empty(t, second.Header().Get(idempotency.HeaderReplayed))
+	assert.Len(t, fakeRepo.orders, 1)
+}
+
+// contextRecordingStore records whether Complete got a live context.
+type contextRecordingStore struct {
+	*idempotency.MemoryStore
+	completeErr error
+}
+
+func (store *contextRecordingStore) Complete(ctx context.Context, scope, key string, response idempotency.Response, ttl time.Duration) error {
+	store.completeErr = ctx.Err()
+	return store.MemoryStore.Complete(ctx, scope, key, response, ttl)
+}
+
+func Test_ShouldStoreResponse_WhenClientDisconnected(t *testing.T) {
+	store := &contextRecordingStore{MemoryStore: idempotency.NewMemoryStore()}
+	e := echo.New()
+	e.POST("/orders", func(c echo.Context) error {
+		cancel := c.Get("cancel").(context.CancelFunc)
+		cancel()
+		return c.JSON(http.StatusCreated, map[string]int{"id": 1})
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, cancel := context.WithCancel(c.Request().Context())
+			c.SetRequest(c.Request().WithContext(ctx))
+			c.Set("cancel", cancel)
+			return next(c)
+		}
+	}, idempotency.Middleware(store, time.Hour))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{}`))
+	req.Header.Set(idempotency.HeaderKey, "gone-away")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.NoError(t, store.completeErr)
+	record, reserved, err := store.Reserve(context.Background(), "anonymous", "gone-away", "", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, http.StatusCreated, record.Response.StatusCode)
+}
+
+func Test_ShouldRejectOverlongIdempotencyKey(t *testing.T) {
+	e, _ := setupIdempotentServer()
+
+	rec := postOrder(e, 1, strings.Repeat("k", idempotency.MaxKeyLength+1), orderPayload)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func Test_ShouldRejectOversizedBody_WithIdempotencyKey(t *testing.T) {
+	e, fakeRepo := setupIdempotentServer()
+
+	rec := postOrder(e, 1, "big-order", `{"note": "`+strings.Repeat("x", idempotency.MaxBodyBytes)+`"}`)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Empty(t, fakeRepo.orders)
+}
+
+func Test_ShouldScopeAnonymousIdempotencyKeysPerCart(t *testing.T) {
+	cartService := usecase.NewCartService(NewFakeCartRepository(NewFakeOrderRepository(nil)),
+		NewFakeProductCatalog(domain.CatalogProduct{Id: 1, Name: "Keyboard", Price: 10}), time.Hour)
+	e := echo.New()
+	httpcontroller.NewCartController(cartService).RegisterRoutes(e, idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour))
+	addItem := func(cartToken string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/cart/items", strings.NewReader(`{"product_id": 1, "quantity": 1}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(idempotency.HeaderKey, "add-keyboard")
+		req.Header.Set(httpcontroller.CartTokenHeader, cartToken)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := addItem("cart-a")
+	second := addItem("cart-b")
+	repeated := addItem("cart-a")
+
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Empty(t, second.Header().Get(idempotency.HeaderReplayed))
+	assert.NotEqual(t, first.Header().Get(httpcontroller.CartTokenHeader), second.Header().Get(httpcontroller.CartTokenHeader))
+	assert.Equal(t, "true", repeated.Header().Get(idempotency.HeaderReplayed))
+	assert.Equal(t, first.Body.String(), repeated.Body.String())
+}
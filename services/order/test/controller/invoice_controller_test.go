@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	httpcontroller "product-app/services/order/internal/adapters/http/controller"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupInvoiceServer() *echo.Echo {
+	orderService := usecase.NewOrderService(NewFakeOrderRepository([]domain.Order{
+		{Id: 1, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPaid, GrandTotal: 20,
+			Items: []domain.OrderItem{{ProductID: 1, Quantity: 2, UnitPrice: 10, LineTotal: 20}}},
+		{Id: 2, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPending, GrandTotal: 15},
+	}), nil, nil, nil)
+	invoiceService := usecase.NewInvoiceService(orderService, NewFakeInvoiceRepository(),
+		NewFakeProductCatalog(domain.CatalogProduct{Id: 1, Name: "Keyboard <Pro>", Price: 10}),
+		usecase.InvoiceSettings{Seller: domain.InvoiceParty{Name: "Product Platform"}, Currency: "EUR", TaxRate: 20})
+
+	e := echo.New()
+	httpcontroller.NewInvoiceController(invoiceService).RegisterRoutes(e)
+	return e
+}
+
+func Test_ShouldRenderInvoiceAsPdfByDefault(t *testing.T) {
+	e := setupInvoiceServer()
+
+	rec := apiCall(e, http.MethodGet, "/api/v1/orders/1/invoice", 1, "buyer", "", "")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "INV-")
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "%PDF-1.4"))
+}
+
+func Test_ShouldRenderInvoiceAsHtml_WhenAsked(t *testing.T) {
+	e := setupInvoiceServer()
+
+	rec := apiCall(e, http.MethodGet, "/api/v1/orders/1/invoice?format=html", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "Keyboard &lt;Pro&gt;")
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/orders/1/invoice?format=docx", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func Test_ShouldMapInvoiceErrors(t *testing.T) {
+	e := setupInvoiceServer()
+
+	rec := apiCall(e, http.MethodGet, "/api/v1/orders/2/invoice", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/orders/1/invoice", 2, "buyer", "", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/orders/1/credit-notes", 1, "admin", "", `{"reason":"returned"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "an order without invoice has nothing to credit")
+}
+
+func Test_ShouldIssueAndRenderCreditNote(t *testing.T) {
+	e := setupInvoiceServer()
+	apiCall(e, http.MethodGet, "/api/v1/orders/1/invoice", 1, "buyer", "", "")
+
+	rec := apiCall(e, http.MethodPost, "/api/v1/orders/1/credit-notes", 1, "buyer", "", `{"reason":"returned"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/orders/1/credit-notes", 9, "admin", "",
+		`{"reason":"returned","items":[{"product_id":1,"quantity":1}]}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var creditNote domain.Invoice
+	json.Unmarshal(rec.Body.Bytes(), &creditNote)
+	assert.Equal(t, -10.0, creditNote.GrandTotal)
+	assert.Equal(t, "/api/v1/orders/1/credit-notes/"+creditNote.Number, rec.Header().Get(echo.HeaderLocation))
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/orders/1/credit-notes", 9, "admin", "",
+		`{"reason":"returned","items":[{"product_id":1,"quantity":2}]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/orders/1/credit-notes", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), creditNote.Number)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/orders/1/credit-notes/"+creditNote.Number, 1, "buyer", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/orders/1/credit-notes/CN-1999-000001", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
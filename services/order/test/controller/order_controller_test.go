@@ -11,6 +11,7 @@ import (
 	httpcontroller "product-app/services/order/internal/adapters/http/controller"
 	"product-app/services/order/internal/domain"
 	"product-app/services/order/internal/usecase"
+	"product-app/shared/auth"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -20,22 +21,28 @@ func setupOrderController() *httpcontroller.OrderController {
 	initialOrders := []domain.Order{
 		{
 			Id:             1,
+			UserId:         1,
 			CustomerNumber: "CUST-001",
-			ProductID:      "PROD-1",
-			Quantity:       2,
+			Status:         domain.OrderStatusPending,
+			Items:          []domain.OrderItem{{Id: 1, ProductID: 1, Quantity: 2, UnitPrice: 10, LineTotal: 20}},
+			Subtotal:       20,
+			GrandTotal:     20,
 			OrderTime:      time.Now(),
 		},
 		{
 			Id:             2,
+			UserId:         2,
 			CustomerNumber: "CUST-002",
-			ProductID:      "PROD-2",
-			Quantity:       1,
+			Status:         domain.OrderStatusPaid,
+			Items:          []domain.OrderItem{{Id: 2, ProductID: 2, Quantity: 1, UnitPrice: 15, LineTotal: 15}},
+			Subtotal:       15,
+			GrandTotal:     15,
 			OrderTime:      time.Now(),
 		},
 	}
 
 	fakeRepo := NewFakeOrderRepository(initialOrders)
-	orderService := usecase.NewOrderService(fakeRepo)
+	orderService := usecase.NewOrderService(fakeRepo, nil, nil, nil)
 	return httpcontroller.NewOrderController(orderService)
 }
 
@@ -44,6 +51,8 @@ func Test_ShouldGetAllOrders(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
+	c.Set("roles", []string{auth.RoleAdmin})
 
 	controller := setupOrderController()
 
@@ -51,9 +60,47 @@ func Test_ShouldGetAllOrders(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var orders []map[string]interface{}
-	json.Unmarshal(rec.Body.Bytes(), &orders)
-	assert.Equal(t, 2, len(orders))
+	var page domain.OrderPage
+	json.Unmarshal(rec.Body.Bytes(), &page)
+	assert.Equal(t, 2, len(page.Orders))
+}
+
+func Test_ShouldFilterAndPageAllOrders(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?status=paid&sort=-grand_total&limit=1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
+	c.Set("roles", []string{auth.RoleAdmin})
+
+	controller := setupOrderController()
+
+	err := controller.GetAllOrders(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var page domain.OrderPage
+	json.Unmarshal(rec.Body.Bytes(), &page)
+	assert.Len(t, page.Orders, 1)
+	assert.Equal(t, int64(2), page.Orders[0].Id)
+	assert.Empty(t, page.NextCursor)
+}
+
+func Test_ShouldReturnBadRequest_WhenOrderSearchInvalid(t *testing.T) {
+	for _, query := range []string{"from=yesterday", "limit=0", "product_id=x", "sort=status", "limit=101", "cursor=abc"} {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user_id", int64(1))
+		c.Set("roles", []string{auth.RoleAdmin})
+
+		controller := setupOrderController()
+
+		err := controller.GetAllOrders(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
 }
 
 func Test_ShouldGetOrderById(t *testing.T) {
@@ -61,6 +108,7 @@ func Test_ShouldGetOrderById(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/1", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
@@ -70,10 +118,12 @@ func Test_ShouldGetOrderById(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var response map[string]interface{}
+	var response domain.Order
 	json.Unmarshal(rec.Body.Bytes(), &response)
-	assert.Equal(t, "CUST-001", response["customer_number"])
-	assert.Equal(t, "PROD-1", response["product_id"])
+	assert.Equal(t, "CUST-001", response.CustomerNumber)
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, int64(1), response.Items[0].ProductID)
+	assert.Equal(t, 20.0, response.GrandTotal)
 }
 
 func Test_ShouldGetOrdersByCustomerNumber(t *testing.T) {
@@ -81,6 +131,7 @@ func Test_ShouldGetOrdersByCustomerNumber(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/customer/CUST-001", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
 	c.SetParamNames("customerNumber")
 	c.SetParamValues("CUST-001")
 
@@ -90,38 +141,102 @@ func Test_ShouldGetOrdersByCustomerNumber(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 
-	var orders []map[string]interface{}
-	json.Unmarshal(rec.Body.Bytes(), &orders)
-	assert.Equal(t, 1, len(orders))
-	assert.Equal(t, "CUST-001", orders[0]["customer_number"])
+	var page domain.OrderPage
+	json.Unmarshal(rec.Body.Bytes(), &page)
+	assert.Equal(t, 1, len(page.Orders))
+	assert.Equal(t, "CUST-001", page.Orders[0].CustomerNumber)
 }
 
 func Test_ShouldCreateOrder(t *testing.T) {
 	e := echo.New()
 	payload := `{
 		"customer_number": "CUST-003",
-		"product_id": "PROD-9",
-		"quantity": 3
+		"items": [
+			{"product_id": 9, "quantity": 3, "unit_price": 12.5},
+			{"product_id": 4, "variant": "XL", "quantity": 1, "unit_price": 40, "discount": 5}
+		]
 	}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(payload))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
 
 	controller := setupOrderController()
 
 	err := controller.CreateOrder(c)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/api/v1/orders/3", rec.Header().Get(echo.HeaderLocation))
+
+	var created domain.Order
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Len(t, created.Items, 2)
+	assert.Equal(t, 77.5, created.Subtotal)
+	assert.Equal(t, 5.0, created.DiscountTotal)
+	assert.Equal(t, 72.5, created.GrandTotal)
+}
+
+func Test_ShouldRejectOrder_WhenProductDoesNotExist(t *testing.T) {
+	e := echo.New()
+	payload := `{"customer_number": "CUST-003", "items": [{"product_id": 42, "quantity": 1}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(payload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
+
+	orderService := usecase.NewOrderService(NewFakeOrderRepository(nil), NewFakeProductCatalog(), nil, nil)
+	controller := httpcontroller.NewOrderController(orderService)
+
+	err := controller.CreateOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func Test_ShouldReturnServiceUnavailable_WhenCatalogUnavailable(t *testing.T) {
+	e := echo.New()
+	payload := `{"customer_number": "CUST-003", "items": [{"product_id": 1, "quantity": 1}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(payload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
+
+	catalog := NewFakeProductCatalog()
+	catalog.unavailable = true
+	orderService := usecase.NewOrderService(NewFakeOrderRepository(nil), catalog, nil, nil)
+	controller := httpcontroller.NewOrderController(orderService)
+
+	err := controller.CreateOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func Test_ShouldRejectOrderWithoutItems(t *testing.T) {
+	e := echo.New()
+	payload := `{"customer_number": "CUST-003", "items": []}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(payload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
+
+	controller := setupOrderController()
+
+	err := controller.CreateOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 }
 
 func Test_ShouldCreateOrder_InvalidJSON(t *testing.T) {
 	e := echo.New()
-	payload := `{"customer_number": "CUST-003", "quantity": "bad"}`
+	payload := `{"customer_number": "CUST-003", "items": [{"product_id": 1, "quantity": "bad"}]}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(payload))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
 
 	controller := setupOrderController()
 
@@ -135,6 +250,8 @@ func Test_ShouldDeleteOrder(t *testing.T) {
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/orders/1", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(99))
+	c.Set("roles", []string{auth.RoleAdmin})
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
@@ -144,3 +261,322 @@ func Test_ShouldDeleteOrder(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+func Test_ShouldForbidDelete_WhenNotAdmin(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/orders/1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	controller := setupOrderController()
+
+	err := controller.DeleteOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func Test_ShouldCancelOwnOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/cancel", strings.NewReader(`{"reason_code":"customer_request","note":"changed my mind"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user_id", int64(1))
+
+	controller := setupOrderController()
+
+	err := controller.CancelOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var order domain.Order
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &order))
+	assert.Equal(t, domain.OrderStatusCancelled, order.Status)
+}
+
+func Test_ShouldReturnUnprocessableEntity_WhenCancelReasonUnknown(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/cancel", strings.NewReader(`{"reason_code":"bored"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user_id", int64(1))
+
+	controller := setupOrderController()
+
+	err := controller.CancelOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func Test_ShouldForbidCancellingAnotherUsersOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/2/cancel", strings.NewReader(`{"reason_code":"customer_request"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("user_id", int64(1))
+
+	controller := setupOrderController()
+
+	err := controller.CancelOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func Test_ShouldRefundPaidOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/2/refunds", strings.NewReader(`{"amount":5,"reason":"damaged box"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("user_id", int64(99))
+	c.Set("roles", []string{auth.RoleAdmin})
+
+	controller := setupOrderController()
+
+	err := controller.RefundOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var refund domain.Refund
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &refund))
+	assert.Equal(t, 5.0, refund.Amount)
+	assert.Equal(t, int64(2), refund.OrderId)
+}
+
+func Test_ShouldReturnUnprocessableEntity_WhenRefundExceedsOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/2/refunds", strings.NewReader(`{"amount":50}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("user_id", int64(99))
+	c.Set("roles", []string{auth.RoleAdmin})
+
+	controller := setupOrderController()
+
+	err := controller.RefundOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func Test_ShouldReturnBadGateway_WhenRefundDeclined(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/2/cancel", strings.NewReader(`{"reason_code":"out_of_stock"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("user_id", int64(99))
+	c.Set("roles", []string{auth.RoleAdmin})
+
+	orderService := usecase.NewOrderService(NewFakeOrderRepository([]domain.Order{
+		{Id: 2, UserId: 2, CustomerNumber: "CUST-002", Status: domain.OrderStatusPaid, GrandTotal: 15},
+	}), nil, &FakePaymentGateway{decline: true}, NewFakePaymentRepository([]domain.Payment{
+		{Id: 1, OrderId: 2, Provider: "fake", ProviderPaymentId: "pi_2", Amount: 15, Status: domain.PaymentStatusCaptured},
+	}))
+	controller := httpcontroller.NewOrderController(orderService)
+
+	err := controller.CancelOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
+func Test_ShouldTransitionOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/transitions", strings.NewReader(`{"status":"paid","reason":"card captured"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user_id", int64(42))
+
+	controller := setupOrderController()
+
+	err := controller.TransitionOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func Test_ShouldTransitionOrder_WhenAdmin(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/transitions", strings.NewReader(`{"status":"paid","reason":"card captured"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user_id", int64(99))
+	c.Set("roles", []string{auth.RoleAdmin})
+
+	controller := setupOrderController()
+
+	err := controller.TransitionOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response domain.Order
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, domain.OrderStatusPaid, response.Status)
+}
+
+func Test_ShouldReturnConflict_WhenTransitionIsIllegal(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/transitions", strings.NewReader(`{"status":"delivered"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user_id", int64(99))
+	c.Set("roles", []string{auth.RoleAdmin})
+
+	controller := setupOrderController()
+
+	err := controller.TransitionOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func Test_ShouldReturnUnprocessableEntity_WhenStatusUnknown(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/transitions", strings.NewReader(`{"status":"lost"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user_id", int64(99))
+	c.Set("roles", []string{auth.RoleAdmin})
+
+	controller := setupOrderController()
+
+	err := controller.TransitionOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func Test_ShouldReturnNotFound_WhenTransitioningMissingOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/99/transitions", strings.NewReader(`{"status":"paid"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("99")
+	c.Set("user_id", int64(99))
+	c.Set("roles", []string{auth.RoleAdmin})
+
+	controller := setupOrderController()
+
+	err := controller.TransitionOrder(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func Test_ShouldForbidListingAllOrders_WhenNotAdmin(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(1))
+
+	controller := setupOrderController()
+
+	err := controller.GetAllOrders(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func Test_ShouldForbidReadingAnotherUsersOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/2", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("user_id", int64(1))
+
+	controller := setupOrderController()
+
+	err := controller.GetOrderById(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func Test_ShouldLetAdminReadAnyOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/2", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("user_id", int64(99))
+	c.Set("roles", []string{auth.RoleAdmin})
+
+	controller := setupOrderController()
+
+	err := controller.GetOrderById(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func Test_ShouldGetMyOrders(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/me", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(2))
+
+	controller := setupOrderController()
+
+	err := controller.GetMyOrders(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var page domain.OrderPage
+	json.Unmarshal(rec.Body.Bytes(), &page)
+	assert.Len(t, page.Orders, 1)
+	assert.Equal(t, int64(2), page.Orders[0].Id)
+}
+
+func Test_ShouldRejectOrderReads_WithoutUser(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/me", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	controller := setupOrderController()
+
+	err := controller.GetMyOrders(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func Test_ShouldRequireTokenForOrderRoutes(t *testing.T) {
+	e := echo.New()
+	setupOrderController().RegisterRoutes(e)
+
+	for _, path := range []string{"/api/v1/orders", "/api/v1/orders/1", "/api/v1/orders/me", "/api/v1/orders/customer/CUST-001"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+	}
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpcontroller "product-app/services/order/internal/adapters/http/controller"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupPaymentController(gateway *FakePaymentGateway) *httpcontroller.PaymentController {
+	payments := NewFakePaymentRepository([]domain.Payment{
+		{Id: 1, OrderId: 1, Provider: "fake", ProviderPaymentId: "pi_existing", Amount: 20, Status: domain.PaymentStatusFailed},
+	})
+	orderService := usecase.NewOrderService(NewFakeOrderRepository([]domain.Order{
+		{Id: 1, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPending, GrandTotal: 20},
+		{Id: 2, UserId: 2, CustomerNumber: "CUST-002", Status: domain.OrderStatusPaid, GrandTotal: 15},
+	}), nil, gateway, payments)
+	return httpcontroller.NewPaymentController(usecase.NewPaymentService(orderService, payments, gateway))
+}
+
+func paymentRequest(body string, orderId string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/"+orderId+"/payments", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(orderId)
+	c.Set("user_id", int64(1))
+	return c, rec
+}
+
+func webhookRequest(body string, signature string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payments/webhook", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if signature != "" {
+		req.Header.Set(fakeSignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	return e.NewContext(req, rec), rec
+}
+
+func Test_ShouldStartPayment(t *testing.T) {
+	c, rec := paymentRequest(`{"payment_method":"pm_card_visa"}`, "1")
+	controller := setupPaymentController(&FakePaymentGateway{})
+
+	err := controller.StartPayment(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/api/v1/orders/1/payments", rec.Header().Get(echo.HeaderLocation))
+
+	var payment domain.Payment
+	json.Unmarshal(rec.Body.Bytes(), &payment)
+	assert.Equal(t, domain.PaymentStatusPending, payment.Status)
+	assert.Equal(t, "pi_1_secret", payment.ClientSecret)
+}
+
+func Test_ShouldMapPaymentErrors(t *testing.T) {
+	c, rec := paymentRequest(`{}`, "1")
+	err := setupPaymentController(&FakePaymentGateway{decline: true}).StartPayment(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+
+	c, rec = paymentRequest(`{}`, "2")
+	err = setupPaymentController(&FakePaymentGateway{}).StartPayment(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	controller := setupPaymentController(&FakePaymentGateway{})
+	c, _ = paymentRequest(`{}`, "1")
+	assert.NoError(t, controller.StartPayment(c))
+	c, rec = paymentRequest(`{}`, "1")
+	assert.NoError(t, controller.StartPayment(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func Test_ShouldListPaymentsOfOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/1/payments", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user_id", int64(1))
+
+	err := setupPaymentController(&FakePaymentGateway{}).GetPayments(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var payments []domain.Payment
+	json.Unmarshal(rec.Body.Bytes(), &payments)
+	assert.Len(t, payments, 1)
+	assert.Empty(t, payments[0].ClientSecret)
+}
+
+func Test_ShouldAcknowledgeWebhookOnceAndDuplicatesAfter(t *testing.T) {
+	controller := setupPaymentController(&FakePaymentGateway{})
+	body := `{"Id":"evt_1","Type":"payment.failed","ProviderPaymentId":"pi_existing"}`
+
+	c, rec := webhookRequest(body, "valid")
+	assert.NoError(t, controller.HandleWebhook(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "processed")
+
+	c, rec = webhookRequest(body, "valid")
+	assert.NoError(t, controller.HandleWebhook(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "duplicate")
+}
+
+func Test_ShouldRejectWebhook_WhenSignatureInvalid(t *testing.T) {
+	c, rec := webhookRequest(`{"Id":"evt_1"}`, "forged")
+
+	err := setupPaymentController(&FakePaymentGateway{}).HandleWebhook(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func Test_ShouldAnswerNotFound_WhenWebhookPaymentUnknown(t *testing.T) {
+	c, rec := webhookRequest(`{"Id":"evt_1","Type":"payment.captured","ProviderPaymentId":"pi_unknown"}`, "valid")
+
+	err := setupPaymentController(&FakePaymentGateway{}).HandleWebhook(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
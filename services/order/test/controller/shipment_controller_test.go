@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	httpcontroller "product-app/services/order/internal/adapters/http/controller"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupShipmentServer() (*echo.Echo, usecase.IOrderService) {
+	orders := NewFakeOrderRepository([]domain.Order{
+		{Id: 1, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPaid, GrandTotal: 20,
+			Items: []domain.OrderItem{{ProductID: 1, Quantity: 2, UnitPrice: 10, LineTotal: 20}}},
+		{Id: 2, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPending, GrandTotal: 15},
+	})
+	orderService := usecase.NewOrderService(orders, nil, nil, nil)
+	shipmentService := usecase.NewShipmentService(orderService, NewFakeShipmentRepository(orders))
+
+	e := echo.New()
+	httpcontroller.NewShipmentController(shipmentService).RegisterRoutes(e)
+	return e, orderService
+}
+
+func Test_ShouldCreateShipmentAndAppendTrackingEvents(t *testing.T) {
+	e, orderService := setupShipmentServer()
+
+	rec := apiCall(e, http.MethodPost, "/api/v1/orders/1/shipments", 99, "admin", "",
+		`{"carrier":"DHL","tracking_number":"JD0001","items":[{"product_id":1,"quantity":2}]}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var shipment domain.Shipment
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &shipment))
+	assert.Equal(t, "/api/v1/orders/1/shipments/1", rec.Header().Get(echo.HeaderLocation))
+	assert.Equal(t, domain.ShipmentStatusShipped, shipment.Status)
+	order, _ := orderService.GetById(1, model.Requester{Admin: true})
+	assert.Equal(t, domain.OrderStatusShipped, order.Status)
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/orders/1/shipments/1/events", 99, "admin", "",
+		`{"status":"delivered","location":"Berlin","occurred_at":"2026-03-02T09:30:00Z"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &shipment))
+	assert.Equal(t, domain.ShipmentStatusDelivered, shipment.Status)
+	assert.Len(t, shipment.Events, 2)
+	order, _ = orderService.GetById(1, model.Requester{Admin: true})
+	assert.Equal(t, domain.OrderStatusDelivered, order.Status)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/orders/1/shipments", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var shipments []domain.Shipment
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &shipments))
+	assert.Len(t, shipments, 1)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/orders/1/shipments/1", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func Test_ShouldMapShipmentErrors(t *testing.T) {
+	e, _ := setupShipmentServer()
+
+	rec := apiCall(e, http.MethodPost, "/api/v1/orders/1/shipments", 1, "buyer", "", `{"carrier":"DHL","tracking_number":"JD0001"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "only admins create shipments")
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/orders/2/shipments", 99, "admin", "", `{"carrier":"DHL","tracking_number":"JD0001"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code, "the order is not paid")
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/orders/1/shipments", 99, "admin", "",
+		`{"carrier":"DHL","tracking_number":"JD0001","items":[{"product_id":1,"quantity":3}]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/orders/1/shipments", 99, "admin", "",
+		`{"carrier":"DHL","tracking_number":"JD0001","items":[{"product_id":1,"quantity":1}]}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = apiCall(e, http.MethodPost, "/api/v1/orders/1/shipments", 99, "admin", "",
+		`{"carrier":"DHL","tracking_number":"JD0001","items":[{"product_id":1,"quantity":1}]}`)
+	assert.Equal(t, http.StatusConflict, rec.Code, "the tracking number is taken")
+
+	rec = apiCall(e, http.MethodPost, "/api/v1/orders/1/shipments/1/events", 99, "admin", "", `{"status":"teleported"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/orders/1/shipments/42", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = apiCall(e, http.MethodGet, "/api/v1/orders/1/shipments/abc", 1, "buyer", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
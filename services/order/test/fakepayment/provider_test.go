@@ -0,0 +1,150 @@
+package fakepayment
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/adapters/fakepayment"
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const secret = "test-webhook-secret"
+
+// webhookServer verifies the provider's calls with the provider itself and
+// forwards their events. reject makes the next n calls answer 503.
+func webhookServer(provider func() ports.PaymentGateway, reject *int32) (*httptest.Server, chan domain.PaymentEvent) {
+	events := make(chan domain.PaymentEvent, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		event, err := provider().ParseWebhook(payload, r.Header)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if atomic.AddInt32(reject, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		events <- event
+	}))
+	return server, events
+}
+
+func setupProvider(t *testing.T, reject int32) (ports.PaymentGateway, chan domain.PaymentEvent) {
+	var provider ports.PaymentGateway
+	server, events := webhookServer(func() ports.PaymentGateway { return provider }, &reject)
+	t.Cleanup(server.Close)
+	provider = fakepayment.NewProvider(fakepayment.Options{
+		WebhookURL:    server.URL,
+		WebhookSecret: secret,
+		Delay:         50 * time.Millisecond,
+		RetryBackoff:  time.Millisecond,
+	})
+	return provider, events
+}
+
+func nextEvent(t *testing.T, events chan domain.PaymentEvent) domain.PaymentEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook call received")
+		return domain.PaymentEvent{}
+	}
+}
+
+func Test_ShouldAuthorizeCaptureAndRefund(t *testing.T) {
+	provider, events := setupProvider(t, 0)
+	ctx := context.Background()
+
+	intent, err := provider.CreatePaymentIntent(ctx, domain.PaymentIntentRequest{OrderId: 1, Amount: 20, PaymentMethod: "pm_card_visa"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, intent.ClientSecret)
+
+	authorized := nextEvent(t, events)
+	assert.Equal(t, domain.PaymentEventAuthorized, authorized.Type)
+	assert.Equal(t, intent.ProviderPaymentId, authorized.ProviderPaymentId)
+	assert.Equal(t, 20.0, authorized.Amount)
+
+	assert.NoError(t, provider.Capture(ctx, intent.ProviderPaymentId, 20))
+	captured := nextEvent(t, events)
+	assert.Equal(t, domain.PaymentEventCaptured, captured.Type)
+	assert.NotEqual(t, authorized.Id, captured.Id)
+
+	reference, err := provider.Refund(ctx, domain.RefundRequest{ProviderPaymentId: intent.ProviderPaymentId, Amount: 15})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, reference)
+	_, err = provider.Refund(ctx, domain.RefundRequest{ProviderPaymentId: intent.ProviderPaymentId, Amount: 10})
+	assert.ErrorIs(t, err, domain.ErrRefundFailed)
+}
+
+func Test_ShouldDeclinePayment(t *testing.T) {
+	provider, events := setupProvider(t, 0)
+	ctx := context.Background()
+
+	intent, err := provider.CreatePaymentIntent(ctx, domain.PaymentIntentRequest{OrderId: 1, Amount: 20, PaymentMethod: fakepayment.MethodDecline})
+	assert.NoError(t, err)
+
+	failed := nextEvent(t, events)
+	assert.Equal(t, domain.PaymentEventFailed, failed.Type)
+	assert.Equal(t, "card declined", failed.FailureReason)
+	assert.ErrorIs(t, provider.Capture(ctx, intent.ProviderPaymentId, 20), domain.ErrPaymentDeclined)
+}
+
+func Test_ShouldDelayAuthorization(t *testing.T) {
+	provider, events := setupProvider(t, 0)
+	started := time.Now()
+
+	_, err := provider.CreatePaymentIntent(context.Background(), domain.PaymentIntentRequest{OrderId: 1, Amount: 20, PaymentMethod: fakepayment.MethodDelay})
+	assert.NoError(t, err)
+
+	authorized := nextEvent(t, events)
+	assert.Equal(t, domain.PaymentEventAuthorized, authorized.Type)
+	assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+}
+
+func Test_ShouldRedeliverEvent_UntilAcknowledged(t *testing.T) {
+	provider, events := setupProvider(t, 2)
+
+	_, err := provider.CreatePaymentIntent(context.Background(), domain.PaymentIntentRequest{OrderId: 1, Amount: 20})
+	assert.NoError(t, err)
+
+	assert.Equal(t, domain.PaymentEventAuthorized, nextEvent(t, events).Type)
+}
+
+func Test_ShouldRejectForgedOrStaleSignatures(t *testing.T) {
+	provider := fakepayment.NewProvider(fakepayment.Options{WebhookSecret: secret})
+	payload := []byte(`{"id":"evt_1","type":"payment.captured","payment_id":"pi_1","amount":20,"created":1}`)
+	header := func(signature string) http.Header {
+		header := http.Header{}
+		header.Set(fakepayment.SignatureHeader, signature)
+		return header
+	}
+
+	event, err := provider.ParseWebhook(payload, header(fakepayment.Sign(secret, payload, time.Now())))
+	assert.NoError(t, err)
+	assert.Equal(t, "evt_1", event.Id)
+	assert.Equal(t, "pi_1", event.ProviderPaymentId)
+
+	_, err = provider.ParseWebhook(payload, header(fakepayment.Sign("another-secret", payload, time.Now())))
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhookSignature)
+
+	stale := time.Now().Add(-fakepayment.SignatureTolerance - time.Minute)
+	_, err = provider.ParseWebhook(payload, header(fakepayment.Sign(secret, payload, stale)))
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhookSignature)
+
+	tampered := []byte(`{"id":"evt_1","type":"payment.captured","payment_id":"pi_1","amount":2000,"created":1}`)
+	_, err = provider.ParseWebhook(tampered, header(fakepayment.Sign(secret, payload, time.Now())))
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhookSignature)
+
+	_, err = provider.ParseWebhook(payload, http.Header{})
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhookSignature)
+}
@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/adapters/postgresql/common"
+	"product-app/services/order/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCartRepository_GetOrCreateForUser(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewCartRepository(dbPool, testEventsTopic)
+
+	created, err := repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.NotZero(t, created.Id)
+	assert.Empty(t, created.Items)
+	assert.Nil(t, created.ExpiresAt)
+
+	found, err := repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.Equal(t, created.Id, found.Id)
+}
+
+func TestCartRepository_AddSetAndRemoveItems(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewCartRepository(dbPool, testEventsTopic)
+	cart, err := repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.AddItem(cart, domain.CartItem{ProductID: 1, Quantity: 2}))
+	assert.NoError(t, repo.AddItem(cart, domain.CartItem{ProductID: 1, Quantity: 1}))
+	assert.NoError(t, repo.AddItem(cart, domain.CartItem{ProductID: 1, Variant: "red", Quantity: 1}))
+	cart, err = repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.Len(t, cart.Items, 2)
+	assert.Equal(t, int32(3), cart.Items[0].Quantity)
+
+	assert.NoError(t, repo.SetItemQuantity(cart, 1, "", 5))
+	assert.NoError(t, repo.SetItemQuantity(cart, 1, "red", 0))
+	cart, err = repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.Len(t, cart.Items, 1)
+	assert.Equal(t, int32(5), cart.Items[0].Quantity)
+
+	assert.ErrorIs(t, repo.SetItemQuantity(cart, 2, "", 1), domain.ErrCartItemNotFound)
+}
+
+func TestCartRepository_MergeAnonymousCart(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewCartRepository(dbPool, testEventsTopic)
+	userCart, err := repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.AddItem(userCart, domain.CartItem{ProductID: 1, Quantity: 1}))
+	anonymous, err := repo.CreateAnonymous("visitor", time.Now().UTC().Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.NoError(t, repo.AddItem(anonymous, domain.CartItem{ProductID: 1, Quantity: 2}))
+	assert.NoError(t, repo.AddItem(anonymous, domain.CartItem{ProductID: 2, Quantity: 1}))
+
+	assert.NoError(t, repo.Merge(anonymous.Id, userCart.Id))
+
+	merged, err := repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.Len(t, merged.Items, 2)
+	assert.Equal(t, int32(3), merged.Items[0].Quantity)
+	_, err = repo.GetByToken("visitor")
+	assert.ErrorIs(t, err, domain.ErrCartNotFound)
+	assert.ErrorIs(t, repo.Merge(anonymous.Id, userCart.Id), domain.ErrCartNotFound)
+}
+
+func TestCartRepository_CheckoutStoresOrderAndEmptiesCart(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewCartRepository(dbPool, testEventsTopic)
+	cart, err := repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.AddItem(cart, domain.CartItem{ProductID: 7, Variant: "red", Quantity: 2}))
+	cart, err = repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	_, err = postgresql.NewStockRepository(dbPool).Set(domain.StockLevel{ProductID: 7, Variant: "red", Available: 5})
+	assert.NoError(t, err)
+
+	order, err := repo.Checkout(cart, stockOrder(2), nil)
+
+	assert.NoError(t, err)
+	assert.NotZero(t, order.Id)
+	assert.Equal(t, int32(3), availableStock(t, 7))
+	emptied, err := repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.Empty(t, emptied.Items)
+}
+
+func TestCartRepository_CheckoutKeepsCartOnConflict(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewCartRepository(dbPool, testEventsTopic)
+	cart, err := repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.AddItem(cart, domain.CartItem{ProductID: 7, Variant: "red", Quantity: 2}))
+	cart, err = repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.AddItem(cart, domain.CartItem{ProductID: 7, Variant: "red", Quantity: 1}))
+	_, err = repo.Checkout(cart, stockOrder(2), nil)
+	assert.ErrorIs(t, err, domain.ErrCartChanged)
+
+	cart, err = repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	_, err = postgresql.NewStockRepository(dbPool).Set(domain.StockLevel{ProductID: 7, Variant: "red", Available: 1})
+	assert.NoError(t, err)
+	_, err = repo.Checkout(cart, stockOrder(3), nil)
+	assert.ErrorIs(t, err, domain.ErrOutOfStock)
+
+	kept, err := repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+	assert.Len(t, kept.Items, 1, "a failed checkout keeps the cart")
+	orders, err := postgresql.NewOrderRepository(dbPool, testEventsTopic).Find(domain.OrderQuery{Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, orders)
+}
+
+func TestCartRepository_DeleteExpired(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewCartRepository(dbPool, testEventsTopic)
+	now := time.Now().UTC()
+	_, err := repo.CreateAnonymous("old", now.Add(-2*time.Hour))
+	assert.NoError(t, err)
+	_, err = repo.CreateAnonymous("fresh", now.Add(time.Hour))
+	assert.NoError(t, err)
+	_, err = repo.GetOrCreateForUser(1)
+	assert.NoError(t, err)
+
+	deleted, err := repo.DeleteExpired(now.Add(-time.Hour))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, err = repo.GetByToken("fresh")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,90 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"product-app/shared/idempotency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyStore_ReserveCompleteAndReplay(t *testing.T) {
+	clearTestData()
+	ctx := context.Background()
+	store := idempotency.NewPostgresStore(dbPool)
+
+	_, reserved, err := store.Reserve(ctx, "user:1", "key-1", "fingerprint-a", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	record, reserved, err := store.Reserve(ctx, "user:1", "key-1", "fingerprint-a", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Nil(t, record.Response, "an unfinished request has no response yet")
+
+	err = store.Complete(ctx, "user:1", "key-1", idempotency.Response{
+		StatusCode: 201,
+		Header:     map[string]string{"Location": "/api/v1/orders/1"},
+		Body:       []byte(`{"id":1}`),
+	}, time.Hour)
+	assert.NoError(t, err)
+
+	record, reserved, err = store.Reserve(ctx, "user:1", "key-1", "fingerprint-b", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "fingerprint-a", record.Fingerprint)
+	assert.Equal(t, 201, record.Response.StatusCode)
+	assert.Equal(t, "/api/v1/orders/1", record.Response.Header["Location"])
+	assert.Equal(t, `{"id":1}`, string(record.Response.Body))
+
+	_, reserved, err = store.Reserve(ctx, "user:2", "key-1", "fingerprint-a", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved, "keys are scoped")
+}
+
+func TestIdempotencyStore_ReleaseAndExpiry(t *testing.T) {
+	clearTestData()
+	ctx := context.Background()
+	store := idempotency.NewPostgresStore(dbPool)
+
+	_, _, err := store.Reserve(ctx, "anonymous", "key-1", "fingerprint-a", time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Release(ctx, "anonymous", "key-1"))
+	_, reserved, err := store.Reserve(ctx, "anonymous", "key-1", "fingerprint-a", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	_, _, err = store.Reserve(ctx, "anonymous", "key-2", "fingerprint-a", -time.Minute)
+	assert.NoError(t, err)
+	_, reserved, err = store.Reserve(ctx, "anonymous", "key-2", "fingerprint-b", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved, "an expired key can be reused")
+
+	_, _, err = store.Reserve(ctx, "anonymous", "key-3", "fingerprint-a", -time.Minute)
+	assert.NoError(t, err)
+	deleted, err := store.Prune(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestIdempotencyStore_LeaseExpiryAndCompletedTTL(t *testing.T) {
+	clearTestData()
+	ctx := context.Background()
+	store := idempotency.NewPostgresStore(dbPool)
+
+	_, _, err := store.Reserve(ctx, "user:1", "crashed", "fingerprint-a", -time.Second)
+	assert.NoError(t, err)
+	_, reserved, err := store.Reserve(ctx, "user:1", "crashed", "fingerprint-a", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, reserved, "a reservation whose lease ran out can be claimed again")
+
+	_, _, err = store.Reserve(ctx, "user:1", "finished", "fingerprint-a", -time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Complete(ctx, "user:1", "finished", idempotency.Response{StatusCode: 201, Body: []byte(`{}`)}, time.Hour))
+	record, reserved, err := store.Reserve(ctx, "user:1", "finished", "fingerprint-a", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, reserved, "a completed key is kept for the ttl, not the lease")
+	assert.Equal(t, 201, record.Response.StatusCode)
+}
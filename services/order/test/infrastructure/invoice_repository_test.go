@@ -0,0 +1,119 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/adapters/postgresql/common"
+	"product-app/services/order/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testInvoice(kind string, orderId int64, issuedAt time.Time) domain.Invoice {
+	return domain.Invoice{
+		Kind:       kind,
+		OrderId:    orderId,
+		Currency:   "EUR",
+		Seller:     domain.InvoiceParty{Name: "Product Platform GmbH", TaxId: "DE123456789"},
+		Customer:   domain.InvoiceParty{Name: "CUST-001"},
+		Lines:      []domain.InvoiceLine{{ProductID: 1, Description: "Keyboard", Quantity: 2, UnitPrice: 10, TaxRate: 20, NetAmount: 16.67, TaxAmount: 3.33, Total: 20}},
+		Taxes:      []domain.InvoiceTax{{Rate: 20, NetAmount: 16.67, TaxAmount: 3.33, Total: 20}},
+		NetTotal:   16.67,
+		TaxTotal:   3.33,
+		GrandTotal: 20,
+		IssuedAt:   issuedAt,
+	}
+}
+
+func TestInvoiceRepository_IssueAndGet(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewInvoiceRepository(dbPool)
+	issuedAt := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+
+	issued, err := repo.Issue(testInvoice(domain.InvoiceKindInvoice, 1, issuedAt))
+	assert.NoError(t, err)
+	assert.Equal(t, "INV-2026-000001", issued.Number)
+
+	found, err := repo.GetInvoice(1)
+	assert.NoError(t, err)
+	assert.Equal(t, issued.Id, found.Id)
+	assert.Equal(t, issuedAt, found.IssuedAt)
+	assert.Equal(t, "DE123456789", found.Seller.TaxId)
+	assert.Equal(t, issued.Lines, found.Lines)
+	assert.Equal(t, issued.Taxes, found.Taxes)
+	assert.Equal(t, 20.0, found.GrandTotal)
+
+	_, err = repo.GetInvoice(2)
+	assert.ErrorIs(t, err, domain.ErrInvoiceNotFound)
+}
+
+func TestInvoiceRepository_NumbersPerKindAndYearWithoutGaps(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewInvoiceRepository(dbPool)
+	in2026 := time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC)
+
+	first, err := repo.Issue(testInvoice(domain.InvoiceKindInvoice, 1, in2026))
+	assert.NoError(t, err)
+	_, err = repo.Issue(testInvoice(domain.InvoiceKindInvoice, 1, in2026))
+	assert.ErrorIs(t, err, domain.ErrInvoiceAlreadyIssued)
+	second, err := repo.Issue(testInvoice(domain.InvoiceKindInvoice, 2, in2026))
+	assert.NoError(t, err)
+	creditNote, err := repo.Issue(testInvoice(domain.InvoiceKindCreditNote, 1, in2026))
+	assert.NoError(t, err)
+	nextYear, err := repo.Issue(testInvoice(domain.InvoiceKindInvoice, 3, in2026.Add(2*time.Hour)))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "INV-2026-000001", first.Number)
+	assert.Equal(t, "INV-2026-000002", second.Number, "a failed issue leaves no gap")
+	assert.Equal(t, "CN-2026-000001", creditNote.Number)
+	assert.Equal(t, "INV-2027-000001", nextYear.Number)
+
+	creditNotes, err := repo.GetCreditNotes(1)
+	assert.NoError(t, err)
+	assert.Len(t, creditNotes, 1)
+}
+
+func TestInvoiceRepository_IssuedInvoicesAreImmutable(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewInvoiceRepository(dbPool)
+	issued, err := repo.Issue(testInvoice(domain.InvoiceKindInvoice, 1, time.Now().UTC()))
+	assert.NoError(t, err)
+
+	_, err = dbPool.Exec(ctx, `UPDATE invoices SET grand_total = 0 WHERE id = $1`, issued.Id)
+	assert.Error(t, err)
+	_, err = dbPool.Exec(ctx, `DELETE FROM invoices WHERE id = $1`, issued.Id)
+	assert.Error(t, err)
+
+	found, err := repo.GetInvoice(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, found.GrandTotal)
+}
+
+func TestInvoiceRepository_CreditsEachUnitOnce_WhenCreditNotesRace(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewInvoiceRepository(dbPool)
+	_, err := repo.Issue(testInvoice(domain.InvoiceKindInvoice, 1, time.Now().UTC()))
+	assert.NoError(t, err)
+
+	creditNote := testInvoice(domain.InvoiceKindCreditNote, 1, time.Now().UTC())
+	creditNote.Lines[0].Quantity = -2
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := repo.Issue(creditNote)
+			errs <- err
+		}()
+	}
+	first, second := <-errs, <-errs
+
+	assert.True(t, (first == nil) != (second == nil), "exactly one credit note is issued: %v, %v", first, second)
+	if first != nil {
+		assert.ErrorIs(t, first, domain.ErrInvalidCreditNote)
+	} else {
+		assert.ErrorIs(t, second, domain.ErrInvalidCreditNote)
+	}
+	creditNotes, err := repo.GetCreditNotes(1)
+	assert.NoError(t, err)
+	assert.Len(t, creditNotes, 1)
+}
@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"testing"
+	"time"
 
 	"product-app/services/order/internal/adapters/postgresql/common"
 	"product-app/services/order/internal/domain"
@@ -9,54 +10,329 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestOrderRepository_GetAll(t *testing.T) {
+func TestOrderRepository_Find(t *testing.T) {
 	setupOrdersOnly()
 
-	repo := postgresql.NewOrderRepository(dbPool)
-	orders, err := repo.GetAll()
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	orders, err := repo.Find(domain.OrderQuery{Limit: 10})
 	assert.NoError(t, err)
 	assert.Len(t, orders, 3)
+	assert.Equal(t, int64(1), orders[0].Id)
+	assert.Len(t, orders[2].Items, 2)
+}
+
+func TestOrderRepository_FindSortsDescendingByTotal(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	orders, err := repo.Find(domain.OrderQuery{SortBy: domain.OrderSortTotal, Descending: true, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 1, 2}, orderIds(orders))
+}
+
+func TestOrderRepository_FindFilters(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	orders, err := repo.Find(domain.OrderQuery{From: day, To: day.AddDate(0, 0, 1), Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, orderIds(orders))
+
+	orders, err = repo.Find(domain.OrderQuery{Status: domain.OrderStatusPending, CustomerNumber: "CUST-001", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, orderIds(orders))
+
+	orders, err = repo.Find(domain.OrderQuery{ProductId: 4, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3}, orderIds(orders))
+
+	orders, err = repo.Find(domain.OrderQuery{UserId: 5, Limit: 10})
+	assert.NoError(t, err)
+	assert.NotNil(t, orders)
+	assert.Empty(t, orders)
+}
+
+func TestOrderRepository_FindContinuesAfterCursor(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	first, err := repo.Find(domain.OrderQuery{Descending: true, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, orderIds(first))
+
+	last := first[len(first)-1]
+	rest, err := repo.Find(domain.OrderQuery{
+		Descending: true,
+		Limit:      2,
+		After:      &domain.OrderCursor{Id: last.Id, OrderTime: last.OrderTime, GrandTotal: last.GrandTotal},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, orderIds(rest))
+
+	byTotal, err := repo.Find(domain.OrderQuery{
+		SortBy: domain.OrderSortTotal,
+		Limit:  10,
+		After:  &domain.OrderCursor{Id: 2, GrandTotal: 15},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, orderIds(byTotal))
 }
 
 func TestOrderRepository_GetById(t *testing.T) {
 	setupOrdersOnly()
 
-	repo := postgresql.NewOrderRepository(dbPool)
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
 	order, err := repo.GetById(1)
 	assert.NoError(t, err)
 	assert.Equal(t, "CUST-001", order.CustomerNumber)
+	assert.Len(t, order.Items, 1)
+	assert.Equal(t, 20.0, order.GrandTotal)
+}
+
+func TestOrderRepository_FindByCustomerNumber(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	orders, err := repo.Find(domain.OrderQuery{CustomerNumber: "CUST-001", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, orders, 2)
+	assert.Len(t, orders[1].Items, 2)
+	assert.Equal(t, "red", orders[1].Items[1].Variant)
+	assert.Equal(t, 5.0, orders[1].DiscountTotal)
 }
 
-func TestOrderRepository_GetByCustomerNumber(t *testing.T) {
+func TestOrderRepository_FindByUserId(t *testing.T) {
 	setupOrdersOnly()
 
-	repo := postgresql.NewOrderRepository(dbPool)
-	orders, err := repo.GetByCustomerNumber("CUST-001")
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	orders, err := repo.Find(domain.OrderQuery{UserId: 1, Limit: 10})
 	assert.NoError(t, err)
 	assert.Len(t, orders, 2)
+	assert.Equal(t, int64(1), orders[0].UserId)
+	assert.Equal(t, int64(3), orders[1].Id)
 }
 
 func TestOrderRepository_Create(t *testing.T) {
 	clearTestData()
 
-	repo := postgresql.NewOrderRepository(dbPool)
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
 	created, err := repo.Create(domain.Order{
+		UserId:         9,
 		CustomerNumber: "CUST-009",
-		ProductID:      "PROD-9",
-		Quantity:       3,
-	})
+		Status:         domain.OrderStatusPending,
+		Items: []domain.OrderItem{
+			{ProductID: 9, Quantity: 3, UnitPrice: 2.5, LineTotal: 7.5},
+			{ProductID: 4, Variant: "XL", Quantity: 1, UnitPrice: 40, Discount: 5, LineTotal: 35},
+		},
+		Subtotal:      47.5,
+		DiscountTotal: 5,
+		GrandTotal:    42.5,
+	}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), created.Id)
 	assert.False(t, created.OrderTime.IsZero())
+	assert.NotZero(t, created.Items[0].Id)
+
+	stored, err := repo.GetById(created.Id)
+	assert.NoError(t, err)
+	assert.Len(t, stored.Items, 2)
+	assert.Equal(t, "XL", stored.Items[1].Variant)
+	assert.Equal(t, 42.5, stored.GrandTotal)
+	assert.Equal(t, domain.OrderStatusPending, stored.Status)
+	assert.Equal(t, int64(9), stored.UserId)
+
+	history, err := repo.GetStatusHistory(created.Id)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Empty(t, history[0].FromStatus)
+	assert.Equal(t, domain.OrderStatusPending, history[0].ToStatus)
+}
+
+func TestOrderRepository_CreateRollsBackWhenAnItemFails(t *testing.T) {
+	clearTestData()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	_, err := repo.Create(domain.Order{
+		CustomerNumber: "CUST-009",
+		Status:         domain.OrderStatusPending,
+		Items: []domain.OrderItem{
+			{ProductID: 9, Quantity: 1, UnitPrice: 5, LineTotal: 5},
+			{ProductID: 4, Quantity: 0, UnitPrice: 5},
+		},
+	}, nil)
+	assert.Error(t, err)
+
+	orders, err := repo.Find(domain.OrderQuery{Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, orders)
 }
 
 func TestOrderRepository_Delete(t *testing.T) {
 	setupOrdersOnly()
 
-	repo := postgresql.NewOrderRepository(dbPool)
-	err := repo.Delete(1)
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	deleted, err := repo.Delete(1, nil)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted.Id)
+	assert.Len(t, deleted.Items, 1)
 
 	_, err = repo.GetById(1)
 	assert.Error(t, err)
 }
+
+func TestOrderRepository_GetStatusHistory(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	history, err := repo.GetStatusHistory(2)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Empty(t, history[0].FromStatus)
+	assert.Equal(t, domain.OrderStatusPending, history[1].FromStatus)
+	assert.Equal(t, domain.OrderStatusPaid, history[1].ToStatus)
+	assert.Equal(t, int64(1), history[1].ActorUserId)
+	assert.Equal(t, "card captured", history[1].Reason)
+}
+
+func TestOrderRepository_UpdateStatus(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	updated, err := repo.UpdateStatus(2, domain.OrderStatusChange{
+		FromStatus:  domain.OrderStatusPaid,
+		ToStatus:    domain.OrderStatusShipped,
+		ActorUserId: 7,
+		Reason:      "handed to carrier",
+		ChangedAt:   time.Now().UTC(),
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusShipped, updated.Status)
+	assert.Len(t, updated.Items, 1)
+
+	history, err := repo.GetStatusHistory(2)
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+	assert.Equal(t, domain.OrderStatusShipped, history[2].ToStatus)
+	assert.Equal(t, int64(7), history[2].ActorUserId)
+}
+
+func TestOrderRepository_UpdateStatusFailsWhenStatusChangedConcurrently(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	_, err := repo.UpdateStatus(1, domain.OrderStatusChange{
+		FromStatus: domain.OrderStatusPaid,
+		ToStatus:   domain.OrderStatusShipped,
+		ChangedAt:  time.Now().UTC(),
+	}, nil)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	history, err := repo.GetStatusHistory(1)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestOrderRepository_UpdateStatusFailsForMissingOrder(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	_, err := repo.UpdateStatus(99, domain.OrderStatusChange{
+		FromStatus: domain.OrderStatusPending,
+		ToStatus:   domain.OrderStatusPaid,
+		ChangedAt:  time.Now().UTC(),
+	}, nil)
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func TestOrderRepository_ReserveAndCompleteRefund(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	refund, err := repo.ReserveRefund(domain.Refund{
+		OrderId:     2,
+		Amount:      5,
+		Reason:      "damaged box",
+		ActorUserId: 9,
+		CreatedAt:   time.Now().UTC(),
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, refund.Id)
+	assert.Equal(t, domain.RefundStatusPending, refund.Status)
+
+	order, err := repo.GetById(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, order.RefundedTotal)
+
+	refund.ProviderReference = "re_1"
+	refund, err = repo.CompleteRefund(refund, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RefundStatusCompleted, refund.Status)
+
+	refunds, err := repo.GetRefunds(2)
+	assert.NoError(t, err)
+	assert.Len(t, refunds, 1)
+	assert.Equal(t, domain.RefundStatusCompleted, refunds[0].Status)
+	assert.Equal(t, "re_1", refunds[0].ProviderReference)
+	assert.Equal(t, int64(9), refunds[0].ActorUserId)
+
+	assert.ErrorIs(t, repo.ReleaseRefund(refund), domain.ErrInvalidRefund)
+}
+
+func TestOrderRepository_ReserveRefundFailsAboveGrandTotal(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	_, err := repo.ReserveRefund(domain.Refund{OrderId: 2, Amount: 10, CreatedAt: time.Now().UTC()})
+	assert.NoError(t, err)
+
+	_, err = repo.ReserveRefund(domain.Refund{OrderId: 2, Amount: 5.01, CreatedAt: time.Now().UTC()})
+	assert.ErrorIs(t, err, domain.ErrInvalidRefund)
+
+	_, err = repo.ReserveRefund(domain.Refund{OrderId: 99, Amount: 1, CreatedAt: time.Now().UTC()})
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+
+	refunds, err := repo.GetRefunds(2)
+	assert.NoError(t, err)
+	assert.Len(t, refunds, 1)
+}
+
+func TestOrderRepository_ReleaseRefund(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	refund, err := repo.ReserveRefund(domain.Refund{OrderId: 2, Amount: 10, CreatedAt: time.Now().UTC()})
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.ReleaseRefund(refund))
+
+	order, err := repo.GetById(2)
+	assert.NoError(t, err)
+	assert.Zero(t, order.RefundedTotal)
+	refunds, err := repo.GetRefunds(2)
+	assert.NoError(t, err)
+	assert.Empty(t, refunds)
+
+	_, err = repo.CompleteRefund(refund, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidRefund)
+}
+
+func TestOrderRepository_UpdateStatusRecordsReasonCode(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	_, err := repo.UpdateStatus(1, domain.OrderStatusChange{
+		FromStatus: domain.OrderStatusPending,
+		ToStatus:   domain.OrderStatusCancelled,
+		ReasonCode: domain.CancelReasonDuplicateOrder,
+		Reason:     "placed twice",
+		ChangedAt:  time.Now().UTC(),
+	}, nil)
+	assert.NoError(t, err)
+
+	history, err := repo.GetStatusHistory(1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CancelReasonDuplicateOrder, history[1].ReasonCode)
+	assert.Empty(t, history[0].ReasonCode)
+}
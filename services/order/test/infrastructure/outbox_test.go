@@ -0,0 +1,53 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/adapters/postgresql/common"
+	"product-app/services/order/internal/domain"
+	"product-app/shared/kafka"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func statusChangedEvents(order domain.Order) []kafka.Event {
+	return []kafka.Event{kafka.OrderStatusChanged{ID: order.Id, CustomerNumber: order.CustomerNumber, Status: order.Status}}
+}
+
+func TestOutbox_UpdateStatusWritesEventInSameTransaction(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	_, err := repo.UpdateStatus(1, domain.OrderStatusChange{
+		FromStatus: domain.OrderStatusPending,
+		ToStatus:   domain.OrderStatusPaid,
+		ChangedAt:  time.Now().UTC(),
+	}, statusChangedEvents)
+	assert.NoError(t, err)
+
+	var topic, key, eventType, status string
+	err = dbPool.QueryRow(ctx, `SELECT topic, message_key, payload->>'type', payload->'data'->>'status' FROM outbox`).
+		Scan(&topic, &key, &eventType, &status)
+	assert.NoError(t, err)
+	assert.Equal(t, testEventsTopic, topic)
+	assert.Equal(t, "1", key)
+	assert.Equal(t, kafka.EventTypeOrderStatusChanged, eventType)
+	assert.Equal(t, domain.OrderStatusPaid, status)
+}
+
+func TestOutbox_UpdateStatusRollsBackEventOnFailure(t *testing.T) {
+	setupOrdersOnly()
+
+	repo := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	_, err := repo.UpdateStatus(1, domain.OrderStatusChange{
+		FromStatus: domain.OrderStatusPaid,
+		ToStatus:   domain.OrderStatusShipped,
+		ChangedAt:  time.Now().UTC(),
+	}, statusChangedEvents)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	var count int
+	assert.NoError(t, dbPool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox`).Scan(&count))
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,80 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"product-app/services/order/internal/adapters/postgresql/common"
+	"product-app/services/order/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentRepository_CreateAndGet(t *testing.T) {
+	setupOrdersOnly()
+	repo := postgresql.NewPaymentRepository(dbPool)
+
+	created, err := repo.Create(domain.Payment{OrderId: 1, Provider: "fake", ProviderPaymentId: "pi_1", Amount: 20, Status: domain.PaymentStatusPending})
+	assert.NoError(t, err)
+	assert.NotZero(t, created.Id)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	found, err := repo.GetByProviderPaymentId("fake", "pi_1")
+	assert.NoError(t, err)
+	assert.Equal(t, created.Id, found.Id)
+	assert.Equal(t, 20.0, found.Amount)
+
+	_, err = repo.GetByProviderPaymentId("other", "pi_1")
+	assert.ErrorIs(t, err, domain.ErrPaymentNotFound)
+
+	payments, err := repo.GetByOrderId(1)
+	assert.NoError(t, err)
+	assert.Len(t, payments, 1)
+	payments, err = repo.GetByOrderId(2)
+	assert.NoError(t, err)
+	assert.Empty(t, payments)
+}
+
+func TestPaymentRepository_AllowsOneOpenPaymentPerOrder(t *testing.T) {
+	setupOrdersOnly()
+	repo := postgresql.NewPaymentRepository(dbPool)
+
+	first, err := repo.Create(domain.Payment{OrderId: 1, Provider: "fake", ProviderPaymentId: "pi_1", Amount: 20, Status: domain.PaymentStatusPending})
+	assert.NoError(t, err)
+	_, err = repo.Create(domain.Payment{OrderId: 1, Provider: "fake", ProviderPaymentId: "pi_2", Amount: 20, Status: domain.PaymentStatusPending})
+	assert.ErrorIs(t, err, domain.ErrPaymentAlreadyInProgress)
+
+	_, err = repo.UpdateStatus(first.Id, []string{domain.PaymentStatusPending}, domain.PaymentStatusFailed, "card declined")
+	assert.NoError(t, err)
+	_, err = repo.Create(domain.Payment{OrderId: 1, Provider: "fake", ProviderPaymentId: "pi_2", Amount: 20, Status: domain.PaymentStatusPending})
+	assert.NoError(t, err)
+}
+
+func TestPaymentRepository_UpdateStatusOnlyFromExpectedStatuses(t *testing.T) {
+	setupOrdersOnly()
+	repo := postgresql.NewPaymentRepository(dbPool)
+	payment, err := repo.Create(domain.Payment{OrderId: 1, Provider: "fake", ProviderPaymentId: "pi_1", Amount: 20, Status: domain.PaymentStatusPending})
+	assert.NoError(t, err)
+
+	updated, err := repo.UpdateStatus(payment.Id, []string{domain.PaymentStatusPending}, domain.PaymentStatusAuthorized, "")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusAuthorized, updated.Status)
+
+	_, err = repo.UpdateStatus(payment.Id, []string{domain.PaymentStatusPending}, domain.PaymentStatusFailed, "late")
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	_, err = repo.UpdateStatus(999, []string{domain.PaymentStatusPending}, domain.PaymentStatusFailed, "")
+	assert.ErrorIs(t, err, domain.ErrPaymentNotFound)
+}
+
+func TestPaymentRepository_ReserveAndReleaseWebhookEvents(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewPaymentRepository(dbPool)
+	event := domain.PaymentEvent{Id: "evt_1", Type: domain.PaymentEventCaptured}
+
+	assert.NoError(t, repo.ReserveWebhookEvent("fake", event))
+	assert.ErrorIs(t, repo.ReserveWebhookEvent("fake", event), domain.ErrDuplicateWebhookEvent)
+	assert.NoError(t, repo.ReserveWebhookEvent("other", event), "event ids are scoped by provider")
+
+	assert.NoError(t, repo.ReleaseWebhookEvent("fake", "evt_1"))
+	assert.NoError(t, repo.ReserveWebhookEvent("fake", event))
+}
@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/adapters/postgresql/common"
+	"product-app/services/order/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testShipment(orderId int64, trackingNumber string, quantity int32) domain.Shipment {
+	return domain.Shipment{
+		OrderId:        orderId,
+		Carrier:        "DHL",
+		TrackingNumber: trackingNumber,
+		Status:         domain.ShipmentStatusShipped,
+		Items:          []domain.ShipmentItem{{ProductID: 7, Variant: "red", Quantity: quantity}},
+		Events: []domain.TrackingEvent{{
+			Status:     domain.ShipmentStatusShipped,
+			OccurredAt: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		}},
+	}
+}
+
+// paidOrder stores an order of quantity units and moves it to paid, the
+// only status shipments are created for.
+func paidOrder(t *testing.T, quantity int32) domain.Order {
+	orders := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	order, err := orders.Create(stockOrder(quantity), nil)
+	assert.NoError(t, err)
+	order, err = orders.UpdateStatus(order.Id, domain.OrderStatusChange{
+		FromStatus: domain.OrderStatusPending,
+		ToStatus:   domain.OrderStatusPaid,
+		ChangedAt:  time.Now().UTC(),
+	}, nil)
+	assert.NoError(t, err)
+	return order
+}
+
+func TestShipmentRepository_CreateAndGet(t *testing.T) {
+	clearTestData()
+	order := paidOrder(t, 3)
+	repo := postgresql.NewShipmentRepository(dbPool, testEventsTopic)
+
+	created, err := repo.Create(testShipment(order.Id, "JD0001", 2), nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, created.Id)
+	assert.NotZero(t, created.Events[0].Id)
+
+	found, err := repo.GetById(created.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "JD0001", found.TrackingNumber)
+	assert.Equal(t, []domain.ShipmentItem{{ProductID: 7, Variant: "red", Quantity: 2}}, found.Items)
+	assert.Len(t, found.Events, 1)
+	assert.Equal(t, created.Events[0].OccurredAt, found.Events[0].OccurredAt)
+
+	shipments, err := repo.GetByOrderId(order.Id)
+	assert.NoError(t, err)
+	assert.Len(t, shipments, 1)
+
+	_, err = repo.GetById(404)
+	assert.ErrorIs(t, err, domain.ErrShipmentNotFound)
+}
+
+func TestShipmentRepository_RejectsMoreThanUnshippedAndDuplicateTracking(t *testing.T) {
+	clearTestData()
+	order := paidOrder(t, 3)
+	repo := postgresql.NewShipmentRepository(dbPool, testEventsTopic)
+
+	_, err := repo.Create(testShipment(order.Id, "JD0001", 2), nil)
+	assert.NoError(t, err)
+	_, err = repo.Create(testShipment(order.Id, "JD0002", 2), nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidShipment)
+	_, err = repo.Create(testShipment(order.Id, "JD0001", 1), nil)
+	assert.ErrorIs(t, err, domain.ErrDuplicateTrackingNumber)
+	_, err = repo.Create(testShipment(order.Id, "JD0002", 1), nil)
+	assert.NoError(t, err)
+
+	_, err = repo.Create(testShipment(order.Id+1, "JD0003", 1), nil)
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func TestShipmentRepository_RejectsOrderThatIsNotPaid(t *testing.T) {
+	clearTestData()
+	orders := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+	order, err := orders.Create(stockOrder(3), nil)
+	assert.NoError(t, err)
+	repo := postgresql.NewShipmentRepository(dbPool, testEventsTopic)
+
+	_, err = repo.Create(testShipment(order.Id, "JD0001", 1), nil)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	_, err = orders.UpdateStatus(order.Id, domain.OrderStatusChange{
+		FromStatus: domain.OrderStatusPending,
+		ToStatus:   domain.OrderStatusCancelled,
+		ChangedAt:  time.Now().UTC(),
+	}, nil)
+	assert.NoError(t, err)
+	_, err = repo.Create(testShipment(order.Id, "JD0001", 1), nil)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	shipments, err := repo.GetByOrderId(order.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, shipments)
+}
+
+func TestShipmentRepository_AddEventUntilDelivered(t *testing.T) {
+	clearTestData()
+	order := paidOrder(t, 3)
+	repo := postgresql.NewShipmentRepository(dbPool, testEventsTopic)
+	created, err := repo.Create(testShipment(order.Id, "JD0001", 3), nil)
+	assert.NoError(t, err)
+
+	updated, err := repo.AddEvent(domain.TrackingEvent{
+		ShipmentId: created.Id,
+		Status:     domain.ShipmentStatusInTransit,
+		Location:   "Leipzig hub",
+		OccurredAt: time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC),
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ShipmentStatusInTransit, updated.Status)
+	assert.Len(t, updated.Events, 2)
+	assert.Equal(t, "Leipzig hub", updated.Events[1].Location)
+
+	_, err = repo.AddEvent(domain.TrackingEvent{ShipmentId: created.Id, Status: domain.ShipmentStatusDelivered, OccurredAt: time.Now().UTC()}, nil)
+	assert.NoError(t, err)
+	_, err = repo.AddEvent(domain.TrackingEvent{ShipmentId: created.Id, Status: domain.ShipmentStatusException, OccurredAt: time.Now().UTC()}, nil)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	_, err = repo.AddEvent(domain.TrackingEvent{ShipmentId: 404, Status: domain.ShipmentStatusInTransit, OccurredAt: time.Now().UTC()}, nil)
+	assert.ErrorIs(t, err, domain.ErrShipmentNotFound)
+}
+
+func TestShipmentRepository_ShipsEachUnitOnce_WhenShipmentsRace(t *testing.T) {
+	clearTestData()
+	order := paidOrder(t, 3)
+
+	repo := postgresql.NewShipmentRepository(dbPool, testEventsTopic)
+	errs := make(chan error, 2)
+	for _, trackingNumber := range []string{"JD0001", "JD0002"} {
+		go func() {
+			_, err := repo.Create(testShipment(order.Id, trackingNumber, 2), nil)
+			errs <- err
+		}()
+	}
+	first, second := <-errs, <-errs
+
+	assert.True(t, (first == nil) != (second == nil), "exactly one shipment is created: %v, %v", first, second)
+	shipments, err := repo.GetByOrderId(order.Id)
+	assert.NoError(t, err)
+	assert.Len(t, shipments, 1)
+}
@@ -0,0 +1,123 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/adapters/postgresql/common"
+	"product-app/services/order/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stockOrder(quantity int32) domain.Order {
+	return domain.Order{
+		UserId:         1,
+		CustomerNumber: "CUST-001",
+		Status:         domain.OrderStatusPending,
+		Items:          []domain.OrderItem{{ProductID: 7, Variant: "red", Quantity: quantity, UnitPrice: 5, LineTotal: float64(quantity) * 5}},
+		GrandTotal:     float64(quantity) * 5,
+	}
+}
+
+func availableStock(t *testing.T, productId int64) int32 {
+	levels, err := postgresql.NewStockRepository(dbPool).GetByProductId(productId)
+	assert.NoError(t, err)
+	assert.Len(t, levels, 1)
+	return levels[0].Available
+}
+
+func TestStockRepository_SetAndGet(t *testing.T) {
+	clearTestData()
+	repo := postgresql.NewStockRepository(dbPool)
+
+	_, err := repo.Set(domain.StockLevel{ProductID: 7, Variant: "red", Available: 5})
+	assert.NoError(t, err)
+	level, err := repo.Set(domain.StockLevel{ProductID: 7, Variant: "red", Available: 3})
+	assert.NoError(t, err)
+	assert.False(t, level.UpdatedAt.IsZero())
+
+	levels, err := repo.GetByProductId(7)
+	assert.NoError(t, err)
+	assert.Len(t, levels, 1)
+	assert.Equal(t, int32(3), levels[0].Available)
+
+	levels, err = repo.GetByProductId(8)
+	assert.NoError(t, err)
+	assert.Empty(t, levels)
+}
+
+func TestStockRepository_OrdersReserveAndCancellationReleases(t *testing.T) {
+	clearTestData()
+	_, err := postgresql.NewStockRepository(dbPool).Set(domain.StockLevel{ProductID: 7, Variant: "red", Available: 5})
+	assert.NoError(t, err)
+	orders := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+
+	order, err := orders.Create(stockOrder(3), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), availableStock(t, 7))
+
+	_, err = orders.Create(stockOrder(3), nil)
+	assert.ErrorIs(t, err, domain.ErrOutOfStock)
+	assert.Equal(t, int32(2), availableStock(t, 7))
+	page, err := orders.Find(domain.OrderQuery{Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, page, 1, "a failed reservation stores no order")
+
+	_, err = orders.UpdateStatus(order.Id, domain.OrderStatusChange{
+		FromStatus: domain.OrderStatusPending,
+		ToStatus:   domain.OrderStatusCancelled,
+		ChangedAt:  time.Now().UTC(),
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), availableStock(t, 7))
+}
+
+func TestStockRepository_DeletingOrderReleasesStock(t *testing.T) {
+	clearTestData()
+	_, err := postgresql.NewStockRepository(dbPool).Set(domain.StockLevel{ProductID: 7, Variant: "red", Available: 5})
+	assert.NoError(t, err)
+	orders := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+
+	order, err := orders.Create(stockOrder(5), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), availableStock(t, 7))
+
+	_, err = orders.Delete(order.Id, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), availableStock(t, 7))
+}
+
+func TestStockRepository_ShippingConsumesReservedStock(t *testing.T) {
+	clearTestData()
+	_, err := postgresql.NewStockRepository(dbPool).Set(domain.StockLevel{ProductID: 7, Variant: "red", Available: 5})
+	assert.NoError(t, err)
+	orders := postgresql.NewOrderRepository(dbPool, testEventsTopic)
+
+	order, err := orders.Create(stockOrder(3), nil)
+	assert.NoError(t, err)
+	for _, change := range []domain.OrderStatusChange{
+		{FromStatus: domain.OrderStatusPending, ToStatus: domain.OrderStatusPaid},
+		{FromStatus: domain.OrderStatusPaid, ToStatus: domain.OrderStatusShipped},
+	} {
+		change.ChangedAt = time.Now().UTC()
+		_, err = orders.UpdateStatus(order.Id, change, nil)
+		assert.NoError(t, err)
+	}
+
+	var reserved int
+	assert.NoError(t, dbPool.QueryRow(ctx, `SELECT COUNT(*) FROM stock_reservations WHERE order_id = $1`, order.Id).Scan(&reserved))
+	assert.Zero(t, reserved)
+
+	_, err = orders.Delete(order.Id, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), availableStock(t, 7))
+}
+
+func TestStockRepository_UntrackedProductsAreNotLimited(t *testing.T) {
+	clearTestData()
+
+	_, err := postgresql.NewOrderRepository(dbPool, testEventsTopic).Create(stockOrder(1000), nil)
+
+	assert.NoError(t, err)
+}
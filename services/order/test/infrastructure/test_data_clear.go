@@ -13,6 +13,24 @@ BEGIN
 	IF to_regclass('public.orders') IS NOT NULL THEN
 		EXECUTE 'TRUNCATE TABLE orders RESTART IDENTITY CASCADE';
 	END IF;
+	IF to_regclass('public.payment_webhook_events') IS NOT NULL THEN
+		EXECUTE 'TRUNCATE TABLE payment_webhook_events';
+	END IF;
+	IF to_regclass('public.carts') IS NOT NULL THEN
+		EXECUTE 'TRUNCATE TABLE carts RESTART IDENTITY CASCADE';
+	END IF;
+	IF to_regclass('public.stock_levels') IS NOT NULL THEN
+		EXECUTE 'TRUNCATE TABLE stock_levels';
+	END IF;
+	IF to_regclass('public.invoices') IS NOT NULL THEN
+		EXECUTE 'TRUNCATE TABLE invoices, invoice_sequences RESTART IDENTITY';
+	END IF;
+	IF to_regclass('public.idempotency_keys') IS NOT NULL THEN
+		EXECUTE 'TRUNCATE TABLE idempotency_keys';
+	END IF;
+	IF to_regclass('public.outbox') IS NOT NULL THEN
+		EXECUTE 'TRUNCATE TABLE outbox RESTART IDENTITY';
+	END IF;
 END $$;
 `
 
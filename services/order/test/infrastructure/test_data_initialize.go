@@ -8,11 +8,25 @@ import (
 )
 
 var INSERT_ORDERS = `
-INSERT INTO orders (customer_number, product_id, quantity, order_time)
+INSERT INTO orders (user_id, customer_number, status, subtotal, discount_total, grand_total, order_time)
 VALUES
-('CUST-001', 'PROD-1', 2, NOW()),
-('CUST-002', 'PROD-2', 1, NOW()),
-('CUST-001', 'PROD-3', 5, NOW());
+(1, 'CUST-001', 'pending', 20.00, 0, 20.00, '2026-01-01 10:00:00'),
+(2, 'CUST-002', 'paid', 15.00, 0, 15.00, '2026-01-02 10:00:00'),
+(1, 'CUST-001', 'pending', 35.00, 5.00, 30.00, '2026-01-03 10:00:00');
+
+INSERT INTO order_items (order_id, product_id, variant, quantity, unit_price, discount, line_total)
+VALUES
+(1, 1, '', 2, 10.00, 0, 20.00),
+(2, 2, '', 1, 15.00, 0, 15.00),
+(3, 3, '', 5, 4.00, 0, 20.00),
+(3, 4, 'red', 1, 15.00, 5.00, 10.00);
+
+INSERT INTO order_status_history (order_id, from_status, to_status, actor_user_id, reason)
+VALUES
+(1, NULL, 'pending', NULL, ''),
+(2, NULL, 'pending', NULL, ''),
+(2, 'pending', 'paid', 1, 'card captured'),
+(3, NULL, 'pending', NULL, '');
 `
 
 func InsertTestOrders(ctx context.Context, dbPool *pgxpool.Pool) {
@@ -1,5 +1,7 @@
 package infrastructure
 
+import "product-app/services/order/internal/domain"
+
 func setupOrdersOnly() {
 	TruncateTestData(ctx, dbPool)
 	InsertTestOrders(ctx, dbPool)
@@ -8,3 +10,11 @@ func setupOrdersOnly() {
 func clearTestData() {
 	TruncateTestData(ctx, dbPool)
 }
+
+func orderIds(orders []domain.Order) []int64 {
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.Id)
+	}
+	return ids
+}
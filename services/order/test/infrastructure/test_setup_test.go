@@ -12,6 +12,8 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+const testEventsTopic = "order.events"
+
 var (
 	ctx             context.Context
 	dbPool          *pgxpool.Pool
@@ -37,7 +39,7 @@ func TestMain(m *testing.M) {
 
 	createSchema(ctx, dbPool)
 
-	orderRepository = postgresql.NewOrderRepository(dbPool)
+	orderRepository = postgresql.NewOrderRepository(dbPool, testEventsTopic)
 	code := m.Run()
 
 	dbPool.Close()
@@ -62,15 +64,226 @@ func createTestDatabase(ctx context.Context, host, port, dbName string) {
 
 func createSchema(ctx context.Context, pool *pgxpool.Pool) {
 	_, err := pool.Exec(ctx, `
+		DROP TABLE IF EXISTS outbox;
+		DROP TABLE IF EXISTS idempotency_keys;
+		DROP TABLE IF EXISTS shipment_events;
+		DROP TABLE IF EXISTS shipment_items;
+		DROP TABLE IF EXISTS shipments;
+		DROP TABLE IF EXISTS invoices;
+		DROP TABLE IF EXISTS invoice_sequences;
+		DROP TABLE IF EXISTS stock_reservations;
+		DROP TABLE IF EXISTS stock_levels;
+		DROP TABLE IF EXISTS cart_items;
+		DROP TABLE IF EXISTS carts;
+		DROP TABLE IF EXISTS payment_webhook_events;
+		DROP TABLE IF EXISTS payments;
+		DROP TABLE IF EXISTS order_refunds;
+		DROP TABLE IF EXISTS order_status_history;
+		DROP TABLE IF EXISTS order_items;
 		DROP TABLE IF EXISTS orders;
 
 		CREATE TABLE orders (
 			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT,
 			customer_number TEXT NOT NULL,
-			product_id TEXT NOT NULL,
-			quantity INT NOT NULL CHECK (quantity > 0),
+			status VARCHAR(20) NOT NULL DEFAULT 'pending'
+				CHECK (status IN ('pending', 'paid', 'shipped', 'delivered', 'cancelled')),
+			subtotal NUMERIC(12, 2) NOT NULL DEFAULT 0,
+			discount_total NUMERIC(12, 2) NOT NULL DEFAULT 0,
+			grand_total NUMERIC(12, 2) NOT NULL DEFAULT 0,
+			refunded_total NUMERIC(12, 2) NOT NULL DEFAULT 0,
 			order_time TIMESTAMP NOT NULL DEFAULT NOW()
 		);
+
+		CREATE TABLE order_items (
+			id BIGSERIAL PRIMARY KEY,
+			order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			product_id BIGINT NOT NULL,
+			variant TEXT NOT NULL DEFAULT '',
+			quantity INT NOT NULL CHECK (quantity > 0),
+			unit_price NUMERIC(12, 2) NOT NULL CHECK (unit_price >= 0),
+			discount NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (discount >= 0),
+			line_total NUMERIC(12, 2) NOT NULL CHECK (line_total >= 0)
+		);
+
+		CREATE TABLE order_status_history (
+			id BIGSERIAL PRIMARY KEY,
+			order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			from_status VARCHAR(20),
+			to_status VARCHAR(20) NOT NULL,
+			actor_user_id BIGINT,
+			reason_code VARCHAR(32),
+			reason TEXT NOT NULL DEFAULT '',
+			changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE order_refunds (
+			id BIGSERIAL PRIMARY KEY,
+			order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+			status VARCHAR(16) NOT NULL DEFAULT 'completed',
+			reason TEXT NOT NULL DEFAULT '',
+			provider_reference VARCHAR(255),
+			actor_user_id BIGINT,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE payments (
+			id BIGSERIAL PRIMARY KEY,
+			order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			provider VARCHAR(32) NOT NULL,
+			provider_payment_id VARCHAR(255) NOT NULL,
+			amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+			status VARCHAR(20) NOT NULL
+				CHECK (status IN ('pending', 'authorized', 'captured', 'failed')),
+			failure_reason TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			UNIQUE (provider, provider_payment_id)
+		);
+		CREATE UNIQUE INDEX idx_payments_one_open_per_order
+			ON payments (order_id) WHERE status IN ('pending', 'authorized');
+
+		CREATE TABLE payment_webhook_events (
+			provider VARCHAR(32) NOT NULL,
+			event_id VARCHAR(255) NOT NULL,
+			event_type VARCHAR(64) NOT NULL,
+			received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (provider, event_id)
+		);
+
+		CREATE TABLE idempotency_keys (
+			scope TEXT NOT NULL,
+			idempotency_key TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			status_code INT,
+			headers JSONB NOT NULL DEFAULT '{}',
+			body BYTEA,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (scope, idempotency_key)
+		);
+
+		CREATE TABLE carts (
+			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT UNIQUE,
+			token VARCHAR(64) UNIQUE,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMP,
+			CHECK ((user_id IS NULL) <> (token IS NULL))
+		);
+
+		CREATE TABLE cart_items (
+			cart_id BIGINT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+			product_id BIGINT NOT NULL,
+			variant TEXT NOT NULL DEFAULT '',
+			quantity INT NOT NULL CHECK (quantity > 0),
+			added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (cart_id, product_id, variant)
+		);
+
+		CREATE TABLE stock_levels (
+			product_id BIGINT NOT NULL,
+			variant TEXT NOT NULL DEFAULT '',
+			available INT NOT NULL CHECK (available >= 0),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (product_id, variant)
+		);
+
+		CREATE TABLE stock_reservations (
+			order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			product_id BIGINT NOT NULL,
+			variant TEXT NOT NULL DEFAULT '',
+			quantity INT NOT NULL CHECK (quantity > 0),
+			PRIMARY KEY (order_id, product_id, variant)
+		);
+
+		CREATE TABLE invoice_sequences (
+			kind VARCHAR(20) NOT NULL,
+			year INT NOT NULL,
+			last_number BIGINT NOT NULL,
+			PRIMARY KEY (kind, year)
+		);
+
+		CREATE TABLE invoices (
+			id BIGSERIAL PRIMARY KEY,
+			kind VARCHAR(20) NOT NULL CHECK (kind IN ('invoice', 'credit_note')),
+			number VARCHAR(32) NOT NULL UNIQUE,
+			year INT NOT NULL,
+			sequence BIGINT NOT NULL,
+			order_id BIGINT NOT NULL,
+			corrects_number VARCHAR(32) NOT NULL DEFAULT '',
+			reason TEXT NOT NULL DEFAULT '',
+			currency VARCHAR(3) NOT NULL,
+			seller JSONB NOT NULL,
+			customer JSONB NOT NULL,
+			lines JSONB NOT NULL,
+			taxes JSONB NOT NULL,
+			net_total NUMERIC(12, 2) NOT NULL,
+			tax_total NUMERIC(12, 2) NOT NULL,
+			grand_total NUMERIC(12, 2) NOT NULL,
+			issued_at TIMESTAMP NOT NULL,
+			UNIQUE (kind, year, sequence)
+		);
+		CREATE UNIQUE INDEX idx_invoices_one_per_order
+			ON invoices (order_id) WHERE kind = 'invoice';
+
+		CREATE OR REPLACE FUNCTION reject_invoice_change() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'invoice % is immutable', OLD.number;
+		END;
+		$$ LANGUAGE plpgsql;
+		CREATE TRIGGER invoices_immutable
+			BEFORE UPDATE OR DELETE ON invoices
+			FOR EACH ROW EXECUTE FUNCTION reject_invoice_change();
+
+		CREATE TABLE shipments (
+			id BIGSERIAL PRIMARY KEY,
+			order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+			carrier VARCHAR(64) NOT NULL,
+			tracking_number VARCHAR(128) NOT NULL,
+			status VARCHAR(20) NOT NULL
+				CHECK (status IN ('shipped', 'in_transit', 'out_for_delivery', 'delivered', 'exception')),
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			UNIQUE (carrier, tracking_number)
+		);
+
+		CREATE TABLE shipment_items (
+			shipment_id BIGINT NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+			product_id BIGINT NOT NULL,
+			variant TEXT NOT NULL DEFAULT '',
+			quantity INT NOT NULL CHECK (quantity > 0),
+			PRIMARY KEY (shipment_id, product_id, variant)
+		);
+
+		CREATE TABLE shipment_events (
+			id BIGSERIAL PRIMARY KEY,
+			shipment_id BIGINT NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+			status VARCHAR(20) NOT NULL
+				CHECK (status IN ('shipped', 'in_transit', 'out_for_delivery', 'delivered', 'exception')),
+			location TEXT NOT NULL DEFAULT '',
+			description TEXT NOT NULL DEFAULT '',
+			occurred_at TIMESTAMP NOT NULL,
+			recorded_at TIMESTAMP NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE outbox (
+			id BIGSERIAL PRIMARY KEY,
+			topic TEXT NOT NULL,
+			message_key TEXT NOT NULL,
+			payload JSON NOT NULL,
+			headers JSONB NOT NULL DEFAULT '{}',
+			attempts INT NOT NULL DEFAULT 0,
+			last_error TEXT,
+			next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			sent_at TIMESTAMP
+		);
+
+		CREATE INDEX idx_outbox_pending ON outbox (topic, id) WHERE sent_at IS NULL;
+		CREATE INDEX idx_outbox_pending_key ON outbox (topic, message_key, id) WHERE sent_at IS NULL;
 	`)
 	if err != nil {
 		panic(err)
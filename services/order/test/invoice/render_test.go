@@ -0,0 +1,134 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/adapters/invoice"
+	"product-app/services/order/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleInvoice(lines int) domain.Invoice {
+	document := domain.Invoice{
+		Kind:       domain.InvoiceKindInvoice,
+		Number:     "INV-2026-000042",
+		OrderId:    7,
+		Currency:   "EUR",
+		Seller:     domain.InvoiceParty{Name: "Product Platform GmbH", Address: "Main Street 1\n10115 Berlin", TaxId: "DE123456789"},
+		Customer:   domain.InvoiceParty{Name: "CUST-001"},
+		Taxes:      []domain.InvoiceTax{{Rate: 20, NetAmount: 10, TaxAmount: 2, Total: 12}},
+		NetTotal:   10,
+		TaxTotal:   2,
+		GrandTotal: 12,
+		IssuedAt:   time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC),
+	}
+	for i := 0; i < lines; i++ {
+		document.Lines = append(document.Lines, domain.InvoiceLine{
+			ProductID:   int64(i + 1),
+			Description: fmt.Sprintf("Café (grande) €%d", i),
+			Store:       "ABC Tech",
+			StoreSlug:   "abc-tech",
+			Quantity:    1,
+			UnitPrice:   12,
+			TaxRate:     20,
+			NetAmount:   10,
+			TaxAmount:   2,
+			Total:       12,
+		})
+	}
+	return document
+}
+
+// assertWellFormed checks the cross-reference table and stream lengths of a
+// PDF file.
+func assertWellFormed(t *testing.T, pdf []byte) {
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if !assert.NotNil(t, startxref, "missing startxref") {
+		return
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	assert.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(pdf[xref:], -1)
+	assert.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d offset", i+1)
+	}
+
+	for _, stream := range regexp.MustCompile(`(?s)/Length (\d+) >>\nstream\n(.*?)endstream`).FindAllSubmatch(pdf, -1) {
+		length, _ := strconv.Atoi(string(stream[1]))
+		assert.Equal(t, length, len(stream[2]))
+	}
+}
+
+func Test_ShouldRenderWellFormedPdf(t *testing.T) {
+	pdf, err := invoice.RenderPDF(sampleInvoice(2))
+
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assertWellFormed(t, pdf)
+	assert.Contains(t, string(pdf), "/Count 1")
+	assert.Contains(t, string(pdf), "(Invoice INV-2026-000042)")
+	assert.Contains(t, string(pdf), "(Tax ID: DE123456789)")
+	assert.Contains(t, string(pdf), `(Caf\351 \(grande\) \2000)`)
+	assert.Contains(t, string(pdf), "(12.00 EUR)")
+}
+
+func Test_ShouldTransliterateLettersMissingFromWinAnsi(t *testing.T) {
+	document := sampleInvoice(1)
+	document.Lines[0].Description = "Buzdolabı"
+	document.Lines[0].Store = "Dekorasyon Sarayı"
+	document.Customer.Name = "Şükrü Gündoğdu"
+
+	pdf, err := invoice.RenderPDF(document)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(pdf), "(Buzdolabi)")
+	assert.Contains(t, string(pdf), "Dekorasyon Sarayi")
+	assert.Contains(t, string(pdf), `(S\374kr\374 G\374ndogdu)`)
+	assert.NotContains(t, string(pdf), "?")
+}
+
+func Test_ShouldBreakLongInvoicesIntoPages(t *testing.T) {
+	pdf, err := invoice.RenderPDF(sampleInvoice(60))
+
+	assert.NoError(t, err)
+	assertWellFormed(t, pdf)
+	assert.Contains(t, string(pdf), "/Count 3")
+	assert.Contains(t, string(pdf), "(INV-2026-000042    Page 3 of 3)")
+	assert.Equal(t, 3, bytes.Count(pdf, []byte("(Description)")), "the table header repeats on every page")
+}
+
+func Test_ShouldRenderSameBytes_ForSameInvoice(t *testing.T) {
+	first, _ := invoice.RenderPDF(sampleInvoice(3))
+	second, _ := invoice.RenderPDF(sampleInvoice(3))
+
+	assert.Equal(t, first, second)
+}
+
+func Test_ShouldRenderCreditNoteAsHtml(t *testing.T) {
+	document := sampleInvoice(1)
+	document.Kind = domain.InvoiceKindCreditNote
+	document.Number = "CN-2026-000001"
+	document.CorrectsNumber = "INV-2026-000042"
+	document.Reason = "returned <damaged>"
+	document.Lines[0].Quantity = -1
+	document.Lines[0].Total = -12
+	document.GrandTotal = -12
+
+	html, err := invoice.RenderHTML(document)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), "<title>Credit note CN-2026-000001</title>")
+	assert.Contains(t, string(html), "Corrects invoice INV-2026-000042. Reason: returned &lt;damaged&gt;")
+	assert.Contains(t, string(html), "Sold by: ABC Tech (abc-tech)")
+	assert.Contains(t, string(html), "Main Street 1<br>10115 Berlin")
+	assert.Contains(t, string(html), "-12.00 EUR")
+}
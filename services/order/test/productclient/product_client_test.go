@@ -0,0 +1,156 @@
+package productclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/adapters/productclient"
+	"product-app/services/order/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fastOptions() productclient.Options {
+	return productclient.Options{
+		Timeout:          200 * time.Millisecond,
+		MaxRetries:       2,
+		RetryBackoff:     time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  50 * time.Millisecond,
+		CacheTTL:         50 * time.Millisecond,
+	}
+}
+
+// productServer answers GET /api/v1/products/1 like the product service and
+// 404 for every other product. fail makes the next n calls answer 503.
+func productServer(calls *int32, fail *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if atomic.AddInt32(fail, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/api/v1/products/1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Error:  product not found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"name":"AirFryer","price":1000,"description":"Digital air fryer","discount":10,"store_id":1,"category_id":1}`))
+	}))
+}
+
+func Test_ShouldGetProduct(t *testing.T) {
+	var calls, fail int32
+	server := productServer(&calls, &fail)
+	defer server.Close()
+
+	client := productclient.NewClient(server.URL+"/", fastOptions())
+	product, err := client.GetProduct(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CatalogProduct{Id: 1, Name: "AirFryer", Price: 1000, Discount: 10}, product)
+}
+
+func Test_ShouldReturnNotFound_WithoutRetrying(t *testing.T) {
+	var calls, fail int32
+	server := productServer(&calls, &fail)
+	defer server.Close()
+
+	client := productclient.NewClient(server.URL, fastOptions())
+	_, err := client.GetProduct(context.Background(), 99)
+
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func Test_ShouldRetryServerErrors(t *testing.T) {
+	var calls int32
+	fail := int32(2)
+	server := productServer(&calls, &fail)
+	defer server.Close()
+
+	client := productclient.NewClient(server.URL, fastOptions())
+	product, err := client.GetProduct(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), product.Id)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func Test_ShouldFailAsUnavailable_WhenRetriesExhausted(t *testing.T) {
+	var calls int32
+	fail := int32(10)
+	server := productServer(&calls, &fail)
+	defer server.Close()
+
+	client := productclient.NewClient(server.URL, fastOptions())
+	_, err := client.GetProduct(context.Background(), 1)
+
+	assert.ErrorIs(t, err, domain.ErrCatalogUnavailable)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func Test_ShouldTimeOutSlowResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	options := fastOptions()
+	options.Timeout = 10 * time.Millisecond
+	options.MaxRetries = 0
+	client := productclient.NewClient(server.URL, options)
+
+	start := time.Now()
+	_, err := client.GetProduct(context.Background(), 1)
+
+	assert.ErrorIs(t, err, domain.ErrCatalogUnavailable)
+	assert.Less(t, time.Since(start), 90*time.Millisecond)
+}
+
+func Test_ShouldOpenCircuit_AfterConsecutiveFailures(t *testing.T) {
+	var calls int32
+	fail := int32(6)
+	server := productServer(&calls, &fail)
+	defer server.Close()
+
+	client := productclient.NewClient(server.URL, fastOptions())
+	for i := 0; i < 2; i++ {
+		_, err := client.GetProduct(context.Background(), 1)
+		assert.ErrorIs(t, err, domain.ErrCatalogUnavailable)
+	}
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+
+	_, err := client.GetProduct(context.Background(), 1)
+	assert.ErrorIs(t, err, domain.ErrCatalogUnavailable)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls), "an open circuit must not call the product service")
+
+	time.Sleep(60 * time.Millisecond)
+	product, err := client.GetProduct(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), product.Id)
+	assert.Equal(t, int32(7), atomic.LoadInt32(&calls))
+}
+
+func Test_ShouldServeProductsFromCache_UntilExpired(t *testing.T) {
+	var calls, fail int32
+	server := productServer(&calls, &fail)
+	defer server.Close()
+
+	client := productclient.NewClient(server.URL, fastOptions())
+	for i := 0; i < 3; i++ {
+		_, err := client.GetProduct(context.Background(), 1)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	time.Sleep(60 * time.Millisecond)
+	_, err := client.GetProduct(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
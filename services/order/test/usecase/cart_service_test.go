@@ -0,0 +1,249 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+	"product-app/shared/kafka"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cartFixture struct {
+	carts   *FakeCartRepository
+	orders  *FakeOrderRepository
+	catalog *FakeProductCatalog
+	service usecase.ICartService
+}
+
+func setupCartService(carts ...domain.Cart) cartFixture {
+	orders := NewFakeOrderRepository(nil)
+	fixture := cartFixture{
+		carts:  NewFakeCartRepository(orders, carts...),
+		orders: orders,
+		catalog: NewFakeProductCatalog(
+			domain.CatalogProduct{Id: 1, Name: "Keyboard", Price: 10, Discount: 10},
+			domain.CatalogProduct{Id: 2, Name: "Mouse", Price: 5},
+		),
+	}
+	fixture.service = usecase.NewCartService(fixture.carts, fixture.catalog, time.Hour)
+	return fixture
+}
+
+func Test_ShouldReturnEmptyCart_WhenVisitorHasNone(t *testing.T) {
+	fixture := setupCartService()
+
+	cart, err := fixture.service.Get(model.CartOwner{})
+
+	assert.NoError(t, err)
+	assert.Zero(t, cart.Id)
+	assert.Empty(t, cart.Items)
+	assert.Empty(t, fixture.carts.carts, "reading must not create a cart")
+}
+
+func Test_ShouldCreateAnonymousCart_OnFirstItem(t *testing.T) {
+	fixture := setupCartService()
+
+	cart, err := fixture.service.AddItem(model.CartOwner{}, model.CartItemCreate{ProductID: 1, Quantity: 2})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cart.Token)
+	assert.NotNil(t, cart.ExpiresAt)
+	assert.Len(t, cart.Items, 1)
+
+	again, err := fixture.service.AddItem(model.CartOwner{Token: cart.Token}, model.CartItemCreate{ProductID: 1, Quantity: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, cart.Id, again.Id)
+	assert.Equal(t, int32(3), again.Items[0].Quantity)
+}
+
+func Test_ShouldRepriceCart_OnEveryRead(t *testing.T) {
+	fixture := setupCartService()
+	owner := model.CartOwner{UserId: 1}
+	_, err := fixture.service.AddItem(owner, model.CartItemCreate{ProductID: 1, Quantity: 2})
+	assert.NoError(t, err)
+	_, err = fixture.service.AddItem(owner, model.CartItemCreate{ProductID: 2, Variant: "black", Quantity: 1})
+	assert.NoError(t, err)
+
+	cart, err := fixture.service.Get(owner)
+	assert.NoError(t, err)
+	assert.Equal(t, "Keyboard", cart.Items[0].Name)
+	assert.Equal(t, 2.0, cart.Items[0].Discount)
+	assert.Equal(t, 18.0, cart.Items[0].LineTotal)
+	assert.Equal(t, 25.0, cart.Subtotal)
+	assert.Equal(t, 23.0, cart.GrandTotal)
+
+	fixture.catalog.products[2] = domain.CatalogProduct{Id: 2, Name: "Mouse", Price: 7}
+	cart, err = fixture.service.Get(owner)
+	assert.NoError(t, err)
+	assert.Equal(t, 7.0, cart.Items[1].UnitPrice)
+	assert.Equal(t, 25.0, cart.GrandTotal)
+}
+
+func Test_ShouldMarkItemUnavailable_WhenProductRemovedFromCatalog(t *testing.T) {
+	fixture := setupCartService(domain.Cart{Id: 1, UserId: 1, Items: []domain.CartItem{
+		{ProductID: 1, Quantity: 1},
+		{ProductID: 3, Quantity: 1},
+	}})
+
+	cart, err := fixture.service.Get(model.CartOwner{UserId: 1})
+
+	assert.NoError(t, err)
+	assert.True(t, cart.Items[1].Unavailable)
+	assert.Equal(t, 9.0, cart.GrandTotal)
+
+	_, err = fixture.service.Checkout(model.CartOwner{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrInvalidCartItem)
+}
+
+func Test_ShouldRejectInvalidCartItems(t *testing.T) {
+	fixture := setupCartService()
+	owner := model.CartOwner{UserId: 1}
+
+	_, err := fixture.service.AddItem(owner, model.CartItemCreate{ProductID: 1, Quantity: 0})
+	assert.ErrorIs(t, err, domain.ErrInvalidCartItem)
+	_, err = fixture.service.AddItem(owner, model.CartItemCreate{ProductID: 1, Quantity: usecase.MaxCartQuantity + 1})
+	assert.ErrorIs(t, err, domain.ErrInvalidCartItem)
+	_, err = fixture.service.AddItem(owner, model.CartItemCreate{ProductID: 3, Quantity: 1})
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	fixture.catalog.unavailable = true
+	_, err = fixture.service.AddItem(owner, model.CartItemCreate{ProductID: 1, Quantity: 1})
+	assert.ErrorIs(t, err, domain.ErrCatalogUnavailable)
+}
+
+func Test_ShouldUpdateAndRemoveCartItems(t *testing.T) {
+	fixture := setupCartService(domain.Cart{Id: 1, UserId: 1, Items: []domain.CartItem{
+		{ProductID: 1, Quantity: 1},
+		{ProductID: 2, Variant: "black", Quantity: 1},
+	}})
+	owner := model.CartOwner{UserId: 1}
+
+	cart, err := fixture.service.UpdateItem(owner, 1, model.CartItemUpdate{Quantity: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), cart.Items[0].Quantity)
+
+	cart, err = fixture.service.RemoveItem(owner, 2, "black")
+	assert.NoError(t, err)
+	assert.Len(t, cart.Items, 1)
+
+	_, err = fixture.service.RemoveItem(owner, 2, "black")
+	assert.ErrorIs(t, err, domain.ErrCartItemNotFound)
+	_, err = fixture.service.UpdateItem(model.CartOwner{}, 1, model.CartItemUpdate{Quantity: 1})
+	assert.ErrorIs(t, err, domain.ErrCartItemNotFound)
+}
+
+func Test_ShouldMergeAnonymousCart_WhenUserSignsIn(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	fixture := setupCartService(
+		domain.Cart{Id: 1, UserId: 1, Items: []domain.CartItem{{ProductID: 1, Quantity: 1}}},
+		domain.Cart{Id: 2, Token: "visitor", ExpiresAt: &expired, Items: []domain.CartItem{
+			{ProductID: 1, Quantity: 2},
+			{ProductID: 2, Quantity: 1},
+		}},
+	)
+
+	cart, err := fixture.service.Get(model.CartOwner{UserId: 1, Token: "visitor"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), cart.Id)
+	assert.Empty(t, cart.Token)
+	assert.Len(t, cart.Items, 2)
+	assert.Equal(t, int32(3), cart.Items[0].Quantity)
+	_, err = fixture.carts.GetByToken("visitor")
+	assert.ErrorIs(t, err, domain.ErrCartNotFound)
+
+	cart, err = fixture.service.Get(model.CartOwner{UserId: 1, Token: "visitor"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), cart.Items[0].Quantity, "a merged cart is merged once")
+}
+
+func Test_ShouldNotReuseExpiredAnonymousCart(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	fixture := setupCartService(domain.Cart{Id: 1, Token: "visitor", ExpiresAt: &expired, Items: []domain.CartItem{
+		{ProductID: 1, Quantity: 2},
+	}})
+
+	cart, err := fixture.service.Get(model.CartOwner{Token: "visitor"})
+	assert.NoError(t, err)
+	assert.Empty(t, cart.Items)
+
+	cart, err = fixture.service.AddItem(model.CartOwner{Token: "visitor"}, model.CartItemCreate{ProductID: 2, Quantity: 1})
+	assert.NoError(t, err)
+	assert.NotEqual(t, "visitor", cart.Token)
+	assert.Len(t, cart.Items, 1)
+}
+
+func Test_ShouldCheckoutCart_IntoOrder(t *testing.T) {
+	fixture := setupCartService(domain.Cart{Id: 1, UserId: 1, Items: []domain.CartItem{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, Variant: "black", Quantity: 1},
+	}})
+
+	order, err := fixture.service.Checkout(model.CartOwner{UserId: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), order.UserId)
+	assert.Equal(t, domain.OrderStatusPending, order.Status)
+	assert.Len(t, order.Items, 2)
+	assert.Equal(t, "black", order.Items[1].Variant)
+	assert.Equal(t, 23.0, order.GrandTotal)
+
+	stored, err := fixture.orders.GetById(order.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, order.GrandTotal, stored.GrandTotal)
+
+	cart, err := fixture.service.Get(model.CartOwner{UserId: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, cart.Items)
+
+	assert.Len(t, fixture.orders.outbox.events, 1)
+	_, ok := fixture.orders.outbox.events[0].(kafka.OrderCreated)
+	assert.True(t, ok)
+}
+
+func Test_ShouldRejectCheckout_WhenCartEmptyOrAnonymous(t *testing.T) {
+	fixture := setupCartService()
+
+	_, err := fixture.service.Checkout(model.CartOwner{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrCartEmpty)
+
+	_, err = fixture.service.Checkout(model.CartOwner{Token: "visitor"})
+	assert.Error(t, err)
+	assert.Empty(t, fixture.orders.outbox.events)
+}
+
+func Test_ShouldPruneCarts_OneTTLAfterExpiry(t *testing.T) {
+	recent := time.Now().Add(-time.Minute)
+	old := time.Now().Add(-2 * time.Hour)
+	fixture := setupCartService(
+		domain.Cart{Id: 1, Token: "recent", ExpiresAt: &recent},
+		domain.Cart{Id: 2, Token: "old", ExpiresAt: &old},
+		domain.Cart{Id: 3, UserId: 1},
+	)
+
+	deleted, err := fixture.service.PruneExpired()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, err = fixture.carts.GetByToken("recent")
+	assert.NoError(t, err)
+}
+
+func Test_ShouldLetOnlyAdminsManageStock(t *testing.T) {
+	service := usecase.NewStockService(&FakeStockRepository{})
+
+	_, err := service.SetStock(1, model.StockLevelUpdate{Available: 5}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+	_, err = service.SetStock(1, model.StockLevelUpdate{Available: -1}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidStockLevel)
+
+	_, err = service.SetStock(1, model.StockLevelUpdate{Variant: "black", Available: 5}, admin)
+	assert.NoError(t, err)
+	levels, err := service.GetStock(1, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.StockLevel{{ProductID: 1, Variant: "black", Available: 5}}, levels)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+)
+
+// FakeCartRepository keeps carts in memory and stores checked-out orders in
+// orders.
+type FakeCartRepository struct {
+	carts  []domain.Cart
+	orders ports.OrderRepository
+}
+
+func NewFakeCartRepository(orders ports.OrderRepository, initialCarts ...domain.Cart) *FakeCartRepository {
+	return &FakeCartRepository{carts: initialCarts, orders: orders}
+}
+
+var _ ports.CartRepository = (*FakeCartRepository)(nil)
+
+func (repo *FakeCartRepository) GetOrCreateForUser(userId int64) (domain.Cart, error) {
+	for _, cart := range repo.carts {
+		if cart.UserId == userId {
+			return copyCart(cart), nil
+		}
+	}
+	return repo.create(domain.Cart{UserId: userId}), nil
+}
+
+func (repo *FakeCartRepository) GetByToken(token string) (domain.Cart, error) {
+	for _, cart := range repo.carts {
+		if cart.Token == token {
+			return copyCart(cart), nil
+		}
+	}
+	return domain.Cart{}, domain.ErrCartNotFound
+}
+
+func (repo *FakeCartRepository) CreateAnonymous(token string, expiresAt time.Time) (domain.Cart, error) {
+	return repo.create(domain.Cart{Token: token, ExpiresAt: &expiresAt}), nil
+}
+
+func (repo *FakeCartRepository) AddItem(cart domain.Cart, item domain.CartItem) error {
+	stored, err := repo.find(cart.Id)
+	if err != nil {
+		return err
+	}
+	stored.ExpiresAt = cart.ExpiresAt
+	for i := range stored.Items {
+		if stored.Items[i].ProductID == item.ProductID && stored.Items[i].Variant == item.Variant {
+			stored.Items[i].Quantity += item.Quantity
+			return nil
+		}
+	}
+	stored.Items = append(stored.Items, domain.CartItem{ProductID: item.ProductID, Variant: item.Variant, Quantity: item.Quantity})
+	return nil
+}
+
+func (repo *FakeCartRepository) SetItemQuantity(cart domain.Cart, productId int64, variant string, quantity int32) error {
+	stored, err := repo.find(cart.Id)
+	if err != nil {
+		return err
+	}
+	stored.ExpiresAt = cart.ExpiresAt
+	for i := range stored.Items {
+		if stored.Items[i].ProductID == productId && stored.Items[i].Variant == variant {
+			if quantity == 0 {
+				stored.Items = slices.Delete(stored.Items, i, i+1)
+			} else {
+				stored.Items[i].Quantity = quantity
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: product %d", domain.ErrCartItemNotFound, productId)
+}
+
+func (repo *FakeCartRepository) Merge(fromCartId, intoCartId int64) error {
+	from, err := repo.find(fromCartId)
+	if err != nil {
+		return err
+	}
+	into, err := repo.find(intoCartId)
+	if err != nil {
+		return err
+	}
+	for _, item := range from.Items {
+		if err := repo.AddItem(*into, item); err != nil {
+			return err
+		}
+	}
+	repo.carts = slices.DeleteFunc(repo.carts, func(cart domain.Cart) bool { return cart.Id == fromCartId })
+	return nil
+}
+
+func (repo *FakeCartRepository) Checkout(cart domain.Cart, order domain.Order, events ports.OrderEvents) (domain.Order, error) {
+	stored, err := repo.find(cart.Id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if len(stored.Items) != len(cart.Items) {
+		return domain.Order{}, domain.ErrCartChanged
+	}
+	for i, item := range stored.Items {
+		if item.ProductID != cart.Items[i].ProductID || item.Variant != cart.Items[i].Variant || item.Quantity != cart.Items[i].Quantity {
+			return domain.Order{}, domain.ErrCartChanged
+		}
+	}
+	created, err := repo.orders.Create(order, events)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	stored.Items = nil
+	return created, nil
+}
+
+func (repo *FakeCartRepository) DeleteExpired(before time.Time) (int64, error) {
+	count := len(repo.carts)
+	repo.carts = slices.DeleteFunc(repo.carts, func(cart domain.Cart) bool {
+		return cart.ExpiresAt != nil && cart.ExpiresAt.Before(before)
+	})
+	return int64(count - len(repo.carts)), nil
+}
+
+func (repo *FakeCartRepository) create(cart domain.Cart) domain.Cart {
+	cart.Id = int64(len(repo.carts)) + 1
+	for _, existing := range repo.carts {
+		cart.Id = max(cart.Id, existing.Id+1)
+	}
+	cart.Items = []domain.CartItem{}
+	cart.UpdatedAt = time.Now().UTC()
+	repo.carts = append(repo.carts, cart)
+	return copyCart(cart)
+}
+
+func (repo *FakeCartRepository) find(id int64) (*domain.Cart, error) {
+	for i := range repo.carts {
+		if repo.carts[i].Id == id {
+			return &repo.carts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", domain.ErrCartNotFound, id)
+}
+
+func copyCart(cart domain.Cart) domain.Cart {
+	cart.Items = append([]domain.CartItem{}, cart.Items...)
+	return cart
+}
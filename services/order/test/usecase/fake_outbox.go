@@ -0,0 +1,31 @@
+package service
+
+import (
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"product-app/shared/kafka"
+)
+
+// FakeOutbox collects the events the fake repositories would write to the
+// outbox with their changes, in order. A nil outbox drops them.
+type FakeOutbox struct {
+	events []kafka.Event
+}
+
+func (fakeOutbox *FakeOutbox) record(events []kafka.Event) {
+	if fakeOutbox != nil {
+		fakeOutbox.events = append(fakeOutbox.events, events...)
+	}
+}
+
+func (fakeOutbox *FakeOutbox) recordOrder(events ports.OrderEvents, order domain.Order) {
+	if events != nil {
+		fakeOutbox.record(events(order))
+	}
+}
+
+func (fakeOutbox *FakeOutbox) recordShipment(events ports.ShipmentEvents, shipment domain.Shipment) {
+	if events != nil {
+		fakeOutbox.record(events(shipment))
+	}
+}
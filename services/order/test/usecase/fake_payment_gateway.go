@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+)
+
+// fakeSignatureHeader must be "valid" for FakePaymentGateway to accept a
+// webhook, whose body is a JSON encoded domain.PaymentEvent.
+const fakeSignatureHeader = "Fake-Signature"
+
+type FakePaymentGateway struct {
+	intents  []domain.PaymentIntentRequest
+	captures []string
+	refunds  []domain.RefundRequest
+	decline  bool
+	// onRefund, if set, runs while a refund is being issued.
+	onRefund func()
+}
+
+var _ ports.PaymentGateway = (*FakePaymentGateway)(nil)
+
+func (gateway *FakePaymentGateway) Provider() string {
+	return "fake"
+}
+
+func (gateway *FakePaymentGateway) CreatePaymentIntent(ctx context.Context, request domain.PaymentIntentRequest) (domain.PaymentIntent, error) {
+	if gateway.decline {
+		return domain.PaymentIntent{}, fmt.Errorf("%w: declined by provider", domain.ErrPaymentDeclined)
+	}
+	gateway.intents = append(gateway.intents, request)
+	id := fmt.Sprintf("pi_%d", len(gateway.intents))
+	return domain.PaymentIntent{ProviderPaymentId: id, ClientSecret: id + "_secret"}, nil
+}
+
+func (gateway *FakePaymentGateway) Capture(ctx context.Context, providerPaymentId string, amount float64) error {
+	if gateway.decline {
+		return fmt.Errorf("%w: declined by provider", domain.ErrPaymentDeclined)
+	}
+	gateway.captures = append(gateway.captures, providerPaymentId)
+	return nil
+}
+
+func (gateway *FakePaymentGateway) Refund(ctx context.Context, request domain.RefundRequest) (string, error) {
+	if gateway.onRefund != nil {
+		gateway.onRefund()
+	}
+	if gateway.decline {
+		return "", fmt.Errorf("%w: declined by provider", domain.ErrRefundFailed)
+	}
+	gateway.refunds = append(gateway.refunds, request)
+	return fmt.Sprintf("re_%d", len(gateway.refunds)), nil
+}
+
+func (gateway *FakePaymentGateway) ParseWebhook(payload []byte, header http.Header) (domain.PaymentEvent, error) {
+	if header.Get(fakeSignatureHeader) != "valid" {
+		return domain.PaymentEvent{}, domain.ErrInvalidWebhookSignature
+	}
+	var event domain.PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return domain.PaymentEvent{}, err
+	}
+	return event, nil
+}
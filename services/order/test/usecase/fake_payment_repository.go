@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+)
+
+type FakePaymentRepository struct {
+	payments []domain.Payment
+	events   map[string]bool
+}
+
+func NewFakePaymentRepository(initialPayments []domain.Payment) *FakePaymentRepository {
+	return &FakePaymentRepository{payments: initialPayments, events: map[string]bool{}}
+}
+
+var _ ports.PaymentRepository = (*FakePaymentRepository)(nil)
+
+func (repo *FakePaymentRepository) Create(payment domain.Payment) (domain.Payment, error) {
+	for _, existing := range repo.payments {
+		if existing.OrderId == payment.OrderId &&
+			(existing.Status == domain.PaymentStatusPending || existing.Status == domain.PaymentStatusAuthorized) {
+			return domain.Payment{}, domain.ErrPaymentAlreadyInProgress
+		}
+	}
+	payment.Id = int64(len(repo.payments)) + 1
+	payment.CreatedAt = time.Now().UTC()
+	payment.UpdatedAt = payment.CreatedAt
+	repo.payments = append(repo.payments, payment)
+	return payment, nil
+}
+
+func (repo *FakePaymentRepository) GetByProviderPaymentId(provider, providerPaymentId string) (domain.Payment, error) {
+	for _, payment := range repo.payments {
+		if payment.Provider == provider && payment.ProviderPaymentId == providerPaymentId {
+			return payment, nil
+		}
+	}
+	return domain.Payment{}, fmt.Errorf("%w: %s", domain.ErrPaymentNotFound, providerPaymentId)
+}
+
+func (repo *FakePaymentRepository) GetByOrderId(orderId int64) ([]domain.Payment, error) {
+	payments := []domain.Payment{}
+	for _, payment := range repo.payments {
+		if payment.OrderId == orderId {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+func (repo *FakePaymentRepository) UpdateStatus(id int64, from []string, status, failureReason string) (domain.Payment, error) {
+	for i, payment := range repo.payments {
+		if payment.Id != id {
+			continue
+		}
+		if !slices.Contains(from, payment.Status) {
+			return domain.Payment{}, fmt.Errorf("%w: payment %d is %s", domain.ErrIllegalTransition, id, payment.Status)
+		}
+		repo.payments[i].Status = status
+		repo.payments[i].FailureReason = failureReason
+		return repo.payments[i], nil
+	}
+	return domain.Payment{}, fmt.Errorf("%w: %d", domain.ErrPaymentNotFound, id)
+}
+
+func (repo *FakePaymentRepository) ReserveWebhookEvent(provider string, event domain.PaymentEvent) error {
+	key := provider + "/" + event.Id
+	if repo.events[key] {
+		return domain.ErrDuplicateWebhookEvent
+	}
+	repo.events[key] = true
+	return nil
+}
+
+func (repo *FakePaymentRepository) ReleaseWebhookEvent(provider, eventId string) error {
+	delete(repo.events, provider+"/"+eventId)
+	return nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+)
+
+type FakeShipmentRepository struct {
+	orders    *FakeOrderRepository
+	shipments []domain.Shipment
+	events    int64
+	outbox    *FakeOutbox
+}
+
+// NewFakeShipmentRepository checks shipments against the orders of orders,
+// as the Postgres repository does, and records their events in its outbox.
+func NewFakeShipmentRepository(orders *FakeOrderRepository) *FakeShipmentRepository {
+	return &FakeShipmentRepository{orders: orders, outbox: orders.outbox}
+}
+
+var _ ports.ShipmentRepository = (*FakeShipmentRepository)(nil)
+
+func (repo *FakeShipmentRepository) Create(shipment domain.Shipment, events ports.ShipmentEvents) (domain.Shipment, error) {
+	order, err := repo.orders.GetById(shipment.OrderId)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if order.Status != domain.OrderStatusPaid {
+		return domain.Shipment{}, fmt.Errorf("%w: order %d is %s", domain.ErrIllegalTransition, order.Id, order.Status)
+	}
+	unshipped := map[string]int32{}
+	for _, item := range order.Items {
+		unshipped[fmt.Sprintf("%d/%s", item.ProductID, item.Variant)] += item.Quantity
+	}
+	for _, stored := range repo.shipments {
+		if stored.Carrier == shipment.Carrier && stored.TrackingNumber == shipment.TrackingNumber {
+			return domain.Shipment{}, fmt.Errorf("%w: %s %s", domain.ErrDuplicateTrackingNumber, shipment.Carrier, shipment.TrackingNumber)
+		}
+		if stored.OrderId == shipment.OrderId {
+			for _, item := range stored.Items {
+				unshipped[fmt.Sprintf("%d/%s", item.ProductID, item.Variant)] -= item.Quantity
+			}
+		}
+	}
+	for _, item := range shipment.Items {
+		if left := unshipped[fmt.Sprintf("%d/%s", item.ProductID, item.Variant)]; item.Quantity > left {
+			return domain.Shipment{}, fmt.Errorf("%w: only %d of product %d %q are left to ship",
+				domain.ErrInvalidShipment, left, item.ProductID, item.Variant)
+		}
+	}
+	shipment.Id = int64(len(repo.shipments)) + 1
+	shipment.CreatedAt = time.Now().UTC()
+	shipment.UpdatedAt = shipment.CreatedAt
+	shipment.Items = slices.Clone(shipment.Items)
+	shipment.Events = slices.Clone(shipment.Events)
+	for i := range shipment.Events {
+		repo.events++
+		shipment.Events[i].Id = repo.events
+		shipment.Events[i].ShipmentId = shipment.Id
+		shipment.Events[i].RecordedAt = shipment.CreatedAt
+	}
+	repo.shipments = append(repo.shipments, shipment)
+	repo.outbox.recordShipment(events, copyShipment(shipment))
+	return copyShipment(shipment), nil
+}
+
+func (repo *FakeShipmentRepository) GetById(id int64) (domain.Shipment, error) {
+	for _, shipment := range repo.shipments {
+		if shipment.Id == id {
+			return copyShipment(shipment), nil
+		}
+	}
+	return domain.Shipment{}, fmt.Errorf("%w with id %d", domain.ErrShipmentNotFound, id)
+}
+
+func (repo *FakeShipmentRepository) GetByOrderId(orderId int64) ([]domain.Shipment, error) {
+	shipments := []domain.Shipment{}
+	for _, shipment := range repo.shipments {
+		if shipment.OrderId == orderId {
+			shipments = append(shipments, copyShipment(shipment))
+		}
+	}
+	return shipments, nil
+}
+
+func (repo *FakeShipmentRepository) AddEvent(event domain.TrackingEvent, events ports.ShipmentEvents) (domain.Shipment, error) {
+	for i := range repo.shipments {
+		shipment := &repo.shipments[i]
+		if shipment.Id != event.ShipmentId {
+			continue
+		}
+		if shipment.Status == domain.ShipmentStatusDelivered {
+			return domain.Shipment{}, fmt.Errorf("%w: shipment %d is delivered", domain.ErrIllegalTransition, shipment.Id)
+		}
+		repo.events++
+		event.Id = repo.events
+		event.RecordedAt = time.Now().UTC()
+		shipment.Events = append(shipment.Events, event)
+		shipment.Status = event.Status
+		shipment.UpdatedAt = event.RecordedAt
+		repo.outbox.recordShipment(events, copyShipment(*shipment))
+		return copyShipment(*shipment), nil
+	}
+	return domain.Shipment{}, fmt.Errorf("%w with id %d", domain.ErrShipmentNotFound, event.ShipmentId)
+}
+
+func copyShipment(shipment domain.Shipment) domain.Shipment {
+	shipment.Items = slices.Clone(shipment.Items)
+	shipment.Events = slices.Clone(shipment.Events)
+	return shipment
+}
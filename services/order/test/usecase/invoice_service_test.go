@@ -0,0 +1,220 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var invoiceSettings = usecase.InvoiceSettings{
+	Seller:   domain.InvoiceParty{Name: "Product Platform GmbH", Address: "Main Street 1\n10115 Berlin", TaxId: "DE123456789"},
+	Currency: "EUR",
+	TaxRate:  20,
+}
+
+func setupInvoiceService(orders ...domain.Order) (usecase.IInvoiceService, *FakeInvoiceRepository, *FakeProductCatalog) {
+	catalog := NewFakeProductCatalog(
+		domain.CatalogProduct{Id: 1, Name: "Keyboard", Price: 10, Store: "ABC Tech", StoreSlug: "abc-tech"},
+		domain.CatalogProduct{Id: 2, Name: "Mouse", Price: 6, Store: "ABC Tech", StoreSlug: "abc-tech"},
+	)
+	invoices := NewFakeInvoiceRepository()
+	orderService := usecase.NewOrderService(NewFakeOrderRepository(orders), nil, nil, nil)
+	return usecase.NewInvoiceService(orderService, invoices, catalog, invoiceSettings), invoices, catalog
+}
+
+func paidOrder(id int64, status string) domain.Order {
+	return domain.Order{
+		Id:             id,
+		UserId:         1,
+		CustomerNumber: "CUST-001",
+		Status:         status,
+		Items: []domain.OrderItem{
+			{ProductID: 1, Quantity: 3, UnitPrice: 10, Discount: 3, LineTotal: 27},
+			{ProductID: 2, Variant: "black", Quantity: 1, UnitPrice: 6, LineTotal: 6},
+			{ProductID: 9, Quantity: 1, UnitPrice: 12, LineTotal: 12},
+		},
+		Subtotal:      48,
+		DiscountTotal: 3,
+		GrandTotal:    45,
+	}
+}
+
+func Test_ShouldIssueInvoiceOnce_ForPaidOrder(t *testing.T) {
+	service, invoices, _ := setupInvoiceService(paidOrder(7, domain.OrderStatusPaid))
+
+	invoice, err := service.GetInvoice(7, model.Requester{UserId: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.InvoiceKindInvoice, invoice.Kind)
+	assert.Equal(t, domain.InvoiceNumber(domain.InvoiceKindInvoice, time.Now().UTC().Year(), 1), invoice.Number)
+	assert.Equal(t, "Product Platform GmbH", invoice.Seller.Name)
+	assert.Equal(t, "CUST-001", invoice.Customer.Name)
+	assert.Equal(t, "EUR", invoice.Currency)
+	assert.Equal(t, "Keyboard", invoice.Lines[0].Description)
+	assert.Equal(t, "ABC Tech", invoice.Lines[0].Store)
+	assert.Equal(t, "Product #9", invoice.Lines[2].Description, "products gone from the catalog are described by id")
+	assert.Equal(t, 22.5, invoice.Lines[0].NetAmount)
+	assert.Equal(t, 4.5, invoice.Lines[0].TaxAmount)
+	assert.Equal(t, []domain.InvoiceTax{{Rate: 20, NetAmount: 37.5, TaxAmount: 7.5, Total: 45}}, invoice.Taxes)
+	assert.Equal(t, 37.5, invoice.NetTotal)
+	assert.Equal(t, 7.5, invoice.TaxTotal)
+	assert.Equal(t, 45.0, invoice.GrandTotal)
+
+	again, err := service.GetInvoice(7, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, invoice.Number, again.Number)
+	assert.Len(t, invoices.invoices, 1)
+}
+
+func Test_ShouldNumberInvoicesWithoutGaps(t *testing.T) {
+	service, _, catalog := setupInvoiceService(
+		paidOrder(7, domain.OrderStatusPaid),
+		paidOrder(8, domain.OrderStatusDelivered),
+		paidOrder(9, domain.OrderStatusShipped),
+	)
+
+	first, err := service.GetInvoice(7, admin)
+	assert.NoError(t, err)
+	catalog.unavailable = true
+	_, err = service.GetInvoice(8, admin)
+	assert.ErrorIs(t, err, domain.ErrCatalogUnavailable)
+	catalog.unavailable = false
+	second, err := service.GetInvoice(9, admin)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first.Sequence+1, second.Sequence)
+}
+
+func Test_ShouldKeepIssuedInvoice_WhenCatalogChanges(t *testing.T) {
+	service, _, catalog := setupInvoiceService(paidOrder(7, domain.OrderStatusPaid))
+	_, err := service.GetInvoice(7, admin)
+	assert.NoError(t, err)
+
+	catalog.products[1] = domain.CatalogProduct{Id: 1, Name: "Renamed keyboard", Price: 99}
+	invoice, err := service.GetInvoice(7, admin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Keyboard", invoice.Lines[0].Description)
+}
+
+func Test_ShouldRejectInvoice_WhenOrderNotPaidOrNotOwned(t *testing.T) {
+	service, _, _ := setupInvoiceService(paidOrder(7, domain.OrderStatusPending), paidOrder(8, domain.OrderStatusPaid))
+
+	_, err := service.GetInvoice(7, admin)
+	assert.ErrorIs(t, err, domain.ErrInvoiceNotAvailable)
+
+	_, err = service.GetInvoice(8, model.Requester{UserId: 2})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+
+	_, err = service.GetInvoice(99, admin)
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func Test_ShouldIssuePartialCreditNotes_UpToInvoicedQuantity(t *testing.T) {
+	service, _, _ := setupInvoiceService(paidOrder(7, domain.OrderStatusPaid))
+	invoice, err := service.GetInvoice(7, admin)
+	assert.NoError(t, err)
+
+	creditNote, err := service.IssueCreditNote(7, model.CreditNoteCreate{
+		Reason: "one keyboard returned",
+		Items:  []model.CreditNoteItem{{ProductID: 1, Quantity: 1}},
+	}, admin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.InvoiceKindCreditNote, creditNote.Kind)
+	assert.Equal(t, domain.InvoiceNumber(domain.InvoiceKindCreditNote, time.Now().UTC().Year(), 1), creditNote.Number)
+	assert.Equal(t, invoice.Number, creditNote.CorrectsNumber)
+	assert.Equal(t, int32(-1), creditNote.Lines[0].Quantity)
+	assert.Equal(t, -9.0, creditNote.Lines[0].Total)
+	assert.Equal(t, -1.5, creditNote.Lines[0].TaxAmount)
+	assert.Equal(t, -9.0, creditNote.GrandTotal)
+
+	_, err = service.IssueCreditNote(7, model.CreditNoteCreate{
+		Reason: "too many",
+		Items:  []model.CreditNoteItem{{ProductID: 1, Quantity: 3}},
+	}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidCreditNote)
+
+	_, err = service.IssueCreditNote(7, model.CreditNoteCreate{
+		Reason: "unknown product",
+		Items:  []model.CreditNoteItem{{ProductID: 5, Quantity: 1}},
+	}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidCreditNote)
+}
+
+func Test_ShouldCreditRemainder_WhenNoItemsGiven(t *testing.T) {
+	service, _, _ := setupInvoiceService(paidOrder(7, domain.OrderStatusPaid))
+	_, err := service.GetInvoice(7, admin)
+	assert.NoError(t, err)
+	_, err = service.IssueCreditNote(7, model.CreditNoteCreate{
+		Reason: "one keyboard returned",
+		Items:  []model.CreditNoteItem{{ProductID: 1, Quantity: 1}},
+	}, admin)
+	assert.NoError(t, err)
+
+	rest, err := service.IssueCreditNote(7, model.CreditNoteCreate{Reason: "order cancelled"}, admin)
+
+	assert.NoError(t, err)
+	assert.Len(t, rest.Lines, 3)
+	assert.Equal(t, int32(-2), rest.Lines[0].Quantity)
+	assert.Equal(t, -18.0, rest.Lines[0].Total)
+	assert.Equal(t, -36.0, rest.GrandTotal, "both credit notes together cancel the invoice")
+	assert.Equal(t, -30.0, rest.NetTotal)
+
+	_, err = service.IssueCreditNote(7, model.CreditNoteCreate{Reason: "again"}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidCreditNote)
+
+	creditNotes, err := service.GetCreditNotes(7, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	assert.Len(t, creditNotes, 2)
+	found, err := service.GetCreditNote(7, rest.Number, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, rest.Id, found.Id)
+}
+
+// staleCreditNotes hides the credit notes already issued, as when another
+// credit note is issued between the service's check and its own.
+type staleCreditNotes struct {
+	*FakeInvoiceRepository
+}
+
+func (staleCreditNotes) GetCreditNotes(orderId int64) ([]domain.Invoice, error) {
+	return []domain.Invoice{}, nil
+}
+
+func Test_ShouldRejectCreditNote_WhenConcurrentCreditNoteCreditedTheUnits(t *testing.T) {
+	service, invoices, catalog := setupInvoiceService(paidOrder(7, domain.OrderStatusPaid))
+	_, err := service.GetInvoice(7, admin)
+	assert.NoError(t, err)
+	_, err = service.IssueCreditNote(7, model.CreditNoteCreate{Reason: "order cancelled"}, admin)
+	assert.NoError(t, err)
+
+	orderService := usecase.NewOrderService(NewFakeOrderRepository([]domain.Order{paidOrder(7, domain.OrderStatusPaid)}), nil, nil, nil)
+	racing := usecase.NewInvoiceService(orderService, staleCreditNotes{invoices}, catalog, invoiceSettings)
+	_, err = racing.IssueCreditNote(7, model.CreditNoteCreate{
+		Reason: "one keyboard returned",
+		Items:  []model.CreditNoteItem{{ProductID: 1, Quantity: 1}},
+	}, admin)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidCreditNote)
+	creditNotes, _ := invoices.GetCreditNotes(7)
+	assert.Len(t, creditNotes, 1)
+}
+
+func Test_ShouldRejectCreditNote_WhenNotAdminOrNoInvoice(t *testing.T) {
+	service, _, _ := setupInvoiceService(paidOrder(7, domain.OrderStatusPaid))
+
+	_, err := service.IssueCreditNote(7, model.CreditNoteCreate{Reason: "refund"}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+
+	_, err = service.IssueCreditNote(7, model.CreditNoteCreate{Reason: "refund"}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvoiceNotFound)
+
+	_, err = service.IssueCreditNote(7, model.CreditNoteCreate{}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidCreditNote)
+}
@@ -1,54 +1,69 @@
 package service
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"product-app/services/order/internal/domain"
 	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+	"product-app/shared/kafka"
 
 	"github.com/stretchr/testify/assert"
 )
 
+var admin = model.Requester{UserId: 99, Admin: true}
+
 func setupOrderService() usecase.IOrderService {
 	initialOrders := []domain.Order{
 		{
 			Id:             1,
+			UserId:         1,
 			CustomerNumber: "CUST-001",
-			ProductID:      "PROD-1",
-			Quantity:       2,
+			Status:         domain.OrderStatusPending,
+			Items:          []domain.OrderItem{{Id: 1, ProductID: 1, Quantity: 2, UnitPrice: 10, LineTotal: 20}},
+			Subtotal:       20,
+			GrandTotal:     20,
 			OrderTime:      time.Now(),
 		},
 		{
 			Id:             2,
+			UserId:         2,
 			CustomerNumber: "CUST-002",
-			ProductID:      "PROD-2",
-			Quantity:       1,
+			Status:         domain.OrderStatusPaid,
+			Items:          []domain.OrderItem{{Id: 2, ProductID: 2, Quantity: 1, UnitPrice: 15, LineTotal: 15}},
+			Subtotal:       15,
+			GrandTotal:     15,
 			OrderTime:      time.Now(),
 		},
 		{
 			Id:             3,
+			UserId:         1,
 			CustomerNumber: "CUST-001",
-			ProductID:      "PROD-3",
-			Quantity:       5,
+			Status:         domain.OrderStatusPending,
+			Items:          []domain.OrderItem{{Id: 3, ProductID: 3, Quantity: 5, UnitPrice: 4, LineTotal: 20}},
+			Subtotal:       20,
+			GrandTotal:     20,
 			OrderTime:      time.Now(),
 		},
 	}
 
 	fakeRepo := NewFakeOrderRepository(initialOrders)
-	return usecase.NewOrderService(fakeRepo)
+	return usecase.NewOrderService(fakeRepo, nil, nil, nil)
 }
 
 func Test_ShouldGetAllOrders(t *testing.T) {
 	service := setupOrderService()
-	orders, err := service.GetAll()
+	page, err := service.GetAll(model.OrderSearch{}, admin)
 	assert.NoError(t, err)
-	assert.Len(t, orders, 3)
+	assert.Len(t, page.Orders, 3)
+	assert.Empty(t, page.NextCursor)
 }
 
 func Test_ShouldGetOrderById(t *testing.T) {
 	service := setupOrderService()
-	order, err := service.GetById(1)
+	order, err := service.GetById(1, admin)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), order.Id)
 	assert.Equal(t, "CUST-001", order.CustomerNumber)
@@ -56,54 +71,704 @@ func Test_ShouldGetOrderById(t *testing.T) {
 
 func Test_ShouldGetOrdersByCustomerNumber(t *testing.T) {
 	service := setupOrderService()
-	orders, err := service.GetByCustomerNumber("CUST-001")
+	page, err := service.GetByCustomerNumber("CUST-001", model.OrderSearch{}, admin)
 	assert.NoError(t, err)
-	assert.Len(t, orders, 2)
+	assert.Len(t, page.Orders, 2)
 }
 
 func Test_ShouldCreateOrder(t *testing.T) {
 	service := setupOrderService()
-	before, _ := service.GetAll()
-	assert.Len(t, before, 3)
+	before, _ := service.GetAll(model.OrderSearch{}, admin)
+	assert.Len(t, before.Orders, 3)
 
-	created, err := service.Create(domain.Order{
-		CustomerNumber: "CUST-003",
-		ProductID:      "PROD-9",
-		Quantity:       3,
-	})
+	created, err := service.Create(model.OrderCreate{
+		Items: []model.OrderItemCreate{
+			{ProductID: 9, Quantity: 3, UnitPrice: 19.99},
+			{ProductID: 4, Variant: "blue", Quantity: 2, UnitPrice: 50, Discount: 10},
+		},
+	}, 3)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(4), created.Id)
+	assert.Equal(t, int64(3), created.UserId)
 	assert.Equal(t, "CUST-003", created.CustomerNumber)
+	assert.Len(t, created.Items, 2)
+	assert.Equal(t, 59.97, created.Items[0].LineTotal)
+	assert.Equal(t, "blue", created.Items[1].Variant)
+	assert.Equal(t, 90.0, created.Items[1].LineTotal)
+	assert.Equal(t, 159.97, created.Subtotal)
+	assert.Equal(t, 10.0, created.DiscountTotal)
+	assert.Equal(t, 149.97, created.GrandTotal)
 
-	after, _ := service.GetAll()
-	assert.Len(t, after, 4)
+	after, _ := service.GetAll(model.OrderSearch{}, admin)
+	assert.Len(t, after.Orders, 4)
+}
+
+func Test_ShouldPublishOrderCreatedEvent(t *testing.T) {
+	repo := NewFakeOrderRepository(nil)
+	service := usecase.NewOrderService(repo, nil, nil, nil)
+
+	created, err := service.Create(model.OrderCreate{
+		Items: []model.OrderItemCreate{{ProductID: 9, Quantity: 3, UnitPrice: 5}},
+	}, 3)
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.outbox.events, 1)
+	event, ok := repo.outbox.events[0].(kafka.OrderCreated)
+	assert.True(t, ok)
+	assert.Equal(t, created.Id, event.ID)
+	assert.Len(t, event.Items, 1)
+	assert.Equal(t, int64(9), event.Items[0].ProductID)
+	assert.Equal(t, int32(3), event.Items[0].Quantity)
+	assert.Equal(t, 15.0, event.GrandTotal)
 }
 
 func Test_ShouldDeleteOrder(t *testing.T) {
 	service := setupOrderService()
-	err := service.Delete(2)
+	err := service.Delete(2, admin)
 	assert.NoError(t, err)
 
-	_, err = service.GetById(2)
+	_, err = service.GetById(2, admin)
 	assert.Error(t, err)
 }
 
-func Test_ShouldFailValidation_WhenCustomerNumberMissing(t *testing.T) {
-	service := setupOrderService()
-	_, err := service.Create(domain.Order{
-		CustomerNumber: "",
-		ProductID:      "PROD-1",
-		Quantity:       1,
+func Test_ShouldPublishCancellationEvents_WhenOrderDeleted(t *testing.T) {
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, CustomerNumber: "CUST-007", Status: domain.OrderStatusPending, Items: []domain.OrderItem{{ProductID: 3, Quantity: 2}}},
 	})
+	service := usecase.NewOrderService(repo, nil, nil, nil)
+
+	err := service.Delete(7, admin)
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.outbox.events, 2)
+	cancelled, ok := repo.outbox.events[0].(kafka.OrderCancelled)
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), cancelled.ID)
+	assert.Equal(t, "CUST-007", cancelled.CustomerNumber)
+	assert.Equal(t, int32(2), cancelled.Items[0].Quantity)
+	assert.False(t, cancelled.CancelledAt.IsZero())
+
+	statusChanged, ok := repo.outbox.events[1].(kafka.OrderStatusChanged)
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), statusChanged.ID)
+	assert.Equal(t, domain.OrderStatusPending, statusChanged.PreviousStatus)
+	assert.Equal(t, domain.OrderStatusCancelled, statusChanged.Status)
+}
+
+func Test_ShouldNotPublishCancellationEvents_WhenDeletingShippedOrCancelledOrder(t *testing.T) {
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, CustomerNumber: "CUST-007", Status: domain.OrderStatusShipped},
+		{Id: 8, CustomerNumber: "CUST-008", Status: domain.OrderStatusDelivered},
+		{Id: 9, CustomerNumber: "CUST-009", Status: domain.OrderStatusCancelled},
+	})
+	service := usecase.NewOrderService(repo, nil, nil, nil)
+
+	for _, id := range []int64{7, 8, 9} {
+		assert.NoError(t, service.Delete(id, admin))
+	}
+
+	assert.Empty(t, repo.outbox.events)
+}
+
+func Test_ShouldNotPublishEvents_WhenDeletingMissingOrder(t *testing.T) {
+	repo := NewFakeOrderRepository(nil)
+	service := usecase.NewOrderService(repo, nil, nil, nil)
+
+	err := service.Delete(99, admin)
+
+	assert.Error(t, err)
+	assert.Empty(t, repo.outbox.events)
+}
+
+func Test_ShouldRejectOrder_WhenUserMissing(t *testing.T) {
+	service := setupOrderService()
+	_, err := service.Create(model.OrderCreate{
+		Items: []model.OrderItemCreate{{ProductID: 1, Quantity: 1}},
+	}, 0)
 	assert.Error(t, err)
 }
 
 func Test_ShouldFailValidation_WhenQuantityInvalid(t *testing.T) {
 	service := setupOrderService()
-	_, err := service.Create(domain.Order{
-		CustomerNumber: "CUST-009",
-		ProductID:      "PROD-1",
-		Quantity:       0,
-	})
+	_, err := service.Create(model.OrderCreate{
+		Items: []model.OrderItemCreate{{ProductID: 1, Quantity: 0}},
+	}, 9)
 	assert.Error(t, err)
 }
+
+func Test_ShouldFailValidation_WhenNoItems(t *testing.T) {
+	service := setupOrderService()
+	_, err := service.Create(model.OrderCreate{}, 9)
+	assert.ErrorIs(t, err, domain.ErrInvalidOrder)
+	assert.EqualError(t, err, "invalid order: order must contain at least one item")
+}
+
+func Test_ShouldFailValidation_WhenDiscountExceedsLine(t *testing.T) {
+	service := setupOrderService()
+	_, err := service.Create(model.OrderCreate{
+		Items: []model.OrderItemCreate{
+			{ProductID: 1, Quantity: 1, UnitPrice: 10},
+			{ProductID: 2, Quantity: 2, UnitPrice: 10, Discount: 25},
+		},
+	}, 9)
+	assert.EqualError(t, err, "invalid order: item 2: discount cannot exceed the line amount")
+}
+
+func Test_ShouldTransitionOrderAndRecordHistory(t *testing.T) {
+	service := setupOrderService()
+
+	order, err := service.Transition(1, model.OrderTransition{Status: domain.OrderStatusPaid, Reason: "card captured"}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPaid, order.Status)
+
+	order, err = service.Transition(1, model.OrderTransition{Status: domain.OrderStatusShipped}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusShipped, order.Status)
+
+	history, err := service.GetStatusHistory(1, admin)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, domain.OrderStatusPending, history[0].FromStatus)
+	assert.Equal(t, domain.OrderStatusPaid, history[0].ToStatus)
+	assert.Equal(t, admin.UserId, history[0].ActorUserId)
+	assert.Equal(t, "card captured", history[0].Reason)
+	assert.Equal(t, domain.OrderStatusShipped, history[1].ToStatus)
+}
+
+func Test_ShouldForbidTransition_WhenRequesterIsNotAdmin(t *testing.T) {
+	service := setupOrderService()
+
+	_, err := service.Transition(1, model.OrderTransition{Status: domain.OrderStatusPaid}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+
+	order, _ := service.GetById(1, admin)
+	assert.Equal(t, domain.OrderStatusPending, order.Status)
+}
+
+func Test_ShouldApplyTransitionWithoutRequester(t *testing.T) {
+	service := setupOrderService()
+
+	order, err := service.ApplyTransition(1, model.OrderTransition{Status: domain.OrderStatusPaid, Reason: "payment captured"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPaid, order.Status)
+
+	history, err := service.GetStatusHistory(1, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), history[0].ActorUserId)
+}
+
+func Test_ShouldCancelThroughCancel_WhenTransitionedToCancelled(t *testing.T) {
+	gateway := &FakePaymentGateway{}
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPaid, GrandTotal: 20},
+	})
+	service := usecase.NewOrderService(repo, nil, gateway, capturedPayments(7, 20))
+
+	_, err := service.Transition(7, model.OrderTransition{Status: domain.OrderStatusCancelled, Reason: "fraud"}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+	assert.Empty(t, gateway.refunds)
+
+	cancelled, err := service.Transition(7, model.OrderTransition{Status: domain.OrderStatusCancelled, Reason: "fraud"}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusCancelled, cancelled.Status)
+	assert.Len(t, gateway.refunds, 1)
+
+	history, err := repo.GetStatusHistory(7)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CancelReasonOther, history[len(history)-1].ReasonCode)
+	assert.Equal(t, admin.UserId, history[len(history)-1].ActorUserId)
+}
+
+func Test_ShouldNotCancel_WhenApplyingTransition(t *testing.T) {
+	service := setupOrderService()
+
+	_, err := service.ApplyTransition(1, model.OrderTransition{Status: domain.OrderStatusCancelled}, 0)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	order, _ := service.GetById(1, admin)
+	assert.Equal(t, domain.OrderStatusPending, order.Status)
+}
+
+func Test_ShouldRejectIllegalTransition(t *testing.T) {
+	service := setupOrderService()
+
+	_, err := service.Transition(1, model.OrderTransition{Status: domain.OrderStatusDelivered}, admin)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	order, _ := service.GetById(1, admin)
+	assert.Equal(t, domain.OrderStatusPending, order.Status)
+}
+
+func Test_ShouldNotCancelShippedOrder(t *testing.T) {
+	service := setupOrderService()
+	_, err := service.Transition(2, model.OrderTransition{Status: domain.OrderStatusShipped}, admin)
+	assert.NoError(t, err)
+
+	_, err = service.Transition(2, model.OrderTransition{Status: domain.OrderStatusCancelled}, admin)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+}
+
+func Test_ShouldRejectUnknownStatus(t *testing.T) {
+	service := setupOrderService()
+	_, err := service.Transition(1, model.OrderTransition{Status: "lost"}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidOrderStatus)
+}
+
+func Test_ShouldReturnNotFound_WhenTransitioningMissingOrder(t *testing.T) {
+	service := setupOrderService()
+	_, err := service.Transition(99, model.OrderTransition{Status: domain.OrderStatusPaid}, admin)
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func Test_ShouldPublishCancellationEvents_WhenTransitionedToCancelled(t *testing.T) {
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, CustomerNumber: "CUST-007", Status: domain.OrderStatusPaid, Items: []domain.OrderItem{{ProductID: 3, Quantity: 2}}},
+	})
+	service := usecase.NewOrderService(repo, nil, nil, nil)
+
+	_, err := service.Transition(7, model.OrderTransition{Status: domain.OrderStatusCancelled, Reason: "customer request"}, admin)
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.outbox.events, 2)
+	_, ok := repo.outbox.events[0].(kafka.OrderCancelled)
+	assert.True(t, ok)
+	statusChanged, ok := repo.outbox.events[1].(kafka.OrderStatusChanged)
+	assert.True(t, ok)
+	assert.Equal(t, domain.OrderStatusPaid, statusChanged.PreviousStatus)
+	assert.Equal(t, domain.OrderStatusCancelled, statusChanged.Status)
+}
+
+func Test_ShouldOnlyPublishStatusChanged_WhenOrderPaid(t *testing.T) {
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, CustomerNumber: "CUST-007", Status: domain.OrderStatusPending},
+	})
+	service := usecase.NewOrderService(repo, nil, nil, nil)
+
+	_, err := service.Transition(7, model.OrderTransition{Status: domain.OrderStatusPaid}, admin)
+
+	assert.NoError(t, err)
+	assert.Len(t, repo.outbox.events, 1)
+	_, ok := repo.outbox.events[0].(kafka.OrderStatusChanged)
+	assert.True(t, ok)
+}
+
+func Test_ShouldPriceItemsFromCatalog(t *testing.T) {
+	catalog := NewFakeProductCatalog(
+		domain.CatalogProduct{Id: 1, Name: "AirFryer", Price: 1000, Discount: 10},
+		domain.CatalogProduct{Id: 2, Name: "Kettle", Price: 19.99},
+	)
+	service := usecase.NewOrderService(NewFakeOrderRepository(nil), catalog, nil, nil)
+
+	created, err := service.Create(model.OrderCreate{
+		Items: []model.OrderItemCreate{
+			{ProductID: 1, Quantity: 2, UnitPrice: 1, Discount: 1},
+			{ProductID: 2, Quantity: 3},
+		},
+	}, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1000.0, created.Items[0].UnitPrice)
+	assert.Equal(t, 200.0, created.Items[0].Discount)
+	assert.Equal(t, 1800.0, created.Items[0].LineTotal)
+	assert.Equal(t, 19.99, created.Items[1].UnitPrice)
+	assert.Equal(t, 59.97, created.Items[1].LineTotal)
+	assert.Equal(t, 2059.97, created.Subtotal)
+	assert.Equal(t, 1859.97, created.GrandTotal)
+}
+
+func Test_ShouldRejectOrder_WhenProductDoesNotExist(t *testing.T) {
+	catalog := NewFakeProductCatalog(domain.CatalogProduct{Id: 1, Price: 10})
+	service := usecase.NewOrderService(NewFakeOrderRepository(nil), catalog, nil, nil)
+
+	_, err := service.Create(model.OrderCreate{
+		Items: []model.OrderItemCreate{{ProductID: 1, Quantity: 1}, {ProductID: 42, Quantity: 1}},
+	}, 3)
+
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	assert.EqualError(t, err, "item 2: product not found with id 42")
+	page, _ := service.GetAll(model.OrderSearch{}, admin)
+	assert.Empty(t, page.Orders)
+}
+
+func Test_ShouldRejectOrder_WhenCatalogUnavailable(t *testing.T) {
+	catalog := NewFakeProductCatalog()
+	catalog.unavailable = true
+	service := usecase.NewOrderService(NewFakeOrderRepository(nil), catalog, nil, nil)
+
+	_, err := service.Create(model.OrderCreate{
+		Items: []model.OrderItemCreate{{ProductID: 1, Quantity: 1}},
+	}, 3)
+
+	assert.ErrorIs(t, err, domain.ErrCatalogUnavailable)
+}
+
+func Test_ShouldGetOwnOrder(t *testing.T) {
+	service := setupOrderService()
+	order, err := service.GetById(2, model.Requester{UserId: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), order.Id)
+}
+
+func Test_ShouldForbidReadingAnotherUsersOrder(t *testing.T) {
+	service := setupOrderService()
+
+	_, err := service.GetById(2, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+
+	_, err = service.GetStatusHistory(2, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+}
+
+func Test_ShouldOnlyLetAdminsListAllOrders(t *testing.T) {
+	service := setupOrderService()
+	_, err := service.GetAll(model.OrderSearch{}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+}
+
+func Test_ShouldOnlyLetUsersLookUpTheirOwnCustomerNumber(t *testing.T) {
+	service := setupOrderService()
+
+	page, err := service.GetByCustomerNumber("CUST-001", model.OrderSearch{}, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	assert.Len(t, page.Orders, 2)
+
+	_, err = service.GetByCustomerNumber("CUST-002", model.OrderSearch{}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+}
+
+func Test_ShouldGetOrdersOfUser(t *testing.T) {
+	service := setupOrderService()
+
+	page, err := service.GetByUserId(1, model.OrderSearch{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Orders, 2)
+
+	page, err = service.GetByUserId(5, model.OrderSearch{})
+	assert.NoError(t, err)
+	assert.NotNil(t, page.Orders)
+	assert.Empty(t, page.Orders)
+}
+
+func setupOrderSearchService() usecase.IOrderService {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return usecase.NewOrderService(NewFakeOrderRepository([]domain.Order{
+		{Id: 1, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPending, GrandTotal: 30, OrderTime: day,
+			Items: []domain.OrderItem{{ProductID: 1, Quantity: 1}}},
+		{Id: 2, UserId: 2, CustomerNumber: "CUST-002", Status: domain.OrderStatusPaid, GrandTotal: 10, OrderTime: day.AddDate(0, 0, 1),
+			Items: []domain.OrderItem{{ProductID: 2, Quantity: 1}}},
+		{Id: 3, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPaid, GrandTotal: 50, OrderTime: day.AddDate(0, 0, 2),
+			Items: []domain.OrderItem{{ProductID: 1, Quantity: 2}, {ProductID: 3, Quantity: 1}}},
+		{Id: 4, UserId: 2, CustomerNumber: "CUST-002", Status: domain.OrderStatusShipped, GrandTotal: 10, OrderTime: day.AddDate(0, 0, 3),
+			Items: []domain.OrderItem{{ProductID: 3, Quantity: 1}}},
+	}), nil, nil, nil)
+}
+
+func orderIds(orders []domain.Order) []int64 {
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.Id)
+	}
+	return ids
+}
+
+func Test_ShouldListNewestOrdersFirstByDefault(t *testing.T) {
+	service := setupOrderSearchService()
+	page, err := service.GetAll(model.OrderSearch{}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 3, 2, 1}, orderIds(page.Orders))
+}
+
+func Test_ShouldFilterOrders(t *testing.T) {
+	service := setupOrderSearchService()
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	page, err := service.GetAll(model.OrderSearch{Status: domain.OrderStatusPaid}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, orderIds(page.Orders))
+
+	page, err = service.GetAll(model.OrderSearch{ProductId: 1}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 1}, orderIds(page.Orders))
+
+	page, err = service.GetAll(model.OrderSearch{CustomerNumber: "CUST-002"}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 2}, orderIds(page.Orders))
+
+	page, err = service.GetAll(model.OrderSearch{From: day.AddDate(0, 0, 1), To: day.AddDate(0, 0, 3)}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, orderIds(page.Orders))
+}
+
+func Test_ShouldPageThroughOrdersByTotal(t *testing.T) {
+	service := setupOrderSearchService()
+
+	page, err := service.GetAll(model.OrderSearch{Sort: "grand_total", Limit: 2}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 4}, orderIds(page.Orders))
+	assert.NotEmpty(t, page.NextCursor)
+
+	page, err = service.GetAll(model.OrderSearch{Sort: "grand_total", Limit: 2, Cursor: page.NextCursor}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, orderIds(page.Orders))
+	assert.Empty(t, page.NextCursor)
+}
+
+func Test_ShouldPageThroughOwnOrders(t *testing.T) {
+	service := setupOrderSearchService()
+
+	page, err := service.GetByUserId(1, model.OrderSearch{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3}, orderIds(page.Orders))
+
+	page, err = service.GetByUserId(1, model.OrderSearch{Limit: 1, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, orderIds(page.Orders))
+	assert.Empty(t, page.NextCursor)
+}
+
+func Test_ShouldRejectInvalidOrderSearch(t *testing.T) {
+	service := setupOrderSearchService()
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	invalid := []model.OrderSearch{
+		{Sort: "customer_number"},
+		{Status: "lost"},
+		{Limit: usecase.MaxOrderPageSize + 1},
+		{From: day, To: day},
+		{Cursor: "not-a-cursor"},
+	}
+	for _, search := range invalid {
+		_, err := service.GetAll(search, admin)
+		assert.ErrorIs(t, err, domain.ErrInvalidOrderQuery, "%+v", search)
+	}
+
+	page, err := service.GetAll(model.OrderSearch{Sort: "grand_total", Limit: 1}, admin)
+	assert.NoError(t, err)
+	_, err = service.GetAll(model.OrderSearch{Sort: "-grand_total", Cursor: page.NextCursor}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidOrderQuery)
+}
+
+func Test_ShouldForbidDelete_WhenNotAdmin(t *testing.T) {
+	service := setupOrderService()
+	err := service.Delete(1, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+
+	_, err = service.GetById(1, admin)
+	assert.NoError(t, err)
+}
+
+func Test_ShouldCancelPendingOrderWithoutRefund(t *testing.T) {
+	gateway := &FakePaymentGateway{}
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPending, GrandTotal: 20, Items: []domain.OrderItem{{ProductID: 3, Quantity: 2}}},
+	})
+	service := usecase.NewOrderService(repo, nil, gateway, nil)
+
+	cancelled, err := service.Cancel(7, model.OrderCancellation{ReasonCode: domain.CancelReasonCustomerRequest, Note: "changed my mind"}, model.Requester{UserId: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusCancelled, cancelled.Status)
+	assert.Empty(t, gateway.refunds)
+	assert.Len(t, repo.outbox.events, 2)
+	event, ok := repo.outbox.events[0].(kafka.OrderCancelled)
+	assert.True(t, ok)
+	assert.Equal(t, domain.CancelReasonCustomerRequest, event.ReasonCode)
+	assert.Equal(t, int32(2), event.Items[0].Quantity)
+
+	history, err := repo.GetStatusHistory(7)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CancelReasonCustomerRequest, history[len(history)-1].ReasonCode)
+	assert.Equal(t, "changed my mind", history[len(history)-1].Reason)
+}
+
+func Test_ShouldRefundInFull_WhenPaidOrderCancelled(t *testing.T) {
+	gateway := &FakePaymentGateway{}
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPaid, GrandTotal: 20, RefundedTotal: 5},
+	})
+	service := usecase.NewOrderService(repo, nil, gateway, capturedPayments(7, 20))
+
+	cancelled, err := service.Cancel(7, model.OrderCancellation{ReasonCode: domain.CancelReasonOutOfStock}, admin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, cancelled.RefundedTotal)
+	assert.Len(t, gateway.refunds, 1)
+	assert.Equal(t, 15.0, gateway.refunds[0].Amount)
+	assert.Equal(t, "pi_7", gateway.refunds[0].ProviderPaymentId)
+
+	refunds, err := repo.GetRefunds(7)
+	assert.NoError(t, err)
+	assert.Len(t, refunds, 1)
+	assert.Equal(t, "re_1", refunds[0].ProviderReference)
+	assert.Equal(t, admin.UserId, refunds[0].ActorUserId)
+
+	assert.Len(t, repo.outbox.events, 3)
+	refunded, ok := repo.outbox.events[2].(kafka.OrderRefunded)
+	assert.True(t, ok)
+	assert.Equal(t, 15.0, refunded.Amount)
+	assert.Equal(t, 20.0, refunded.RefundedTotal)
+}
+
+func Test_ShouldKeepOrderCancelled_WhenRefundDeclined(t *testing.T) {
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPaid, GrandTotal: 20},
+	})
+	service := usecase.NewOrderService(repo, nil, &FakePaymentGateway{decline: true}, capturedPayments(7, 20))
+
+	_, err := service.Cancel(7, model.OrderCancellation{ReasonCode: domain.CancelReasonOther}, admin)
+	assert.ErrorIs(t, err, domain.ErrRefundFailed)
+
+	order, err := repo.GetById(7)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusCancelled, order.Status)
+	assert.Zero(t, order.RefundedTotal)
+}
+
+func Test_ShouldRejectCancellation_WhenReasonUnknownOrOrderShipped(t *testing.T) {
+	service := usecase.NewOrderService(NewFakeOrderRepository([]domain.Order{
+		{Id: 7, UserId: 1, Status: domain.OrderStatusShipped},
+	}), nil, nil, nil)
+
+	_, err := service.Cancel(7, model.OrderCancellation{ReasonCode: "bored"}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrInvalidCancelReason)
+
+	_, err = service.Cancel(7, model.OrderCancellation{ReasonCode: domain.CancelReasonCustomerRequest}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	_, err = service.Cancel(7, model.OrderCancellation{ReasonCode: domain.CancelReasonCustomerRequest}, model.Requester{UserId: 2})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+}
+
+func Test_ShouldRefundPartiallyAndThenTheRest(t *testing.T) {
+	service := usecase.NewOrderService(NewFakeOrderRepository([]domain.Order{
+		{Id: 7, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusDelivered, GrandTotal: 20},
+	}), nil, nil, nil)
+
+	refund, err := service.Refund(7, model.RefundCreate{Amount: 7.5, Reason: "damaged box"}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, 7.5, refund.Amount)
+	assert.Empty(t, refund.ProviderReference)
+
+	_, err = service.Refund(7, model.RefundCreate{Amount: 13}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidRefund)
+
+	refund, err = service.Refund(7, model.RefundCreate{}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, 12.5, refund.Amount)
+
+	_, err = service.Refund(7, model.RefundCreate{}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidRefund)
+
+	refunds, err := service.GetRefunds(7, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	assert.Len(t, refunds, 2)
+}
+
+func Test_ShouldRejectRefund_WhenNotAdminOrNotPaid(t *testing.T) {
+	service := setupOrderService()
+
+	_, err := service.Refund(2, model.RefundCreate{Amount: 5}, model.Requester{UserId: 2})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+
+	_, err = service.Refund(1, model.RefundCreate{Amount: 5}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidRefund)
+
+	_, err = service.Refund(2, model.RefundCreate{Amount: -5}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidRefund)
+}
+
+func Test_ShouldRefundCancelledOrder_OnlyIfItWasPaid(t *testing.T) {
+	service := setupOrderService()
+
+	_, err := service.Transition(1, model.OrderTransition{Status: domain.OrderStatusCancelled}, admin)
+	assert.NoError(t, err)
+	_, err = service.Refund(1, model.RefundCreate{}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidRefund)
+
+	_, err = service.Transition(3, model.OrderTransition{Status: domain.OrderStatusPaid}, admin)
+	assert.NoError(t, err)
+	_, err = service.Transition(3, model.OrderTransition{Status: domain.OrderStatusCancelled}, admin)
+	assert.NoError(t, err)
+
+	refunds, err := service.GetRefunds(3, admin)
+	assert.NoError(t, err)
+	assert.Len(t, refunds, 1)
+	assert.Equal(t, 20.0, refunds[0].Amount)
+}
+
+func Test_ShouldReleaseReservedRefund_WhenGatewayDeclines(t *testing.T) {
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPaid, GrandTotal: 20},
+	})
+	gateway := &FakePaymentGateway{decline: true}
+	service := usecase.NewOrderService(repo, nil, gateway, capturedPayments(7, 20))
+
+	_, err := service.Refund(7, model.RefundCreate{Amount: 5}, admin)
+	assert.ErrorIs(t, err, domain.ErrRefundFailed)
+
+	order, err := repo.GetById(7)
+	assert.NoError(t, err)
+	assert.Zero(t, order.RefundedTotal)
+	refunds, err := repo.GetRefunds(7)
+	assert.NoError(t, err)
+	assert.Empty(t, refunds)
+	assert.Empty(t, repo.outbox.events)
+
+	gateway.decline = false
+	refund, err := service.Refund(7, model.RefundCreate{}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, refund.Amount)
+	assert.Equal(t, domain.RefundStatusCompleted, refund.Status)
+}
+
+func Test_ShouldReserveRefundAmount_WhileGatewayRefunds(t *testing.T) {
+	repo := NewFakeOrderRepository([]domain.Order{
+		{Id: 7, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPaid, GrandTotal: 20},
+	})
+	gateway := &FakePaymentGateway{}
+	service := usecase.NewOrderService(repo, nil, gateway, capturedPayments(7, 20))
+
+	var concurrentErr error
+	gateway.onRefund = func() {
+		gateway.onRefund = nil
+		_, concurrentErr = service.Refund(7, model.RefundCreate{Amount: 20}, admin)
+	}
+	refund, err := service.Refund(7, model.RefundCreate{Amount: 20}, admin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "re_1", refund.ProviderReference)
+	assert.ErrorIs(t, concurrentErr, domain.ErrInvalidRefund)
+	assert.Len(t, gateway.refunds, 1)
+	order, err := repo.GetById(7)
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, order.RefundedTotal)
+}
+
+func Test_ShouldOnlyRecordRefund_WhenOrderWasNotPaidThroughGateway(t *testing.T) {
+	gateway := &FakePaymentGateway{}
+	payments := NewFakePaymentRepository([]domain.Payment{
+		{Id: 1, OrderId: 7, Provider: "fake", ProviderPaymentId: "pi_7", Amount: 20, Status: domain.PaymentStatusFailed},
+	})
+	service := usecase.NewOrderService(NewFakeOrderRepository([]domain.Order{
+		{Id: 7, UserId: 1, CustomerNumber: "CUST-001", Status: domain.OrderStatusPaid, GrandTotal: 20},
+	}), nil, gateway, payments)
+
+	refund, err := service.Refund(7, model.RefundCreate{}, admin)
+
+	assert.NoError(t, err)
+	assert.Empty(t, refund.ProviderReference)
+	assert.Empty(t, gateway.refunds)
+}
+
+// capturedPayments holds a payment captured through FakePaymentGateway for
+// the order.
+func capturedPayments(orderId int64, amount float64) *FakePaymentRepository {
+	return NewFakePaymentRepository([]domain.Payment{{
+		Id:                1,
+		OrderId:           orderId,
+		Provider:          "fake",
+		ProviderPaymentId: fmt.Sprintf("pi_%d", orderId),
+		Amount:            amount,
+		Status:            domain.PaymentStatusCaptured,
+	}})
+}
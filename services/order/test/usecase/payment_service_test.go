@@ -0,0 +1,218 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/ports"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type paymentFixture struct {
+	orders       ports.OrderRepository
+	payments     *FakePaymentRepository
+	gateway      *FakePaymentGateway
+	orderService usecase.IOrderService
+	service      usecase.IPaymentService
+}
+
+func setupPaymentService(orders ...domain.Order) paymentFixture {
+	repo := NewFakeOrderRepository(orders)
+	payments := NewFakePaymentRepository(nil)
+	gateway := &FakePaymentGateway{}
+	orderService := usecase.NewOrderService(repo, nil, gateway, payments)
+	return paymentFixture{
+		orders:       repo,
+		payments:     payments,
+		gateway:      gateway,
+		orderService: orderService,
+		service:      usecase.NewPaymentService(orderService, payments, gateway),
+	}
+}
+
+func signedHeader() http.Header {
+	header := http.Header{}
+	header.Set(fakeSignatureHeader, "valid")
+	return header
+}
+
+func webhookPayload(t *testing.T, event domain.PaymentEvent) []byte {
+	payload, err := json.Marshal(event)
+	assert.NoError(t, err)
+	return payload
+}
+
+func Test_ShouldStartPayment_ForPendingOrderOfOwner(t *testing.T) {
+	fixture := setupPaymentService(domain.Order{Id: 7, UserId: 1, Status: domain.OrderStatusPending, GrandTotal: 20})
+
+	payment, err := fixture.service.StartPayment(7, model.PaymentStart{PaymentMethod: "pm_card_visa"}, model.Requester{UserId: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusPending, payment.Status)
+	assert.Equal(t, "pi_1", payment.ProviderPaymentId)
+	assert.Equal(t, "pi_1_secret", payment.ClientSecret)
+	assert.Equal(t, 20.0, payment.Amount)
+	assert.Equal(t, "pm_card_visa", fixture.gateway.intents[0].PaymentMethod)
+
+	_, err = fixture.service.StartPayment(7, model.PaymentStart{}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrPaymentAlreadyInProgress)
+	assert.Len(t, fixture.gateway.intents, 1)
+}
+
+func Test_ShouldRejectPayment_WhenNotOwnerOrOrderNotPending(t *testing.T) {
+	fixture := setupPaymentService(
+		domain.Order{Id: 7, UserId: 1, Status: domain.OrderStatusPending, GrandTotal: 20},
+		domain.Order{Id: 8, UserId: 1, Status: domain.OrderStatusPaid, GrandTotal: 20},
+	)
+
+	_, err := fixture.service.StartPayment(7, model.PaymentStart{}, model.Requester{UserId: 2})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+
+	_, err = fixture.service.StartPayment(8, model.PaymentStart{}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+	assert.Empty(t, fixture.gateway.intents)
+}
+
+func Test_ShouldCaptureAndMarkOrderPaid_FromWebhooks(t *testing.T) {
+	fixture := setupPaymentService(domain.Order{Id: 7, UserId: 1, Status: domain.OrderStatusPending, GrandTotal: 20})
+	payment, err := fixture.service.StartPayment(7, model.PaymentStart{}, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+
+	err = fixture.service.HandleWebhook(webhookPayload(t, domain.PaymentEvent{
+		Id: "evt_1", Type: domain.PaymentEventAuthorized, ProviderPaymentId: payment.ProviderPaymentId,
+	}), signedHeader())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pi_1"}, fixture.gateway.captures)
+
+	err = fixture.service.HandleWebhook(webhookPayload(t, domain.PaymentEvent{
+		Id: "evt_2", Type: domain.PaymentEventCaptured, ProviderPaymentId: payment.ProviderPaymentId,
+	}), signedHeader())
+	assert.NoError(t, err)
+
+	order, err := fixture.orders.GetById(7)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPaid, order.Status)
+	payments, err := fixture.service.GetPayments(7, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusCaptured, payments[0].Status)
+}
+
+func Test_ShouldFailAuthorizedPayment_WhenOrderNoLongerPending(t *testing.T) {
+	fixture := setupPaymentService(domain.Order{Id: 7, UserId: 1, Status: domain.OrderStatusPending, GrandTotal: 20})
+	_, err := fixture.service.StartPayment(7, model.PaymentStart{}, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	_, err = fixture.orders.UpdateStatus(7, domain.OrderStatusChange{
+		OrderId:    7,
+		FromStatus: domain.OrderStatusPending,
+		ToStatus:   domain.OrderStatusCancelled,
+	}, nil)
+	assert.NoError(t, err)
+
+	err = fixture.service.HandleWebhook(webhookPayload(t, domain.PaymentEvent{
+		Id: "evt_1", Type: domain.PaymentEventAuthorized, ProviderPaymentId: "pi_1",
+	}), signedHeader())
+
+	assert.NoError(t, err)
+	assert.Empty(t, fixture.gateway.captures)
+	payments, err := fixture.service.GetPayments(7, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusFailed, payments[0].Status)
+	assert.Equal(t, "order 7 is cancelled", payments[0].FailureReason)
+}
+
+func Test_ShouldFailOpenPayments_WhenOrderCancelled(t *testing.T) {
+	fixture := setupPaymentService(domain.Order{Id: 7, UserId: 1, Status: domain.OrderStatusPending, GrandTotal: 20})
+	_, err := fixture.service.StartPayment(7, model.PaymentStart{}, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+
+	_, err = fixture.orderService.Cancel(7, model.OrderCancellation{ReasonCode: domain.CancelReasonCustomerRequest}, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+
+	payments, err := fixture.service.GetPayments(7, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusFailed, payments[0].Status)
+	assert.Equal(t, "order cancelled: "+domain.CancelReasonCustomerRequest, payments[0].FailureReason)
+
+	err = fixture.service.HandleWebhook(webhookPayload(t, domain.PaymentEvent{
+		Id: "evt_1", Type: domain.PaymentEventAuthorized, ProviderPaymentId: "pi_1",
+	}), signedHeader())
+	assert.NoError(t, err)
+	assert.Empty(t, fixture.gateway.captures)
+}
+
+func Test_ShouldIgnoreDuplicateWebhookEvents(t *testing.T) {
+	fixture := setupPaymentService(domain.Order{Id: 7, UserId: 1, Status: domain.OrderStatusPending, GrandTotal: 20})
+	_, err := fixture.service.StartPayment(7, model.PaymentStart{}, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	payload := webhookPayload(t, domain.PaymentEvent{Id: "evt_1", Type: domain.PaymentEventAuthorized, ProviderPaymentId: "pi_1"})
+
+	assert.NoError(t, fixture.service.HandleWebhook(payload, signedHeader()))
+	err = fixture.service.HandleWebhook(payload, signedHeader())
+
+	assert.ErrorIs(t, err, domain.ErrDuplicateWebhookEvent)
+	assert.Len(t, fixture.gateway.captures, 1)
+}
+
+func Test_ShouldRejectUnsignedWebhook(t *testing.T) {
+	fixture := setupPaymentService()
+
+	err := fixture.service.HandleWebhook(webhookPayload(t, domain.PaymentEvent{Id: "evt_1"}), http.Header{})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhookSignature)
+}
+
+func Test_ShouldReleaseWebhookEvent_WhenApplyingFails(t *testing.T) {
+	fixture := setupPaymentService()
+	payload := webhookPayload(t, domain.PaymentEvent{Id: "evt_1", Type: domain.PaymentEventCaptured, ProviderPaymentId: "pi_9"})
+
+	err := fixture.service.HandleWebhook(payload, signedHeader())
+	assert.ErrorIs(t, err, domain.ErrPaymentNotFound)
+
+	err = fixture.service.HandleWebhook(payload, signedHeader())
+	assert.ErrorIs(t, err, domain.ErrPaymentNotFound)
+}
+
+func Test_ShouldFailPaymentAndKeepOrderPending_WhenDeclined(t *testing.T) {
+	fixture := setupPaymentService(domain.Order{Id: 7, UserId: 1, Status: domain.OrderStatusPending, GrandTotal: 20})
+	_, err := fixture.service.StartPayment(7, model.PaymentStart{}, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+
+	err = fixture.service.HandleWebhook(webhookPayload(t, domain.PaymentEvent{
+		Id: "evt_1", Type: domain.PaymentEventFailed, ProviderPaymentId: "pi_1", FailureReason: "card declined",
+	}), signedHeader())
+	assert.NoError(t, err)
+
+	order, err := fixture.orders.GetById(7)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPending, order.Status)
+	payments, err := fixture.service.GetPayments(7, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusFailed, payments[0].Status)
+	assert.Equal(t, "card declined", payments[0].FailureReason)
+
+	retry, err := fixture.service.StartPayment(7, model.PaymentStart{}, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "pi_2", retry.ProviderPaymentId)
+}
+
+func Test_ShouldRefundThroughCapturedPayment(t *testing.T) {
+	fixture := setupPaymentService(domain.Order{Id: 7, UserId: 1, Status: domain.OrderStatusPending, GrandTotal: 20})
+	_, err := fixture.service.StartPayment(7, model.PaymentStart{}, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	err = fixture.service.HandleWebhook(webhookPayload(t, domain.PaymentEvent{
+		Id: "evt_1", Type: domain.PaymentEventCaptured, ProviderPaymentId: "pi_1",
+	}), signedHeader())
+	assert.NoError(t, err)
+
+	orderService := usecase.NewOrderService(fixture.orders, nil, fixture.gateway, fixture.payments)
+	refund, err := orderService.Refund(7, model.RefundCreate{Amount: 5}, admin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "re_1", refund.ProviderReference)
+	assert.Equal(t, "pi_1", fixture.gateway.refunds[0].ProviderPaymentId)
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"product-app/services/order/internal/domain"
+	"product-app/services/order/internal/usecase"
+	"product-app/services/order/internal/usecase/model"
+	"product-app/shared/kafka"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupShipmentService(orders ...domain.Order) (usecase.IShipmentService, usecase.IOrderService, *FakeShipmentRepository, *FakeOutbox) {
+	orderRepository := NewFakeOrderRepository(orders)
+	shipments := NewFakeShipmentRepository(orderRepository)
+	orderService := usecase.NewOrderService(orderRepository, nil, nil, nil)
+	return usecase.NewShipmentService(orderService, shipments), orderService, shipments, orderRepository.outbox
+}
+
+func eventTypes(events []kafka.Event) []string {
+	types := []string{}
+	for _, event := range events {
+		types = append(types, event.EventType())
+	}
+	return types
+}
+
+func Test_ShouldCreatePartialShipments_AndMarkOrderShippedWhenAllItemsShip(t *testing.T) {
+	service, orderService, _, outbox := setupShipmentService(paidOrder(7, domain.OrderStatusPaid))
+
+	first, err := service.CreateShipment(7, model.ShipmentCreate{
+		Carrier:        " DHL ",
+		TrackingNumber: "JD0001",
+		Items: []model.ShipmentItemCreate{
+			{ProductID: 1, Quantity: 2},
+			{ProductID: 2, Variant: "black", Quantity: 1},
+		},
+	}, admin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "DHL", first.Carrier)
+	assert.Equal(t, domain.ShipmentStatusShipped, first.Status)
+	assert.Len(t, first.Events, 1)
+	order, _ := orderService.GetById(7, admin)
+	assert.Equal(t, domain.OrderStatusPaid, order.Status, "one unit of product 1 and product 9 are left")
+
+	second, err := service.CreateShipment(7, model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0002"}, admin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ShipmentItem{{ProductID: 1, Quantity: 1}, {ProductID: 9, Quantity: 1}}, second.Items,
+		"without items the shipment carries everything left")
+	order, _ = orderService.GetById(7, admin)
+	assert.Equal(t, domain.OrderStatusShipped, order.Status)
+	assert.Equal(t, []string{
+		kafka.EventTypeShipmentCreated,
+		kafka.EventTypeShipmentCreated,
+		kafka.EventTypeOrderStatusChanged,
+	}, eventTypes(outbox.events))
+	created := outbox.events[0].(kafka.ShipmentCreated)
+	assert.Equal(t, "CUST-001", created.CustomerNumber)
+	assert.Equal(t, "JD0001", created.TrackingNumber)
+	assert.Len(t, created.Items, 2)
+}
+
+func Test_ShouldRejectShipment_WhenItemsExceedWhatIsLeft(t *testing.T) {
+	service, _, _, _ := setupShipmentService(paidOrder(7, domain.OrderStatusPaid))
+	_, err := service.CreateShipment(7, model.ShipmentCreate{
+		Carrier: "DHL", TrackingNumber: "JD0001",
+		Items: []model.ShipmentItemCreate{{ProductID: 1, Quantity: 2}},
+	}, admin)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		create model.ShipmentCreate
+	}{
+		{"more than left", model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0002",
+			Items: []model.ShipmentItemCreate{{ProductID: 1, Quantity: 2}}}},
+		{"repeated product", model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0002",
+			Items: []model.ShipmentItemCreate{{ProductID: 1, Quantity: 1}, {ProductID: 1, Quantity: 1}}}},
+		{"not on order", model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0002",
+			Items: []model.ShipmentItemCreate{{ProductID: 2, Quantity: 1}}}},
+		{"zero quantity", model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0002",
+			Items: []model.ShipmentItemCreate{{ProductID: 9}}}},
+		{"no tracking number", model.ShipmentCreate{Carrier: "DHL"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.CreateShipment(7, test.create, admin)
+			assert.ErrorIs(t, err, domain.ErrInvalidShipment)
+		})
+	}
+
+	_, err = service.CreateShipment(7, model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0001"}, admin)
+	assert.ErrorIs(t, err, domain.ErrDuplicateTrackingNumber)
+}
+
+func Test_ShouldRejectShipment_WhenNotAdminOrOrderNotPaid(t *testing.T) {
+	service, _, _, _ := setupShipmentService(paidOrder(7, domain.OrderStatusPaid), paidOrder(8, domain.OrderStatusPending))
+	create := model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0001"}
+
+	_, err := service.CreateShipment(7, create, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+
+	_, err = service.CreateShipment(8, create, admin)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition)
+
+	_, err = service.CreateShipment(404, create, admin)
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func Test_ShouldTrackShipments_AndMarkOrderDeliveredWhenAllAreDelivered(t *testing.T) {
+	service, orderService, _, outbox := setupShipmentService(paidOrder(7, domain.OrderStatusPaid))
+	first, _ := service.CreateShipment(7, model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0001",
+		Items: []model.ShipmentItemCreate{{ProductID: 1, Quantity: 3}}}, admin)
+	second, _ := service.CreateShipment(7, model.ShipmentCreate{Carrier: "UPS", TrackingNumber: "1Z0001"}, admin)
+	outbox.events = nil
+
+	occurredAt := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	updated, err := service.AddTrackingEvent(7, first.Id, model.TrackingEventCreate{
+		Status: domain.ShipmentStatusInTransit, Location: "Leipzig hub", OccurredAt: occurredAt,
+	}, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ShipmentStatusInTransit, updated.Status)
+	assert.Len(t, updated.Events, 2)
+	assert.Equal(t, "Leipzig hub", updated.Events[1].Location)
+
+	_, err = service.AddTrackingEvent(7, first.Id, model.TrackingEventCreate{Status: domain.ShipmentStatusDelivered}, admin)
+	assert.NoError(t, err)
+	order, _ := orderService.GetById(7, admin)
+	assert.Equal(t, domain.OrderStatusShipped, order.Status, "the second shipment is still on its way")
+
+	_, err = service.AddTrackingEvent(7, second.Id, model.TrackingEventCreate{Status: domain.ShipmentStatusDelivered}, admin)
+	assert.NoError(t, err)
+	order, _ = orderService.GetById(7, admin)
+	assert.Equal(t, domain.OrderStatusDelivered, order.Status)
+
+	assert.Equal(t, []string{
+		kafka.EventTypeShipmentUpdated,
+		kafka.EventTypeShipmentUpdated,
+		kafka.EventTypeShipmentDelivered,
+		kafka.EventTypeShipmentUpdated,
+		kafka.EventTypeShipmentDelivered,
+		kafka.EventTypeOrderStatusChanged,
+	}, eventTypes(outbox.events))
+	inTransit := outbox.events[0].(kafka.ShipmentUpdated)
+	assert.Equal(t, domain.ShipmentStatusShipped, inTransit.PreviousStatus)
+	assert.Equal(t, domain.ShipmentStatusInTransit, inTransit.Status)
+	assert.Equal(t, occurredAt, inTransit.OccurredAt)
+}
+
+func Test_ShouldRejectTrackingEvent_WhenInvalid(t *testing.T) {
+	service, _, _, _ := setupShipmentService(paidOrder(7, domain.OrderStatusPaid), paidOrder(8, domain.OrderStatusPaid))
+	shipment, _ := service.CreateShipment(7, model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0001"}, admin)
+
+	_, err := service.AddTrackingEvent(7, shipment.Id, model.TrackingEventCreate{Status: "lost_in_space"}, admin)
+	assert.ErrorIs(t, err, domain.ErrInvalidTrackingEvent)
+
+	_, err = service.AddTrackingEvent(8, shipment.Id, model.TrackingEventCreate{Status: domain.ShipmentStatusInTransit}, admin)
+	assert.ErrorIs(t, err, domain.ErrShipmentNotFound, "shipments of other orders are not found")
+
+	_, err = service.AddTrackingEvent(7, shipment.Id, model.TrackingEventCreate{Status: domain.ShipmentStatusInTransit}, model.Requester{UserId: 1})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+
+	_, err = service.AddTrackingEvent(7, shipment.Id, model.TrackingEventCreate{Status: domain.ShipmentStatusDelivered}, admin)
+	assert.NoError(t, err)
+	_, err = service.AddTrackingEvent(7, shipment.Id, model.TrackingEventCreate{Status: domain.ShipmentStatusException}, admin)
+	assert.ErrorIs(t, err, domain.ErrIllegalTransition, "a delivered shipment takes no more events")
+}
+
+func Test_ShouldListShipments_OnlyToOwnerOrAdmin(t *testing.T) {
+	service, _, _, _ := setupShipmentService(paidOrder(7, domain.OrderStatusPaid))
+	shipment, _ := service.CreateShipment(7, model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0001"}, admin)
+
+	shipments, err := service.GetShipments(7, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	assert.Len(t, shipments, 1)
+
+	found, err := service.GetShipment(7, shipment.Id, model.Requester{UserId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "JD0001", found.TrackingNumber)
+
+	_, err = service.GetShipments(7, model.Requester{UserId: 2})
+	assert.ErrorIs(t, err, domain.ErrOrderForbidden)
+}
+
+// staleShipments hides the shipments already recorded, as when another
+// shipment is created between the service's check and its own.
+type staleShipments struct {
+	*FakeShipmentRepository
+}
+
+func (staleShipments) GetByOrderId(orderId int64) ([]domain.Shipment, error) {
+	return []domain.Shipment{}, nil
+}
+
+func Test_ShouldRejectShipment_WhenConcurrentShipmentTookTheItems(t *testing.T) {
+	orders := NewFakeOrderRepository([]domain.Order{paidOrder(7, domain.OrderStatusPaid)})
+	shipments := NewFakeShipmentRepository(orders)
+	orderService := usecase.NewOrderService(orders, nil, nil, nil)
+	service := usecase.NewShipmentService(orderService, shipments)
+	_, err := service.CreateShipment(7, model.ShipmentCreate{Carrier: "DHL", TrackingNumber: "JD0001",
+		Items: []model.ShipmentItemCreate{{ProductID: 1, Quantity: 2}}}, admin)
+	assert.NoError(t, err)
+
+	racing := usecase.NewShipmentService(orderService, staleShipments{shipments})
+	_, err = racing.CreateShipment(7, model.ShipmentCreate{Carrier: "UPS", TrackingNumber: "1Z0001",
+		Items: []model.ShipmentItemCreate{{ProductID: 1, Quantity: 2}}}, admin)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidShipment)
+	stored, _ := shipments.GetByOrderId(7)
+	assert.Len(t, stored, 1)
+}
@@ -2,41 +2,130 @@ package main
 
 import (
 	"context"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"product-app/services/product/internal/adapters/categoryclient"
+	"product-app/services/product/internal/adapters/graphql"
+	productgrpc "product-app/services/product/internal/adapters/grpc"
 	"product-app/services/product/internal/adapters/http/controller"
 	"product-app/services/product/internal/adapters/kafka"
 	"product-app/services/product/internal/adapters/postgresql"
 	pgcommon "product-app/services/product/internal/adapters/postgresql/common"
 	"product-app/services/product/internal/config"
 	"product-app/services/product/internal/usecase"
+	"product-app/shared/auth"
+	"product-app/shared/grpcx"
+	"product-app/shared/idempotency"
+	sharedkafka "product-app/shared/kafka"
+	"product-app/shared/outbox"
+	productv1 "product-app/shared/proto/product/v1"
 
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
 	ctx := context.Background()
-	e := buildServer(ctx)
-	e.Start(":8081")
+
+	configurationManager, err := config.NewConfigurationManager()
+	if err != nil {
+		log.Fatalf("product-service not started: %v", err)
+	}
+	log.Printf("effective configuration:\n%s", configurationManager)
+	auth.SetSecret(configurationManager.Auth.JWTSecret)
+
+	e := buildServer(ctx, configurationManager)
+	e.Logger.Fatal(e.Start(configurationManager.HttpAddress))
 }
 
-func buildServer(ctx context.Context) *echo.Echo {
+func buildServer(ctx context.Context, configurationManager *config.ConfigurationManager) *echo.Echo {
 	e := echo.New()
 
-	configurationManager := config.NewConfigurationManager()
 	dbPool := pgcommon.GetConnectionPool(ctx, configurationManager.PostgreSqlConfig)
 
-	registerRoutes(e, dbPool)
+	registerRoutes(e, dbPool, configurationManager)
 	return e
 }
 
-func registerRoutes(e *echo.Echo, dbPool *pgxpool.Pool) {
+func registerRoutes(e *echo.Echo, dbPool *pgxpool.Pool, configurationManager *config.ConfigurationManager) {
 	productRepository := postgresql.NewProductRepository(dbPool)
-	publisher := kafka.NewProducerAdapter([]string{"kafka:9092"}, "product.events")
-	productService := usecase.NewProductService(productRepository, publisher)
+	storeRepository := postgresql.NewStoreRepository(dbPool)
+	productService := usecase.NewProductService(productRepository, storeRepository, configurationManager.ProductEventsTopic)
 	productController := controller.NewProductController(productService)
 
-	productController.RegisterRoutes(e)
+	storeService := usecase.NewStoreService(storeRepository)
+	storeController := controller.NewStoreController(storeService)
+
+	recommendationRepository := postgresql.NewRecommendationRepository(dbPool)
+	recommendationService := usecase.NewRecommendationService(recommendationRepository, productRepository, 5*time.Minute)
+	recommendationController := controller.NewRecommendationController(recommendationService)
+
+	categoryClient := categoryclient.NewClient(configurationManager.CategoryServiceURL, 3*time.Second)
+	graphqlHandler := graphql.NewHandler(productService, categoryClient)
+
+	idempotencyStore := idempotency.NewPostgresStore(dbPool)
+	go idempotencyStore.StartPruning(context.Background(), time.Hour)
+	idempotent := idempotency.Middleware(idempotencyStore, configurationManager.IdempotencyTTL)
+	productController.RegisterRoutes(e, idempotent)
+	storeController.RegisterRoutes(e, idempotent)
+	recommendationController.RegisterRoutes(e)
+	graphqlHandler.RegisterRoutes(e)
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+
+	go startOrderEventConsumer(recommendationService, configurationManager)
+	go startOutboxRelay(dbPool, configurationManager.Kafka.Brokers, configurationManager.ProductEventsTopic)
+	go startGRPCServer(configurationManager.GrpcAddress, productService)
+}
+
+func startOutboxRelay(dbPool *pgxpool.Pool, brokers []string, topic string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	producer := sharedkafka.NewProducer(brokers, topic)
+	defer producer.Close()
+
+	relay := outbox.NewRelay(dbPool, producer, outbox.RelayConfig{Topic: topic})
+	if err := relay.Start(ctx); err != nil {
+		log.Printf("outbox relay stopped: %v", err)
+	}
+}
+
+func startGRPCServer(address string, productService usecase.IProductService) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Printf("grpc server not started: %v", err)
+		return
+	}
+
+	server, healthServer := grpcx.NewServer(productgrpc.ProtectedMethods...)
+	productv1.RegisterProductServiceServer(server, productgrpc.NewProductServer(productService))
+	healthServer.SetServingStatus(productv1.ProductService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	if err := server.Serve(listener); err != nil {
+		log.Printf("grpc server stopped: %v", err)
+	}
+}
+
+func startOrderEventConsumer(recommendationService usecase.IRecommendationService, configurationManager *config.ConfigurationManager) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	consumer := kafka.NewConsumerAdapter(
+		configurationManager.Kafka,
+		configurationManager.OrderEventsTopic,
+		configurationManager.RecommendationsConsumerGroup,
+		kafka.NewOrderEventHandler(recommendationService),
+	)
+	defer consumer.Close()
+
+	if err := consumer.Start(ctx); err != nil {
+		log.Printf("kafka consumer stopped: %v", err)
+	}
 }
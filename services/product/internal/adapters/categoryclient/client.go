@@ -0,0 +1,62 @@
+package categoryclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+	"strings"
+	"time"
+)
+
+// Client reads categories from the category service's public REST API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL string, timeout time.Duration) ports.CategoryClient {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (client *Client) GetAllCategories() ([]domain.Category, error) {
+	var categories []domain.Category
+	if err := client.getJSON("/api/v1/categories", &categories); err != nil {
+		return nil, err
+	}
+	if categories == nil {
+		categories = []domain.Category{}
+	}
+	return categories, nil
+}
+
+func (client *Client) GetById(categoryId int64) (domain.Category, error) {
+	var category domain.Category
+	if err := client.getJSON(fmt.Sprintf("/api/v1/categories/%d", categoryId), &category); err != nil {
+		return domain.Category{}, err
+	}
+	return category, nil
+}
+
+func (client *Client) getJSON(path string, target interface{}) error {
+	resp, err := client.httpClient.Get(client.baseURL + path)
+	if err != nil {
+		return fmt.Errorf("category service request %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("category not found: %s", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("category service request %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode category service response: %w", err)
+	}
+	return nil
+}
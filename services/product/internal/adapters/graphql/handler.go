@@ -0,0 +1,98 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-app/services/product/internal/ports"
+	"product-app/services/product/internal/usecase"
+	"product-app/shared/auth"
+	"strconv"
+	"strings"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/labstack/echo/v4"
+)
+
+// Limits on incoming queries. Category and product resolvers nest into each
+// other, so the depth bounds how many lookups one query can fan out to.
+const (
+	maxRequestBytes = 1 << 20
+	maxQueryLength  = 10000
+	maxQueryDepth   = 8
+	maxParallelism  = 10
+)
+
+type claimsKey struct{}
+
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler serves GraphQL queries over products and categories.
+type Handler struct {
+	schema         *graphqlgo.Schema
+	productService usecase.IProductService
+	categoryClient ports.CategoryClient
+}
+
+// NewHandler parses the schema and binds it to the given services. It panics
+// if the schema and resolvers disagree, which is a programming error.
+func NewHandler(productService usecase.IProductService, categoryClient ports.CategoryClient) *Handler {
+	schema := graphqlgo.MustParseSchema(schemaString, &rootResolver{productService: productService},
+		graphqlgo.MaxDepth(maxQueryDepth),
+		graphqlgo.MaxParallelism(maxParallelism),
+		graphqlgo.MaxQueryLength(maxQueryLength),
+	)
+	return &Handler{
+		schema:         schema,
+		productService: productService,
+		categoryClient: categoryClient,
+	}
+}
+
+// RegisterRoutes registers the GraphQL endpoint
+//   - POST /graphql - Execute a query or mutation (mutations need a bearer token)
+func (handler *Handler) RegisterRoutes(e *echo.Echo) {
+	e.POST("/graphql", handler.Serve)
+}
+
+func (handler *Handler) Serve(c echo.Context) error {
+	var request graphqlRequest
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxRequestBytes)
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+				"error": "Request body must be at most " + strconv.Itoa(maxRequestBytes) + " bytes",
+			})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx := withLoaders(c.Request().Context(), newLoaders(handler.productService, handler.categoryClient))
+
+	if authHeader := c.Request().Header.Get("Authorization"); authHeader != "" {
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		claims, err := auth.ParseToken(tokenString)
+		if tokenString == authHeader || err != nil {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "Invalid or expired token",
+			})
+		}
+		ctx = context.WithValue(ctx, claimsKey{}, claims)
+	}
+
+	result := handler.schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+	return c.JSON(http.StatusOK, result)
+}
+
+func isAuthenticated(ctx context.Context) bool {
+	claims, ok := ctx.Value(claimsKey{}).(*auth.Claims)
+	return ok && claims != nil
+}
@@ -0,0 +1,131 @@
+package graphql
+
+import (
+	"context"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+	"product-app/services/product/internal/usecase"
+	"sort"
+	"sync"
+)
+
+type loadersKey struct{}
+
+// loaders holds the per-request batching caches. A fresh set is created for
+// every GraphQL request so results never leak between callers.
+type loaders struct {
+	productsByCategory *productsByCategoryLoader
+	categories         *categoryLoader
+}
+
+func newLoaders(productService usecase.IProductService, categoryClient ports.CategoryClient) *loaders {
+	return &loaders{
+		productsByCategory: &productsByCategoryLoader{
+			productService: productService,
+			pending:        map[int64]struct{}{},
+			results:        map[int64][]domain.Product{},
+		},
+		categories: &categoryLoader{categoryClient: categoryClient},
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// productsByCategoryLoader batches "products of category X" lookups. List
+// resolvers prime it with every category id they return; the first Load then
+// fetches all primed categories in one call, avoiding one query per category.
+type productsByCategoryLoader struct {
+	productService usecase.IProductService
+
+	mu      sync.Mutex
+	pending map[int64]struct{}
+	results map[int64][]domain.Product
+}
+
+func (loader *productsByCategoryLoader) Prime(categoryIds ...int64) {
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+	for _, categoryId := range categoryIds {
+		if _, loaded := loader.results[categoryId]; !loaded {
+			loader.pending[categoryId] = struct{}{}
+		}
+	}
+}
+
+func (loader *productsByCategoryLoader) Load(categoryId int64) ([]domain.Product, error) {
+	loader.mu.Lock()
+	defer loader.mu.Unlock()
+
+	if products, loaded := loader.results[categoryId]; loaded {
+		return products, nil
+	}
+
+	loader.pending[categoryId] = struct{}{}
+	keys := make([]int64, 0, len(loader.pending))
+	for key := range loader.pending {
+		keys = append(keys, key)
+	}
+
+	grouped, err := loader.productService.GetProductsByCategoryIds(keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		loader.results[key] = grouped[key]
+		delete(loader.pending, key)
+	}
+	return loader.results[categoryId], nil
+}
+
+// categoryLoader fetches the category list once per request and serves
+// every Product.category field from it.
+type categoryLoader struct {
+	categoryClient ports.CategoryClient
+
+	once       sync.Once
+	categories map[int64]domain.Category
+	err        error
+}
+
+func (loader *categoryLoader) all() (map[int64]domain.Category, error) {
+	loader.once.Do(func() {
+		categories, err := loader.categoryClient.GetAllCategories()
+		if err != nil {
+			loader.err = err
+			return
+		}
+		loader.categories = make(map[int64]domain.Category, len(categories))
+		for _, category := range categories {
+			loader.categories[category.Id] = category
+		}
+	})
+	return loader.categories, loader.err
+}
+
+func (loader *categoryLoader) Load(categoryId int64) (domain.Category, bool, error) {
+	categories, err := loader.all()
+	if err != nil {
+		return domain.Category{}, false, err
+	}
+	category, ok := categories[categoryId]
+	return category, ok, nil
+}
+
+func (loader *categoryLoader) LoadAll() ([]domain.Category, error) {
+	categories, err := loader.all()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]domain.Category, 0, len(categories))
+	for _, category := range categories {
+		list = append(list, category)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	return list, nil
+}
@@ -0,0 +1,328 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase"
+	"product-app/services/product/internal/usecase/model"
+	"strconv"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+var errUnauthorized = errors.New("unauthorized: a valid bearer token is required")
+
+// rootResolver implements the Query and Mutation types.
+type rootResolver struct {
+	productService usecase.IProductService
+}
+
+/* =========================
+   QUERIES
+========================= */
+
+func (r *rootResolver) Product(ctx context.Context, args struct{ ID graphqlgo.ID }) (*productResolver, error) {
+	productId, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := r.productService.GetById(productId)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &productResolver{product: product}, nil
+}
+
+type productFilterInput struct {
+	StoreSlug    *string
+	CategoryId   *graphqlgo.ID
+	MinPrice     *float64
+	MaxPrice     *float64
+	NameContains *string
+}
+
+type productsArgs struct {
+	Filter *productFilterInput
+	First  *int32
+	After  *string
+}
+
+// Products reads one page of products. Filters, limit and cursor are all
+// applied by the repository query.
+func (r *rootResolver) Products(ctx context.Context, args productsArgs) (*productConnectionResolver, error) {
+	limit, err := pageSize(args.First)
+	if err != nil {
+		return nil, err
+	}
+	search := model.ProductSearch{Limit: limit}
+	if args.After != nil {
+		search.Cursor = *args.After
+	}
+	if filter := args.Filter; filter != nil {
+		if filter.StoreSlug != nil {
+			search.StoreSlug = *filter.StoreSlug
+		}
+		if filter.CategoryId != nil {
+			categoryId, err := parseID(*filter.CategoryId)
+			if err != nil {
+				return nil, err
+			}
+			search.CategoryId = categoryId
+		}
+		search.MinPrice = filter.MinPrice
+		search.MaxPrice = filter.MaxPrice
+		if filter.NameContains != nil {
+			search.NameContains = *filter.NameContains
+		}
+	}
+
+	page, err := r.productService.FindProducts(search)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*productResolver, 0, len(page.Products))
+	for _, product := range page.Products {
+		items = append(items, &productResolver{product: product})
+	}
+	connection := &productConnectionResolver{
+		totalCount:  int32(page.TotalCount),
+		hasNextPage: page.NextCursor != "",
+		items:       items,
+	}
+	if page.NextCursor != "" {
+		connection.endCursor = &page.NextCursor
+	}
+	return connection, nil
+}
+
+func (r *rootResolver) Category(ctx context.Context, args struct{ ID graphqlgo.ID }) (*categoryResolver, error) {
+	categoryId, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	category, found, err := loadersFrom(ctx).categories.Load(categoryId)
+	if err != nil || !found {
+		return nil, err
+	}
+	loadersFrom(ctx).productsByCategory.Prime(categoryId)
+	return &categoryResolver{category: category}, nil
+}
+
+func (r *rootResolver) Categories(ctx context.Context) ([]*categoryResolver, error) {
+	categories, err := loadersFrom(ctx).categories.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*categoryResolver, 0, len(categories))
+	categoryIds := make([]int64, 0, len(categories))
+	for _, category := range categories {
+		resolvers = append(resolvers, &categoryResolver{category: category})
+		categoryIds = append(categoryIds, category.Id)
+	}
+	loadersFrom(ctx).productsByCategory.Prime(categoryIds...)
+	return resolvers, nil
+}
+
+/* =========================
+   MUTATIONS
+========================= */
+
+type createProductInput struct {
+	Name        string
+	Price       float64
+	Description *string
+	Discount    *float64
+	StoreId     *graphqlgo.ID
+	Store       *string
+	ImageUrls   *[]string
+	CategoryId  *graphqlgo.ID
+}
+
+func (r *rootResolver) CreateProduct(ctx context.Context, args struct{ Input createProductInput }) (*productResolver, error) {
+	if !isAuthenticated(ctx) {
+		return nil, errUnauthorized
+	}
+
+	productCreate := model.ProductCreate{
+		Name:  args.Input.Name,
+		Price: float32(args.Input.Price),
+	}
+	if args.Input.Description != nil {
+		productCreate.Description = *args.Input.Description
+	}
+	if args.Input.Discount != nil {
+		productCreate.Discount = float32(*args.Input.Discount)
+	}
+	if args.Input.Store != nil {
+		productCreate.Store = *args.Input.Store
+	}
+	if args.Input.ImageUrls != nil {
+		productCreate.ImageUrls = *args.Input.ImageUrls
+	}
+	if args.Input.StoreId != nil {
+		storeId, err := parseID(*args.Input.StoreId)
+		if err != nil {
+			return nil, err
+		}
+		productCreate.StoreID = storeId
+	}
+	if args.Input.CategoryId != nil {
+		categoryId, err := parseID(*args.Input.CategoryId)
+		if err != nil {
+			return nil, err
+		}
+		productCreate.CategoryID = categoryId
+	}
+
+	product, err := r.productService.Add(productCreate)
+	if err != nil {
+		return nil, err
+	}
+	return &productResolver{product: product}, nil
+}
+
+func (r *rootResolver) UpdateProductPrice(ctx context.Context, args struct {
+	ID    graphqlgo.ID
+	Price float64
+}) (*productResolver, error) {
+	if !isAuthenticated(ctx) {
+		return nil, errUnauthorized
+	}
+	productId, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if args.Price <= 0 {
+		return nil, errors.New("price must be greater than zero")
+	}
+
+	if _, err := r.productService.GetById(productId); err != nil {
+		return nil, err
+	}
+	if err := r.productService.UpdatePrice(productId, float32(args.Price)); err != nil {
+		return nil, err
+	}
+	product, err := r.productService.GetById(productId)
+	if err != nil {
+		return nil, err
+	}
+	return &productResolver{product: product}, nil
+}
+
+/* =========================
+   TYPES
+========================= */
+
+type productConnectionResolver struct {
+	totalCount  int32
+	hasNextPage bool
+	endCursor   *string
+	items       []*productResolver
+}
+
+func (r *productConnectionResolver) TotalCount() int32         { return r.totalCount }
+func (r *productConnectionResolver) HasNextPage() bool         { return r.hasNextPage }
+func (r *productConnectionResolver) EndCursor() *string        { return r.endCursor }
+func (r *productConnectionResolver) Items() []*productResolver { return r.items }
+
+type productResolver struct {
+	product domain.Product
+}
+
+func (r *productResolver) ID() graphqlgo.ID      { return formatID(r.product.Id) }
+func (r *productResolver) Name() string          { return r.product.Name }
+func (r *productResolver) Price() float64        { return float64(r.product.Price) }
+func (r *productResolver) Description() string   { return r.product.Description }
+func (r *productResolver) Discount() float64     { return float64(r.product.Discount) }
+func (r *productResolver) StoreId() graphqlgo.ID { return formatID(r.product.StoreID) }
+func (r *productResolver) StoreSlug() string     { return r.product.StoreSlug }
+func (r *productResolver) Store() string         { return r.product.Store }
+
+func (r *productResolver) ImageUrls() []string {
+	if r.product.ImageUrls == nil {
+		return []string{}
+	}
+	return r.product.ImageUrls
+}
+
+func (r *productResolver) CategoryId() *graphqlgo.ID {
+	if r.product.CategoryID <= 0 {
+		return nil
+	}
+	id := formatID(r.product.CategoryID)
+	return &id
+}
+
+func (r *productResolver) Category(ctx context.Context) (*categoryResolver, error) {
+	if r.product.CategoryID <= 0 {
+		return nil, nil
+	}
+	category, found, err := loadersFrom(ctx).categories.Load(r.product.CategoryID)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &categoryResolver{category: category}, nil
+}
+
+type categoryResolver struct {
+	category domain.Category
+}
+
+func (r *categoryResolver) ID() graphqlgo.ID    { return formatID(r.category.Id) }
+func (r *categoryResolver) Name() string        { return r.category.Name }
+func (r *categoryResolver) Description() string { return r.category.Description }
+
+func (r *categoryResolver) Products(ctx context.Context, args struct{ First *int32 }) ([]*productResolver, error) {
+	products, err := loadersFrom(ctx).productsByCategory.Load(r.category.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := pageSize(args.First)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) > first {
+		products = products[:first]
+	}
+
+	resolvers := make([]*productResolver, 0, len(products))
+	for _, product := range products {
+		resolvers = append(resolvers, &productResolver{product: product})
+	}
+	return resolvers, nil
+}
+
+/* =========================
+   HELPERS
+========================= */
+
+func parseID(id graphqlgo.ID) (int64, error) {
+	parsed, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid id %q", string(id))
+	}
+	return parsed, nil
+}
+
+func formatID(id int64) graphqlgo.ID {
+	return graphqlgo.ID(strconv.FormatInt(id, 10))
+}
+
+// pageSize reads a first argument, defaulting to the service's page size.
+func pageSize(first *int32) (int, error) {
+	if first == nil {
+		return usecase.DefaultProductPageSize, nil
+	}
+	if *first <= 0 || *first > usecase.MaxProductPageSize {
+		return 0, fmt.Errorf("first must be between 1 and %d", usecase.MaxProductPageSize)
+	}
+	return int(*first), nil
+}
@@ -0,0 +1,69 @@
+package graphql
+
+// schemaString is the GraphQL schema served on /graphql. Mutations require a
+// bearer token signed with the shared JWT secret.
+const schemaString = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	product(id: ID!): Product
+	products(filter: ProductFilter, first: Int, after: String): ProductConnection!
+	category(id: ID!): Category
+	categories: [Category!]!
+}
+
+type Mutation {
+	createProduct(input: CreateProductInput!): Product!
+	updateProductPrice(id: ID!, price: Float!): Product!
+}
+
+input ProductFilter {
+	storeSlug: String
+	categoryId: ID
+	minPrice: Float
+	maxPrice: Float
+	nameContains: String
+}
+
+input CreateProductInput {
+	name: String!
+	price: Float!
+	description: String
+	discount: Float
+	storeId: ID
+	store: String
+	imageUrls: [String!]
+	categoryId: ID
+}
+
+type ProductConnection {
+	totalCount: Int!
+	hasNextPage: Boolean!
+	endCursor: String
+	items: [Product!]!
+}
+
+type Product {
+	id: ID!
+	name: String!
+	price: Float!
+	description: String!
+	discount: Float!
+	storeId: ID!
+	storeSlug: String!
+	store: String!
+	imageUrls: [String!]!
+	categoryId: ID
+	category: Category
+}
+
+type Category {
+	id: ID!
+	name: String!
+	description: String!
+	products(first: Int): [Product!]!
+}
+`
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase"
+	"product-app/services/product/internal/usecase/model"
+	productv1 "product-app/shared/proto/product/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProtectedMethods lists the RPCs that require a bearer token.
+var ProtectedMethods = []string{
+	productv1.ProductService_CreateProduct_FullMethodName,
+	productv1.ProductService_UpdateProductPrice_FullMethodName,
+	productv1.ProductService_DeleteProduct_FullMethodName,
+}
+
+// ProductServer serves productv1.ProductService on top of IProductService.
+type ProductServer struct {
+	productv1.UnimplementedProductServiceServer
+	productService usecase.IProductService
+}
+
+// NewProductServer creates a new instance of ProductServer
+func NewProductServer(productService usecase.IProductService) *ProductServer {
+	return &ProductServer{productService: productService}
+}
+
+func (server *ProductServer) GetProduct(ctx context.Context, req *productv1.GetProductRequest) (*productv1.GetProductResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid product ID")
+	}
+	product, err := server.productService.GetById(req.GetId())
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &productv1.GetProductResponse{Product: toProtoProduct(product)}, nil
+}
+
+func (server *ProductServer) ListProducts(ctx context.Context, req *productv1.ListProductsRequest) (*productv1.ListProductsResponse, error) {
+	var products []domain.Product
+	switch {
+	case req.GetCategoryId() < 0:
+		return nil, status.Error(codes.InvalidArgument, "invalid category ID")
+	case req.GetCategoryId() > 0:
+		byCategory, err := server.productService.GetProductsByCategoryId(req.GetCategoryId())
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		products = byCategory
+	case req.GetStoreSlug() != "":
+		products = server.productService.GetAllProductsByStore(req.GetStoreSlug())
+	default:
+		products = server.productService.GetAllProducts()
+	}
+
+	response := &productv1.ListProductsResponse{
+		Products: make([]*productv1.Product, 0, len(products)),
+	}
+	for _, product := range products {
+		response.Products = append(response.Products, toProtoProduct(product))
+	}
+	return response, nil
+}
+
+func (server *ProductServer) CreateProduct(ctx context.Context, req *productv1.CreateProductRequest) (*productv1.CreateProductResponse, error) {
+	product, err := server.productService.Add(model.ProductCreate{
+		Name:        req.GetName(),
+		Price:       req.GetPrice(),
+		Description: req.GetDescription(),
+		Discount:    req.GetDiscount(),
+		StoreID:     req.GetStoreId(),
+		Store:       req.GetStore(),
+		ImageUrls:   req.GetImageUrls(),
+		CategoryID:  req.GetCategoryId(),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrStoreNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrInvalidProduct) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &productv1.CreateProductResponse{Product: toProtoProduct(product)}, nil
+}
+
+func (server *ProductServer) UpdateProductPrice(ctx context.Context, req *productv1.UpdateProductPriceRequest) (*productv1.UpdateProductPriceResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid product ID")
+	}
+	if req.GetPrice() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "price must be greater than zero")
+	}
+	if _, err := server.productService.GetById(req.GetId()); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err := server.productService.UpdatePrice(req.GetId(), req.GetPrice()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	product, err := server.productService.GetById(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &productv1.UpdateProductPriceResponse{Product: toProtoProduct(product)}, nil
+}
+
+func (server *ProductServer) DeleteProduct(ctx context.Context, req *productv1.DeleteProductRequest) (*productv1.DeleteProductResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid product ID")
+	}
+	if err := server.productService.DeleteById(req.GetId()); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &productv1.DeleteProductResponse{}, nil
+}
+
+func toProtoProduct(product domain.Product) *productv1.Product {
+	return &productv1.Product{
+		Id:          product.Id,
+		Name:        product.Name,
+		Price:       product.Price,
+		Description: product.Description,
+		Discount:    product.Discount,
+		StoreId:     product.StoreID,
+		StoreSlug:   product.StoreSlug,
+		Store:       product.Store,
+		ImageUrls:   product.ImageUrls,
+		CategoryId:  product.CategoryID,
+	}
+}
@@ -2,6 +2,8 @@ package controller
 
 import (
 	"fmt"
+	"product-app/services/product/internal/usecase/model"
+	"product-app/shared/auth"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
@@ -15,3 +17,25 @@ func parsePositiveIDParam(c echo.Context, name string) (int64, error) {
 	}
 	return int64(id), nil
 }
+
+// currentUserId returns the user id stored by the JWT middleware.
+func currentUserId(c echo.Context) (int64, bool) {
+	userId, ok := c.Get("user_id").(int64)
+	return userId, ok && userId > 0
+}
+
+// currentRequester describes the authenticated caller for ownership checks.
+func currentRequester(c echo.Context) (model.Requester, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return model.Requester{}, false
+	}
+	roles, _ := c.Get("roles").([]string)
+	requester := model.Requester{UserId: userId}
+	for _, role := range roles {
+		if role == auth.RoleAdmin {
+			requester.Admin = true
+		}
+	}
+	return requester, true
+}
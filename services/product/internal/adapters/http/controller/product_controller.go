@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"product-app/services/product/internal/adapters/http/controller/request"
 	"product-app/services/product/internal/adapters/http/controller/response"
 	"product-app/services/product/internal/adapters/http/middleware"
+	"product-app/services/product/internal/domain"
 	"product-app/services/product/internal/usecase"
 	"strconv"
 
@@ -43,11 +45,12 @@ func NewProductController(productService usecase.IProductService) *ProductContro
 //
 // Parameters:
 //   - e: Echo instance for route registration
-func (productController *ProductController) RegisterRoutes(e *echo.Echo) {
+//   - idempotent: middleware applied to the POST routes, e.g. Idempotency-Key handling
+func (productController *ProductController) RegisterRoutes(e *echo.Echo, idempotent ...echo.MiddlewareFunc) {
 	// Public routes (no authentication required)
 	e.GET("/api/v1/products/:id", productController.GetProductById)
 	e.GET("/api/v1/products", productController.GetAllProducts)
-	e.POST("/api/v1/products", productController.AddProduct)
+	e.POST("/api/v1/products", productController.AddProduct, idempotent...)
 
 	// Protected routes (authentication required)
 	protected := e.Group("/api/v1/products", middleware.JWTMiddleware())
@@ -82,11 +85,17 @@ func (productController *ProductController) GetProductById(c echo.Context) error
 	}
 
 	product, err := productController.productService.GetById(productId)
-	if err != nil {
+	if errors.Is(err, domain.ErrProductNotFound) {
 		return c.JSON(http.StatusNotFound, response.ErrorResponse{
 			Error: "Error:  " + err.Error(),
 		})
 	}
+	if err != nil {
+		log.Printf("GetProductById error: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error: "Product lookup failed",
+		})
+	}
 	return c.JSON(http.StatusOK, response.ToResponse(product))
 }
 
@@ -108,14 +117,20 @@ func (productController *ProductController) AddProduct(c echo.Context) error {
 			Error: bindErr.Error(),
 		})
 	}
-	err := productController.productService.Add(addProductRequest.ToModel())
-
-	if err != nil {
+	product, err := productController.productService.Add(addProductRequest.ToModel())
+	if errors.Is(err, domain.ErrInvalidProduct) || errors.Is(err, domain.ErrStoreNotFound) {
 		return c.JSON(http.StatusUnprocessableEntity, response.ErrorResponse{
 			Error: err.Error(),
 		})
 	}
-	return c.NoContent(http.StatusCreated)
+	if err != nil {
+		log.Printf("AddProduct error: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error: "Product could not be added",
+		})
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/products/%d", product.Id))
+	return c.JSON(http.StatusCreated, response.ToResponse(product))
 }
 func (productController *ProductController) UpdatePrice(c echo.Context) error {
 	productId, err := parsePositiveIDParam(c, "id")
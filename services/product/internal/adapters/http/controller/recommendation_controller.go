@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"net/http"
+	"product-app/services/product/internal/adapters/http/controller/response"
+	"product-app/services/product/internal/usecase"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RecommendationController serves "customers also bought" recommendations
+type RecommendationController struct {
+	recommendationService usecase.IRecommendationService
+}
+
+// NewRecommendationController creates a new instance of RecommendationController
+func NewRecommendationController(recommendationService usecase.IRecommendationService) *RecommendationController {
+	return &RecommendationController{recommendationService: recommendationService}
+}
+
+// RegisterRoutes registers recommendation routes
+// Public routes (no authentication):
+//   - GET /api/v1/products/:id/related - Products frequently bought together (optional limit)
+func (recommendationController *RecommendationController) RegisterRoutes(e *echo.Echo) {
+	e.GET("/api/v1/products/:id/related", recommendationController.GetRelatedProducts)
+}
+
+func (recommendationController *RecommendationController) GetRelatedProducts(c echo.Context) error {
+	productId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid product ID",
+		})
+	}
+
+	limit := usecase.DefaultRelatedProductsLimit
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > usecase.MaxRelatedProductsLimit {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error: "Invalid limit",
+			})
+		}
+	}
+
+	related, err := recommendationController.recommendationService.GetRelatedProducts(productId, limit)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, response.ToRelatedProductsResponse(productId, related))
+}
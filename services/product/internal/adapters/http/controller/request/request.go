@@ -17,7 +17,10 @@ type AddProductRequest struct {
 	// Discount amount or rate applied to the product
 	Discount float32 `json:"discount"`
 
-	// Store or seller name
+	// Identifier of the store selling the product
+	StoreID int64 `json:"store_id"`
+
+	// Store slug or display name, accepted when store_id is not given
 	Store string `json:"store"`
 
 	// List of product image URLs
@@ -34,8 +37,59 @@ func (addProductRequest AddProductRequest) ToModel() model.ProductCreate {
 		Price:       addProductRequest.Price,
 		Description: addProductRequest.Description,
 		Discount:    addProductRequest.Discount,
+		StoreID:     addProductRequest.StoreID,
 		Store:       addProductRequest.Store,
 		ImageUrls:   addProductRequest.ImageUrls,
 		CategoryID:  addProductRequest.CategoryID,
 	}
 }
+
+// AddStoreRequest represents the request payload used to create a store.
+type AddStoreRequest struct {
+	// URL friendly identifier, derived from the name when empty
+	Slug string `json:"slug"`
+
+	// Display name of the store
+	Name string `json:"name"`
+
+	// Contact details shown to customers
+	ContactEmail string `json:"contact_email"`
+	ContactPhone string `json:"contact_phone"`
+}
+
+// ToModel converts AddStoreRequest to StoreCreate model.
+func (addStoreRequest AddStoreRequest) ToModel() model.StoreCreate {
+	return model.StoreCreate{
+		Slug:         addStoreRequest.Slug,
+		Name:         addStoreRequest.Name,
+		ContactEmail: addStoreRequest.ContactEmail,
+		ContactPhone: addStoreRequest.ContactPhone,
+	}
+}
+
+// UpdateStoreRequest represents the request payload used to update a store.
+type UpdateStoreRequest struct {
+	// New slug, kept unchanged when empty
+	Slug string `json:"slug"`
+
+	// Display name of the store
+	Name string `json:"name"`
+
+	// Contact details shown to customers
+	ContactEmail string `json:"contact_email"`
+	ContactPhone string `json:"contact_phone"`
+
+	// One of active, inactive or closed; kept unchanged when empty
+	Status string `json:"status"`
+}
+
+// ToModel converts UpdateStoreRequest to StoreUpdate model.
+func (updateStoreRequest UpdateStoreRequest) ToModel() model.StoreUpdate {
+	return model.StoreUpdate{
+		Slug:         updateStoreRequest.Slug,
+		Name:         updateStoreRequest.Name,
+		ContactEmail: updateStoreRequest.ContactEmail,
+		ContactPhone: updateStoreRequest.ContactPhone,
+		Status:       updateStoreRequest.Status,
+	}
+}
@@ -1,16 +1,22 @@
 package response
 
-import "product-app/services/product/internal/domain"
+import (
+	"product-app/services/product/internal/domain"
+	"time"
+)
 
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
 type ProductResponse struct {
+	Id          int64    `json:"id"`
 	Name        string   `json:"name"`
 	Price       float32  `json:"price"`
 	Description string   `json:"description"`
 	Discount    float32  `json:"discount"`
+	StoreID     int64    `json:"store_id"`
+	StoreSlug   string   `json:"store_slug"`
 	Store       string   `json:"store"`
 	ImageUrls   []string `json:"image_urls"`
 	CategoryID  int64    `json:"category_id"`
@@ -18,10 +24,13 @@ type ProductResponse struct {
 
 func ToResponse(product domain.Product) ProductResponse {
 	return ProductResponse{
+		Id:          product.Id,
 		Name:        product.Name,
 		Price:       product.Price,
 		Description: product.Description,
 		Discount:    product.Discount,
+		StoreID:     product.StoreID,
+		StoreSlug:   product.StoreSlug,
 		Store:       product.Store,
 		ImageUrls:   product.ImageUrls,
 		CategoryID:  product.CategoryID,
@@ -34,3 +43,67 @@ func ToResponseList(products []domain.Product) []ProductResponse {
 	}
 	return productResponseList
 }
+
+type RelatedProductResponse struct {
+	Id              int64           `json:"id"`
+	Product         ProductResponse `json:"product"`
+	CoPurchaseCount int64           `json:"co_purchase_count"`
+	UnitsSold       int64           `json:"units_sold"`
+	Reason          string          `json:"reason"`
+}
+
+type RelatedProductsResponse struct {
+	ProductId int64                    `json:"product_id"`
+	Related   []RelatedProductResponse `json:"related"`
+}
+
+func ToRelatedProductsResponse(productId int64, related []domain.RelatedProduct) RelatedProductsResponse {
+	relatedResponses := []RelatedProductResponse{}
+	for _, relatedProduct := range related {
+		relatedResponses = append(relatedResponses, RelatedProductResponse{
+			Id:              relatedProduct.Product.Id,
+			Product:         ToResponse(relatedProduct.Product),
+			CoPurchaseCount: relatedProduct.CoPurchaseCount,
+			UnitsSold:       relatedProduct.UnitsSold,
+			Reason:          relatedProduct.Reason,
+		})
+	}
+	return RelatedProductsResponse{
+		ProductId: productId,
+		Related:   relatedResponses,
+	}
+}
+
+type StoreResponse struct {
+	Id           int64     `json:"id"`
+	Slug         string    `json:"slug"`
+	Name         string    `json:"name"`
+	OwnerUserId  int64     `json:"owner_user_id,omitempty"`
+	ContactEmail string    `json:"contact_email,omitempty"`
+	ContactPhone string    `json:"contact_phone,omitempty"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func ToStoreResponse(store domain.Store) StoreResponse {
+	return StoreResponse{
+		Id:           store.Id,
+		Slug:         store.Slug,
+		Name:         store.Name,
+		OwnerUserId:  store.OwnerUserId,
+		ContactEmail: store.ContactEmail,
+		ContactPhone: store.ContactPhone,
+		Status:       store.Status,
+		CreatedAt:    store.CreatedAt,
+		UpdatedAt:    store.UpdatedAt,
+	}
+}
+
+func ToStoreResponseList(stores []domain.Store) []StoreResponse {
+	var storeResponseList = []StoreResponse{}
+	for _, store := range stores {
+		storeResponseList = append(storeResponseList, ToStoreResponse(store))
+	}
+	return storeResponseList
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"product-app/services/product/internal/adapters/http/controller/request"
+	"product-app/services/product/internal/adapters/http/controller/response"
+	"product-app/services/product/internal/adapters/http/middleware"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StoreController handles HTTP requests for store operations
+type StoreController struct {
+	storeService usecase.IStoreService
+}
+
+// NewStoreController creates a new instance of StoreController
+func NewStoreController(storeService usecase.IStoreService) *StoreController {
+	return &StoreController{storeService: storeService}
+}
+
+// RegisterRoutes registers all store-related HTTP routes
+// Public routes (no authentication):
+//   - GET /api/v1/stores - Get all stores
+//   - GET /api/v1/stores/:id - Get single store by ID
+//   - GET /api/v1/stores/slug/:slug - Get single store by slug
+//
+// Protected routes (JWT required):
+//   - POST /api/v1/stores - Create store owned by the current user
+//   - PUT /api/v1/stores/:id - Update store (owner only)
+//   - DELETE /api/v1/stores/:id - Delete store without products (owner only)
+//
+// idempotent is applied to the POST route after authentication.
+func (storeController *StoreController) RegisterRoutes(e *echo.Echo, idempotent ...echo.MiddlewareFunc) {
+	e.GET("/api/v1/stores", storeController.GetAllStores)
+	e.GET("/api/v1/stores/:id", storeController.GetStoreById)
+	e.GET("/api/v1/stores/slug/:slug", storeController.GetStoreBySlug)
+
+	protected := e.Group("/api/v1/stores", middleware.JWTMiddleware())
+	protected.POST("", storeController.AddStore, idempotent...)
+	protected.PUT("/:id", storeController.UpdateStore)
+	protected.DELETE("/:id", storeController.DeleteStoreById)
+}
+
+func (storeController *StoreController) GetAllStores(c echo.Context) error {
+	stores, err := storeController.storeService.GetAllStores()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, response.ToStoreResponseList(stores))
+}
+
+func (storeController *StoreController) GetStoreById(c echo.Context) error {
+	storeId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid store ID",
+		})
+	}
+
+	store, err := storeController.storeService.GetById(storeId)
+	if err != nil {
+		return storeErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, response.ToStoreResponse(store))
+}
+
+func (storeController *StoreController) GetStoreBySlug(c echo.Context) error {
+	store, err := storeController.storeService.GetBySlug(c.Param("slug"))
+	if err != nil {
+		return storeErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, response.ToStoreResponse(store))
+}
+
+func (storeController *StoreController) AddStore(c echo.Context) error {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error: "Missing user in token",
+		})
+	}
+
+	var addStoreRequest request.AddStoreRequest
+	if err := c.Bind(&addStoreRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	store, err := storeController.storeService.Add(addStoreRequest.ToModel(), userId)
+	if err != nil {
+		return storeErrorResponse(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/stores/%d", store.Id))
+	return c.JSON(http.StatusCreated, response.ToStoreResponse(store))
+}
+
+func (storeController *StoreController) UpdateStore(c echo.Context) error {
+	storeId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid store ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error: "Missing user in token",
+		})
+	}
+
+	var updateStoreRequest request.UpdateStoreRequest
+	if err := c.Bind(&updateStoreRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	store, err := storeController.storeService.Update(storeId, updateStoreRequest.ToModel(), requester)
+	if err != nil {
+		return storeErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, response.ToStoreResponse(store))
+}
+
+func (storeController *StoreController) DeleteStoreById(c echo.Context) error {
+	storeId, err := parsePositiveIDParam(c, "id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error: "Invalid store ID",
+		})
+	}
+	requester, ok := currentRequester(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error: "Missing user in token",
+		})
+	}
+
+	if err := storeController.storeService.DeleteById(storeId, requester); err != nil {
+		return storeErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func storeErrorResponse(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrInvalidStore):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrStoreNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrStoreForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrStoreSlugTaken), errors.Is(err, domain.ErrStoreHasProducts):
+		status = http.StatusConflict
+	}
+	return c.JSON(status, response.ErrorResponse{
+		Error: err.Error(),
+	})
+}
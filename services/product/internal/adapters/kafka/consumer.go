@@ -0,0 +1,34 @@
+package kafka
+
+import (
+	"context"
+
+	sharedconfig "product-app/shared/config"
+	"product-app/shared/kafka"
+)
+
+type ConsumerAdapter struct {
+	consumer *kafka.Consumer
+}
+
+// NewConsumerAdapter consumes topic as groupID with the brokers, concurrency
+// and ordering of settings.
+func NewConsumerAdapter(settings sharedconfig.Kafka, topic, groupID string, handler kafka.MessageHandler) *ConsumerAdapter {
+	return &ConsumerAdapter{
+		consumer: kafka.NewConsumer(kafka.ConsumerConfig{
+			Brokers:     settings.Brokers,
+			Topic:       topic,
+			GroupID:     groupID,
+			Concurrency: settings.ConsumerConcurrency,
+			OrderBy:     kafka.Ordering(settings.ConsumerOrderBy),
+		}, handler),
+	}
+}
+
+func (c *ConsumerAdapter) Start(ctx context.Context) error {
+	return c.consumer.Start(ctx)
+}
+
+func (c *ConsumerAdapter) Close() error {
+	return c.consumer.Close()
+}
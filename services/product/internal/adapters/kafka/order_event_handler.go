@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase"
+	"product-app/shared/kafka"
+)
+
+// orderCreatedV1 is the single-line order.created payload published before
+// orders had items. It is still decoded so events retained in the topic
+// from before the upgrade are not rejected.
+type orderCreatedV1 struct {
+	ID             int64  `json:"id"`
+	CustomerNumber string `json:"customer_number"`
+	ProductID      string `json:"product_id"`
+	Quantity       int32  `json:"quantity"`
+}
+
+func (orderCreatedV1) EventType() string         { return kafka.EventTypeOrderCreated }
+func (orderCreatedV1) SchemaVersion() string     { return "1.0" }
+func (event orderCreatedV1) AggregateID() string { return strconv.FormatInt(event.ID, 10) }
+
+// NewOrderEventHandler returns a handler that feeds order.created events into
+// the recommendation statistics. Other event types are ignored; malformed
+// envelopes and envelopes with an unsupported major schema version are
+// rejected, so they end up in the dead-letter topic.
+func NewOrderEventHandler(recommendationService usecase.IRecommendationService) kafka.MessageHandler {
+	return func(ctx context.Context, message kafka.Message) error {
+		envelope, err := kafka.DecodeEnvelope(message.Value)
+		if err != nil {
+			return fmt.Errorf("malformed order event at offset %d: %w", message.Offset, err)
+		}
+		if envelope.Type != kafka.EventTypeOrderCreated {
+			return nil
+		}
+
+		if strings.HasPrefix(envelope.SchemaVersion, "1.") {
+			return recordLegacyOrder(recommendationService, envelope)
+		}
+
+		var event kafka.OrderCreated
+		if err := envelope.DecodeData(&event); err != nil {
+			return err
+		}
+
+		purchase := domain.Purchase{
+			OrderId:        event.ID,
+			CustomerNumber: event.CustomerNumber,
+		}
+		for _, item := range event.Items {
+			purchase.Items = append(purchase.Items, domain.PurchaseItem{
+				ProductId: item.ProductID,
+				Quantity:  item.Quantity,
+			})
+		}
+		return recommendationService.RecordPurchase(purchase)
+	}
+}
+
+func recordLegacyOrder(recommendationService usecase.IRecommendationService, envelope kafka.Envelope) error {
+	var event orderCreatedV1
+	if err := envelope.DecodeData(&event); err != nil {
+		return err
+	}
+
+	productId, err := strconv.ParseInt(event.ProductID, 10, 64)
+	if err != nil || productId <= 0 {
+		log.Printf("skipping order %d with non-numeric product id %q", event.ID, event.ProductID)
+		return nil
+	}
+
+	return recommendationService.RecordPurchase(domain.Purchase{
+		OrderId:        event.ID,
+		CustomerNumber: event.CustomerNumber,
+		Items: []domain.PurchaseItem{
+			{ProductId: productId, Quantity: event.Quantity},
+		},
+	})
+}
@@ -6,12 +6,19 @@ import (
 	"fmt"
 	"product-app/services/product/internal/domain"
 	"product-app/services/product/internal/ports"
+	"product-app/shared/outbox"
+	"strings"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/labstack/gommon/log"
 )
 
+const selectProductColumns = `
+	SELECT p.id, p.name, p.price, p.description, p.discount, p.store_id, s.slug, s.name, p.category_id
+	FROM products p
+	JOIN stores s ON s.id = p.store_id`
+
 type ProductRepository struct {
 	dbPool *pgxpool.Pool
 }
@@ -23,8 +30,7 @@ func NewProductRepository(dbPool *pgxpool.Pool) ports.ProductRepository {
 func (r *ProductRepository) GetAllProducts() []domain.Product {
 	ctx := context.Background()
 
-	rows, err := r.dbPool.Query(ctx,
-		`SELECT id, name, price, description, discount, store, category_id FROM products`)
+	rows, err := r.dbPool.Query(ctx, selectProductColumns+` ORDER BY p.id`)
 	if err != nil {
 		log.Errorf("❌ Error getting all products: %v", err)
 		return []domain.Product{}
@@ -39,14 +45,13 @@ func (r *ProductRepository) GetAllProducts() []domain.Product {
 	return products
 }
 
-func (r *ProductRepository) GetAllProductsByStore(storeName string) []domain.Product {
+func (r *ProductRepository) GetAllProductsByStore(storeSlug string) []domain.Product {
 	ctx := context.Background()
 
-	rows, err := r.dbPool.Query(ctx, `
-		SELECT id, name, price, description, discount, store, category_id
-		FROM products
-		WHERE store = $1
-	`, storeName)
+	rows, err := r.dbPool.Query(ctx, selectProductColumns+`
+		WHERE s.slug = $1
+		ORDER BY p.id
+	`, storeSlug)
 
 	if err != nil {
 		log.Errorf("❌ Error querying products by store: %v", err)
@@ -64,6 +69,8 @@ func (r *ProductRepository) GetAllProductsByStore(storeName string) []domain.Pro
 			&p.Price,
 			&p.Description,
 			&p.Discount,
+			&p.StoreID,
+			&p.StoreSlug,
 			&p.Store,
 			&p.CategoryID,
 		); err != nil {
@@ -78,32 +85,120 @@ func (r *ProductRepository) GetAllProductsByStore(storeName string) []domain.Pro
 	return products
 }
 
-func (r *ProductRepository) AddProduct(product domain.Product) error {
+// FindProducts returns the products matching query in id order, starting
+// after query.AfterId and reading at most query.Limit rows.
+func (r *ProductRepository) FindProducts(query domain.ProductQuery) ([]domain.Product, error) {
+	ctx := context.Background()
+
+	conditions, args := productConditions(query)
+	if query.AfterId > 0 {
+		args = append(args, query.AfterId)
+		conditions = append(conditions, fmt.Sprintf("p.id > $%d", len(args)))
+	}
+	sql := selectProductColumns
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, query.Limit)
+	sql += fmt.Sprintf(" ORDER BY p.id LIMIT $%d", len(args))
+
+	rows, err := r.dbPool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding products: %w", err)
+	}
+	defer rows.Close()
+
+	return r.extractProducts(ctx, rows)
+}
+
+// CountProducts counts the products matching the filters of query; its
+// cursor and limit are ignored.
+func (r *ProductRepository) CountProducts(query domain.ProductQuery) (int, error) {
+	ctx := context.Background()
+
+	conditions, args := productConditions(query)
+	sql := `SELECT COUNT(*) FROM products p JOIN stores s ON s.id = p.store_id`
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var count int
+	if err := r.dbPool.QueryRow(ctx, sql, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error while counting products: %w", err)
+	}
+	return count, nil
+}
+
+// productConditions turns the filters of query into WHERE conditions over
+// selectProductColumns and their arguments.
+func productConditions(query domain.ProductQuery) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if query.StoreSlug != "" {
+		conditions = append(conditions, "s.slug = "+arg(query.StoreSlug))
+	}
+	if query.CategoryId != 0 {
+		conditions = append(conditions, "p.category_id = "+arg(query.CategoryId))
+	}
+	if query.MinPrice != nil {
+		conditions = append(conditions, "p.price >= "+arg(*query.MinPrice))
+	}
+	if query.MaxPrice != nil {
+		conditions = append(conditions, "p.price <= "+arg(*query.MaxPrice))
+	}
+	if query.NameContains != "" {
+		conditions = append(conditions, "p.name ILIKE '%' || "+arg(likeEscaper.Replace(query.NameContains))+" || '%'")
+	}
+	return conditions, args
+}
+
+// likeEscaper escapes the LIKE wildcards of user input, so they match
+// literally under the default backslash escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// AddProduct inserts the product with its images and, when newEvent is set,
+// the resulting outbox message in a single transaction.
+func (r *ProductRepository) AddProduct(product domain.Product, newEvent ports.ProductEventFactory) (domain.Product, error) {
 	ctx := context.Background()
 
 	tx, err := r.dbPool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
+		return domain.Product{}, fmt.Errorf("failed to start transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
 	productId, err := r.insertProduct(ctx, tx, product)
 	if err != nil {
-		_ = tx.Rollback(ctx)
-		return err
+		return domain.Product{}, err
 	}
+	product.Id = productId
 
 	if len(product.ImageUrls) > 0 {
 		if err := r.insertProductImages(ctx, tx, productId, product.ImageUrls); err != nil {
-			_ = tx.Rollback(ctx)
-			return err
+			return domain.Product{}, err
+		}
+	}
+
+	if newEvent != nil {
+		message, err := newEvent(product)
+		if err != nil {
+			return domain.Product{}, err
+		}
+		if err := outbox.Enqueue(ctx, tx, message); err != nil {
+			return domain.Product{}, err
 		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return domain.Product{}, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	return product, nil
 }
 
 func (r *ProductRepository) insertProduct(
@@ -113,7 +208,7 @@ func (r *ProductRepository) insertProduct(
 ) (int64, error) {
 	var productId int64
 	err := tx.QueryRow(ctx, `
-		INSERT INTO products (name, price, description, discount, store, category_id)
+		INSERT INTO products (name, price, description, discount, store_id, category_id)
 		VALUES ($1,$2,$3,$4,$5,$6)
 		RETURNING id
 	`,
@@ -121,7 +216,7 @@ func (r *ProductRepository) insertProduct(
 		product.Price,
 		product.Description,
 		product.Discount,
-		product.Store,
+		product.StoreID,
 		product.CategoryID,
 	).Scan(&productId)
 	if err != nil {
@@ -152,21 +247,22 @@ func (r *ProductRepository) GetById(productId int64) (domain.Product, error) {
 	ctx := context.Background()
 
 	var p domain.Product
-	err := r.dbPool.QueryRow(ctx, `
-		SELECT id, name, price, description, discount, store, category_id
-		FROM products WHERE id = $1
+	err := r.dbPool.QueryRow(ctx, selectProductColumns+`
+		WHERE p.id = $1
 	`, productId).Scan(
 		&p.Id,
 		&p.Name,
 		&p.Price,
 		&p.Description,
 		&p.Discount,
+		&p.StoreID,
+		&p.StoreSlug,
 		&p.Store,
 		&p.CategoryID,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return domain.Product{}, fmt.Errorf("product not found with id %d", productId)
+		return domain.Product{}, fmt.Errorf("%w with id %d", domain.ErrProductNotFound, productId)
 	}
 	if err != nil {
 		return domain.Product{}, err
@@ -176,54 +272,156 @@ func (r *ProductRepository) GetById(productId int64) (domain.Product, error) {
 	return p, nil
 }
 
-func (r *ProductRepository) DeleteById(productId int64) error {
+// DeleteById removes the product and, when newEvent is set, enqueues the
+// resulting outbox message in the same transaction.
+func (r *ProductRepository) DeleteById(productId int64, newEvent ports.ProductEventFactory) error {
 	ctx := context.Background()
 
-	ct, err := r.dbPool.Exec(ctx,
-		`DELETE FROM products WHERE id = $1`, productId)
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	deleted, err := r.deleteProducts(ctx, tx, `DELETE FROM products WHERE id = $1 RETURNING id, category_id`, productId)
 	if err != nil {
 		return err
 	}
-	if ct.RowsAffected() == 0 {
-		return fmt.Errorf("product not found")
+	if len(deleted) == 0 {
+		return fmt.Errorf("%w with id %d", domain.ErrProductNotFound, productId)
+	}
+	if err := r.enqueueEvents(ctx, tx, deleted, newEvent); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	log.Infof("✅ Product deleted with id %d", productId)
 	return nil
 }
 
-func (r *ProductRepository) DeleteAllProducts() error {
+// DeleteAllProducts removes every product and, when newEvent is set,
+// enqueues one outbox message per deleted product in the same transaction.
+func (r *ProductRepository) DeleteAllProducts(newEvent ports.ProductEventFactory) error {
 	ctx := context.Background()
 
-	_, err := r.dbPool.Exec(ctx, `DELETE FROM products`)
+	tx, err := r.dbPool.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	deleted, err := r.deleteProducts(ctx, tx, `DELETE FROM products RETURNING id, category_id`)
+	if err != nil {
+		return err
+	}
+	if err := r.enqueueEvents(ctx, tx, deleted, newEvent); err != nil {
 		return err
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	log.Info("✅ All products deleted (or already empty)")
 	return nil
 }
 
-func (r *ProductRepository) UpdatePrice(productId int64, newPrice float32) error {
+// UpdatePrice changes the price and, when newEvent is set, enqueues an
+// outbox message describing the updated product in the same transaction.
+func (r *ProductRepository) UpdatePrice(productId int64, newPrice float32, newEvent ports.ProductEventFactory) error {
 	ctx := context.Background()
 
-	_, err := r.dbPool.Exec(ctx,
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ct, err := tx.Exec(ctx,
 		`UPDATE products SET price = $1 WHERE id = $2`,
 		newPrice, productId,
 	)
 	if err != nil {
 		return err
 	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("%w with id %d", domain.ErrProductNotFound, productId)
+	}
 
+	if newEvent != nil {
+		var product domain.Product
+		err := tx.QueryRow(ctx, selectProductColumns+`
+			WHERE p.id = $1
+		`, productId).Scan(
+			&product.Id,
+			&product.Name,
+			&product.Price,
+			&product.Description,
+			&product.Discount,
+			&product.StoreID,
+			&product.StoreSlug,
+			&product.Store,
+			&product.CategoryID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to reload product: %w", err)
+		}
+		product.ImageUrls = r.loadImagesSafe(ctx, productId)
+		if err := r.enqueueEvents(ctx, tx, []domain.Product{product}, newEvent); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	log.Infof("✅ Product %d price updated to %v", productId, newPrice)
 	return nil
 }
 
+// deleteProducts runs a DELETE ... RETURNING id, category_id statement and
+// returns the removed products.
+func (r *ProductRepository) deleteProducts(ctx context.Context, tx pgx.Tx, query string, args ...interface{}) ([]domain.Product, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deleted []domain.Product
+	for rows.Next() {
+		var product domain.Product
+		if err := rows.Scan(&product.Id, &product.CategoryID); err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, product)
+	}
+	return deleted, rows.Err()
+}
+
+func (r *ProductRepository) enqueueEvents(ctx context.Context, tx pgx.Tx, products []domain.Product, newEvent ports.ProductEventFactory) error {
+	if newEvent == nil {
+		return nil
+	}
+	for _, product := range products {
+		message, err := newEvent(product)
+		if err != nil {
+			return err
+		}
+		if err := outbox.Enqueue(ctx, tx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *ProductRepository) GetProductsByCategoryId(categoryId int64) ([]domain.Product, error) {
 	ctx := context.Background()
 
-	rows, err := r.dbPool.Query(ctx, `
-		SELECT id, name, price, description, discount, store, category_id
-		FROM products WHERE category_id = $1
+	rows, err := r.dbPool.Query(ctx, selectProductColumns+`
+		WHERE p.category_id = $1
+		ORDER BY p.id
 	`, categoryId)
 
 	if err != nil {
@@ -241,6 +439,8 @@ func (r *ProductRepository) GetProductsByCategoryId(categoryId int64) ([]domain.
 			&p.Price,
 			&p.Description,
 			&p.Discount,
+			&p.StoreID,
+			&p.StoreSlug,
 			&p.Store,
 			&p.CategoryID,
 		); err != nil {
@@ -254,6 +454,21 @@ func (r *ProductRepository) GetProductsByCategoryId(categoryId int64) ([]domain.
 	return products, nil
 }
 
+func (r *ProductRepository) GetProductsByCategoryIds(categoryIds []int64) ([]domain.Product, error) {
+	ctx := context.Background()
+
+	rows, err := r.dbPool.Query(ctx, selectProductColumns+`
+		WHERE p.category_id = ANY($1)
+		ORDER BY p.id
+	`, categoryIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.extractProducts(ctx, rows)
+}
+
 func (r *ProductRepository) extractProducts(
 	ctx context.Context,
 	rows pgx.Rows,
@@ -269,6 +484,8 @@ func (r *ProductRepository) extractProducts(
 			&p.Price,
 			&p.Description,
 			&p.Discount,
+			&p.StoreID,
+			&p.StoreSlug,
 			&p.Store,
 			&p.CategoryID,
 		); err != nil {
@@ -0,0 +1,196 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/labstack/gommon/log"
+)
+
+type RecommendationRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewRecommendationRepository(dbPool *pgxpool.Pool) ports.RecommendationRepository {
+	return &RecommendationRepository{dbPool: dbPool}
+}
+
+// RecordPurchase folds a single order into the sales and co-purchase tables.
+// Orders that were already recorded are ignored so redelivered events do not
+// inflate the counts.
+func (r *RecommendationRepository) RecordPurchase(purchase domain.Purchase) error {
+	ctx := context.Background()
+
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ct, err := tx.Exec(ctx, `
+		INSERT INTO recommendation_processed_orders (order_id)
+		VALUES ($1)
+		ON CONFLICT (order_id) DO NOTHING
+	`, purchase.OrderId)
+	if err != nil {
+		return fmt.Errorf("failed to mark order %d as processed: %w", purchase.OrderId, err)
+	}
+	if ct.RowsAffected() == 0 {
+		log.Infof("Order %d already recorded for recommendations, skipping", purchase.OrderId)
+		return nil
+	}
+
+	for _, item := range purchase.Items {
+		if err := r.recordSale(ctx, tx, item); err != nil {
+			return err
+		}
+		if err := r.recordCustomerPurchase(ctx, tx, purchase.CustomerNumber, item.ProductId); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *RecommendationRepository) recordSale(ctx context.Context, tx pgx.Tx, item domain.PurchaseItem) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO product_sales (product_id, units_sold, order_count, updated_at)
+		VALUES ($1, $2, 1, NOW())
+		ON CONFLICT (product_id) DO UPDATE
+		SET units_sold = product_sales.units_sold + EXCLUDED.units_sold,
+		    order_count = product_sales.order_count + 1,
+		    updated_at = NOW()
+	`, item.ProductId, item.Quantity)
+	if err != nil {
+		return fmt.Errorf("failed to record sale for product %d: %w", item.ProductId, err)
+	}
+	return nil
+}
+
+// recordCustomerPurchase pairs the product with every other product the
+// customer has bought before. A customer contributes at most once to a pair.
+func (r *RecommendationRepository) recordCustomerPurchase(
+	ctx context.Context,
+	tx pgx.Tx,
+	customerNumber string,
+	productId int64,
+) error {
+	ct, err := tx.Exec(ctx, `
+		INSERT INTO customer_purchases (customer_number, product_id)
+		VALUES ($1, $2)
+		ON CONFLICT (customer_number, product_id) DO NOTHING
+	`, customerNumber, productId)
+	if err != nil {
+		return fmt.Errorf("failed to record customer purchase: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO product_co_purchases (product_id, related_product_id, co_purchase_count, updated_at)
+		SELECT pair.product_id, pair.related_product_id, 1, NOW()
+		FROM customer_purchases cp
+		CROSS JOIN LATERAL (
+			VALUES ($2::BIGINT, cp.product_id), (cp.product_id, $2::BIGINT)
+		) AS pair (product_id, related_product_id)
+		WHERE cp.customer_number = $1 AND cp.product_id <> $2
+		ON CONFLICT (product_id, related_product_id) DO UPDATE
+		SET co_purchase_count = product_co_purchases.co_purchase_count + 1,
+		    updated_at = NOW()
+	`, customerNumber, productId)
+	if err != nil {
+		return fmt.Errorf("failed to update co-purchases for product %d: %w", productId, err)
+	}
+	return nil
+}
+
+func (r *RecommendationRepository) GetFrequentlyBoughtTogether(productId int64, limit int) ([]domain.RelatedProduct, error) {
+	ctx := context.Background()
+
+	rows, err := r.dbPool.Query(ctx, `
+		SELECT p.id, p.name, p.price, p.description, p.discount, p.store_id, s.slug, s.name, p.category_id,
+		       ARRAY(SELECT image_url FROM product_images WHERE product_id = p.id ORDER BY id),
+		       cp.co_purchase_count, COALESCE(ps.units_sold, 0)
+		FROM product_co_purchases cp
+		JOIN products p ON p.id = cp.related_product_id
+		JOIN stores s ON s.id = p.store_id
+		LEFT JOIN product_sales ps ON ps.product_id = p.id
+		WHERE cp.product_id = $1
+		ORDER BY cp.co_purchase_count DESC, COALESCE(ps.units_sold, 0) DESC, p.id
+		LIMIT $2
+	`, productId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query co-purchases for product %d: %w", productId, err)
+	}
+	defer rows.Close()
+
+	return r.extractRelatedProducts(rows, domain.RecommendationReasonBoughtTogether)
+}
+
+func (r *RecommendationRepository) GetCategoryBestsellers(
+	categoryId int64,
+	excludeProductIds []int64,
+	limit int,
+) ([]domain.RelatedProduct, error) {
+	ctx := context.Background()
+
+	if excludeProductIds == nil {
+		excludeProductIds = []int64{}
+	}
+
+	rows, err := r.dbPool.Query(ctx, `
+		SELECT p.id, p.name, p.price, p.description, p.discount, p.store_id, s.slug, s.name, p.category_id,
+		       ARRAY(SELECT image_url FROM product_images WHERE product_id = p.id ORDER BY id),
+		       0, COALESCE(ps.units_sold, 0)
+		FROM products p
+		JOIN stores s ON s.id = p.store_id
+		LEFT JOIN product_sales ps ON ps.product_id = p.id
+		WHERE p.category_id = $1 AND NOT (p.id = ANY($2))
+		ORDER BY COALESCE(ps.units_sold, 0) DESC, p.id
+		LIMIT $3
+	`, categoryId, excludeProductIds, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bestsellers for category %d: %w", categoryId, err)
+	}
+	defer rows.Close()
+
+	return r.extractRelatedProducts(rows, domain.RecommendationReasonCategoryBestseller)
+}
+
+func (r *RecommendationRepository) extractRelatedProducts(rows pgx.Rows, reason string) ([]domain.RelatedProduct, error) {
+	var related []domain.RelatedProduct
+
+	for rows.Next() {
+		var rp domain.RelatedProduct
+		if err := rows.Scan(
+			&rp.Product.Id,
+			&rp.Product.Name,
+			&rp.Product.Price,
+			&rp.Product.Description,
+			&rp.Product.Discount,
+			&rp.Product.StoreID,
+			&rp.Product.StoreSlug,
+			&rp.Product.Store,
+			&rp.Product.CategoryID,
+			&rp.Product.ImageUrls,
+			&rp.CoPurchaseCount,
+			&rp.UnitsSold,
+		); err != nil {
+			return nil, err
+		}
+		if len(rp.Product.ImageUrls) == 0 {
+			rp.Product.ImageUrls = nil
+		}
+		rp.Reason = reason
+		related = append(related, rp)
+	}
+	return related, rows.Err()
+}
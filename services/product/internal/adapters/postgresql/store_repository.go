@@ -0,0 +1,174 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+)
+
+const selectStoreColumns = `
+	SELECT id, slug, name, COALESCE(owner_user_id, 0), COALESCE(contact_email, ''),
+	       COALESCE(contact_phone, ''), status, created_at, updated_at
+	FROM stores`
+
+type StoreRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewStoreRepository(dbPool *pgxpool.Pool) ports.StoreRepository {
+	return &StoreRepository{dbPool: dbPool}
+}
+
+func (r *StoreRepository) GetAllStores() ([]domain.Store, error) {
+	ctx := context.Background()
+
+	rows, err := r.dbPool.Query(ctx, selectStoreColumns+` ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting all stores: %w", err)
+	}
+	defer rows.Close()
+
+	stores := []domain.Store{}
+	for rows.Next() {
+		store, err := scanStore(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error while scanning store: %w", err)
+		}
+		stores = append(stores, store)
+	}
+	return stores, rows.Err()
+}
+
+func (r *StoreRepository) GetById(storeId int64) (domain.Store, error) {
+	ctx := context.Background()
+
+	store, err := scanStore(r.dbPool.QueryRow(ctx, selectStoreColumns+` WHERE id = $1`, storeId))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Store{}, fmt.Errorf("%w with id %d", domain.ErrStoreNotFound, storeId)
+	}
+	if err != nil {
+		return domain.Store{}, fmt.Errorf("error while getting store with id %d: %w", storeId, err)
+	}
+	return store, nil
+}
+
+func (r *StoreRepository) GetBySlug(slug string) (domain.Store, error) {
+	ctx := context.Background()
+
+	store, err := scanStore(r.dbPool.QueryRow(ctx, selectStoreColumns+` WHERE slug = $1`, slug))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Store{}, fmt.Errorf("%w with slug %s", domain.ErrStoreNotFound, slug)
+	}
+	if err != nil {
+		return domain.Store{}, fmt.Errorf("error while getting store with slug %s: %w", slug, err)
+	}
+	return store, nil
+}
+
+func (r *StoreRepository) AddStore(store domain.Store) (domain.Store, error) {
+	ctx := context.Background()
+
+	err := r.dbPool.QueryRow(ctx, `
+		INSERT INTO stores (slug, name, owner_user_id, contact_email, contact_phone, status, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, ''), NULLIF($5, ''), $6, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`,
+		store.Slug,
+		store.Name,
+		store.OwnerUserId,
+		store.ContactEmail,
+		store.ContactPhone,
+		store.Status,
+	).Scan(&store.Id, &store.CreatedAt, &store.UpdatedAt)
+	if isPgError(err, uniqueViolationCode) {
+		return domain.Store{}, fmt.Errorf("%w: %s", domain.ErrStoreSlugTaken, store.Slug)
+	}
+	if err != nil {
+		return domain.Store{}, fmt.Errorf("failed to insert store: %w", err)
+	}
+
+	log.Infof("✅ Store inserted with id %d", store.Id)
+	return store, nil
+}
+
+func (r *StoreRepository) UpdateStore(store domain.Store) (domain.Store, error) {
+	ctx := context.Background()
+
+	err := r.dbPool.QueryRow(ctx, `
+		UPDATE stores
+		SET slug = $1, name = $2, contact_email = NULLIF($3, ''), contact_phone = NULLIF($4, ''),
+		    status = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING created_at, updated_at
+	`,
+		store.Slug,
+		store.Name,
+		store.ContactEmail,
+		store.ContactPhone,
+		store.Status,
+		store.Id,
+	).Scan(&store.CreatedAt, &store.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Store{}, fmt.Errorf("%w with id %d", domain.ErrStoreNotFound, store.Id)
+	}
+	if isPgError(err, uniqueViolationCode) {
+		return domain.Store{}, fmt.Errorf("%w: %s", domain.ErrStoreSlugTaken, store.Slug)
+	}
+	if err != nil {
+		return domain.Store{}, fmt.Errorf("error while updating store with id %d: %w", store.Id, err)
+	}
+
+	log.Infof("✅ Store updated with id %d", store.Id)
+	return store, nil
+}
+
+func (r *StoreRepository) DeleteById(storeId int64) error {
+	ctx := context.Background()
+
+	ct, err := r.dbPool.Exec(ctx, `DELETE FROM stores WHERE id = $1`, storeId)
+	if isPgError(err, foreignKeyViolationCode) {
+		return fmt.Errorf("%w: store %d", domain.ErrStoreHasProducts, storeId)
+	}
+	if err != nil {
+		return fmt.Errorf("error while deleting store with id %d: %w", storeId, err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("%w with id %d", domain.ErrStoreNotFound, storeId)
+	}
+
+	log.Infof("✅ Store deleted with id %d", storeId)
+	return nil
+}
+
+func scanStore(row pgx.Row) (domain.Store, error) {
+	var store domain.Store
+	err := row.Scan(
+		&store.Id,
+		&store.Slug,
+		&store.Name,
+		&store.OwnerUserId,
+		&store.ContactEmail,
+		&store.ContactPhone,
+		&store.Status,
+		&store.CreatedAt,
+		&store.UpdatedAt,
+	)
+	return store, err
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
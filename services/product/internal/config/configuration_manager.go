@@ -1,59 +1,65 @@
 package config
 
 import (
-	"os"
-	"product-app/services/product/internal/adapters/postgresql/common"
-	"strconv"
 	"time"
+
+	postgresql "product-app/services/product/internal/adapters/postgresql/common"
+	sharedconfig "product-app/shared/config"
 )
 
 // ConfigurationManager holds application-level configurations.
 type ConfigurationManager struct {
-	// PostgreSqlConfig contains PostgreSQL related configuration values
-	PostgreSqlConfig postgresql.Config
-}
+	// Environment is APP_ENV; secrets may only use defaults in development
+	Environment string `env:"APP_ENV" yaml:"environment" default:"production"`
+	// HttpAddress is the listen address of the REST and GraphQL server
+	HttpAddress string `env:"HTTP_ADDRESS" yaml:"http_address" default:":8081" required:"true"`
+	// GrpcAddress is the listen address of the gRPC server
+	GrpcAddress string `env:"GRPC_ADDRESS" yaml:"grpc_address" default:":9081" required:"true"`
+	// CategoryServiceURL is the base URL of the category service used by GraphQL
+	CategoryServiceURL string `env:"CATEGORY_SERVICE_URL" yaml:"category_service_url" default:"http://category:8082" required:"true"`
+	// ProductEventsTopic receives the product change events
+	ProductEventsTopic string `env:"KAFKA_PRODUCT_EVENTS_TOPIC" yaml:"product_events_topic" default:"product.events" required:"true"`
+	// OrderEventsTopic is consumed to build recommendations
+	OrderEventsTopic string `env:"KAFKA_ORDER_EVENTS_TOPIC" yaml:"order_events_topic" default:"order.events" required:"true"`
+	// RecommendationsConsumerGroup is the consumer group reading OrderEventsTopic
+	RecommendationsConsumerGroup string `env:"KAFKA_RECOMMENDATIONS_GROUP" yaml:"recommendations_group" default:"product-service-recommendations" required:"true"`
+	// IdempotencyTTL is how long Idempotency-Key responses are replayed
+	IdempotencyTTL time.Duration `env:"IDEMPOTENCY_TTL" yaml:"idempotency_ttl" default:"24h"`
 
-// NewConfigurationManager creates and returns a new ConfigurationManager
-// with all required configurations initialized.
-func NewConfigurationManager() *ConfigurationManager {
-	postgreSqlConfig := getPostgreSqlConfig()
+	Database sharedconfig.Postgres `yaml:"database"`
+	Kafka    sharedconfig.Kafka    `yaml:"kafka"`
+	Auth     sharedconfig.Auth     `yaml:"auth"`
 
-	return &ConfigurationManager{
-		PostgreSqlConfig: postgreSqlConfig,
-	}
+	// PostgreSqlConfig contains PostgreSQL related configuration values
+	PostgreSqlConfig postgresql.Config `yaml:"-"`
 }
 
-// getPostgreSqlConfig returns PostgreSQL configuration values.
-// In a real-world scenario, these values are typically loaded
-// from environment variables or a configuration file.
-func getPostgreSqlConfig() postgresql.Config {
-	return postgresql.Config{
-		Host:                  getEnvString("DB_HOST", "localhost"),
-		Port:                  getEnvString("DB_PORT", "6432"),
-		UserName:              getEnvString("DB_USER", "postgres"),
-		Password:              getEnvString("DB_PASSWORD", "postgres"),
-		DbName:                getEnvString("DB_NAME", "productapp"),
-		MaxConnections:        int32(getEnvInt("DB_MAX_CONNECTIONS", 10)),
-		MaxConnectionIdleTime: time.Duration(getEnvInt("DB_MAX_IDLE_SECONDS", 30)) * time.Second,
+// NewConfigurationManager loads the configuration from defaults, the
+// optional CONFIG_FILE, environment variables and *_FILE secrets, and
+// returns an error describing every invalid or missing value.
+func NewConfigurationManager() (*ConfigurationManager, error) {
+	configurationManager := &ConfigurationManager{}
+	if err := sharedconfig.Load(configurationManager); err != nil {
+		return nil, err
 	}
+	configurationManager.PostgreSqlConfig = getPostgreSqlConfig(configurationManager.Database)
+	return configurationManager, nil
 }
 
-func getEnvString(key, fallback string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return fallback
-	}
-	return value
+// String prints the effective configuration with secrets redacted.
+func (configurationManager *ConfigurationManager) String() string {
+	return sharedconfig.Describe(configurationManager)
 }
 
-func getEnvInt(key string, fallback int) int {
-	raw := os.Getenv(key)
-	if raw == "" {
-		return fallback
-	}
-	parsed, err := strconv.Atoi(raw)
-	if err != nil {
-		return fallback
+// getPostgreSqlConfig maps the loaded database settings to the pool config.
+func getPostgreSqlConfig(database sharedconfig.Postgres) postgresql.Config {
+	return postgresql.Config{
+		Host:                  database.Host,
+		Port:                  database.Port,
+		UserName:              database.User,
+		Password:              database.Password,
+		DbName:                database.Name,
+		MaxConnections:        database.MaxConnections,
+		MaxConnectionIdleTime: database.MaxIdleTime,
 	}
-	return parsed
 }
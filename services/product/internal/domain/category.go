@@ -0,0 +1,8 @@
+package domain
+
+// Category mirrors the category service's resource as seen by this service.
+type Category struct {
+	Id          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
@@ -1,11 +1,20 @@
 package domain
 
+import "errors"
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrInvalidProduct  = errors.New("invalid product")
+)
+
 type Product struct {
 	Id          int64    `json:"id"`
 	Name        string   `json:"name"`
 	Price       float32  `json:"price"`
 	Description string   `json:"description"`
 	Discount    float32  `json:"discount"`
+	StoreID     int64    `json:"store_id"`
+	StoreSlug   string   `json:"store_slug"`
 	Store       string   `json:"store"`
 	ImageUrls   []string `json:"image_urls"`
 	CategoryID  int64    `json:"category_id"`
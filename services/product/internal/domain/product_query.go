@@ -0,0 +1,27 @@
+package domain
+
+import "errors"
+
+var ErrInvalidProductQuery = errors.New("invalid product query")
+
+// ProductQuery selects a page of products ordered by id. Zero-valued filters
+// match every product; MinPrice and MaxPrice are inclusive.
+type ProductQuery struct {
+	StoreSlug    string
+	CategoryId   int64
+	MinPrice     *float64
+	MaxPrice     *float64
+	NameContains string
+	// AfterId continues a previous page: only products with a larger id are
+	// returned.
+	AfterId int64
+	Limit   int
+}
+
+// ProductPage is one page of a query. NextCursor is empty on the last page;
+// TotalCount counts every product matching the filters.
+type ProductPage struct {
+	Products   []Product
+	NextCursor string
+	TotalCount int
+}
@@ -0,0 +1,32 @@
+package domain
+
+const (
+	// RecommendationReasonBoughtTogether marks products that share customers
+	// with the requested product.
+	RecommendationReasonBoughtTogether = "bought_together"
+	// RecommendationReasonCategoryBestseller marks fallback products picked
+	// from the best sellers of the requested product's category.
+	RecommendationReasonCategoryBestseller = "category_bestseller"
+)
+
+// RelatedProduct is a recommended product together with the signals that
+// explain why it was ranked.
+type RelatedProduct struct {
+	Product         Product `json:"product"`
+	CoPurchaseCount int64   `json:"co_purchase_count"`
+	UnitsSold       int64   `json:"units_sold"`
+	Reason          string  `json:"reason"`
+}
+
+// Purchase is the product service's view of a placed order, used to keep
+// the co-purchase and sales statistics up to date.
+type Purchase struct {
+	OrderId        int64
+	CustomerNumber string
+	Items          []PurchaseItem
+}
+
+type PurchaseItem struct {
+	ProductId int64
+	Quantity  int32
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	StoreStatusActive   = "active"
+	StoreStatusInactive = "inactive"
+	StoreStatusClosed   = "closed"
+)
+
+var (
+	ErrStoreNotFound    = errors.New("store not found")
+	ErrStoreSlugTaken   = errors.New("store slug already exists")
+	ErrStoreHasProducts = errors.New("store still has products")
+	ErrStoreForbidden   = errors.New("only the store owner can modify this store")
+	ErrInvalidStore     = errors.New("invalid store")
+)
+
+type Store struct {
+	Id           int64     `json:"id"`
+	Slug         string    `json:"slug"`
+	Name         string    `json:"name"`
+	OwnerUserId  int64     `json:"owner_user_id"`
+	ContactEmail string    `json:"contact_email"`
+	ContactPhone string    `json:"contact_phone"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// IsValidStoreStatus reports whether status is one of the known store statuses.
+func IsValidStoreStatus(status string) bool {
+	switch status {
+	case StoreStatusActive, StoreStatusInactive, StoreStatusClosed:
+		return true
+	}
+	return false
+}
+
+// Slugify lower-cases name and joins its letter and digit runs with dashes,
+// so "Tech Store" and "tech  store" both become "tech-store". The same rule
+// is used by the store migration in SQL.
+func Slugify(name string) string {
+	var builder strings.Builder
+	pendingDash := false
+
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingDash && builder.Len() > 0 {
+				builder.WriteRune('-')
+			}
+			builder.WriteRune(r)
+			pendingDash = false
+			continue
+		}
+		pendingDash = true
+	}
+	return builder.String()
+}
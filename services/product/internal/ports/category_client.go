@@ -0,0 +1,8 @@
+package ports
+
+import "product-app/services/product/internal/domain"
+
+type CategoryClient interface {
+	GetAllCategories() ([]domain.Category, error)
+	GetById(categoryId int64) (domain.Category, error)
+}
@@ -1,14 +1,24 @@
 package ports
 
-import "product-app/services/product/internal/domain"
+import (
+	"product-app/services/product/internal/domain"
+	"product-app/shared/outbox"
+)
+
+// ProductEventFactory builds the outbox message that is stored in the same
+// transaction as the product change it describes.
+type ProductEventFactory func(product domain.Product) (outbox.Message, error)
 
 type ProductRepository interface {
 	GetAllProducts() []domain.Product
 	GetProductsByCategoryId(categoryId int64) ([]domain.Product, error)
+	GetProductsByCategoryIds(categoryIds []int64) ([]domain.Product, error)
 	GetAllProductsByStore(storeName string) []domain.Product
-	AddProduct(product domain.Product) error
+	FindProducts(query domain.ProductQuery) ([]domain.Product, error)
+	CountProducts(query domain.ProductQuery) (int, error)
+	AddProduct(product domain.Product, newEvent ProductEventFactory) (domain.Product, error)
 	GetById(productId int64) (domain.Product, error)
-	DeleteById(productId int64) error
-	UpdatePrice(productId int64, newPrice float32) error
-	DeleteAllProducts() error
+	DeleteById(productId int64, newEvent ProductEventFactory) error
+	UpdatePrice(productId int64, newPrice float32, newEvent ProductEventFactory) error
+	DeleteAllProducts(newEvent ProductEventFactory) error
 }
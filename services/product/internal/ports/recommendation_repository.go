@@ -0,0 +1,9 @@
+package ports
+
+import "product-app/services/product/internal/domain"
+
+type RecommendationRepository interface {
+	RecordPurchase(purchase domain.Purchase) error
+	GetFrequentlyBoughtTogether(productId int64, limit int) ([]domain.RelatedProduct, error)
+	GetCategoryBestsellers(categoryId int64, excludeProductIds []int64, limit int) ([]domain.RelatedProduct, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "product-app/services/product/internal/domain"
+
+type StoreRepository interface {
+	GetAllStores() ([]domain.Store, error)
+	GetById(storeId int64) (domain.Store, error)
+	GetBySlug(slug string) (domain.Store, error)
+	AddStore(store domain.Store) (domain.Store, error)
+	UpdateStore(store domain.Store) (domain.Store, error)
+	DeleteById(storeId int64) error
+}
@@ -5,7 +5,41 @@ type ProductCreate struct {
 	Price       float32  `json:"price"`
 	Description string   `json:"description"`
 	Discount    float32  `json:"discount"`
+	StoreID     int64    `json:"store_id"`
 	Store       string   `json:"store"`
 	ImageUrls   []string `json:"image_urls"`
 	CategoryID  int64    `json:"category_id"`
 }
+
+// ProductSearch holds the filters and page of a product list request.
+// Cursor is the NextCursor of the previous page.
+type ProductSearch struct {
+	StoreSlug    string
+	CategoryId   int64
+	MinPrice     *float64
+	MaxPrice     *float64
+	NameContains string
+	Limit        int
+	Cursor       string
+}
+
+// Requester identifies the authenticated caller for ownership checks.
+type Requester struct {
+	UserId int64
+	Admin  bool
+}
+
+type StoreCreate struct {
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	ContactEmail string `json:"contact_email"`
+	ContactPhone string `json:"contact_phone"`
+}
+
+type StoreUpdate struct {
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	ContactEmail string `json:"contact_email"`
+	ContactPhone string `json:"contact_phone"`
+	Status       string `json:"status"`
+}
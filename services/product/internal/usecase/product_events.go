@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"product-app/services/product/internal/domain"
+	"product-app/shared/kafka"
+	"product-app/shared/outbox"
+)
+
+// eventSource identifies this service in event envelopes.
+const eventSource = "product-service"
+
+func (productService *ProductService) productCreatedEvent(product domain.Product) (outbox.Message, error) {
+	return outbox.NewEventMessage(productService.eventsTopic, eventSource, kafka.ProductCreated{
+		ID:          product.Id,
+		Name:        product.Name,
+		Price:       product.Price,
+		Description: product.Description,
+		Discount:    product.Discount,
+		StoreID:     product.StoreID,
+		StoreSlug:   product.StoreSlug,
+		Store:       product.Store,
+		ImageUrls:   product.ImageUrls,
+		CategoryID:  product.CategoryID,
+	})
+}
+
+func (productService *ProductService) productUpdatedEvent(product domain.Product) (outbox.Message, error) {
+	return outbox.NewEventMessage(productService.eventsTopic, eventSource, kafka.ProductUpdated{
+		ID:          product.Id,
+		Name:        product.Name,
+		Price:       product.Price,
+		Description: product.Description,
+		Discount:    product.Discount,
+		StoreID:     product.StoreID,
+		StoreSlug:   product.StoreSlug,
+		Store:       product.Store,
+		ImageUrls:   product.ImageUrls,
+		CategoryID:  product.CategoryID,
+	})
+}
+
+func (productService *ProductService) productDeletedEvent(product domain.Product) (outbox.Message, error) {
+	return outbox.NewEventMessage(productService.eventsTopic, eventSource, kafka.ProductDeleted{
+		ID:         product.Id,
+		CategoryID: product.CategoryID,
+	})
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase/model"
+)
+
+const (
+	// DefaultProductPageSize is the page size of product lists without a
+	// limit.
+	DefaultProductPageSize = 20
+	// MaxProductPageSize caps the limit of product lists.
+	MaxProductPageSize = 100
+)
+
+// FindProducts returns one page of the products matching search, in id
+// order, together with the number of products matching its filters.
+func (productService *ProductService) FindProducts(search model.ProductSearch) (domain.ProductPage, error) {
+	query, err := buildProductQuery(search)
+	if err != nil {
+		return domain.ProductPage{}, err
+	}
+	products, err := productService.productRepository.FindProducts(query)
+	if err != nil {
+		return domain.ProductPage{}, err
+	}
+	total, err := productService.productRepository.CountProducts(query)
+	if err != nil {
+		return domain.ProductPage{}, err
+	}
+	page := productPage(products, query)
+	page.TotalCount = total
+	return page, nil
+}
+
+// buildProductQuery validates search and turns it into a repository query
+// that reads one product more than the page size, to tell whether another
+// page follows.
+func buildProductQuery(search model.ProductSearch) (domain.ProductQuery, error) {
+	query := domain.ProductQuery{
+		StoreSlug:    domain.Slugify(search.StoreSlug),
+		CategoryId:   search.CategoryId,
+		MinPrice:     search.MinPrice,
+		MaxPrice:     search.MaxPrice,
+		NameContains: search.NameContains,
+		Limit:        search.Limit,
+	}
+	if query.CategoryId < 0 {
+		return domain.ProductQuery{}, fmt.Errorf("%w: category id must be a positive integer", domain.ErrInvalidProductQuery)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultProductPageSize
+	}
+	if query.Limit < 0 || query.Limit > MaxProductPageSize {
+		return domain.ProductQuery{}, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidProductQuery, MaxProductPageSize)
+	}
+	query.Limit++
+
+	if search.Cursor != "" {
+		afterId, err := decodeProductCursor(search.Cursor)
+		if err != nil {
+			return domain.ProductQuery{}, fmt.Errorf("%w: invalid cursor", domain.ErrInvalidProductQuery)
+		}
+		query.AfterId = afterId
+	}
+	return query, nil
+}
+
+// productPage trims the extra product read by a query built with
+// buildProductQuery and issues the cursor of the next page if there is one.
+func productPage(products []domain.Product, query domain.ProductQuery) domain.ProductPage {
+	if products == nil {
+		products = []domain.Product{}
+	}
+	pageSize := query.Limit - 1
+	if len(products) <= pageSize {
+		return domain.ProductPage{Products: products}
+	}
+	products = products[:pageSize]
+	return domain.ProductPage{
+		Products:   products,
+		NextCursor: encodeProductCursor(products[len(products)-1].Id),
+	}
+}
+
+func encodeProductCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeProductCursor(encoded string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid product cursor %q", encoded)
+	}
+	return id, nil
+}
@@ -1,8 +1,8 @@
 package usecase
 
 import (
-	"context"
 	"errors"
+	"fmt"
 	"product-app/services/product/internal/domain"
 	"product-app/services/product/internal/ports"
 	"product-app/services/product/internal/usecase/model"
@@ -11,68 +11,80 @@ import (
 
 type IProductService interface {
 	GetProductsByCategoryId(categoryId int64) ([]domain.Product, error)
-	Add(productCreate model.ProductCreate) error
+	GetProductsByCategoryIds(categoryIds []int64) (map[int64][]domain.Product, error)
+	Add(productCreate model.ProductCreate) (domain.Product, error)
 	DeleteById(productId int64) error
 	GetById(productId int64) (domain.Product, error)
 	UpdatePrice(productId int64, newPrice float32) error
 	GetAllProducts() []domain.Product
 	GetAllProductsByStore(storeName string) []domain.Product
+	FindProducts(search model.ProductSearch) (domain.ProductPage, error)
 	DeleteAllProducts() error
 }
 
 type ProductService struct {
 	productRepository ports.ProductRepository
-	eventPublisher    ports.EventPublisher
+	storeRepository   ports.StoreRepository
+	eventsTopic       string
 }
 
-func NewProductService(productRepository ports.ProductRepository, eventPublisher ports.EventPublisher) IProductService {
+// NewProductService returns a product service whose change events are
+// written to the outbox for eventsTopic.
+func NewProductService(
+	productRepository ports.ProductRepository,
+	storeRepository ports.StoreRepository,
+	eventsTopic string,
+) IProductService {
 	return &ProductService{
 		productRepository: productRepository,
-		eventPublisher:    eventPublisher,
+		storeRepository:   storeRepository,
+		eventsTopic:       eventsTopic,
 	}
 }
-func (productService *ProductService) Add(productCreate model.ProductCreate) error {
+func (productService *ProductService) Add(productCreate model.ProductCreate) (domain.Product, error) {
 	validateError := validateProductCreate(productCreate)
 	if validateError != nil {
-		return validateError
+		return domain.Product{}, fmt.Errorf("%w: %v", domain.ErrInvalidProduct, validateError)
+	}
+	store, err := productService.resolveStore(productCreate)
+	if err != nil {
+		return domain.Product{}, err
 	}
 	newProduct := domain.Product{
 		Name:        productCreate.Name,
 		Price:       productCreate.Price,
 		Description: productCreate.Description,
 		Discount:    productCreate.Discount,
-		Store:       productCreate.Store,
+		StoreID:     store.Id,
+		StoreSlug:   store.Slug,
+		Store:       store.Name,
 		ImageUrls:   productCreate.ImageUrls,
 		CategoryID:  productCreate.CategoryID,
 	}
-	if err := productService.productRepository.AddProduct(newProduct); err != nil {
-		return err
-	}
-	if productService.eventPublisher != nil {
-		_ = productService.eventPublisher.Publish(context.Background(), "product.created", newProduct)
-	}
-	return nil
+	return productService.productRepository.AddProduct(newProduct, productService.productCreatedEvent)
 
 }
 func (productService *ProductService) DeleteById(productId int64) error {
-	return productService.productRepository.DeleteById(productId)
+	return productService.productRepository.DeleteById(productId, productService.productDeletedEvent)
 }
 func (productService *ProductService) GetById(productId int64) (domain.Product, error) {
 	return productService.productRepository.GetById(productId)
 }
 func (productService *ProductService) UpdatePrice(productId int64, newPrice float32) error {
-	return productService.productRepository.UpdatePrice(productId, newPrice)
+	return productService.productRepository.UpdatePrice(productId, newPrice, productService.productUpdatedEvent)
 }
 func (productService *ProductService) GetAllProducts() []domain.Product {
 	return productService.productRepository.GetAllProducts()
 }
 
-func (productService *ProductService) GetAllProductsByStore(storeName string) []domain.Product {
-	return productService.productRepository.GetAllProductsByStore(storeName)
+// GetAllProductsByStore filters by store slug. Display names are accepted
+// as well and normalised to their slug.
+func (productService *ProductService) GetAllProductsByStore(storeSlug string) []domain.Product {
+	return productService.productRepository.GetAllProductsByStore(domain.Slugify(storeSlug))
 }
 
 func (productService *ProductService) DeleteAllProducts() error {
-	return productService.productRepository.DeleteAllProducts()
+	return productService.productRepository.DeleteAllProducts(productService.productDeletedEvent)
 }
 
 func (productService *ProductService) GetProductsByCategoryId(categoryId int64) ([]domain.Product, error) {
@@ -82,6 +94,23 @@ func (productService *ProductService) GetProductsByCategoryId(categoryId int64)
 	return productService.productRepository.GetProductsByCategoryId(categoryId)
 }
 
+// GetProductsByCategoryIds loads the products of several categories with a
+// single repository call, grouped by category id.
+func (productService *ProductService) GetProductsByCategoryIds(categoryIds []int64) (map[int64][]domain.Product, error) {
+	grouped := make(map[int64][]domain.Product, len(categoryIds))
+	if len(categoryIds) == 0 {
+		return grouped, nil
+	}
+	products, err := productService.productRepository.GetProductsByCategoryIds(categoryIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range products {
+		grouped[product.CategoryID] = append(grouped[product.CategoryID], product)
+	}
+	return grouped, nil
+}
+
 func validateProductCreate(productCreate model.ProductCreate) error {
 	if err := validateNameWithRegex(productCreate.Name, "product name is required"); err != nil {
 		return err
@@ -91,8 +120,8 @@ func validateProductCreate(productCreate model.ProductCreate) error {
 		return errors.New("product price must be greater than zero")
 	}
 
-	if err := validateNameWithRegex(productCreate.Store, "store name is required"); err != nil {
-		return err
+	if productCreate.StoreID <= 0 && productCreate.Store == "" {
+		return errors.New("store is required")
 	}
 
 	if productCreate.Discount < 0 || productCreate.Discount > 70 {
@@ -102,6 +131,25 @@ func validateProductCreate(productCreate model.ProductCreate) error {
 	return nil
 }
 
+// resolveStore finds the store referenced by store_id, falling back to the
+// legacy store field interpreted as a slug or display name.
+func (productService *ProductService) resolveStore(productCreate model.ProductCreate) (domain.Store, error) {
+	var store domain.Store
+	var err error
+	if productCreate.StoreID > 0 {
+		store, err = productService.storeRepository.GetById(productCreate.StoreID)
+	} else {
+		store, err = productService.storeRepository.GetBySlug(domain.Slugify(productCreate.Store))
+	}
+	if err != nil {
+		return domain.Store{}, err
+	}
+	if store.Status != domain.StoreStatusActive {
+		return domain.Store{}, fmt.Errorf("%w: store %s is not active", domain.ErrInvalidProduct, store.Slug)
+	}
+	return store, nil
+}
+
 func validateNameWithRegex(name string, errorMessage string) error {
 	if name == "" {
 		return errors.New(errorMessage)
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRelatedProductsLimit = 10
+	MaxRelatedProductsLimit     = 50
+	// MaxCachedRelatedProducts caps the entries of the related products
+	// cache; beyond it the least recently used entry is evicted.
+	MaxCachedRelatedProducts = 1000
+)
+
+type IRecommendationService interface {
+	GetRelatedProducts(productId int64, limit int) ([]domain.RelatedProduct, error)
+	RecordPurchase(purchase domain.Purchase) error
+}
+
+type RecommendationService struct {
+	recommendationRepository ports.RecommendationRepository
+	productRepository        ports.ProductRepository
+	cache                    *relatedProductsCache
+}
+
+// NewRecommendationService creates a recommendation service whose results are
+// cached for cacheTTL. A zero TTL disables caching.
+func NewRecommendationService(
+	recommendationRepository ports.RecommendationRepository,
+	productRepository ports.ProductRepository,
+	cacheTTL time.Duration,
+) IRecommendationService {
+	return &RecommendationService{
+		recommendationRepository: recommendationRepository,
+		productRepository:        productRepository,
+		cache:                    newRelatedProductsCache(cacheTTL),
+	}
+}
+
+// GetRelatedProducts returns products frequently bought together with the
+// given product, topped up with best sellers from the same category.
+func (recommendationService *RecommendationService) GetRelatedProducts(productId int64, limit int) ([]domain.RelatedProduct, error) {
+	if productId <= 0 {
+		return nil, errors.New("product ID must be a positive integer")
+	}
+	if limit <= 0 || limit > MaxRelatedProductsLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxRelatedProductsLimit)
+	}
+
+	if cached, ok := recommendationService.cache.get(productId, limit); ok {
+		return cached, nil
+	}
+
+	product, err := recommendationService.productRepository.GetById(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	related, err := recommendationService.recommendationRepository.GetFrequentlyBoughtTogether(productId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(related) < limit && product.CategoryID > 0 {
+		excluded := []int64{productId}
+		for _, relatedProduct := range related {
+			excluded = append(excluded, relatedProduct.Product.Id)
+		}
+		bestsellers, err := recommendationService.recommendationRepository.GetCategoryBestsellers(
+			product.CategoryID, excluded, limit-len(related))
+		if err != nil {
+			return nil, err
+		}
+		related = append(related, bestsellers...)
+	}
+
+	if related == nil {
+		related = []domain.RelatedProduct{}
+	}
+	recommendationService.cache.put(productId, limit, related)
+	return related, nil
+}
+
+func (recommendationService *RecommendationService) RecordPurchase(purchase domain.Purchase) error {
+	if purchase.OrderId <= 0 {
+		return errors.New("order ID must be a positive integer")
+	}
+	if purchase.CustomerNumber == "" {
+		return errors.New("customer number is required")
+	}
+	if len(purchase.Items) == 0 {
+		return errors.New("purchase must contain at least one item")
+	}
+	return recommendationService.recommendationRepository.RecordPurchase(purchase)
+}
+
+type relatedProductsCacheKey struct {
+	productId int64
+	limit     int
+}
+
+type relatedProductsCacheEntry struct {
+	key       relatedProductsCacheKey
+	related   []domain.RelatedProduct
+	expiresAt time.Time
+}
+
+// relatedProductsCache is a small in-memory TTL cache holding at most
+// MaxCachedRelatedProducts entries. Every product id and limit is a key of
+// its own, so the least recently used entry is evicted when it is full;
+// expired entries are dropped when read or evicted.
+type relatedProductsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	recency *list.List
+	entries map[relatedProductsCacheKey]*list.Element
+}
+
+func newRelatedProductsCache(ttl time.Duration) *relatedProductsCache {
+	return &relatedProductsCache{
+		ttl:     ttl,
+		recency: list.New(),
+		entries: make(map[relatedProductsCacheKey]*list.Element),
+	}
+}
+
+func (cache *relatedProductsCache) get(productId int64, limit int) ([]domain.RelatedProduct, bool) {
+	if cache.ttl <= 0 {
+		return nil, false
+	}
+	key := relatedProductsCacheKey{productId: productId, limit: limit}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*relatedProductsCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		cache.recency.Remove(element)
+		delete(cache.entries, key)
+		return nil, false
+	}
+	cache.recency.MoveToFront(element)
+	return entry.related, true
+}
+
+func (cache *relatedProductsCache) put(productId int64, limit int, related []domain.RelatedProduct) {
+	if cache.ttl <= 0 {
+		return
+	}
+	key := relatedProductsCacheKey{productId: productId, limit: limit}
+	entry := &relatedProductsCacheEntry{key: key, related: related, expiresAt: time.Now().Add(cache.ttl)}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if element, ok := cache.entries[key]; ok {
+		element.Value = entry
+		cache.recency.MoveToFront(element)
+		return
+	}
+	cache.entries[key] = cache.recency.PushFront(entry)
+	for cache.recency.Len() > MaxCachedRelatedProducts {
+		oldest := cache.recency.Back()
+		cache.recency.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*relatedProductsCacheEntry).key)
+	}
+}
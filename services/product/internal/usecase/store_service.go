@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+	"product-app/services/product/internal/usecase/model"
+	"regexp"
+)
+
+var (
+	storeSlugRegex    = regexp.MustCompile(`^[\p{Ll}\p{N}]+(-[\p{Ll}\p{N}]+)*$`)
+	storeEmailRegex   = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	storePhoneRegex   = regexp.MustCompile(`^\+?[0-9 ()-]{6,20}$`)
+	errStoreSlugEmpty = errors.New("store slug must contain at least one letter or digit")
+)
+
+type IStoreService interface {
+	GetAllStores() ([]domain.Store, error)
+	GetById(storeId int64) (domain.Store, error)
+	GetBySlug(slug string) (domain.Store, error)
+	Add(storeCreate model.StoreCreate, ownerUserId int64) (domain.Store, error)
+	Update(storeId int64, storeUpdate model.StoreUpdate, requester model.Requester) (domain.Store, error)
+	DeleteById(storeId int64, requester model.Requester) error
+}
+
+type StoreService struct {
+	storeRepository ports.StoreRepository
+}
+
+func NewStoreService(storeRepository ports.StoreRepository) IStoreService {
+	return &StoreService{
+		storeRepository: storeRepository,
+	}
+}
+
+func (storeService *StoreService) GetAllStores() ([]domain.Store, error) {
+	return storeService.storeRepository.GetAllStores()
+}
+
+func (storeService *StoreService) GetById(storeId int64) (domain.Store, error) {
+	return storeService.storeRepository.GetById(storeId)
+}
+
+// GetBySlug normalises the given value before the lookup, so display names
+// such as "Tech Store" resolve to the "tech-store" slug.
+func (storeService *StoreService) GetBySlug(slug string) (domain.Store, error) {
+	return storeService.storeRepository.GetBySlug(domain.Slugify(slug))
+}
+
+func (storeService *StoreService) Add(storeCreate model.StoreCreate, ownerUserId int64) (domain.Store, error) {
+	store := domain.Store{
+		Slug:         storeCreate.Slug,
+		Name:         storeCreate.Name,
+		OwnerUserId:  ownerUserId,
+		ContactEmail: storeCreate.ContactEmail,
+		ContactPhone: storeCreate.ContactPhone,
+		Status:       domain.StoreStatusActive,
+	}
+	if store.Slug == "" {
+		store.Slug = domain.Slugify(store.Name)
+	}
+	if err := validateStore(store); err != nil {
+		return domain.Store{}, fmt.Errorf("%w: %v", domain.ErrInvalidStore, err)
+	}
+	return storeService.storeRepository.AddStore(store)
+}
+
+func (storeService *StoreService) Update(storeId int64, storeUpdate model.StoreUpdate, requester model.Requester) (domain.Store, error) {
+	store, err := storeService.getOwnedStore(storeId, requester)
+	if err != nil {
+		return domain.Store{}, err
+	}
+
+	store.Name = storeUpdate.Name
+	store.ContactEmail = storeUpdate.ContactEmail
+	store.ContactPhone = storeUpdate.ContactPhone
+	if storeUpdate.Slug != "" {
+		store.Slug = storeUpdate.Slug
+	}
+	if storeUpdate.Status != "" {
+		store.Status = storeUpdate.Status
+	}
+	if err := validateStore(store); err != nil {
+		return domain.Store{}, fmt.Errorf("%w: %v", domain.ErrInvalidStore, err)
+	}
+	return storeService.storeRepository.UpdateStore(store)
+}
+
+func (storeService *StoreService) DeleteById(storeId int64, requester model.Requester) error {
+	if _, err := storeService.getOwnedStore(storeId, requester); err != nil {
+		return err
+	}
+	return storeService.storeRepository.DeleteById(storeId)
+}
+
+// getOwnedStore loads the store and checks that the requester may modify it.
+// Admins may modify every store. Stores migrated from the old free-text
+// column have no owner, so only admins may maintain them.
+func (storeService *StoreService) getOwnedStore(storeId int64, requester model.Requester) (domain.Store, error) {
+	store, err := storeService.storeRepository.GetById(storeId)
+	if err != nil {
+		return domain.Store{}, err
+	}
+	if !requester.Admin && (store.OwnerUserId == 0 || store.OwnerUserId != requester.UserId) {
+		return domain.Store{}, domain.ErrStoreForbidden
+	}
+	return store, nil
+}
+
+func validateStore(store domain.Store) error {
+	if err := validateNameWithRegex(store.Name, "store name is required"); err != nil {
+		return err
+	}
+	if store.Slug == "" {
+		return errStoreSlugEmpty
+	}
+	if !storeSlugRegex.MatchString(store.Slug) {
+		return errors.New("store slug may only contain lower-case letters, digits and single dashes")
+	}
+	if store.ContactEmail != "" && !storeEmailRegex.MatchString(store.ContactEmail) {
+		return errors.New("invalid contact email format")
+	}
+	if store.ContactPhone != "" && !storePhoneRegex.MatchString(store.ContactPhone) {
+		return errors.New("invalid contact phone format")
+	}
+	if !domain.IsValidStoreStatus(store.Status) {
+		return errors.New("store status must be one of active, inactive or closed")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+-- Order events already applied to the recommendation tables, so that a
+-- redelivered event is not counted twice.
+CREATE TABLE IF NOT EXISTS recommendation_processed_orders (
+  order_id BIGINT PRIMARY KEY,
+  processed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Distinct products each customer has bought.
+CREATE TABLE IF NOT EXISTS customer_purchases (
+  customer_number VARCHAR(255) NOT NULL,
+  product_id BIGINT NOT NULL,
+  first_purchased_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (customer_number, product_id)
+);
+
+-- Number of customers that bought both products. Rows are stored in both
+-- directions so lookups only need the leading product_id.
+CREATE TABLE IF NOT EXISTS product_co_purchases (
+  product_id BIGINT NOT NULL,
+  related_product_id BIGINT NOT NULL,
+  co_purchase_count BIGINT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (product_id, related_product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_co_purchases_rank
+  ON product_co_purchases (product_id, co_purchase_count DESC);
+
+CREATE TABLE IF NOT EXISTS product_sales (
+  product_id BIGINT PRIMARY KEY,
+  units_sold BIGINT NOT NULL DEFAULT 0,
+  order_count BIGINT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
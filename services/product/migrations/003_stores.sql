@@ -0,0 +1,63 @@
+CREATE TABLE IF NOT EXISTS stores (
+  id BIGSERIAL PRIMARY KEY,
+  slug VARCHAR(255) NOT NULL UNIQUE,
+  name VARCHAR(255) NOT NULL,
+  owner_user_id BIGINT,
+  contact_email VARCHAR(255),
+  contact_phone VARCHAR(50),
+  status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'inactive', 'closed')),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS store_id BIGINT REFERENCES stores(id);
+
+CREATE INDEX IF NOT EXISTS idx_products_store_id ON products (store_id);
+
+-- Move the free-text store names into the stores table. Names that only
+-- differ in case, spacing or punctuation collapse into a single slug
+-- (same rule as domain.Slugify); the first name seen becomes the display name.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM information_schema.columns
+    WHERE table_name = 'products' AND column_name = 'store'
+  ) THEN
+    INSERT INTO stores (slug, name)
+    SELECT DISTINCT ON (slug) slug, name
+    FROM (
+      SELECT
+        trim(BOTH '-' FROM regexp_replace(lower(trim(store)), '[^[:alnum:]]+', '-', 'g')) AS slug,
+        trim(store) AS name,
+        id
+      FROM products
+      WHERE store_id IS NULL
+    ) legacy
+    WHERE slug <> ''
+    ORDER BY slug, id
+    ON CONFLICT (slug) DO NOTHING;
+
+    UPDATE products p
+    SET store_id = s.id
+    FROM stores s
+    WHERE p.store_id IS NULL
+      AND s.slug = trim(BOTH '-' FROM regexp_replace(lower(trim(p.store)), '[^[:alnum:]]+', '-', 'g'));
+
+    -- Names without a letter or digit (empty, "--", ...) have no slug; keep
+    -- those products under a fallback store so store_id can become NOT NULL.
+    IF EXISTS (SELECT 1 FROM products WHERE store_id IS NULL) THEN
+      INSERT INTO stores (slug, name)
+      VALUES ('unknown-store', 'Unknown store')
+      ON CONFLICT (slug) DO NOTHING;
+
+      UPDATE products
+      SET store_id = (SELECT id FROM stores WHERE slug = 'unknown-store')
+      WHERE store_id IS NULL;
+    END IF;
+
+    ALTER TABLE products DROP COLUMN store;
+  END IF;
+END $$;
+
+ALTER TABLE products ALTER COLUMN store_id SET NOT NULL;
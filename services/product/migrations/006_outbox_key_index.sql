@@ -0,0 +1,3 @@
+-- Lets the relay find earlier pending rows with the same key, which hold
+-- back later ones while they wait for a retry.
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_key ON outbox (topic, message_key, id) WHERE sent_at IS NULL;
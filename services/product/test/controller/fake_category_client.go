@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"fmt"
+
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+)
+
+type FakeCategoryClient struct {
+	categories []domain.Category
+	calls      int
+}
+
+func NewFakeCategoryClient(categories []domain.Category) *FakeCategoryClient {
+	return &FakeCategoryClient{categories: categories}
+}
+
+var _ ports.CategoryClient = (*FakeCategoryClient)(nil)
+
+func (fakeClient *FakeCategoryClient) GetAllCategories() ([]domain.Category, error) {
+	fakeClient.calls++
+	return fakeClient.categories, nil
+}
+
+func (fakeClient *FakeCategoryClient) GetById(categoryId int64) (domain.Category, error) {
+	fakeClient.calls++
+	for _, category := range fakeClient.categories {
+		if category.Id == categoryId {
+			return category, nil
+		}
+	}
+	return domain.Category{}, fmt.Errorf("category with id %d not found", categoryId)
+}
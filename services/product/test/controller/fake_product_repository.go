@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"product-app/services/product/internal/domain"
 	"product-app/services/product/internal/ports"
+	"product-app/shared/outbox"
+	"strings"
 )
 
 type FakeProductRepository struct {
 	products []domain.Product
+	events   []outbox.Message
 }
 
 func NewFakeProductRepository(initialProducts []domain.Product) ports.ProductRepository {
@@ -17,6 +20,20 @@ func NewFakeProductRepository(initialProducts []domain.Product) ports.ProductRep
 	}
 }
 
+func (fakeRepository *FakeProductRepository) GetProductsByCategoryIds(categoryIds []int64) ([]domain.Product, error) {
+	wanted := map[int64]bool{}
+	for _, categoryId := range categoryIds {
+		wanted[categoryId] = true
+	}
+	var products []domain.Product
+	for _, product := range fakeRepository.products {
+		if wanted[product.CategoryID] {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
 func (fakeRepository *FakeProductRepository) GetAllProducts() []domain.Product {
 	return fakeRepository.products
 }
@@ -34,28 +51,76 @@ func (fakeRepository *FakeProductRepository) GetProductsByCategoryId(categoryId
 	return productsByCategory, nil
 }
 
-func (fakeRepository *FakeProductRepository) GetAllProductsByStore(storeName string) []domain.Product {
+func (fakeRepository *FakeProductRepository) GetAllProductsByStore(storeSlug string) []domain.Product {
 	var productsByStore []domain.Product
 	for _, product := range fakeRepository.products {
-		if product.Store == storeName {
+		if product.StoreSlug == storeSlug {
 			productsByStore = append(productsByStore, product)
 		}
 	}
 	return productsByStore
 }
 
-func (fakeRepository *FakeProductRepository) AddProduct(product domain.Product) error {
-	fakeRepository.products = append(fakeRepository.products, domain.Product{
+func (fakeRepository *FakeProductRepository) FindProducts(query domain.ProductQuery) ([]domain.Product, error) {
+	var products []domain.Product
+	for _, product := range fakeRepository.matching(query) {
+		if product.Id > query.AfterId && len(products) < query.Limit {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (fakeRepository *FakeProductRepository) CountProducts(query domain.ProductQuery) (int, error) {
+	return len(fakeRepository.matching(query)), nil
+}
+
+func (fakeRepository *FakeProductRepository) matching(query domain.ProductQuery) []domain.Product {
+	var products []domain.Product
+	for _, product := range fakeRepository.products {
+		if query.StoreSlug != "" && product.StoreSlug != query.StoreSlug {
+			continue
+		}
+		if query.CategoryId != 0 && product.CategoryID != query.CategoryId {
+			continue
+		}
+		if query.MinPrice != nil && float64(product.Price) < *query.MinPrice {
+			continue
+		}
+		if query.MaxPrice != nil && float64(product.Price) > *query.MaxPrice {
+			continue
+		}
+		if query.NameContains != "" &&
+			!strings.Contains(strings.ToLower(product.Name), strings.ToLower(query.NameContains)) {
+			continue
+		}
+		products = append(products, product)
+	}
+	return products
+}
+
+func (fakeRepository *FakeProductRepository) AddProduct(product domain.Product, newEvent ports.ProductEventFactory) (domain.Product, error) {
+	stored := domain.Product{
 		Id:          int64(len(fakeRepository.products)) + 1,
 		Name:        product.Name,
 		Price:       product.Price,
 		Description: product.Description,
 		Discount:    product.Discount,
+		StoreID:     product.StoreID,
+		StoreSlug:   product.StoreSlug,
 		Store:       product.Store,
 		ImageUrls:   product.ImageUrls,
 		CategoryID:  product.CategoryID,
-	})
-	return nil
+	}
+	fakeRepository.products = append(fakeRepository.products, stored)
+	if newEvent != nil {
+		message, err := newEvent(stored)
+		if err != nil {
+			return domain.Product{}, err
+		}
+		fakeRepository.events = append(fakeRepository.events, message)
+	}
+	return stored, nil
 }
 
 func (fakeRepository *FakeProductRepository) GetById(productId int64) (domain.Product, error) {
@@ -64,10 +129,10 @@ func (fakeRepository *FakeProductRepository) GetById(productId int64) (domain.Pr
 			return product, nil
 		}
 	}
-	return domain.Product{}, errors.New(fmt.Sprintf("Product not found with id %d", productId))
+	return domain.Product{}, fmt.Errorf("%w with id %d", domain.ErrProductNotFound, productId)
 }
 
-func (fakeRepository *FakeProductRepository) DeleteById(productId int64) error {
+func (fakeRepository *FakeProductRepository) DeleteById(productId int64, newEvent ports.ProductEventFactory) error {
 	foundIndex := -1
 	for i, product := range fakeRepository.products {
 		if product.Id == productId {
@@ -77,29 +142,44 @@ func (fakeRepository *FakeProductRepository) DeleteById(productId int64) error {
 	}
 
 	if foundIndex == -1 {
-		return errors.New(fmt.Sprintf("Product not found with id %d", productId))
+		return fmt.Errorf("%w with id %d", domain.ErrProductNotFound, productId)
+	}
+	if err := fakeRepository.record(fakeRepository.products[foundIndex], newEvent); err != nil {
+		return err
 	}
 
 	fakeRepository.products = append(fakeRepository.products[:foundIndex], fakeRepository.products[foundIndex+1:]...)
 	return nil
 }
 
-func (fakeRepository *FakeProductRepository) UpdatePrice(productId int64, newPrice float32) error {
-	found := false
+func (fakeRepository *FakeProductRepository) UpdatePrice(productId int64, newPrice float32, newEvent ports.ProductEventFactory) error {
 	for i, product := range fakeRepository.products {
 		if product.Id == productId {
 			fakeRepository.products[i].Price = newPrice
-			found = true
-			break
+			return fakeRepository.record(fakeRepository.products[i], newEvent)
 		}
 	}
-	if !found {
-		return errors.New(fmt.Sprintf("Product not found with id %d", productId))
+	return fmt.Errorf("%w with id %d", domain.ErrProductNotFound, productId)
+}
+
+func (fakeRepository *FakeProductRepository) record(product domain.Product, newEvent ports.ProductEventFactory) error {
+	if newEvent == nil {
+		return nil
+	}
+	message, err := newEvent(product)
+	if err != nil {
+		return err
 	}
+	fakeRepository.events = append(fakeRepository.events, message)
 	return nil
 }
 
-func (fakeRepository *FakeProductRepository) DeleteAllProducts() error {
+func (fakeRepository *FakeProductRepository) DeleteAllProducts(newEvent ports.ProductEventFactory) error {
+	for _, product := range fakeRepository.products {
+		if err := fakeRepository.record(product, newEvent); err != nil {
+			return err
+		}
+	}
 	fakeRepository.products = []domain.Product{}
 	return nil
 }
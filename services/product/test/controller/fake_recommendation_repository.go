@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+)
+
+type FakeRecommendationRepository struct {
+	boughtTogether map[int64][]domain.RelatedProduct
+}
+
+func NewFakeRecommendationRepository(boughtTogether map[int64][]domain.RelatedProduct) ports.RecommendationRepository {
+	return &FakeRecommendationRepository{boughtTogether: boughtTogether}
+}
+
+func (fakeRepository *FakeRecommendationRepository) RecordPurchase(purchase domain.Purchase) error {
+	return nil
+}
+
+func (fakeRepository *FakeRecommendationRepository) GetFrequentlyBoughtTogether(productId int64, limit int) ([]domain.RelatedProduct, error) {
+	related := fakeRepository.boughtTogether[productId]
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
+
+func (fakeRepository *FakeRecommendationRepository) GetCategoryBestsellers(
+	categoryId int64,
+	excludeProductIds []int64,
+	limit int,
+) ([]domain.RelatedProduct, error) {
+	return nil, nil
+}
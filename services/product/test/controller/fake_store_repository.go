@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+)
+
+type FakeStoreRepository struct {
+	stores []domain.Store
+}
+
+func NewFakeStoreRepository(initialStores []domain.Store) ports.StoreRepository {
+	return &FakeStoreRepository{stores: initialStores}
+}
+
+func (fakeRepository *FakeStoreRepository) GetAllStores() ([]domain.Store, error) {
+	return fakeRepository.stores, nil
+}
+
+func (fakeRepository *FakeStoreRepository) GetById(storeId int64) (domain.Store, error) {
+	for _, store := range fakeRepository.stores {
+		if store.Id == storeId {
+			return store, nil
+		}
+	}
+	return domain.Store{}, fmt.Errorf("%w with id %d", domain.ErrStoreNotFound, storeId)
+}
+
+func (fakeRepository *FakeStoreRepository) GetBySlug(slug string) (domain.Store, error) {
+	for _, store := range fakeRepository.stores {
+		if store.Slug == slug {
+			return store, nil
+		}
+	}
+	return domain.Store{}, fmt.Errorf("%w with slug %s", domain.ErrStoreNotFound, slug)
+}
+
+func (fakeRepository *FakeStoreRepository) AddStore(store domain.Store) (domain.Store, error) {
+	for _, existing := range fakeRepository.stores {
+		if existing.Slug == store.Slug {
+			return domain.Store{}, fmt.Errorf("%w: %s", domain.ErrStoreSlugTaken, store.Slug)
+		}
+	}
+	store.Id = int64(len(fakeRepository.stores)) + 1
+	store.CreatedAt = time.Now()
+	store.UpdatedAt = store.CreatedAt
+	fakeRepository.stores = append(fakeRepository.stores, store)
+	return store, nil
+}
+
+func (fakeRepository *FakeStoreRepository) UpdateStore(store domain.Store) (domain.Store, error) {
+	for i, existing := range fakeRepository.stores {
+		if existing.Id == store.Id {
+			store.UpdatedAt = time.Now()
+			fakeRepository.stores[i] = store
+			return store, nil
+		}
+	}
+	return domain.Store{}, fmt.Errorf("%w with id %d", domain.ErrStoreNotFound, store.Id)
+}
+
+func (fakeRepository *FakeStoreRepository) DeleteById(storeId int64) error {
+	for i, store := range fakeRepository.stores {
+		if store.Id == storeId {
+			fakeRepository.stores = append(fakeRepository.stores[:i], fakeRepository.stores[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w with id %d", domain.ErrStoreNotFound, storeId)
+}
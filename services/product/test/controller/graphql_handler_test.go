@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"product-app/services/product/internal/adapters/graphql"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+	"product-app/services/product/internal/usecase"
+	"product-app/shared/auth"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingProductRepository records how often products are fetched by category.
+type countingProductRepository struct {
+	ports.ProductRepository
+	categoryBatches int
+}
+
+func (repository *countingProductRepository) GetProductsByCategoryIds(categoryIds []int64) ([]domain.Product, error) {
+	repository.categoryBatches++
+	return repository.ProductRepository.GetProductsByCategoryIds(categoryIds)
+}
+
+func setupGraphQLHandler() (*graphql.Handler, *countingProductRepository, *FakeCategoryClient) {
+	initialProducts := []domain.Product{
+		{Id: 1, Name: "AirFryer", Price: 1000, StoreID: 1, StoreSlug: "abc-tech", Store: "ABC TECH", CategoryID: 1},
+		{Id: 2, Name: "Blender", Price: 500, StoreID: 2, StoreSlug: "xyz-appliances", Store: "XYZ Appliances", CategoryID: 1},
+		{Id: 3, Name: "Laptop", Price: 25000, StoreID: 1, StoreSlug: "abc-tech", Store: "ABC TECH", CategoryID: 2},
+	}
+	categoryClient := NewFakeCategoryClient([]domain.Category{
+		{Id: 1, Name: "Kitchen"},
+		{Id: 2, Name: "Computers"},
+		{Id: 3, Name: "Garden"},
+	})
+
+	productRepository := &countingProductRepository{ProductRepository: NewFakeProductRepository(initialProducts)}
+	productService := usecase.NewProductService(productRepository, NewFakeStoreRepository(testStores()), testProductEventsTopic)
+	return graphql.NewHandler(productService, categoryClient), productRepository, categoryClient
+}
+
+func executeGraphQL(t *testing.T, handler *graphql.Handler, query string, token string) (int, map[string]interface{}) {
+	e := echo.New()
+	body, _ := json.Marshal(map[string]interface{}{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+
+	err := handler.Serve(e.NewContext(req, rec))
+	assert.NoError(t, err)
+
+	var response map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	return rec.Code, response
+}
+
+func Test_ShouldQueryProductsWithFilterAndPagination(t *testing.T) {
+	handler, _, _ := setupGraphQLHandler()
+
+	code, response := executeGraphQL(t, handler, `{
+		products(filter: {storeSlug: "ABC TECH", minPrice: 500}, first: 1) {
+			totalCount
+			hasNextPage
+			items { id name storeSlug category { name } }
+		}
+	}`, "")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, response["errors"])
+
+	products := response["data"].(map[string]interface{})["products"].(map[string]interface{})
+	assert.Equal(t, float64(2), products["totalCount"])
+	assert.Equal(t, true, products["hasNextPage"])
+
+	items := products["items"].([]interface{})
+	assert.Len(t, items, 1)
+	first := items[0].(map[string]interface{})
+	assert.Equal(t, "1", first["id"])
+	assert.Equal(t, "abc-tech", first["storeSlug"])
+	assert.Equal(t, "Kitchen", first["category"].(map[string]interface{})["name"])
+}
+
+func Test_ShouldContinueProductsAfterEndCursor(t *testing.T) {
+	handler, _, _ := setupGraphQLHandler()
+
+	_, response := executeGraphQL(t, handler, `{ products(first: 2) { endCursor items { id } } }`, "")
+	firstPage := response["data"].(map[string]interface{})["products"].(map[string]interface{})
+	cursor := firstPage["endCursor"].(string)
+
+	code, response := executeGraphQL(t, handler, `{ products(first: 2, after: "`+cursor+`") { hasNextPage endCursor items { id } } }`, "")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, response["errors"])
+	secondPage := response["data"].(map[string]interface{})["products"].(map[string]interface{})
+	assert.Equal(t, false, secondPage["hasNextPage"])
+	assert.Nil(t, secondPage["endCursor"])
+	items := secondPage["items"].([]interface{})
+	assert.Len(t, items, 1)
+	assert.Equal(t, "3", items[0].(map[string]interface{})["id"])
+}
+
+func Test_ShouldRejectQueryDeeperThanLimit(t *testing.T) {
+	handler, _, _ := setupGraphQLHandler()
+
+	code, response := executeGraphQL(t, handler, `{
+		categories { products { category { products { category { products { category { products { name } } } } } } } }
+	}`, "")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.NotNil(t, response["errors"])
+	assert.Nil(t, response["data"])
+}
+
+func Test_ShouldRejectOversizedGraphQLBody(t *testing.T) {
+	handler, _, _ := setupGraphQLHandler()
+	e := echo.New()
+	body := `{"query": "` + strings.Repeat(" ", 1<<20) + `{ categories { name } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	err := handler.Serve(e.NewContext(req, rec))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func Test_ShouldReturnNullForUnknownProduct(t *testing.T) {
+	handler, _, _ := setupGraphQLHandler()
+
+	code, response := executeGraphQL(t, handler, `{ product(id: "42") { name } }`, "")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, response["errors"])
+	assert.Nil(t, response["data"].(map[string]interface{})["product"])
+}
+
+// failingProductRepository fails every product lookup by id.
+type failingProductRepository struct {
+	ports.ProductRepository
+}
+
+func (repository *failingProductRepository) GetById(productId int64) (domain.Product, error) {
+	return domain.Product{}, errors.New("connection refused")
+}
+
+func Test_ShouldReturnErrorWhenProductLookupFails(t *testing.T) {
+	productRepository := &failingProductRepository{ProductRepository: NewFakeProductRepository(nil)}
+	productService := usecase.NewProductService(productRepository, NewFakeStoreRepository(testStores()), testProductEventsTopic)
+	handler := graphql.NewHandler(productService, NewFakeCategoryClient(nil))
+
+	code, response := executeGraphQL(t, handler, `{ product(id: "1") { name } }`, "")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.NotNil(t, response["errors"])
+}
+
+func Test_ShouldBatchCategoryProductsIntoSingleLookup(t *testing.T) {
+	handler, productRepository, categoryClient := setupGraphQLHandler()
+
+	code, response := executeGraphQL(t, handler, `{
+		categories {
+			name
+			products { name category { name } }
+		}
+	}`, "")
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, response["errors"])
+	assert.Equal(t, 1, productRepository.categoryBatches)
+	assert.Equal(t, 1, categoryClient.calls)
+
+	categories := response["data"].(map[string]interface{})["categories"].([]interface{})
+	assert.Len(t, categories, 3)
+	assert.Len(t, categories[0].(map[string]interface{})["products"], 2)
+	assert.Len(t, categories[1].(map[string]interface{})["products"], 1)
+	assert.Len(t, categories[2].(map[string]interface{})["products"], 0)
+}
+
+func Test_ShouldRejectMutationWithoutToken(t *testing.T) {
+	handler, _, _ := setupGraphQLHandler()
+
+	code, response := executeGraphQL(t, handler, `mutation {
+		updateProductPrice(id: "1", price: 900) { price }
+	}`, "")
+
+	assert.Equal(t, http.StatusOK, code)
+	errors := response["errors"].([]interface{})
+	assert.Contains(t, errors[0].(map[string]interface{})["message"], "unauthorized")
+}
+
+func Test_ShouldRejectInvalidToken(t *testing.T) {
+	handler, _, _ := setupGraphQLHandler()
+
+	code, _ := executeGraphQL(t, handler, `{ categories { name } }`, "not-a-token")
+
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func Test_ShouldUpdateProductPriceWithToken(t *testing.T) {
+	handler, _, _ := setupGraphQLHandler()
+	token, _ := auth.GenerateToken(7, "owner", "owner@example.com")
+
+	code, response := executeGraphQL(t, handler, `mutation {
+		updateProductPrice(id: "2", price: 450) { id price }
+	}`, token)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, response["errors"])
+	product := response["data"].(map[string]interface{})["updateProductPrice"].(map[string]interface{})
+	assert.Equal(t, float64(450), product["price"])
+}
+
+func Test_ShouldCreateProductWithToken(t *testing.T) {
+	handler, _, _ := setupGraphQLHandler()
+	token, _ := auth.GenerateToken(7, "owner", "owner@example.com")
+
+	code, response := executeGraphQL(t, handler, `mutation {
+		createProduct(input: {name: "Kettle", price: 300, storeId: "1", categoryId: "1"}) { id storeSlug }
+	}`, token)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, response["errors"])
+	product := response["data"].(map[string]interface{})["createProduct"].(map[string]interface{})
+	assert.Equal(t, "4", product["id"])
+	assert.Equal(t, "abc-tech", product["storeSlug"])
+}
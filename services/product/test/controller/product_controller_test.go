@@ -15,6 +15,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+const testProductEventsTopic = "product.events"
+
 func setupProductController() *httpcontroller.ProductController {
 	initialProducts := []domain.Product{
 		{
@@ -23,6 +25,8 @@ func setupProductController() *httpcontroller.ProductController {
 			Price:       1000,
 			Description: "Digital air fryer",
 			Discount:    10,
+			StoreID:     1,
+			StoreSlug:   "abc-tech",
 			Store:       "ABC TECH",
 			CategoryID:  1,
 		},
@@ -32,13 +36,15 @@ func setupProductController() *httpcontroller.ProductController {
 			Price:       500,
 			Description: "High speed blender",
 			Discount:    5,
+			StoreID:     2,
+			StoreSlug:   "xyz-appliances",
 			Store:       "XYZ Appliances",
 			CategoryID:  1,
 		},
 	}
 
 	fakeRepo := NewFakeProductRepository(initialProducts)
-	productService := usecase.NewProductService(fakeRepo, nil)
+	productService := usecase.NewProductService(fakeRepo, NewFakeStoreRepository(testStores()), testProductEventsTopic)
 	return httpcontroller.NewProductController(productService)
 }
 func Test_ShouldGetProductId(t *testing.T) {
@@ -67,6 +73,36 @@ func Test_ShouldGetProductId(t *testing.T) {
 	assert.Equal(t, float64(1), response["category_id"])
 }
 
+func Test_ShouldReturnNotFound_WhenProductUnknown(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/42", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("42")
+
+	err := setupProductController().GetProductById(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func Test_ShouldReturnInternalError_WhenProductLookupFails(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	productRepository := &failingProductRepository{ProductRepository: NewFakeProductRepository(nil)}
+	productService := usecase.NewProductService(productRepository, NewFakeStoreRepository(testStores()), testProductEventsTopic)
+
+	err := httpcontroller.NewProductController(productService).GetProductById(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
 func Test_ShouldGetAllProducts(t *testing.T) {
 
 	e := echo.New()
@@ -107,6 +143,13 @@ func TestAddProduct_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/api/v1/products/3", rec.Header().Get(echo.HeaderLocation))
+
+	var response map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, float64(3), response["id"])
+	assert.Equal(t, "Microwave", response["name"])
+	assert.Equal(t, "abc-tech", response["store_slug"])
 }
 
 func Test_ShouldAddProduct_InvalidJSON(t *testing.T) {
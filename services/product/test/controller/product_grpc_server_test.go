@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	productgrpc "product-app/services/product/internal/adapters/grpc"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase"
+	"product-app/shared/auth"
+	"product-app/shared/grpcx"
+	productv1 "product-app/shared/proto/product/v1"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func setupProductGRPCClient(t *testing.T) *grpc.ClientConn {
+	initialProducts := []domain.Product{
+		{Id: 1, Name: "AirFryer", Price: 1000, StoreID: 1, StoreSlug: "abc-tech", Store: "ABC TECH", CategoryID: 1},
+		{Id: 2, Name: "Blender", Price: 500, StoreID: 2, StoreSlug: "xyz-appliances", Store: "XYZ Appliances", CategoryID: 2},
+	}
+	productService := usecase.NewProductService(NewFakeProductRepository(initialProducts), NewFakeStoreRepository(testStores()), testProductEventsTopic)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server, healthServer := grpcx.NewServer(productgrpc.ProtectedMethods...)
+	productv1.RegisterProductServiceServer(server, productgrpc.NewProductServer(productService))
+	healthServer.SetServingStatus(productv1.ProductService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpcx.Dial("passthrough:///bufnet", grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func Test_ShouldGetProductOverGRPC(t *testing.T) {
+	client := productv1.NewProductServiceClient(setupProductGRPCClient(t))
+
+	response, err := client.GetProduct(context.Background(), &productv1.GetProductRequest{Id: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "AirFryer", response.GetProduct().GetName())
+	assert.Equal(t, "abc-tech", response.GetProduct().GetStoreSlug())
+}
+
+func Test_ShouldReturnNotFoundOverGRPC(t *testing.T) {
+	client := productv1.NewProductServiceClient(setupProductGRPCClient(t))
+
+	_, err := client.GetProduct(context.Background(), &productv1.GetProductRequest{Id: 99})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_ShouldListProductsByStoreOverGRPC(t *testing.T) {
+	client := productv1.NewProductServiceClient(setupProductGRPCClient(t))
+
+	response, err := client.ListProducts(context.Background(), &productv1.ListProductsRequest{StoreSlug: "XYZ Appliances"})
+
+	assert.NoError(t, err)
+	assert.Len(t, response.GetProducts(), 1)
+	assert.Equal(t, int64(2), response.GetProducts()[0].GetId())
+}
+
+func Test_ShouldRejectProtectedRPCWithoutToken(t *testing.T) {
+	client := productv1.NewProductServiceClient(setupProductGRPCClient(t))
+
+	_, err := client.UpdateProductPrice(context.Background(), &productv1.UpdateProductPriceRequest{Id: 1, Price: 900})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_ShouldUpdatePriceOverGRPCWithToken(t *testing.T) {
+	client := productv1.NewProductServiceClient(setupProductGRPCClient(t))
+	token, _ := auth.GenerateToken(7, "owner", "owner@example.com")
+	ctx := auth.ContextWithToken(context.Background(), token)
+
+	response, err := client.UpdateProductPrice(ctx, &productv1.UpdateProductPriceRequest{Id: 1, Price: 900})
+
+	assert.NoError(t, err)
+	assert.Equal(t, float32(900), response.GetProduct().GetPrice())
+}
+
+func Test_ShouldReportHealthOverGRPC(t *testing.T) {
+	client := healthpb.NewHealthClient(setupProductGRPCClient(t))
+
+	response, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: productv1.ProductService_ServiceDesc.ServiceName,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.GetStatus())
+}
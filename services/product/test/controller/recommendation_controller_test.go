@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpcontroller "product-app/services/product/internal/adapters/http/controller"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRecommendationController() *httpcontroller.RecommendationController {
+	products := []domain.Product{
+		{Id: 1, Name: "AirFryer", Price: 1000, Store: "ABC TECH", CategoryID: 1},
+		{Id: 2, Name: "Blender", Price: 500, Store: "XYZ Appliances", CategoryID: 1},
+	}
+
+	recommendationRepository := NewFakeRecommendationRepository(map[int64][]domain.RelatedProduct{
+		1: {{Product: products[1], CoPurchaseCount: 4, UnitsSold: 12, Reason: domain.RecommendationReasonBoughtTogether}},
+	})
+	recommendationService := usecase.NewRecommendationService(recommendationRepository, NewFakeProductRepository(products), 0)
+	return httpcontroller.NewRecommendationController(recommendationService)
+}
+
+func Test_ShouldGetRelatedProducts(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/1/related", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := setupRecommendationController().GetRelatedProducts(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	related := response["related"].([]interface{})
+	assert.Len(t, related, 1)
+
+	first := related[0].(map[string]interface{})
+	assert.Equal(t, float64(2), first["id"])
+	assert.Equal(t, float64(4), first["co_purchase_count"])
+	assert.Equal(t, "bought_together", first["reason"])
+	assert.Equal(t, "Blender", first["product"].(map[string]interface{})["name"])
+}
+
+func Test_ShouldRejectRelatedProducts_InvalidLimit(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/1/related?limit=500", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := setupRecommendationController().GetRelatedProducts(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
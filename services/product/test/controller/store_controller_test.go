@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpcontroller "product-app/services/product/internal/adapters/http/controller"
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStores() []domain.Store {
+	return []domain.Store{
+		{Id: 1, Slug: "abc-tech", Name: "ABC TECH", OwnerUserId: 7, Status: domain.StoreStatusActive},
+		{Id: 2, Slug: "xyz-appliances", Name: "XYZ Appliances", Status: domain.StoreStatusActive},
+	}
+}
+
+func setupStoreController() *httpcontroller.StoreController {
+	storeService := usecase.NewStoreService(NewFakeStoreRepository(testStores()))
+	return httpcontroller.NewStoreController(storeService)
+}
+
+func Test_ShouldGetStoreBySlug(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stores/slug/abc-tech", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("slug")
+	c.SetParamValues("abc-tech")
+
+	err := setupStoreController().GetStoreBySlug(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, float64(1), response["id"])
+	assert.Equal(t, "ABC TECH", response["name"])
+}
+
+func Test_ShouldAddStore(t *testing.T) {
+	e := echo.New()
+	storeJSON := `{"name": "Tech Store", "contact_email": "hello@techstore.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stores", strings.NewReader(storeJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", int64(9))
+
+	err := setupStoreController().AddStore(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/api/v1/stores/3", rec.Header().Get(echo.HeaderLocation))
+
+	var response map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, "tech-store", response["slug"])
+	assert.Equal(t, float64(9), response["owner_user_id"])
+}
+
+func Test_ShouldRejectStoreUpdate_WhenNotOwner(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/stores/1", strings.NewReader(`{"name": "Hijacked"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user_id", int64(8))
+
+	err := setupStoreController().UpdateStore(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
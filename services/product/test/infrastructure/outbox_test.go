@@ -0,0 +1,190 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"product-app/services/product/internal/domain"
+	"product-app/shared/outbox"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingPublisher struct {
+	messages []kafka.Message
+	err      error
+}
+
+func (publisher *recordingPublisher) PublishRawMessage(ctx context.Context, message kafka.Message) error {
+	if publisher.err != nil {
+		return publisher.err
+	}
+	publisher.messages = append(publisher.messages, message)
+	return nil
+}
+
+func productCreatedMessage(product domain.Product) (outbox.Message, error) {
+	return outbox.NewMessage("product.events", "product.created", product)
+}
+
+func addProductWithEvent(t *testing.T, name string) {
+	_, err := productRepository.AddProduct(domain.Product{Name: name, Price: 100, StoreID: 1}, productCreatedMessage)
+	assert.NoError(t, err)
+}
+
+func TestOutbox_AddProductWritesEventInSameTransaction(t *testing.T) {
+	setupStoresOnly()
+
+	addProductWithEvent(t, "Phone")
+
+	var key string
+	var productId int64
+	err := dbPool.QueryRow(ctx, `SELECT message_key, (payload->>'id')::BIGINT FROM outbox`).Scan(&key, &productId)
+	assert.NoError(t, err)
+	assert.Equal(t, "product.created", key)
+	assert.Equal(t, int64(1), productId)
+}
+
+func TestOutbox_AddProductRollsBackEventOnFailure(t *testing.T) {
+	setupStoresOnly()
+
+	_, err := productRepository.AddProduct(domain.Product{Name: "Phone", Price: 100, StoreID: 999}, productCreatedMessage)
+	assert.Error(t, err)
+
+	var count int
+	assert.NoError(t, dbPool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox`).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestOutbox_UpdatePriceWritesEventInSameTransaction(t *testing.T) {
+	setupStoresOnly()
+	addProductWithEvent(t, "Phone")
+
+	err := productRepository.UpdatePrice(1, 250, func(product domain.Product) (outbox.Message, error) {
+		return outbox.NewMessage("product.events", "product.updated", product)
+	})
+	assert.NoError(t, err)
+
+	var price float32
+	err = dbPool.QueryRow(ctx, `SELECT (payload->>'price')::REAL FROM outbox WHERE message_key = 'product.updated'`).Scan(&price)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(250), price)
+}
+
+func TestOutbox_DeleteAllWritesOneEventPerProduct(t *testing.T) {
+	setupStoresOnly()
+	addProductWithEvent(t, "Phone")
+	addProductWithEvent(t, "Tablet")
+
+	err := productRepository.DeleteAllProducts(func(product domain.Product) (outbox.Message, error) {
+		return outbox.NewMessage("product.events", "product.deleted", product)
+	})
+	assert.NoError(t, err)
+
+	var count int
+	assert.NoError(t, dbPool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox WHERE message_key = 'product.deleted'`).Scan(&count))
+	assert.Equal(t, 2, count)
+}
+
+func TestOutbox_DeleteMissingProductWritesNoEvent(t *testing.T) {
+	setupStoresOnly()
+
+	err := productRepository.DeleteById(999, func(product domain.Product) (outbox.Message, error) {
+		return outbox.NewMessage("product.events", "product.deleted", product)
+	})
+	assert.Error(t, err)
+
+	var count int
+	assert.NoError(t, dbPool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox`).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestOutbox_RelayPublishesPendingRowsAndMarksThemSent(t *testing.T) {
+	setupStoresOnly()
+	addProductWithEvent(t, "Phone")
+	addProductWithEvent(t, "Tablet")
+
+	publisher := &recordingPublisher{}
+	relay := outbox.NewRelay(dbPool, publisher, outbox.RelayConfig{Topic: "product.events"})
+
+	published, err := relay.RelayBatch(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Len(t, publisher.messages, 2)
+	assert.Equal(t, "product.created", string(publisher.messages[0].Key))
+	assert.Contains(t, string(publisher.messages[1].Value), `"name":"Tablet"`)
+
+	var pending int
+	assert.NoError(t, dbPool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox WHERE sent_at IS NULL`).Scan(&pending))
+	assert.Equal(t, 0, pending)
+
+	published, err = relay.RelayBatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+}
+
+func TestOutbox_RelayKeepsRowsAndBacksOffWhenPublishFails(t *testing.T) {
+	setupStoresOnly()
+	addProductWithEvent(t, "Phone")
+	addProductWithEvent(t, "Tablet")
+
+	relay := outbox.NewRelay(dbPool, &recordingPublisher{err: errors.New("broker unavailable")}, outbox.RelayConfig{
+		Topic:      "product.events",
+		BackoffMin: time.Minute,
+	})
+
+	published, err := relay.RelayBatch(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+
+	var attempts int
+	var lastError string
+	var due bool
+	err = dbPool.QueryRow(ctx, `
+		SELECT attempts, last_error, next_attempt_at <= NOW() FROM outbox WHERE id = 1
+	`).Scan(&attempts, &lastError, &due)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, "broker unavailable", lastError)
+	assert.False(t, due)
+
+	var untouched int
+	assert.NoError(t, dbPool.QueryRow(ctx, `SELECT attempts FROM outbox WHERE id = 2`).Scan(&untouched))
+	assert.Equal(t, 0, untouched)
+}
+
+func TestOutbox_RelayHoldsBackLaterRowsOfAFailedKey(t *testing.T) {
+	setupStoresOnly()
+	for _, key := range []string{"product-1", "product-2", "product-1"} {
+		_, err := dbPool.Exec(ctx, `INSERT INTO outbox (topic, message_key, payload) VALUES ('product.events', $1, '{}')`, key)
+		assert.NoError(t, err)
+	}
+	config := outbox.RelayConfig{Topic: "product.events", BackoffMin: time.Minute}
+
+	_, err := outbox.NewRelay(dbPool, &recordingPublisher{err: errors.New("broker unavailable")}, config).RelayBatch(ctx)
+	assert.NoError(t, err)
+
+	publisher := &recordingPublisher{}
+	relay := outbox.NewRelay(dbPool, publisher, config)
+	published, err := relay.RelayBatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published, "product-1's second row waits behind its failed first row")
+	assert.Equal(t, "product-2", string(publisher.messages[0].Key))
+
+	_, err = dbPool.Exec(ctx, `UPDATE outbox SET next_attempt_at = NOW() WHERE id = 1`)
+	assert.NoError(t, err)
+	published, err = relay.RelayBatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, "product-1", string(publisher.messages[1].Key))
+	assert.Equal(t, "product-1", string(publisher.messages[2].Key))
+
+	var pending int
+	assert.NoError(t, dbPool.QueryRow(ctx, `SELECT COUNT(*) FROM outbox WHERE sent_at IS NULL`).Scan(&pending))
+	assert.Equal(t, 0, pending)
+}
@@ -12,10 +12,10 @@ func TestProductRepository_GetAll(t *testing.T) {
 	setupFullTestData()
 
 	expected := []domain.Product{
-		{Id: 1, Name: "AirFryer", Price: 3000, Description: "AirFryer açıklaması", Discount: 22, Store: "ABC TECH", CategoryID: 1},
-		{Id: 2, Name: "Ütü", Price: 1500, Description: "Ütü açıklaması", Discount: 10, Store: "ABC TECH", CategoryID: 1},
-		{Id: 3, Name: "Çamaşır Makinesi", Price: 10000, Description: "Çamaşır Makinesi açıklaması", Discount: 15, Store: "ABC TECH", CategoryID: 2},
-		{Id: 4, Name: "Lambader", Price: 2000, Description: "Lambader açıklaması", Discount: 0, Store: "Dekorasyon Sarayı", CategoryID: 3},
+		{Id: 1, Name: "AirFryer", Price: 3000, Description: "AirFryer açıklaması", Discount: 22, StoreID: 1, StoreSlug: "abc-tech", Store: "ABC TECH", CategoryID: 1},
+		{Id: 2, Name: "Ütü", Price: 1500, Description: "Ütü açıklaması", Discount: 10, StoreID: 1, StoreSlug: "abc-tech", Store: "ABC TECH", CategoryID: 1},
+		{Id: 3, Name: "Çamaşır Makinesi", Price: 10000, Description: "Çamaşır Makinesi açıklaması", Discount: 15, StoreID: 1, StoreSlug: "abc-tech", Store: "ABC TECH", CategoryID: 2},
+		{Id: 4, Name: "Lambader", Price: 2000, Description: "Lambader açıklaması", Discount: 0, StoreID: 2, StoreSlug: "dekorasyon-sarayı", Store: "Dekorasyon Sarayı", CategoryID: 3},
 	}
 
 	actual := productRepository.GetAllProducts()
@@ -28,34 +28,74 @@ func TestProductRepository_GetAllByStore(t *testing.T) {
 	setupFullTestData()
 
 	expected := []domain.Product{
-		{Id: 1, Name: "AirFryer", Price: 3000, Description: "AirFryer açıklaması", Discount: 22, Store: "ABC TECH", CategoryID: 1},
-		{Id: 2, Name: "Ütü", Price: 1500, Description: "Ütü açıklaması", Discount: 10, Store: "ABC TECH", CategoryID: 1},
-		{Id: 3, Name: "Çamaşır Makinesi", Price: 10000, Description: "Çamaşır Makinesi açıklaması", Discount: 15, Store: "ABC TECH", CategoryID: 2},
+		{Id: 1, Name: "AirFryer", Price: 3000, Description: "AirFryer açıklaması", Discount: 22, StoreID: 1, StoreSlug: "abc-tech", Store: "ABC TECH", CategoryID: 1},
+		{Id: 2, Name: "Ütü", Price: 1500, Description: "Ütü açıklaması", Discount: 10, StoreID: 1, StoreSlug: "abc-tech", Store: "ABC TECH", CategoryID: 1},
+		{Id: 3, Name: "Çamaşır Makinesi", Price: 10000, Description: "Çamaşır Makinesi açıklaması", Discount: 15, StoreID: 1, StoreSlug: "abc-tech", Store: "ABC TECH", CategoryID: 2},
 	}
 
-	actual := productRepository.GetAllProductsByStore("ABC TECH")
+	actual := productRepository.GetAllProductsByStore("abc-tech")
 
 	assert.Equal(t, expected, actual)
 }
 
+func TestProductRepository_FindProducts(t *testing.T) {
+	setupFullTestData()
+	minPrice := 1500.0
+
+	query := domain.ProductQuery{StoreSlug: "abc-tech", MinPrice: &minPrice, AfterId: 1, Limit: 1}
+	actual, err := productRepository.FindProducts(query)
+	assert.NoError(t, err)
+	assert.Len(t, actual, 1)
+	assert.Equal(t, int64(2), actual[0].Id)
+
+	count, err := productRepository.CountProducts(query)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestProductRepository_FindProducts_MatchesNameWildcardsLiterally(t *testing.T) {
+	setupFullTestData()
+
+	actual, err := productRepository.FindProducts(domain.ProductQuery{NameContains: "%", Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, actual)
+
+	actual, err = productRepository.FindProducts(domain.ProductQuery{NameContains: "fRYer", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, actual, 1)
+}
+
+func TestProductRepository_GetProductsByCategoryIds(t *testing.T) {
+	setupFullTestData()
+
+	actual, err := productRepository.GetProductsByCategoryIds([]int64{2, 3, 99})
+
+	assert.NoError(t, err)
+	assert.Len(t, actual, 2)
+	assert.Equal(t, "Çamaşır Makinesi", actual[0].Name)
+	assert.Equal(t, "Lambader", actual[1].Name)
+}
+
 func TestProductRepository_Add(t *testing.T) {
-	clearTestData()
+	setupStoresOnly()
 
 	newProduct := domain.Product{
 		Name:        "Phone",
 		Price:       3000,
 		Description: "Apple phone",
 		Discount:    0,
-		Store:       "Apple Store",
+		StoreID:     1,
 		CategoryID:  0,
 	}
 
-	err := productRepository.AddProduct(newProduct)
+	created, err := productRepository.AddProduct(newProduct, nil)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(1), created.Id)
 
 	products := productRepository.GetAllProducts()
 	assert.Len(t, products, 1)
 	assert.Equal(t, "Phone", products[0].Name)
+	assert.Equal(t, "ABC TECH", products[0].Store)
 }
 
 func TestProductRepository_GetById(t *testing.T) {
@@ -73,7 +113,7 @@ func TestProductRepository_GetById(t *testing.T) {
 func TestProductRepository_DeleteById(t *testing.T) {
 	setupFullTestData()
 
-	err := productRepository.DeleteById(1)
+	err := productRepository.DeleteById(1, nil)
 	assert.NoError(t, err)
 
 	_, err = productRepository.GetById(1)
@@ -86,7 +126,7 @@ func TestProductRepository_UpdatePrice(t *testing.T) {
 	before, _ := productRepository.GetById(1)
 	assert.Equal(t, float32(3000), before.Price)
 
-	err := productRepository.UpdatePrice(1, 4000)
+	err := productRepository.UpdatePrice(1, 4000, nil)
 	assert.NoError(t, err)
 
 	after, _ := productRepository.GetById(1)
@@ -96,7 +136,7 @@ func TestProductRepository_UpdatePrice(t *testing.T) {
 func TestProductRepository_DeleteAll(t *testing.T) {
 	clearTestData()
 
-	err := productRepository.DeleteAllProducts()
+	err := productRepository.DeleteAllProducts(nil)
 	assert.NoError(t, err)
 
 	products := productRepository.GetAllProducts()
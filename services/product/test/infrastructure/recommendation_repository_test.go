@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"product-app/services/product/internal/adapters/postgresql"
+	"product-app/services/product/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecommendationRepository_RecordPurchaseBuildsCoPurchases(t *testing.T) {
+	setupFullTestData()
+	repo := postgresql.NewRecommendationRepository(dbPool)
+
+	assert.NoError(t, repo.RecordPurchase(domain.Purchase{
+		OrderId: 1, CustomerNumber: "CUST-001",
+		Items: []domain.PurchaseItem{{ProductId: 1, Quantity: 1}},
+	}))
+	assert.NoError(t, repo.RecordPurchase(domain.Purchase{
+		OrderId: 2, CustomerNumber: "CUST-001",
+		Items: []domain.PurchaseItem{{ProductId: 2, Quantity: 3}},
+	}))
+
+	related, err := repo.GetFrequentlyBoughtTogether(1, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, related, 1)
+	assert.Equal(t, int64(2), related[0].Product.Id)
+	assert.Equal(t, int64(1), related[0].CoPurchaseCount)
+	assert.Equal(t, int64(3), related[0].UnitsSold)
+	assert.Equal(t, domain.RecommendationReasonBoughtTogether, related[0].Reason)
+}
+
+func TestRecommendationRepository_RecordPurchaseIgnoresDuplicateOrders(t *testing.T) {
+	setupFullTestData()
+	repo := postgresql.NewRecommendationRepository(dbPool)
+
+	purchase := domain.Purchase{
+		OrderId: 1, CustomerNumber: "CUST-001",
+		Items: []domain.PurchaseItem{{ProductId: 1, Quantity: 2}, {ProductId: 2, Quantity: 1}},
+	}
+	assert.NoError(t, repo.RecordPurchase(purchase))
+	assert.NoError(t, repo.RecordPurchase(purchase))
+
+	related, err := repo.GetFrequentlyBoughtTogether(2, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, related, 1)
+	assert.Equal(t, int64(1), related[0].CoPurchaseCount)
+	assert.Equal(t, int64(2), related[0].UnitsSold)
+}
+
+func TestRecommendationRepository_GetCategoryBestsellers(t *testing.T) {
+	setupFullTestData()
+	repo := postgresql.NewRecommendationRepository(dbPool)
+
+	assert.NoError(t, repo.RecordPurchase(domain.Purchase{
+		OrderId: 1, CustomerNumber: "CUST-001",
+		Items: []domain.PurchaseItem{{ProductId: 2, Quantity: 5}},
+	}))
+
+	related, err := repo.GetCategoryBestsellers(1, []int64{1}, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, related, 1)
+	assert.Equal(t, int64(2), related[0].Product.Id)
+	assert.Equal(t, int64(5), related[0].UnitsSold)
+	assert.Equal(t, domain.RecommendationReasonCategoryBestseller, related[0].Reason)
+}
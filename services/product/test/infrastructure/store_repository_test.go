@@ -0,0 +1,45 @@
+package infrastructure
+
+import (
+	"errors"
+	"testing"
+
+	"product-app/services/product/internal/adapters/postgresql"
+	"product-app/services/product/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreRepository_GetBySlug(t *testing.T) {
+	setupStoresOnly()
+	repo := postgresql.NewStoreRepository(dbPool)
+
+	store, err := repo.GetBySlug("abc-tech")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), store.Id)
+	assert.Equal(t, "ABC TECH", store.Name)
+	assert.Equal(t, int64(1), store.OwnerUserId)
+}
+
+func TestStoreRepository_AddRejectsDuplicateSlug(t *testing.T) {
+	setupStoresOnly()
+	repo := postgresql.NewStoreRepository(dbPool)
+
+	created, err := repo.AddStore(domain.Store{Slug: "tech-store", Name: "Tech Store", Status: domain.StoreStatusActive})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), created.Id)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	_, err = repo.AddStore(domain.Store{Slug: "tech-store", Name: "tech store", Status: domain.StoreStatusActive})
+	assert.True(t, errors.Is(err, domain.ErrStoreSlugTaken))
+}
+
+func TestStoreRepository_DeleteRejectsStoreWithProducts(t *testing.T) {
+	setupFullTestData()
+	repo := postgresql.NewStoreRepository(dbPool)
+
+	err := repo.DeleteById(1)
+
+	assert.True(t, errors.Is(err, domain.ErrStoreHasProducts))
+}
@@ -10,6 +10,14 @@ import (
 const resetTablesQuery = `
 DO $$
 BEGIN
+	IF to_regclass('public.recommendation_processed_orders') IS NOT NULL THEN
+		EXECUTE 'TRUNCATE TABLE recommendation_processed_orders, customer_purchases, product_co_purchases, product_sales';
+	END IF;
+
+	IF to_regclass('public.outbox') IS NOT NULL THEN
+		EXECUTE 'TRUNCATE TABLE outbox RESTART IDENTITY';
+	END IF;
+
 	IF to_regclass('public.product_images') IS NOT NULL THEN
 		EXECUTE 'TRUNCATE TABLE product_images RESTART IDENTITY CASCADE';
 	END IF;
@@ -18,6 +26,10 @@ BEGIN
 		EXECUTE 'TRUNCATE TABLE products RESTART IDENTITY CASCADE';
 	END IF;
 
+	IF to_regclass('public.stores') IS NOT NULL THEN
+		EXECUTE 'TRUNCATE TABLE stores RESTART IDENTITY CASCADE';
+	END IF;
+
 	IF to_regclass('public.categories') IS NOT NULL THEN
 		EXECUTE 'TRUNCATE TABLE categories RESTART IDENTITY CASCADE';
 	END IF;
@@ -27,17 +27,36 @@ func InsertTestCategories(ctx context.Context, dbPool *pgxpool.Pool) {
 	log.Infof("✅ Categories created: %d rows", result.RowsAffected())
 }
 
+/* =========================
+   STORE TEST DATA
+========================= */
+
+var INSERT_STORES = `
+INSERT INTO stores (slug, name, owner_user_id, status)
+VALUES
+('abc-tech', 'ABC TECH', 1, 'active'),
+('dekorasyon-sarayı', 'Dekorasyon Sarayı', NULL, 'active');
+`
+
+func InsertTestStores(ctx context.Context, dbPool *pgxpool.Pool) {
+	result, err := dbPool.Exec(ctx, INSERT_STORES)
+	if err != nil {
+		log.Fatalf("❌ Failed to insert test stores: %v", err)
+	}
+	log.Infof("✅ Stores created: %d rows", result.RowsAffected())
+}
+
 /* =========================
    PRODUCT TEST DATA
 ========================= */
 
 var INSERT_PRODUCTS = `
-INSERT INTO products (name, price, description, discount, store, category_id)
+INSERT INTO products (name, price, description, discount, store_id, category_id)
 VALUES
-('AirFryer', 3000.0, 'AirFryer açıklaması', 22.0, 'ABC TECH', 1),
-('Ütü', 1500.0, 'Ütü açıklaması', 10.0, 'ABC TECH', 1),
-('Çamaşır Makinesi', 10000.0, 'Çamaşır Makinesi açıklaması', 15.0, 'ABC TECH', 2),
-('Lambader', 2000.0, 'Lambader açıklaması', 0.0, 'Dekorasyon Sarayı', 3);
+('AirFryer', 3000.0, 'AirFryer açıklaması', 22.0, 1, 1),
+('Ütü', 1500.0, 'Ütü açıklaması', 10.0, 1, 1),
+('Çamaşır Makinesi', 10000.0, 'Çamaşır Makinesi açıklaması', 15.0, 1, 2),
+('Lambader', 2000.0, 'Lambader açıklaması', 0.0, 2, 3);
 `
 
 func InsertTestProducts(ctx context.Context, dbPool *pgxpool.Pool) {
@@ -3,15 +3,22 @@ package infrastructure
 func setupFullTestData() {
 	TruncateTestData(ctx, dbPool)
 	InsertTestCategories(ctx, dbPool)
+	InsertTestStores(ctx, dbPool)
 	InsertTestProducts(ctx, dbPool)
 }
 
 func setupProductsOnly() {
 	TruncateTestData(ctx, dbPool)
 	InsertTestCategories(ctx, dbPool)
+	InsertTestStores(ctx, dbPool)
 	InsertTestProducts(ctx, dbPool)
 }
 
+func setupStoresOnly() {
+	TruncateTestData(ctx, dbPool)
+	InsertTestStores(ctx, dbPool)
+}
+
 func clearTestData() {
 	TruncateTestData(ctx, dbPool)
 }
@@ -62,8 +62,14 @@ func createTestDatabase(ctx context.Context, host, port, dbName string) {
 }
 func createSchema(ctx context.Context, pool *pgxpool.Pool) {
 	_, err := pool.Exec(ctx, `
+		DROP TABLE IF EXISTS outbox;
+		DROP TABLE IF EXISTS recommendation_processed_orders;
+		DROP TABLE IF EXISTS customer_purchases;
+		DROP TABLE IF EXISTS product_co_purchases;
+		DROP TABLE IF EXISTS product_sales;
 		DROP TABLE IF EXISTS product_images;
 		DROP TABLE IF EXISTS products;
+		DROP TABLE IF EXISTS stores;
 		DROP TABLE IF EXISTS categories;
 		DROP TABLE IF EXISTS users;
 
@@ -73,13 +79,25 @@ func createSchema(ctx context.Context, pool *pgxpool.Pool) {
 			description TEXT
 		);
 
+		CREATE TABLE stores (
+			id BIGSERIAL PRIMARY KEY,
+			slug TEXT NOT NULL UNIQUE,
+			name TEXT NOT NULL,
+			owner_user_id BIGINT,
+			contact_email TEXT,
+			contact_phone TEXT,
+			status TEXT NOT NULL DEFAULT 'active',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		);
+
 		CREATE TABLE products (
 			id SERIAL PRIMARY KEY,
 			name TEXT NOT NULL,
 			price REAL NOT NULL,
 			description TEXT,
 			discount REAL,
-			store TEXT,
+			store_id BIGINT NOT NULL REFERENCES stores(id),
 			category_id BIGINT
 		);
 
@@ -89,6 +107,46 @@ func createSchema(ctx context.Context, pool *pgxpool.Pool) {
 			image_url TEXT NOT NULL
 		);
 
+		CREATE TABLE recommendation_processed_orders (
+			order_id BIGINT PRIMARY KEY,
+			processed_at TIMESTAMP NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE customer_purchases (
+			customer_number TEXT NOT NULL,
+			product_id BIGINT NOT NULL,
+			first_purchased_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (customer_number, product_id)
+		);
+
+		CREATE TABLE product_co_purchases (
+			product_id BIGINT NOT NULL,
+			related_product_id BIGINT NOT NULL,
+			co_purchase_count BIGINT NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (product_id, related_product_id)
+		);
+
+		CREATE TABLE product_sales (
+			product_id BIGINT PRIMARY KEY,
+			units_sold BIGINT NOT NULL DEFAULT 0,
+			order_count BIGINT NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE outbox (
+			id BIGSERIAL PRIMARY KEY,
+			topic TEXT NOT NULL,
+			message_key TEXT NOT NULL,
+			payload JSON NOT NULL,
+			headers JSONB NOT NULL DEFAULT '{}',
+			attempts INT NOT NULL DEFAULT 0,
+			last_error TEXT,
+			next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			sent_at TIMESTAMP
+		);
+
 		CREATE TABLE users (
 			id BIGSERIAL PRIMARY KEY,
 			username TEXT NOT NULL UNIQUE,
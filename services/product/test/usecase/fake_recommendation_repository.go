@@ -0,0 +1,59 @@
+package service
+
+import (
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/ports"
+)
+
+type FakeRecommendationRepository struct {
+	boughtTogether map[int64][]domain.RelatedProduct
+	bestsellers    map[int64][]domain.RelatedProduct
+	purchases      []domain.Purchase
+	lookups        int
+}
+
+func NewFakeRecommendationRepository(
+	boughtTogether map[int64][]domain.RelatedProduct,
+	bestsellers map[int64][]domain.RelatedProduct,
+) *FakeRecommendationRepository {
+	return &FakeRecommendationRepository{
+		boughtTogether: boughtTogether,
+		bestsellers:    bestsellers,
+	}
+}
+
+var _ ports.RecommendationRepository = (*FakeRecommendationRepository)(nil)
+
+func (fakeRepository *FakeRecommendationRepository) RecordPurchase(purchase domain.Purchase) error {
+	fakeRepository.purchases = append(fakeRepository.purchases, purchase)
+	return nil
+}
+
+func (fakeRepository *FakeRecommendationRepository) GetFrequentlyBoughtTogether(productId int64, limit int) ([]domain.RelatedProduct, error) {
+	fakeRepository.lookups++
+	related := fakeRepository.boughtTogether[productId]
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
+
+func (fakeRepository *FakeRecommendationRepository) GetCategoryBestsellers(
+	categoryId int64,
+	excludeProductIds []int64,
+	limit int,
+) ([]domain.RelatedProduct, error) {
+	excluded := map[int64]bool{}
+	for _, id := range excludeProductIds {
+		excluded[id] = true
+	}
+
+	var related []domain.RelatedProduct
+	for _, candidate := range fakeRepository.bestsellers[categoryId] {
+		if excluded[candidate.Product.Id] || len(related) == limit {
+			continue
+		}
+		related = append(related, candidate)
+	}
+	return related, nil
+}
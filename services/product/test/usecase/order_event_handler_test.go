@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	productkafka "product-app/services/product/internal/adapters/kafka"
+	"product-app/services/product/internal/usecase"
+	"product-app/shared/kafka"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type orderCreatedV3 struct {
+	kafka.OrderCreated
+}
+
+func (orderCreatedV3) SchemaVersion() string { return "3.0" }
+
+type orderCreatedV1 struct {
+	ID             int64  `json:"id"`
+	CustomerNumber string `json:"customer_number"`
+	ProductID      string `json:"product_id"`
+	Quantity       int32  `json:"quantity"`
+}
+
+func (orderCreatedV1) EventType() string         { return kafka.EventTypeOrderCreated }
+func (orderCreatedV1) SchemaVersion() string     { return "1.0" }
+func (event orderCreatedV1) AggregateID() string { return strconv.FormatInt(event.ID, 10) }
+
+func setupOrderEventHandler() (kafka.MessageHandler, *FakeRecommendationRepository) {
+	recommendationRepository := NewFakeRecommendationRepository(nil, nil)
+	recommendationService := usecase.NewRecommendationService(recommendationRepository, NewFakeProductRepository(nil), 0)
+	return productkafka.NewOrderEventHandler(recommendationService), recommendationRepository
+}
+
+func encodeEvent(t *testing.T, event kafka.Event) kafka.Message {
+	value, err := kafka.EncodeEvent("order-service", event)
+	assert.NoError(t, err)
+	return kafka.Message{Key: []byte(event.AggregateID()), Value: value}
+}
+
+func Test_ShouldRecordPurchaseFromOrderCreatedEnvelope(t *testing.T) {
+	handler, recommendationRepository := setupOrderEventHandler()
+
+	err := handler(context.Background(), encodeEvent(t, kafka.OrderCreated{
+		ID: 10, CustomerNumber: "CUST-1",
+		Items: []kafka.OrderLine{{ProductID: 3, Quantity: 2}, {ProductID: 5, Quantity: 1}},
+	}))
+
+	assert.NoError(t, err)
+	assert.Len(t, recommendationRepository.purchases, 1)
+	assert.Equal(t, int64(10), recommendationRepository.purchases[0].OrderId)
+	assert.Len(t, recommendationRepository.purchases[0].Items, 2)
+	assert.Equal(t, int64(3), recommendationRepository.purchases[0].Items[0].ProductId)
+	assert.Equal(t, int64(5), recommendationRepository.purchases[0].Items[1].ProductId)
+}
+
+func Test_ShouldRecordPurchaseFromLegacySingleLineOrder(t *testing.T) {
+	handler, recommendationRepository := setupOrderEventHandler()
+
+	err := handler(context.Background(), encodeEvent(t, orderCreatedV1{
+		ID: 10, CustomerNumber: "CUST-1", ProductID: "3", Quantity: 2,
+	}))
+
+	assert.NoError(t, err)
+	assert.Len(t, recommendationRepository.purchases, 1)
+	assert.Equal(t, int64(3), recommendationRepository.purchases[0].Items[0].ProductId)
+	assert.Equal(t, int32(2), recommendationRepository.purchases[0].Items[0].Quantity)
+}
+
+func Test_ShouldRejectOrderEventWithUnknownMajorVersion(t *testing.T) {
+	handler, recommendationRepository := setupOrderEventHandler()
+
+	err := handler(context.Background(), encodeEvent(t, orderCreatedV3{kafka.OrderCreated{
+		ID: 10, CustomerNumber: "CUST-1", Items: []kafka.OrderLine{{ProductID: 3, Quantity: 2}},
+	}}))
+
+	assert.ErrorIs(t, err, kafka.ErrUnsupportedSchemaVersion)
+	assert.Empty(t, recommendationRepository.purchases)
+}
+
+func Test_ShouldRejectOrderEventWithoutEnvelope(t *testing.T) {
+	handler, recommendationRepository := setupOrderEventHandler()
+	value, _ := json.Marshal(map[string]interface{}{"id": 10, "customer_number": "CUST-1", "product_id": "3", "quantity": 2})
+
+	err := handler(context.Background(), kafka.Message{Key: []byte("order.created"), Value: value})
+
+	assert.ErrorIs(t, err, kafka.ErrInvalidEnvelope)
+	assert.Empty(t, recommendationRepository.purchases)
+}
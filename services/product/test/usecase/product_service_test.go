@@ -1,33 +1,41 @@
 package service
 
 import (
+	"strconv"
 	"testing"
 
 	"product-app/services/product/internal/domain"
 	"product-app/services/product/internal/usecase"
 	"product-app/services/product/internal/usecase/model"
+	"product-app/shared/kafka"
 
 	"github.com/stretchr/testify/assert"
 )
 
+const testProductEventsTopic = "product.events.test"
+
 func setupProductService() usecase.IProductService {
 	initialProducts := []domain.Product{
 		{
-			Id:    1,
-			Name:  "AirFryer",
-			Price: 1000,
-			Store: "ABC TECH",
+			Id:        1,
+			Name:      "AirFryer",
+			Price:     1000,
+			StoreID:   1,
+			StoreSlug: "abc-tech",
+			Store:     "ABC TECH",
 		},
 		{
-			Id:    2,
-			Name:  "Blender",
-			Price: 500,
-			Store: "XYZ Appliances",
+			Id:        2,
+			Name:      "Blender",
+			Price:     500,
+			StoreID:   2,
+			StoreSlug: "xyz-appliances",
+			Store:     "XYZ Appliances",
 		},
 	}
 
 	fakeRepository := NewFakeProductRepository(initialProducts)
-	return usecase.NewProductService(fakeRepository, nil)
+	return usecase.NewProductService(fakeRepository, NewFakeStoreRepository(testStores()), testProductEventsTopic)
 }
 
 func Test_ShouldGetAllProducts(t *testing.T) {
@@ -49,6 +57,41 @@ func Test_ShouldGetAllProductsByStore(t *testing.T) {
 	}
 }
 
+func Test_ShouldFindProductsPageByPage(t *testing.T) {
+	productService := setupProductService()
+
+	page, err := productService.FindProducts(model.ProductSearch{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page.TotalCount)
+	assert.Len(t, page.Products, 1)
+	assert.Equal(t, int64(1), page.Products[0].Id)
+	assert.NotEmpty(t, page.NextCursor)
+
+	page, err = productService.FindProducts(model.ProductSearch{Limit: 1, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	assert.Len(t, page.Products, 1)
+	assert.Equal(t, int64(2), page.Products[0].Id)
+	assert.Empty(t, page.NextCursor)
+}
+
+func Test_ShouldFindProductsByStoreName(t *testing.T) {
+	productService := setupProductService()
+
+	page, err := productService.FindProducts(model.ProductSearch{StoreSlug: "XYZ Appliances"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, page.TotalCount)
+	assert.Equal(t, int64(2), page.Products[0].Id)
+}
+
+func Test_ShouldRejectInvalidProductCursor(t *testing.T) {
+	productService := setupProductService()
+
+	_, err := productService.FindProducts(model.ProductSearch{Cursor: "not a cursor"})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidProductQuery)
+}
+
 func Test_ShouldGetById(t *testing.T) {
 	productService := setupProductService()
 
@@ -109,9 +152,11 @@ func Test_ShouldAddProduct(t *testing.T) {
 		Store:       "ABC TECH",
 		CategoryID:  1,
 	}
-	err := productService.Add(newProduct)
+	created, err := productService.Add(newProduct)
 
 	assert.NoError(t, err)
+	assert.Equal(t, int64(3), created.Id)
+	assert.Equal(t, "abc-tech", created.StoreSlug)
 
 	afterProducts := productService.GetAllProducts()
 	assert.Equal(t, 3, len(afterProducts))
@@ -123,5 +168,126 @@ func Test_ShouldAddProduct(t *testing.T) {
 	assert.Equal(t, "Digital microwave oven", addedProduct.Description)
 	assert.Equal(t, float32(10), addedProduct.Discount)
 	assert.Equal(t, "ABC TECH", addedProduct.Store)
+	assert.Equal(t, int64(1), addedProduct.StoreID)
 	assert.Equal(t, int64(1), addedProduct.CategoryID)
 }
+
+func Test_ShouldRecordProductCreatedEvent_WhenProductAdded(t *testing.T) {
+	fakeRepository := NewFakeProductRepository(nil)
+	productService := usecase.NewProductService(fakeRepository, NewFakeStoreRepository(testStores()), testProductEventsTopic)
+
+	_, err := productService.Add(model.ProductCreate{Name: "Kettle", Price: 300, StoreID: 1})
+
+	assert.NoError(t, err)
+	events := fakeRepository.(*FakeProductRepository).events
+	assert.Len(t, events, 1)
+	assert.Equal(t, testProductEventsTopic, events[0].Topic)
+	assert.Equal(t, "1", events[0].Key, "events are keyed by product id")
+
+	envelope, err := kafka.DecodeEnvelope(events[0].Value)
+	assert.NoError(t, err)
+	assert.Equal(t, kafka.EventTypeProductCreated, envelope.Type)
+	assert.Equal(t, "product-service", envelope.Source)
+	assert.Equal(t, "1.0", envelope.SchemaVersion)
+	assert.NotEmpty(t, envelope.ID)
+	assert.Equal(t, envelope.ID, envelope.CorrelationID)
+
+	var payload kafka.ProductCreated
+	assert.NoError(t, envelope.DecodeData(&payload))
+	assert.Equal(t, int64(1), payload.ID)
+	assert.Equal(t, "abc-tech", payload.StoreSlug)
+}
+
+func Test_ShouldRecordProductUpdatedEvent_WhenPriceUpdated(t *testing.T) {
+	fakeRepository := NewFakeProductRepository([]domain.Product{{Id: 1, Name: "AirFryer", Price: 1000, CategoryID: 4}})
+	productService := usecase.NewProductService(fakeRepository, NewFakeStoreRepository(testStores()), testProductEventsTopic)
+
+	err := productService.UpdatePrice(1, 4200)
+
+	assert.NoError(t, err)
+	events := fakeRepository.(*FakeProductRepository).events
+	assert.Len(t, events, 1)
+	assert.Equal(t, "1", events[0].Key)
+
+	envelope, err := kafka.DecodeEnvelope(events[0].Value)
+	assert.NoError(t, err)
+	var payload kafka.ProductUpdated
+	assert.NoError(t, envelope.DecodeData(&payload))
+	assert.Equal(t, int64(1), payload.ID)
+	assert.Equal(t, float32(4200), payload.Price)
+	assert.Equal(t, int64(4), payload.CategoryID)
+}
+
+func Test_ShouldRecordProductDeletedEvents_WhenProductsDeleted(t *testing.T) {
+	fakeRepository := NewFakeProductRepository([]domain.Product{
+		{Id: 1, Name: "AirFryer", CategoryID: 4},
+		{Id: 2, Name: "Blender", CategoryID: 5},
+		{Id: 3, Name: "Kettle", CategoryID: 5},
+	})
+	productService := usecase.NewProductService(fakeRepository, NewFakeStoreRepository(testStores()), testProductEventsTopic)
+
+	assert.NoError(t, productService.DeleteById(1))
+	assert.NoError(t, productService.DeleteAllProducts())
+
+	events := fakeRepository.(*FakeProductRepository).events
+	assert.Len(t, events, 3)
+	var deletedIds []int64
+	for _, event := range events {
+		envelope, err := kafka.DecodeEnvelope(event.Value)
+		assert.NoError(t, err)
+		var payload kafka.ProductDeleted
+		assert.NoError(t, envelope.DecodeData(&payload))
+		assert.Equal(t, strconv.FormatInt(payload.ID, 10), event.Key)
+		deletedIds = append(deletedIds, payload.ID)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, deletedIds)
+}
+
+func Test_ShouldNotRecordEvent_WhenProductInvalid(t *testing.T) {
+	fakeRepository := NewFakeProductRepository(nil)
+	productService := usecase.NewProductService(fakeRepository, NewFakeStoreRepository(testStores()), testProductEventsTopic)
+
+	_, err := productService.Add(model.ProductCreate{Name: "Kettle", Price: 0, StoreID: 1})
+
+	assert.Error(t, err)
+	assert.Empty(t, fakeRepository.(*FakeProductRepository).events)
+}
+
+func Test_ShouldAddProduct_ByStoreId(t *testing.T) {
+	productService := setupProductService()
+
+	_, err := productService.Add(model.ProductCreate{
+		Name:    "Kettle",
+		Price:   200,
+		StoreID: 2,
+	})
+	assert.NoError(t, err)
+
+	products := productService.GetAllProductsByStore("xyz-appliances")
+	assert.Len(t, products, 2)
+	assert.Equal(t, "XYZ Appliances", products[1].Store)
+}
+
+func Test_ShouldFailAddProduct_WhenStoreUnknown(t *testing.T) {
+	productService := setupProductService()
+
+	_, err := productService.Add(model.ProductCreate{
+		Name:  "Kettle",
+		Price: 200,
+		Store: "Unknown Store",
+	})
+
+	assert.Error(t, err)
+}
+
+func Test_ShouldFailAddProduct_WhenStoreClosed(t *testing.T) {
+	productService := setupProductService()
+
+	_, err := productService.Add(model.ProductCreate{
+		Name:    "Kettle",
+		Price:   200,
+		StoreID: 3,
+	})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRecommendationService(cacheTTL time.Duration) (usecase.IRecommendationService, *FakeRecommendationRepository) {
+	products := []domain.Product{
+		{Id: 1, Name: "AirFryer", Price: 1000, Store: "ABC TECH", CategoryID: 1},
+		{Id: 2, Name: "Blender", Price: 500, Store: "ABC TECH", CategoryID: 1},
+		{Id: 3, Name: "Toaster", Price: 300, Store: "ABC TECH", CategoryID: 1},
+		{Id: 4, Name: "Kettle", Price: 200, Store: "ABC TECH", CategoryID: 1},
+	}
+
+	recommendationRepository := NewFakeRecommendationRepository(
+		map[int64][]domain.RelatedProduct{
+			1: {
+				{Product: products[1], CoPurchaseCount: 7, Reason: domain.RecommendationReasonBoughtTogether},
+			},
+		},
+		map[int64][]domain.RelatedProduct{
+			1: {
+				{Product: products[0], UnitsSold: 40, Reason: domain.RecommendationReasonCategoryBestseller},
+				{Product: products[1], UnitsSold: 30, Reason: domain.RecommendationReasonCategoryBestseller},
+				{Product: products[3], UnitsSold: 20, Reason: domain.RecommendationReasonCategoryBestseller},
+				{Product: products[2], UnitsSold: 10, Reason: domain.RecommendationReasonCategoryBestseller},
+			},
+		},
+	)
+	productRepository := NewFakeProductRepository(products)
+	return usecase.NewRecommendationService(recommendationRepository, productRepository, cacheTTL), recommendationRepository
+}
+
+func Test_ShouldRankBoughtTogetherBeforeCategoryBestsellers(t *testing.T) {
+	recommendationService, _ := setupRecommendationService(0)
+
+	related, err := recommendationService.GetRelatedProducts(1, 3)
+
+	assert.NoError(t, err)
+	assert.Len(t, related, 3)
+	assert.Equal(t, int64(2), related[0].Product.Id)
+	assert.Equal(t, int64(7), related[0].CoPurchaseCount)
+	assert.Equal(t, domain.RecommendationReasonBoughtTogether, related[0].Reason)
+	assert.Equal(t, int64(4), related[1].Product.Id)
+	assert.Equal(t, domain.RecommendationReasonCategoryBestseller, related[1].Reason)
+	assert.Equal(t, int64(3), related[2].Product.Id)
+}
+
+func Test_ShouldServeRelatedProductsFromCache(t *testing.T) {
+	recommendationService, recommendationRepository := setupRecommendationService(time.Minute)
+
+	_, err := recommendationService.GetRelatedProducts(1, 3)
+	assert.NoError(t, err)
+	_, err = recommendationService.GetRelatedProducts(1, 3)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, recommendationRepository.lookups)
+}
+
+func Test_ShouldEvictLeastRecentlyUsedRelatedProducts_WhenCacheFull(t *testing.T) {
+	products := []domain.Product{}
+	for id := int64(1); id <= usecase.MaxCachedRelatedProducts/usecase.MaxRelatedProductsLimit+1; id++ {
+		products = append(products, domain.Product{Id: id, Name: "Product", Price: 100})
+	}
+	recommendationRepository := NewFakeRecommendationRepository(nil, nil)
+	recommendationService := usecase.NewRecommendationService(recommendationRepository, NewFakeProductRepository(products), time.Minute)
+
+	for _, product := range products {
+		for limit := 1; limit <= usecase.MaxRelatedProductsLimit; limit++ {
+			_, err := recommendationService.GetRelatedProducts(product.Id, limit)
+			assert.NoError(t, err)
+		}
+	}
+	lookups := recommendationRepository.lookups
+
+	_, _ = recommendationService.GetRelatedProducts(products[len(products)-1].Id, usecase.MaxRelatedProductsLimit)
+	assert.Equal(t, lookups, recommendationRepository.lookups, "the latest entry is still cached")
+	_, _ = recommendationService.GetRelatedProducts(1, 1)
+	assert.Equal(t, lookups+1, recommendationRepository.lookups, "the oldest entry was evicted")
+}
+
+func Test_ShouldFailRelatedProducts_WhenProductMissing(t *testing.T) {
+	recommendationService, _ := setupRecommendationService(0)
+
+	_, err := recommendationService.GetRelatedProducts(99, 3)
+
+	assert.Error(t, err)
+}
+
+func Test_ShouldRecordPurchase(t *testing.T) {
+	recommendationService, recommendationRepository := setupRecommendationService(0)
+
+	err := recommendationService.RecordPurchase(domain.Purchase{
+		OrderId:        10,
+		CustomerNumber: "CUST-001",
+		Items:          []domain.PurchaseItem{{ProductId: 1, Quantity: 2}},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, recommendationRepository.purchases, 1)
+}
+
+func Test_ShouldFailRecordPurchase_WhenCustomerMissing(t *testing.T) {
+	recommendationService, _ := setupRecommendationService(0)
+
+	err := recommendationService.RecordPurchase(domain.Purchase{
+		OrderId: 10,
+		Items:   []domain.PurchaseItem{{ProductId: 1, Quantity: 2}},
+	})
+
+	assert.Error(t, err)
+}
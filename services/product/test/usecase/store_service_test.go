@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"product-app/services/product/internal/domain"
+	"product-app/services/product/internal/usecase"
+	"product-app/services/product/internal/usecase/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStores() []domain.Store {
+	return []domain.Store{
+		{Id: 1, Slug: "abc-tech", Name: "ABC TECH", OwnerUserId: 7, Status: domain.StoreStatusActive},
+		{Id: 2, Slug: "xyz-appliances", Name: "XYZ Appliances", Status: domain.StoreStatusActive},
+		{Id: 3, Slug: "old-shop", Name: "Old Shop", OwnerUserId: 7, Status: domain.StoreStatusClosed},
+	}
+}
+
+func setupStoreService() usecase.IStoreService {
+	return usecase.NewStoreService(NewFakeStoreRepository(testStores()))
+}
+
+func Test_ShouldAddStoreWithDerivedSlug(t *testing.T) {
+	storeService := setupStoreService()
+
+	store, err := storeService.Add(model.StoreCreate{
+		Name:         "Tech Store",
+		ContactEmail: "hello@techstore.com",
+	}, 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tech-store", store.Slug)
+	assert.Equal(t, int64(9), store.OwnerUserId)
+	assert.Equal(t, domain.StoreStatusActive, store.Status)
+}
+
+func Test_ShouldRejectDuplicateStoreSlug(t *testing.T) {
+	storeService := setupStoreService()
+
+	_, err := storeService.Add(model.StoreCreate{Name: "abc tech"}, 9)
+
+	assert.True(t, errors.Is(err, domain.ErrStoreSlugTaken))
+}
+
+func Test_ShouldRejectInvalidStoreSlug(t *testing.T) {
+	storeService := setupStoreService()
+
+	_, err := storeService.Add(model.StoreCreate{Name: "Tech Store", Slug: "Tech Store"}, 9)
+
+	assert.Error(t, err)
+}
+
+func Test_ShouldGetStoreBySlug_IgnoringCase(t *testing.T) {
+	storeService := setupStoreService()
+
+	store, err := storeService.GetBySlug("Abc Tech")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), store.Id)
+}
+
+func Test_ShouldUpdateStore_WhenOwner(t *testing.T) {
+	storeService := setupStoreService()
+
+	store, err := storeService.Update(1, model.StoreUpdate{
+		Name:   "ABC TECH Outlet",
+		Status: domain.StoreStatusInactive,
+	}, model.Requester{UserId: 7})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ABC TECH Outlet", store.Name)
+	assert.Equal(t, "abc-tech", store.Slug)
+	assert.Equal(t, domain.StoreStatusInactive, store.Status)
+}
+
+func Test_ShouldRejectStoreUpdate_WhenNotOwner(t *testing.T) {
+	storeService := setupStoreService()
+
+	_, err := storeService.Update(1, model.StoreUpdate{Name: "Hijacked"}, model.Requester{UserId: 8})
+
+	assert.True(t, errors.Is(err, domain.ErrStoreForbidden))
+}
+
+func Test_ShouldRejectUnownedStoreUpdate_WhenNotAdmin(t *testing.T) {
+	storeService := setupStoreService()
+
+	_, err := storeService.Update(2, model.StoreUpdate{Name: "Hijacked"}, model.Requester{UserId: 8})
+
+	assert.True(t, errors.Is(err, domain.ErrStoreForbidden))
+}
+
+func Test_ShouldUpdateUnownedStore_WhenAdmin(t *testing.T) {
+	storeService := setupStoreService()
+
+	store, err := storeService.Update(2, model.StoreUpdate{Name: "XYZ Home"}, model.Requester{UserId: 99, Admin: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "XYZ Home", store.Name)
+}
+
+func Test_ShouldDeleteStore_WhenOwner(t *testing.T) {
+	storeService := setupStoreService()
+
+	err := storeService.DeleteById(3, model.Requester{UserId: 7})
+	assert.NoError(t, err)
+
+	_, err = storeService.GetById(3)
+	assert.True(t, errors.Is(err, domain.ErrStoreNotFound))
+}
+
+func Test_ShouldSlugifyStoreNames(t *testing.T) {
+	assert.Equal(t, "tech-store", domain.Slugify("  Tech   Store "))
+	assert.Equal(t, "tech-store", domain.Slugify("tech store"))
+	assert.Equal(t, "dekorasyon-sarayı", domain.Slugify("Dekorasyon Sarayı"))
+	assert.Equal(t, "", domain.Slugify("--"))
+}
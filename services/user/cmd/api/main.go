@@ -2,39 +2,75 @@ package main
 
 import (
 	"context"
+	"log"
+	"net"
+	"time"
 
+	usergrpc "product-app/services/user/internal/adapters/grpc"
 	"product-app/services/user/internal/adapters/http/controller"
 	"product-app/services/user/internal/adapters/postgresql"
 	pgcommon "product-app/services/user/internal/adapters/postgresql/common"
 	"product-app/services/user/internal/config"
 	"product-app/services/user/internal/usecase"
+	"product-app/shared/auth"
+	"product-app/shared/grpcx"
+	"product-app/shared/idempotency"
+	userv1 "product-app/shared/proto/user/v1"
 
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
 	ctx := context.Background()
-	e := buildServer(ctx)
-	e.Start(":8083")
+
+	configurationManager, err := config.NewConfigurationManager()
+	if err != nil {
+		log.Fatalf("user-service not started: %v", err)
+	}
+	log.Printf("effective configuration:\n%s", configurationManager)
+	auth.SetSecret(configurationManager.Auth.JWTSecret)
+
+	e := buildServer(ctx, configurationManager)
+	e.Logger.Fatal(e.Start(configurationManager.HttpAddress))
 }
 
-func buildServer(ctx context.Context) *echo.Echo {
+func buildServer(ctx context.Context, configurationManager *config.ConfigurationManager) *echo.Echo {
 	e := echo.New()
 
-	configurationManager := config.NewConfigurationManager()
 	dbPool := pgcommon.GetConnectionPool(ctx, configurationManager.PostgreSqlConfig)
 
-	registerRoutes(e, dbPool)
+	registerRoutes(e, dbPool, configurationManager)
 	return e
 }
 
-func registerRoutes(e *echo.Echo, dbPool *pgxpool.Pool) {
+func registerRoutes(e *echo.Echo, dbPool *pgxpool.Pool, configurationManager *config.ConfigurationManager) {
 	userRepository := postgresql.NewUserRepository(dbPool)
 	userService := usecase.NewUserService(userRepository)
 	userController := controller.NewUserController(userService)
 
-	userController.RegisterRoutes(e)
+	idempotencyStore := idempotency.NewPostgresStore(dbPool)
+	go idempotencyStore.StartPruning(context.Background(), time.Hour)
+	userController.RegisterRoutes(e, idempotency.Middleware(idempotencyStore, configurationManager.IdempotencyTTL))
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+
+	go startGRPCServer(configurationManager.GrpcAddress, userService)
+}
+
+func startGRPCServer(address string, userService usecase.IUserService) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Printf("grpc server not started: %v", err)
+		return
+	}
+
+	server, healthServer := grpcx.NewServer(usergrpc.ProtectedMethods...)
+	userv1.RegisterUserServiceServer(server, usergrpc.NewUserServer(userService))
+	healthServer.SetServingStatus(userv1.UserService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	if err := server.Serve(listener); err != nil {
+		log.Printf("grpc server stopped: %v", err)
+	}
 }
@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"product-app/services/user/internal/domain"
+	"product-app/services/user/internal/usecase"
+	"product-app/shared/auth"
+	userv1 "product-app/shared/proto/user/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProtectedMethods lists the RPCs that require a bearer token.
+var ProtectedMethods = []string{
+	userv1.UserService_GetUser_FullMethodName,
+	userv1.UserService_UpdateUser_FullMethodName,
+	userv1.UserService_DeleteUser_FullMethodName,
+}
+
+// UserServer serves userv1.UserService on top of IUserService.
+type UserServer struct {
+	userv1.UnimplementedUserServiceServer
+	userService usecase.IUserService
+}
+
+// NewUserServer creates a new instance of UserServer
+func NewUserServer(userService usecase.IUserService) *UserServer {
+	return &UserServer{userService: userService}
+}
+
+func (server *UserServer) Register(ctx context.Context, req *userv1.RegisterRequest) (*userv1.RegisterResponse, error) {
+	user, err := server.userService.Register(req.GetUsername(), req.GetEmail(), req.GetPassword(), req.GetFirstName(), req.GetLastName())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &userv1.RegisterResponse{User: toProtoUser(user)}, nil
+}
+
+func (server *UserServer) Login(ctx context.Context, req *userv1.LoginRequest) (*userv1.LoginResponse, error) {
+	user, err := server.userService.Login(req.GetUsernameOrEmail(), req.GetPassword())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	token, err := auth.GenerateToken(user.Id, user.Username, user.Email, user.Role)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to generate token")
+	}
+	return &userv1.LoginResponse{Token: token, User: toProtoUser(user)}, nil
+}
+
+func (server *UserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+	user, err := server.userService.GetById(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &userv1.GetUserResponse{User: toProtoUser(user)}, nil
+}
+
+func (server *UserServer) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequest) (*userv1.UpdateUserResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+	user, err := server.userService.GetById(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	user.Username = req.GetUsername()
+	user.Email = req.GetEmail()
+	user.FirstName = req.GetFirstName()
+	user.LastName = req.GetLastName()
+	if err := server.userService.UpdateUser(user); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	updated, err := server.userService.GetById(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &userv1.UpdateUserResponse{User: toProtoUser(updated)}, nil
+}
+
+func (server *UserServer) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest) (*userv1.DeleteUserResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+	if err := server.userService.DeleteById(req.GetId()); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &userv1.DeleteUserResponse{}, nil
+}
+
+func toProtoUser(user domain.User) *userv1.User {
+	return &userv1.User{
+		Id:        user.Id,
+		Username:  user.Username,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+	}
+}
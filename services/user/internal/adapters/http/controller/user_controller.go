@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"product-app/services/user/internal/adapters/http/controller/response"
 	"product-app/services/user/internal/adapters/http/middleware"
@@ -41,6 +44,7 @@ type UserResponse struct {
 	Email     string    `json:"email"`
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -59,9 +63,11 @@ func NewUserController(userService usecase.IUserService) *UserController {
 	return &UserController{userService: userService}
 }
 
-func (userController *UserController) RegisterRoutes(e *echo.Echo) {
+// RegisterRoutes registers the user routes; idempotent is applied to
+// registration.
+func (userController *UserController) RegisterRoutes(e *echo.Echo, idempotent ...echo.MiddlewareFunc) {
 	// Public routes (no authentication required)
-	e.POST("/api/v1/auth/register", userController.Register)
+	e.POST("/api/v1/auth/register", userController.Register, idempotent...)
 	e.POST("/api/v1/auth/login", userController.Login)
 
 	// Protected routes (authentication required)
@@ -79,15 +85,21 @@ func (userController *UserController) Register(c echo.Context) error {
 		})
 	}
 
-	if err := userController.userService.Register(req.Username, req.Email, req.Password, req.FirstName, req.LastName); err != nil {
+	user, err := userController.userService.Register(req.Username, req.Email, req.Password, req.FirstName, req.LastName)
+	if errors.Is(err, domain.ErrInvalidRegistration) {
 		return c.JSON(http.StatusUnprocessableEntity, response.ErrorResponse{
 			Error: err.Error(),
 		})
 	}
+	if err != nil {
+		log.Printf("registration failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error: "Registration failed",
+		})
+	}
 
-	return c.JSON(http.StatusCreated, MessageResponse{
-		Message: "User registered successfully",
-	})
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/users/%d", user.Id))
+	return c.JSON(http.StatusCreated, buildUserResponse(user))
 }
 
 func (userController *UserController) Login(c echo.Context) error {
@@ -106,7 +118,7 @@ func (userController *UserController) Login(c echo.Context) error {
 	}
 
 	// Generate JWT token
-	token, err := middleware.GenerateToken(user.Id, user.Username, user.Email)
+	token, err := middleware.GenerateToken(user.Id, user.Username, user.Email, user.Role)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error: "Failed to generate token",
@@ -220,6 +232,7 @@ func buildUserResponse(user domain.User) UserResponse {
 		Email:     user.Email,
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
+		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}
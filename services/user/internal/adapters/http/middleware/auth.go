@@ -6,8 +6,8 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func GenerateToken(userId int64, username, email string) (string, error) {
-	return auth.GenerateToken(userId, username, email)
+func GenerateToken(userId int64, username, email string, roles ...string) (string, error) {
+	return auth.GenerateToken(userId, username, email, roles...)
 }
 
 func JWTMiddleware() echo.MiddlewareFunc {
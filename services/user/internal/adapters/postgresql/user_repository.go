@@ -25,11 +25,11 @@ func NewUserRepository(dbPool *pgxpool.Pool) ports.UserRepository {
 func (userRepository *UserRepository) GetById(userId int64) (domain.User, error) {
 	ctx := context.Background()
 
-	getByIdSql := `SELECT id, username, email, password, first_name, last_name, created_at, updated_at FROM users WHERE id = $1`
+	getByIdSql := `SELECT id, username, email, password, first_name, last_name, role, created_at, updated_at FROM users WHERE id = $1`
 	queryRow := userRepository.dbPool.QueryRow(ctx, getByIdSql, userId)
 
 	var user domain.User
-	scanErr := queryRow.Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	scanErr := queryRow.Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.FirstName, &user.LastName, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
 	if errors.Is(scanErr, pgx.ErrNoRows) {
 		return domain.User{}, fmt.Errorf("user not found with id %d: %w", userId, scanErr)
@@ -45,11 +45,11 @@ func (userRepository *UserRepository) GetById(userId int64) (domain.User, error)
 func (userRepository *UserRepository) GetByUsername(username string) (domain.User, error) {
 	ctx := context.Background()
 
-	getByUsernameSql := `SELECT id, username, email, password, first_name, last_name, created_at, updated_at FROM users WHERE username = $1`
+	getByUsernameSql := `SELECT id, username, email, password, first_name, last_name, role, created_at, updated_at FROM users WHERE username = $1`
 	queryRow := userRepository.dbPool.QueryRow(ctx, getByUsernameSql, username)
 
 	var user domain.User
-	scanErr := queryRow.Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	scanErr := queryRow.Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.FirstName, &user.LastName, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
 	if errors.Is(scanErr, pgx.ErrNoRows) {
 		return domain.User{}, fmt.Errorf("user not found with username %s: %w", username, scanErr)
@@ -65,11 +65,11 @@ func (userRepository *UserRepository) GetByUsername(username string) (domain.Use
 func (userRepository *UserRepository) GetByEmail(email string) (domain.User, error) {
 	ctx := context.Background()
 
-	getByEmailSql := `SELECT id, username, email, password, first_name, last_name, created_at, updated_at FROM users WHERE email = $1`
+	getByEmailSql := `SELECT id, username, email, password, first_name, last_name, role, created_at, updated_at FROM users WHERE email = $1`
 	queryRow := userRepository.dbPool.QueryRow(ctx, getByEmailSql, email)
 
 	var user domain.User
-	scanErr := queryRow.Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	scanErr := queryRow.Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.FirstName, &user.LastName, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
 	if errors.Is(scanErr, pgx.ErrNoRows) {
 		return domain.User{}, fmt.Errorf("user not found with email %s: %w", email, scanErr)
@@ -82,26 +82,26 @@ func (userRepository *UserRepository) GetByEmail(email string) (domain.User, err
 	return user, nil
 }
 
-func (userRepository *UserRepository) AddUser(user domain.User) error {
+func (userRepository *UserRepository) AddUser(user domain.User) (domain.User, error) {
 	ctx := context.Background()
 
 	insertUserSQL := `
-		INSERT INTO users (username, email, password, first_name, last_name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id;
+		INSERT INTO users (username, email, password, first_name, last_name, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, ''), 'customer'), $7, $8)
+		RETURNING id, role, created_at, updated_at;
 	`
 
-	var userId int64
 	err := userRepository.dbPool.QueryRow(ctx, insertUserSQL,
-		user.Username, user.Email, user.Password, user.FirstName, user.LastName, user.CreatedAt, user.UpdatedAt).Scan(&userId)
+		user.Username, user.Email, user.Password, user.FirstName, user.LastName, user.Role, user.CreatedAt, user.UpdatedAt).
+		Scan(&user.Id, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		log.Printf("❌ Error inserting user: %v", err)
-		return fmt.Errorf("failed to insert user: %w", err)
+		return domain.User{}, fmt.Errorf("failed to insert user: %w", err)
 	}
 
-	log.Printf("✅ User inserted with ID: %d", userId)
-	return nil
+	log.Printf("✅ User inserted with ID: %d", user.Id)
+	return user, nil
 }
 
 func (userRepository *UserRepository) UpdateUser(user domain.User) error {
@@ -1,6 +1,17 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidRegistration marks a registration the client has to correct.
+var ErrInvalidRegistration = errors.New("invalid registration")
+
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+)
 
 type User struct {
 	Id        int64     `json:"id"`
@@ -9,6 +20,7 @@ type User struct {
 	Password  string    `json:"-"`
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -16,7 +16,7 @@ import (
 )
 
 type IUserService interface {
-	Register(username, email, password, firstName, lastName string) error
+	Register(username, email, password, firstName, lastName string) (domain.User, error)
 	Login(usernameOrEmail, password string) (domain.User, error)
 	GetById(userId int64) (domain.User, error)
 	UpdateUser(user domain.User) error
@@ -33,22 +33,22 @@ func NewUserService(userRepository ports.UserRepository) IUserService {
 	}
 }
 
-func (userService *UserService) Register(username, email, password, firstName, lastName string) error {
+func (userService *UserService) Register(username, email, password, firstName, lastName string) (domain.User, error) {
 	if err := validateRegistration(username, email, password, firstName, lastName); err != nil {
-		return err
+		return domain.User{}, fmt.Errorf("%w: %v", domain.ErrInvalidRegistration, err)
 	}
 
 	if err := userService.ensureUsernameAvailable(username); err != nil {
-		return err
+		return domain.User{}, err
 	}
 
 	if err := userService.ensureEmailAvailable(email); err != nil {
-		return err
+		return domain.User{}, err
 	}
 
 	hashedPassword, err := hashPassword(password)
 	if err != nil {
-		return fmt.Errorf("failed to hash password: %w", err)
+		return domain.User{}, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	user := buildUser(username, email, hashedPassword, firstName, lastName)
@@ -100,14 +100,14 @@ func (userService *UserService) DeleteById(userId int64) error {
 
 func (userService *UserService) ensureUsernameAvailable(username string) error {
 	if _, err := userService.userRepository.GetByUsername(username); err == nil {
-		return errors.New("username already exists")
+		return fmt.Errorf("%w: username already exists", domain.ErrInvalidRegistration)
 	}
 	return nil
 }
 
 func (userService *UserService) ensureEmailAvailable(email string) error {
 	if _, err := userService.userRepository.GetByEmail(email); err == nil {
-		return errors.New("email already exists")
+		return fmt.Errorf("%w: email already exists", domain.ErrInvalidRegistration)
 	}
 	return nil
 }
@@ -120,6 +120,7 @@ func buildUser(username, email, hashedPassword, firstName, lastName string) doma
 		Password:  hashedPassword,
 		FirstName: firstName,
 		LastName:  lastName,
+		Role:      domain.RoleCustomer,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -0,0 +1,4 @@
+-- Roles are copied into the JWT at login. Grant admin with:
+--   UPDATE users SET role = 'admin' WHERE username = '...';
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'customer';
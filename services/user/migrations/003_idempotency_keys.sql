@@ -0,0 +1,14 @@
+-- Idempotency-Key records for retried POST requests (see shared/idempotency).
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  scope           TEXT      NOT NULL,
+  idempotency_key TEXT      NOT NULL,
+  fingerprint     TEXT      NOT NULL,
+  status_code     INT,
+  headers         JSONB     NOT NULL DEFAULT '{}',
+  body            BYTEA,
+  created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+  expires_at      TIMESTAMP NOT NULL,
+  PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -42,10 +42,10 @@ func (repo *FakeUserRepository) GetByEmail(email string) (domain.User, error) {
 	return domain.User{}, errors.New(fmt.Sprintf("user not found with email %s", email))
 }
 
-func (repo *FakeUserRepository) AddUser(user domain.User) error {
+func (repo *FakeUserRepository) AddUser(user domain.User) (domain.User, error) {
 	user.Id = int64(len(repo.users)) + 1
 	repo.users = append(repo.users, user)
-	return nil
+	return user, nil
 }
 
 func (repo *FakeUserRepository) UpdateUser(user domain.User) error {
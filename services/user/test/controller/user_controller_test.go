@@ -6,9 +6,13 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	httpcontroller "product-app/services/user/internal/adapters/http/controller"
+	"product-app/services/user/internal/domain"
 	"product-app/services/user/internal/usecase"
+	"product-app/shared/auth"
+	"product-app/shared/idempotency"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -40,6 +44,13 @@ func Test_ShouldRegisterUser(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/api/v1/users/1", rec.Header().Get(echo.HeaderLocation))
+
+	var response map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, float64(1), response["id"])
+	assert.Equal(t, "johndoe", response["username"])
+	assert.Nil(t, response["password"])
 }
 
 func Test_ShouldLoginUser(t *testing.T) {
@@ -80,6 +91,11 @@ func Test_ShouldLoginUser(t *testing.T) {
 	json.Unmarshal(loginRec.Body.Bytes(), &response)
 	assert.Equal(t, "Login successful", response["message"])
 	assert.NotEmpty(t, response["token"])
+
+	claims, err := auth.ParseToken(response["token"].(string))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.RoleCustomer}, claims.Roles)
+	assert.False(t, claims.HasRole(auth.RoleAdmin))
 }
 
 func Test_ShouldGetUserById(t *testing.T) {
@@ -183,3 +199,26 @@ func Test_ShouldDeleteUser(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+func Test_ShouldReplayRegistration_WhenIdempotencyKeyRepeated(t *testing.T) {
+	e := echo.New()
+	setupUserController().RegisterRoutes(e, idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour))
+
+	register := func() *httptest.ResponseRecorder {
+		registerJSON := `{"username":"janedoe","email":"jane@example.com","password":"secret123","first_name":"Jane","last_name":"Doe"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", strings.NewReader(registerJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(idempotency.HeaderKey, "register-jane")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := register()
+	second := register()
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code, "a retried registration must not fail as a duplicate")
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(idempotency.HeaderReplayed))
+}
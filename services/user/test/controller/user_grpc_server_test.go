@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	usergrpc "product-app/services/user/internal/adapters/grpc"
+	"product-app/services/user/internal/usecase"
+	"product-app/shared/auth"
+	"product-app/shared/grpcx"
+	userv1 "product-app/shared/proto/user/v1"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func setupUserGRPCClient(t *testing.T) userv1.UserServiceClient {
+	userService := usecase.NewUserService(NewFakeUserRepository(nil))
+
+	listener := bufconn.Listen(1024 * 1024)
+	server, _ := grpcx.NewServer(usergrpc.ProtectedMethods...)
+	userv1.RegisterUserServiceServer(server, usergrpc.NewUserServer(userService))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpcx.Dial("passthrough:///bufnet", grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return userv1.NewUserServiceClient(conn)
+}
+
+func Test_ShouldRegisterLoginAndGetUserOverGRPC(t *testing.T) {
+	client := setupUserGRPCClient(t)
+	ctx := context.Background()
+
+	registered, err := client.Register(ctx, &userv1.RegisterRequest{
+		Username:  "johndoe",
+		Email:     "john@example.com",
+		Password:  "secret123",
+		FirstName: "John",
+		LastName:  "Doe",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), registered.GetUser().GetId())
+
+	login, err := client.Login(ctx, &userv1.LoginRequest{UsernameOrEmail: "johndoe", Password: "secret123"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, login.GetToken())
+
+	response, err := client.GetUser(auth.ContextWithToken(ctx, login.GetToken()), &userv1.GetUserRequest{Id: login.GetUser().GetId()})
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", response.GetUser().GetEmail())
+}
+
+func Test_ShouldRejectLoginWithWrongPasswordOverGRPC(t *testing.T) {
+	client := setupUserGRPCClient(t)
+
+	_, err := client.Login(context.Background(), &userv1.LoginRequest{UsernameOrEmail: "nobody", Password: "wrong"})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_ShouldRejectGetUserWithoutToken(t *testing.T) {
+	client := setupUserGRPCClient(t)
+
+	_, err := client.GetUser(context.Background(), &userv1.GetUserRequest{Id: 1})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_ShouldRejectGetUserWithInvalidToken(t *testing.T) {
+	client := setupUserGRPCClient(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-token")
+
+	_, err := client.GetUser(ctx, &userv1.GetUserRequest{Id: 1})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
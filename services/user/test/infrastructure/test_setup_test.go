@@ -74,6 +74,7 @@ func createSchema(ctx context.Context, pool *pgxpool.Pool) {
 			password TEXT NOT NULL,
 			first_name TEXT NOT NULL,
 			last_name TEXT NOT NULL,
+			role VARCHAR(20) NOT NULL DEFAULT 'customer',
 			created_at TIMESTAMP NOT NULL,
 			updated_at TIMESTAMP NOT NULL
 		);
@@ -26,10 +26,12 @@ func TestUserRepository_AddAndGetById(t *testing.T) {
 		UpdatedAt: now,
 	}
 
-	err := repo.AddUser(user)
+	created, err := repo.AddUser(user)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(1), created.Id)
+	assert.False(t, created.CreatedAt.IsZero())
 
-	savedUser, err := repo.GetById(1)
+	savedUser, err := repo.GetById(created.Id)
 	assert.NoError(t, err)
 	assert.Equal(t, user.Username, savedUser.Username)
 }
@@ -40,7 +42,7 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 	repo := postgresql.NewUserRepository(dbPool)
 	now := time.Now()
 
-	err := repo.AddUser(domain.User{
+	_, err := repo.AddUser(domain.User{
 		Username:  "admin",
 		Email:     "admin@test.com",
 		Password:  "admin123",
@@ -60,7 +62,7 @@ func TestUserRepository_Update(t *testing.T) {
 	repo := postgresql.NewUserRepository(dbPool)
 	now := time.Now()
 
-	_ = repo.AddUser(domain.User{
+	_, _ = repo.AddUser(domain.User{
 		Username:  "oldname",
 		Email:     "user@test.com",
 		Password:  "123",
@@ -88,7 +90,7 @@ func TestUserRepository_DeleteById(t *testing.T) {
 	repo := postgresql.NewUserRepository(dbPool)
 	now := time.Now()
 
-	_ = repo.AddUser(domain.User{
+	_, _ = repo.AddUser(domain.User{
 		Username:  "todelete",
 		Email:     "delete@test.com",
 		Password:  "123",
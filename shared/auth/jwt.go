@@ -2,37 +2,73 @@ package auth
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
+// RoleAdmin is the role that may act on every user's resources.
+const RoleAdmin = "admin"
+
 type Claims struct {
-	UserId   int64  `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	UserId   int64    `json:"user_id"`
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Roles    []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// HasRole reports whether the token grants role.
+func (claims *Claims) HasRole(role string) bool {
+	for _, granted := range claims.Roles {
+		if granted == role {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	jwtSecret         []byte
+	warnDefaultSecret sync.Once
+)
+
+// SetSecret installs the signing key loaded by the service configuration.
+// Services call it once at startup, before serving requests.
+func SetSecret(secret string) {
+	jwtSecret = []byte(secret)
+}
+
+// getJWTSecret falls back to JWT_SECRET and then to a development key only
+// when SetSecret was never called, as in tests and local tools; services
+// reject a missing secret at startup outside development.
 func getJWTSecret() []byte {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return []byte("your-secret-key-change-this-in-production")
+	if len(jwtSecret) > 0 {
+		return jwtSecret
+	}
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		return []byte(secret)
 	}
-	return []byte(secret)
+	warnDefaultSecret.Do(func() {
+		log.Println("auth: no JWT secret configured, using the development key")
+	})
+	return []byte("dev-only-jwt-secret")
 }
 
-func GenerateToken(userId int64, username, email string) (string, error) {
+func GenerateToken(userId int64, username, email string, roles ...string) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 
 	claims := &Claims{
 		UserId:   userId,
 		Username: username,
 		Email:    email,
+		Roles:    roles,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -43,6 +79,24 @@ func GenerateToken(userId int64, username, email string) (string, error) {
 	return token.SignedString(getJWTSecret())
 }
 
+// ParseToken validates a signed token string and returns its claims.
+func ParseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return getJWTSecret(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
+
 func JWTMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -60,25 +114,38 @@ func JWTMiddleware() echo.MiddlewareFunc {
 				})
 			}
 
-			claims := &Claims{}
-			token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-				}
-				return getJWTSecret(), nil
-			})
-
-			if err != nil || !token.Valid {
+			claims, err := ParseToken(tokenString)
+			if err != nil {
 				return c.JSON(http.StatusUnauthorized, map[string]string{
 					"error": "Invalid or expired token",
 				})
 			}
 
-			c.Set("user_id", claims.UserId)
-			c.Set("username", claims.Username)
-			c.Set("email", claims.Email)
-
+			setClaims(c, claims)
 			return next(c)
 		}
 	}
 }
+
+// OptionalJWTMiddleware lets requests without an Authorization header
+// through anonymously and authenticates the others like JWTMiddleware,
+// rejecting invalid tokens rather than ignoring them.
+func OptionalJWTMiddleware() echo.MiddlewareFunc {
+	required := JWTMiddleware()
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		authenticated := required(next)
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("Authorization") == "" {
+				return next(c)
+			}
+			return authenticated(c)
+		}
+	}
+}
+
+func setClaims(c echo.Context, claims *Claims) {
+	c.Set("user_id", claims.UserId)
+	c.Set("username", claims.Username)
+	c.Set("email", claims.Email)
+	c.Set("roles", claims.Roles)
+}
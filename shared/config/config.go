@@ -0,0 +1,276 @@
+// Package config loads typed service configuration from struct tags.
+//
+// Values are resolved in this order, later sources winning:
+//
+//  1. the `default` tag
+//  2. the YAML file named by CONFIG_FILE, following the `yaml` tags
+//  3. the environment variable named by the `env` tag
+//  4. the file named by <env>_FILE, e.g. JWT_SECRET_FILE (trailing newline trimmed)
+//
+// Fields tagged `secret:"true"` only fall back to their default when APP_ENV
+// is a development environment; anywhere else a missing secret is an error,
+// so a service never starts with a well-known key. `required:"true"` fields
+// must end up non-empty in every environment. The target and any nested
+// section may also implement Validator for rules beyond a single value.
+//
+// Supported field types are string, bool, signed integers, float64,
+// time.Duration (a bare number means seconds), []string (comma separated)
+// and nested structs. Fields tagged `yaml:"-"` are ignored.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvironmentVariable selects the environment, e.g. "development" or
+	// "production". When unset, Load also accepts a top-level "environment"
+	// key in the YAML file, and otherwise assumes production.
+	EnvironmentVariable = "APP_ENV"
+	// FileVariable optionally names a YAML file with configuration values.
+	FileVariable = "CONFIG_FILE"
+)
+
+// Validator is implemented by configuration structs with cross-field rules.
+type Validator interface {
+	Validate() error
+}
+
+// Environment returns the value of APP_ENV, defaulting to "production".
+func Environment() string {
+	if environment := strings.TrimSpace(os.Getenv(EnvironmentVariable)); environment != "" {
+		return strings.ToLower(environment)
+	}
+	return "production"
+}
+
+// IsDevelopment reports whether environment allows default secrets.
+func IsDevelopment(environment string) bool {
+	switch environment {
+	case "dev", "development", "local", "test":
+		return true
+	}
+	return false
+}
+
+// Load fills target, which must be a pointer to a struct, and validates it.
+// All problems are reported together.
+func Load(target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return errors.New("config: target must be a pointer to a struct")
+	}
+
+	document, err := readFile(os.Getenv(FileVariable))
+	if err != nil {
+		return err
+	}
+
+	environment := Environment()
+	if _, set := os.LookupEnv(EnvironmentVariable); !set && document["environment"] != nil {
+		environment = strings.ToLower(yamlString(document["environment"]))
+	}
+
+	loader := &loader{development: IsDevelopment(environment)}
+	loader.loadStruct(value.Elem(), document)
+
+	if len(loader.problems) == 0 {
+		loader.validate(value.Elem())
+	}
+	if len(loader.problems) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(loader.problems...))
+	}
+	return nil
+}
+
+func readFile(path string) (map[string]interface{}, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: failed to read %s: %w", path, err)
+	}
+	document := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("config: failed to parse %s: %w", path, err)
+	}
+	return document, nil
+}
+
+type loader struct {
+	development bool
+	problems    []error
+}
+
+func (loader *loader) loadStruct(value reflect.Value, document map[string]interface{}) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() || field.Tag.Get("yaml") == "-" {
+			continue
+		}
+
+		yamlKey := yamlName(field)
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			nested, _ := document[yamlKey].(map[string]interface{})
+			loader.loadStruct(value.Field(i), nested)
+			continue
+		}
+
+		loader.loadField(value.Field(i), field, document, yamlKey)
+	}
+}
+
+// validate calls Validate on nested sections first, then on the struct
+// itself.
+func (loader *loader) validate(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.IsExported() && field.Tag.Get("yaml") != "-" &&
+			field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			loader.validate(value.Field(i))
+		}
+	}
+	if validator, ok := value.Addr().Interface().(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			loader.problems = append(loader.problems, err)
+		}
+	}
+}
+
+func (loader *loader) loadField(value reflect.Value, field reflect.StructField, document map[string]interface{}, yamlKey string) {
+	name := settingName(field)
+	envKey := field.Tag.Get("env")
+	secret := field.Tag.Get("secret") == "true"
+
+	raw, found := "", false
+	if defaultValue, ok := field.Tag.Lookup("default"); ok && (!secret || loader.development) {
+		raw, found = defaultValue, true
+	}
+	if fileValue, ok := document[yamlKey]; ok {
+		raw, found = yamlString(fileValue), true
+	}
+	if envKey != "" {
+		if envValue, ok := os.LookupEnv(envKey); ok && envValue != "" {
+			raw, found = envValue, true
+		}
+		if path := os.Getenv(envKey + "_FILE"); path != "" {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				loader.problems = append(loader.problems, fmt.Errorf("%s_FILE: %w", envKey, err))
+				return
+			}
+			raw, found = strings.TrimRight(string(content), "\r\n"), true
+		}
+	}
+
+	if !found || raw == "" {
+		switch {
+		case secret && !loader.development:
+			loader.problems = append(loader.problems,
+				fmt.Errorf("%s is a secret and must be set outside development (use %s or %s_FILE)", name, name, name))
+		case field.Tag.Get("required") == "true":
+			loader.problems = append(loader.problems, fmt.Errorf("%s is required", name))
+		}
+		return
+	}
+
+	if err := setValue(value, raw); err != nil {
+		loader.problems = append(loader.problems, fmt.Errorf("%s: %w", name, err))
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setValue(value reflect.Value, raw string) error {
+	if value.Type() == durationType {
+		if seconds, err := strconv.ParseFloat(raw, 64); err == nil {
+			value.SetInt(int64(seconds * float64(time.Second)))
+			return nil
+		}
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		value.SetFloat(parsed)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", value.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// yamlString turns a decoded YAML scalar or list into the same text form
+// used by environment variables.
+func yamlString(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		items := make([]string, 0, len(list))
+		for _, item := range list {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ",")
+	}
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func yamlName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+// settingName is the name shown in errors and in Describe: the environment
+// variable when there is one, otherwise the YAML key.
+func settingName(field reflect.StructField) string {
+	if envKey := field.Tag.Get("env"); envKey != "" {
+		return envKey
+	}
+	return yamlName(field)
+}
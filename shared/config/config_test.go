@@ -0,0 +1,223 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSection struct {
+	Host string `env:"TEST_HOST" yaml:"host" default:"localhost" required:"true"`
+	Port int32  `env:"TEST_PORT" yaml:"port" default:"5432"`
+}
+
+type testConfig struct {
+	Name     string        `env:"TEST_NAME" yaml:"name" default:"app"`
+	Enabled  bool          `env:"TEST_ENABLED" yaml:"enabled"`
+	Ratio    float64       `env:"TEST_RATIO" yaml:"ratio" default:"0.5"`
+	Timeout  time.Duration `env:"TEST_TIMEOUT" yaml:"timeout" default:"30"`
+	Brokers  []string      `env:"TEST_BROKERS" yaml:"brokers" default:"a:1,b:2"`
+	Token    string        `env:"TEST_TOKEN" yaml:"token" default:"dev-token" secret:"true"`
+	Required string        `env:"TEST_REQUIRED" yaml:"required" required:"true" default:"set"`
+	Ignored  string        `yaml:"-" default:"never"`
+
+	Section testSection `yaml:"section"`
+}
+
+type validatedConfig struct {
+	Min int `env:"TEST_MIN" default:"1"`
+	Max int `env:"TEST_MAX" default:"2"`
+}
+
+func (config *validatedConfig) Validate() error {
+	if config.Max < config.Min {
+		return errors.New("TEST_MAX must not be below TEST_MIN")
+	}
+	return nil
+}
+
+// setEnvironment isolates a test from the environment of the process.
+func setEnvironment(t *testing.T, environment string) {
+	t.Setenv(EnvironmentVariable, environment)
+	t.Setenv(FileVariable, "")
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setEnvironment(t, "development")
+
+	var config testConfig
+	assert.NoError(t, Load(&config))
+
+	assert.Equal(t, testConfig{
+		Name:     "app",
+		Ratio:    0.5,
+		Timeout:  30 * time.Second,
+		Brokers:  []string{"a:1", "b:2"},
+		Token:    "dev-token",
+		Required: "set",
+		Section:  testSection{Host: "localhost", Port: 5432},
+	}, config)
+}
+
+func TestLoad_EnvironmentOverridesDefaults(t *testing.T) {
+	setEnvironment(t, "development")
+	t.Setenv("TEST_NAME", "orders")
+	t.Setenv("TEST_ENABLED", "true")
+	t.Setenv("TEST_RATIO", "0.25")
+	t.Setenv("TEST_BROKERS", " k1:9092 , ,k2:9092 ")
+	t.Setenv("TEST_PORT", "6432")
+	t.Setenv("TEST_HOST", "")
+
+	var config testConfig
+	assert.NoError(t, Load(&config))
+
+	assert.Equal(t, "orders", config.Name)
+	assert.True(t, config.Enabled)
+	assert.Equal(t, 0.25, config.Ratio)
+	assert.Equal(t, []string{"k1:9092", "k2:9092"}, config.Brokers)
+	assert.Equal(t, int32(6432), config.Section.Port)
+	assert.Equal(t, "localhost", config.Section.Host, "an empty variable keeps the default")
+	assert.Empty(t, config.Ignored)
+}
+
+func TestLoad_Durations(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want time.Duration
+	}{
+		{"30", 30 * time.Second},
+		{"1.5", 1500 * time.Millisecond},
+		{"250ms", 250 * time.Millisecond},
+		{"2h", 2 * time.Hour},
+	}
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			setEnvironment(t, "development")
+			t.Setenv("TEST_TIMEOUT", test.raw)
+
+			var config testConfig
+			assert.NoError(t, Load(&config))
+			assert.Equal(t, test.want, config.Timeout)
+		})
+	}
+}
+
+func TestLoad_ReportsEveryInvalidValue(t *testing.T) {
+	setEnvironment(t, "development")
+	t.Setenv("TEST_ENABLED", "maybe")
+	t.Setenv("TEST_RATIO", "half")
+	t.Setenv("TEST_TIMEOUT", "soon")
+	t.Setenv("TEST_PORT", "99999999999")
+
+	var config testConfig
+	err := Load(&config)
+
+	assert.ErrorContains(t, err, `TEST_ENABLED: invalid boolean "maybe"`)
+	assert.ErrorContains(t, err, `TEST_RATIO: invalid number "half"`)
+	assert.ErrorContains(t, err, `TEST_TIMEOUT: invalid duration "soon"`)
+	assert.ErrorContains(t, err, `TEST_PORT: invalid integer "99999999999"`)
+}
+
+func TestLoad_Required(t *testing.T) {
+	setEnvironment(t, "development")
+	var config struct {
+		Value string `env:"TEST_VALUE" required:"true"`
+	}
+
+	assert.EqualError(t, Load(&config), "invalid configuration: TEST_VALUE is required")
+
+	t.Setenv("TEST_VALUE", "x")
+	assert.NoError(t, Load(&config))
+}
+
+func TestLoad_SecretsNeedAValueOutsideDevelopment(t *testing.T) {
+	setEnvironment(t, "production")
+
+	var config testConfig
+	err := Load(&config)
+	assert.ErrorContains(t, err, "TEST_TOKEN is a secret and must be set outside development")
+
+	t.Setenv("TEST_TOKEN", "from-env")
+	assert.NoError(t, Load(&config))
+	assert.Equal(t, "from-env", config.Token)
+}
+
+func TestLoad_SecretFromFile(t *testing.T) {
+	setEnvironment(t, "production")
+	path := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+	t.Setenv("TEST_TOKEN", "from-env")
+	t.Setenv("TEST_TOKEN_FILE", path)
+
+	var config testConfig
+	assert.NoError(t, Load(&config))
+	assert.Equal(t, "from-file", config.Token, "the file wins and its trailing newline is trimmed")
+
+	t.Setenv("TEST_TOKEN_FILE", filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, Load(&config), "TEST_TOKEN_FILE:")
+}
+
+func TestLoad_YAMLFile(t *testing.T) {
+	t.Setenv(EnvironmentVariable, "")
+	os.Unsetenv(EnvironmentVariable)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+environment: development
+name: from-file
+brokers: [x:1, y:2]
+timeout: 1m
+section:
+  host: db
+`), 0o600))
+	t.Setenv(FileVariable, path)
+	t.Setenv("TEST_NAME", "from-env")
+
+	var config testConfig
+	assert.NoError(t, Load(&config), "the file's environment allows the default secret")
+
+	assert.Equal(t, "from-env", config.Name, "the environment wins over the file")
+	assert.Equal(t, []string{"x:1", "y:2"}, config.Brokers)
+	assert.Equal(t, time.Minute, config.Timeout)
+	assert.Equal(t, "db", config.Section.Host)
+	assert.Equal(t, int32(5432), config.Section.Port)
+}
+
+func TestLoad_Validator(t *testing.T) {
+	setEnvironment(t, "development")
+	t.Setenv("TEST_MIN", "5")
+
+	var config validatedConfig
+	assert.EqualError(t, Load(&config), "invalid configuration: TEST_MAX must not be below TEST_MIN")
+}
+
+func TestLoad_RejectsNonStructTarget(t *testing.T) {
+	var config testConfig
+	assert.Error(t, Load(config))
+	name := "x"
+	assert.Error(t, Load(&name))
+}
+
+func TestDescribe_RedactsSecrets(t *testing.T) {
+	config := testConfig{
+		Name:    "app",
+		Brokers: []string{"a:1", "b:2"},
+		Token:   "hunter2",
+		Section: testSection{Host: "db", Port: 5432},
+	}
+
+	description := Describe(&config)
+
+	assert.Contains(t, description, "TEST_NAME=app\n")
+	assert.Contains(t, description, "TEST_BROKERS=a:1,b:2\n")
+	assert.Contains(t, description, "TEST_TOKEN=******\n")
+	assert.Contains(t, description, "TEST_HOST=db\n")
+	assert.NotContains(t, description, "hunter2")
+	assert.NotContains(t, description, "Ignored")
+
+	config.Token = ""
+	assert.Contains(t, Describe(config), "TEST_TOKEN=<unset>")
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const redacted = "******"
+
+// Describe renders the effective configuration one setting per line, with
+// secret values replaced so the output is safe to log.
+func Describe(target interface{}) string {
+	value := reflect.Indirect(reflect.ValueOf(target))
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+
+	var builder strings.Builder
+	describeStruct(&builder, value)
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func describeStruct(builder *strings.Builder, value reflect.Value) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() || field.Tag.Get("yaml") == "-" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			describeStruct(builder, value.Field(i))
+			continue
+		}
+
+		shown := fmt.Sprint(value.Field(i).Interface())
+		if field.Type.Kind() == reflect.Slice {
+			shown = strings.Trim(shown, "[]")
+			shown = strings.Join(strings.Fields(shown), ",")
+		}
+		if field.Tag.Get("secret") == "true" {
+			if value.Field(i).IsZero() {
+				shown = "<unset>"
+			} else {
+				shown = redacted
+			}
+		}
+		fmt.Fprintf(builder, "%s=%s\n", settingName(field), shown)
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Postgres holds the database settings every service reads.
+type Postgres struct {
+	Host           string        `env:"DB_HOST" yaml:"host" default:"localhost" required:"true"`
+	Port           string        `env:"DB_PORT" yaml:"port" default:"6432" required:"true"`
+	User           string        `env:"DB_USER" yaml:"user" default:"postgres" required:"true"`
+	Password       string        `env:"DB_PASSWORD" yaml:"password" default:"postgres" secret:"true"`
+	Name           string        `env:"DB_NAME" yaml:"name" default:"productapp" required:"true"`
+	MaxConnections int32         `env:"DB_MAX_CONNECTIONS" yaml:"max_connections" default:"10"`
+	MaxIdleTime    time.Duration `env:"DB_MAX_IDLE_SECONDS" yaml:"max_idle_seconds" default:"30"`
+}
+
+// Kafka holds the broker list and how consumers spread work. Topics and
+// consumer groups are declared by each service next to the code that uses
+// them.
+type Kafka struct {
+	Brokers []string `env:"KAFKA_BROKERS" yaml:"brokers" default:"kafka:9092" required:"true"`
+	// ConsumerConcurrency above 1 handles messages on that many workers
+	ConsumerConcurrency int `env:"KAFKA_CONSUMER_CONCURRENCY" yaml:"consumer_concurrency" default:"1"`
+	// ConsumerOrderBy is "key" or "partition": what stays in order when
+	// ConsumerConcurrency is above 1
+	ConsumerOrderBy string `env:"KAFKA_CONSUMER_ORDER_BY" yaml:"consumer_order_by" default:"key"`
+}
+
+// Validate rejects a concurrency below one and unknown orderings.
+func (kafka Kafka) Validate() error {
+	var problems []error
+	if kafka.ConsumerConcurrency < 1 {
+		problems = append(problems, errors.New("KAFKA_CONSUMER_CONCURRENCY must be at least 1"))
+	}
+	if kafka.ConsumerOrderBy != "key" && kafka.ConsumerOrderBy != "partition" {
+		problems = append(problems, fmt.Errorf("KAFKA_CONSUMER_ORDER_BY must be key or partition, not %q", kafka.ConsumerOrderBy))
+	}
+	return errors.Join(problems...)
+}
+
+// Auth holds the JWT signing secret shared by all services.
+type Auth struct {
+	JWTSecret string `env:"JWT_SECRET" yaml:"jwt_secret" default:"dev-only-jwt-secret" secret:"true"`
+}
+
+// Validate rejects secrets too short to sign tokens safely.
+func (auth Auth) Validate() error {
+	if len(auth.JWTSecret) < 16 {
+		return errors.New("JWT_SECRET must be at least 16 characters")
+	}
+	return nil
+}
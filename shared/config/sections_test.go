@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafka_ConsumerSettings(t *testing.T) {
+	setEnvironment(t, "development")
+	var settings struct {
+		Kafka Kafka `yaml:"kafka"`
+	}
+	assert.NoError(t, Load(&settings))
+	assert.Equal(t, 1, settings.Kafka.ConsumerConcurrency)
+	assert.Equal(t, "key", settings.Kafka.ConsumerOrderBy)
+
+	t.Setenv("KAFKA_CONSUMER_CONCURRENCY", "8")
+	t.Setenv("KAFKA_CONSUMER_ORDER_BY", "partition")
+	assert.NoError(t, Load(&settings))
+	assert.Equal(t, 8, settings.Kafka.ConsumerConcurrency)
+	assert.Equal(t, "partition", settings.Kafka.ConsumerOrderBy)
+
+	t.Setenv("KAFKA_CONSUMER_CONCURRENCY", "-1")
+	t.Setenv("KAFKA_CONSUMER_ORDER_BY", "random")
+	err := Load(&settings)
+	assert.ErrorContains(t, err, "KAFKA_CONSUMER_CONCURRENCY must be at least 1")
+	assert.ErrorContains(t, err, `KAFKA_CONSUMER_ORDER_BY must be key or partition, not "random"`)
+}
@@ -0,0 +1,22 @@
+package grpcx
+
+import (
+	"product-app/shared/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Dial opens a client connection to another service inside the cluster.
+// Calls forward the bearer token of the current request; see
+// auth.ContextWithToken.
+//
+//	conn, err := grpcx.Dial("product:9081")
+//	products := productv1.NewProductServiceClient(conn)
+func Dial(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	defaults := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(auth.UnaryClientInterceptor()),
+	}
+	return grpc.NewClient(target, append(defaults, opts...)...)
+}
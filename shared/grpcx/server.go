@@ -0,0 +1,28 @@
+package grpcx
+
+import (
+	"product-app/shared/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer creates a gRPC server with JWT authentication, health checking
+// and server reflection registered. protectedMethods are full method names
+// (e.g. "/product.v1.ProductService/DeleteProduct") that require a token.
+//
+// The returned health server reports SERVING for the overall server; callers
+// can set per-service status once their services are registered.
+func NewServer(protectedMethods ...string) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(auth.UnaryServerInterceptor(protectedMethods...)),
+	)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return server, healthServer
+}
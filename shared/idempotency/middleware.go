@@ -0,0 +1,198 @@
+// Package idempotency makes retried POST requests safe. A client sends an
+// Idempotency-Key header; the first request with a key is handled normally
+// and its response is stored, and every repeat within the TTL gets the
+// stored response back instead of running the handler again. Reusing a key
+// with a different request is rejected with 422. A key stays reserved for
+// ReservationLease while its first request runs, so a request lost in a
+// crash does not block the key for the whole TTL.
+//
+// Keys are scoped to the authenticated user when the route runs after
+// auth.JWTMiddleware, so two users cannot collide on the same key.
+// Anonymous requests are scoped to their X-Cart-Token, if they send one.
+//
+// Services storing keys in Postgres need the table:
+//
+//	CREATE TABLE idempotency_keys (
+//	    scope TEXT NOT NULL,
+//	    idempotency_key TEXT NOT NULL,
+//	    fingerprint TEXT NOT NULL,
+//	    status_code INT,
+//	    headers JSONB NOT NULL DEFAULT '{}',
+//	    body BYTEA,
+//	    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+//	    expires_at TIMESTAMP NOT NULL,
+//	    PRIMARY KEY (scope, idempotency_key)
+//	);
+//	CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"product-app/shared/httpx"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// HeaderKey is the request header carrying the client's key.
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed is set to "true" on responses served from the store.
+	HeaderReplayed = "Idempotent-Replayed"
+	// MaxKeyLength is the longest key accepted.
+	MaxKeyLength = 255
+	// DefaultTTL is how long a key is remembered when Middleware gets no TTL.
+	DefaultTTL = 24 * time.Hour
+	// ReservationLease is how long a key stays reserved by a request that
+	// has not finished. Once it runs out, a retry may claim the key again.
+	ReservationLease = time.Minute
+	// MaxBodyBytes is the largest request body read for an idempotent
+	// request; larger ones are rejected with 413.
+	MaxBodyBytes = 1 << 20
+	// headerCartToken carries the token of an anonymous cart, which tells
+	// anonymous clients apart.
+	headerCartToken = "X-Cart-Token"
+)
+
+// replayedHeaders are the response headers stored and replayed along with
+// the status and body.
+var replayedHeaders = []string{echo.HeaderContentType, echo.HeaderLocation}
+
+// Middleware replays stored responses for repeated Idempotency-Key
+// requests. Requests without the header pass through unchanged. Responses
+// with a 5xx status, and handler errors, are not stored so the client can
+// retry them with the same key.
+func Middleware(store Store, ttl time.Duration) echo.MiddlewareFunc {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderKey)
+			if key == "" {
+				return next(c)
+			}
+			if len(key) > MaxKeyLength {
+				return c.JSON(http.StatusBadRequest, httpx.ErrorResponse{
+					Error: "Idempotency-Key must be at most " + strconv.Itoa(MaxKeyLength) + " characters",
+				})
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, MaxBodyBytes))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					return c.JSON(http.StatusRequestEntityTooLarge, httpx.ErrorResponse{
+						Error: "Request body must be at most " + strconv.Itoa(MaxBodyBytes) + " bytes",
+					})
+				}
+				return c.JSON(http.StatusBadRequest, httpx.ErrorResponse{Error: "Invalid request body"})
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			scope := requestScope(c)
+			fingerprint := requestFingerprint(c.Request(), body)
+
+			existing, reserved, err := store.Reserve(ctx, scope, key, fingerprint, ReservationLease)
+			if err != nil {
+				log.Printf("idempotency: failed to reserve key: %v", err)
+				return c.JSON(http.StatusServiceUnavailable, httpx.ErrorResponse{
+					Error: "Idempotency-Key could not be checked, retry later",
+				})
+			}
+			if !reserved {
+				return replay(c, existing, fingerprint)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			handlerErr := next(c)
+			c.Response().Writer = recorder.ResponseWriter
+
+			// The outcome is recorded even if the client has gone away.
+			ctx = context.WithoutCancel(ctx)
+			status := c.Response().Status
+			if handlerErr != nil || !c.Response().Committed || status >= http.StatusInternalServerError {
+				if err := store.Release(ctx, scope, key); err != nil {
+					log.Printf("idempotency: failed to release key: %v", err)
+				}
+				return handlerErr
+			}
+
+			response := Response{StatusCode: status, Header: map[string]string{}, Body: recorder.body.Bytes()}
+			for _, name := range replayedHeaders {
+				if value := c.Response().Header().Get(name); value != "" {
+					response.Header[name] = value
+				}
+			}
+			if err := store.Complete(ctx, scope, key, response, ttl); err != nil {
+				log.Printf("idempotency: failed to store response: %v", err)
+			}
+			return nil
+		}
+	}
+}
+
+func replay(c echo.Context, existing Record, fingerprint string) error {
+	if existing.Fingerprint != fingerprint {
+		return c.JSON(http.StatusUnprocessableEntity, httpx.ErrorResponse{
+			Error: "Idempotency-Key was already used with a different request",
+		})
+	}
+	if existing.Response == nil {
+		return c.JSON(http.StatusConflict, httpx.ErrorResponse{
+			Error: "A request with this Idempotency-Key is still being processed",
+		})
+	}
+	for name, value := range existing.Response.Header {
+		c.Response().Header().Set(name, value)
+	}
+	c.Response().Header().Set(HeaderReplayed, "true")
+	c.Response().WriteHeader(existing.Response.StatusCode)
+	_, err := c.Response().Write(existing.Response.Body)
+	return err
+}
+
+// requestScope separates the keys of different users, and of anonymous
+// clients holding different carts. The cart token is hashed so it is not
+// stored in clear. Anonymous requests without a cart token share one scope.
+func requestScope(c echo.Context) string {
+	if userId, ok := c.Get("user_id").(int64); ok && userId > 0 {
+		return "user:" + strconv.FormatInt(userId, 10)
+	}
+	if token := c.Request().Header.Get(headerCartToken); token != "" {
+		hash := sha256.Sum256([]byte(token))
+		return "cart:" + hex.EncodeToString(hash[:])
+	}
+	return "anonymous"
+}
+
+// requestFingerprint identifies what was asked, so a key reused for another
+// request is detected.
+func requestFingerprint(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(request.Method + " " + request.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copies everything written to the response.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (recorder *responseRecorder) Write(b []byte) (int, error) {
+	recorder.body.Write(b)
+	return recorder.ResponseWriter.Write(b)
+}
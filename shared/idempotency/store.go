@@ -0,0 +1,203 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Response is a stored response.
+type Response struct {
+	StatusCode int
+	Header     map[string]string
+	Body       []byte
+}
+
+// Record is what the store knows about a key. Response is nil while the
+// first request with the key is still being handled.
+type Record struct {
+	Fingerprint string
+	Response    *Response
+}
+
+// Store keeps idempotency keys.
+type Store interface {
+	// Reserve claims key for a request with fingerprint for lease and
+	// reports true. When an unexpired record already holds the key it is
+	// returned with false instead. A reservation that is neither completed
+	// nor released within lease, because its request crashed, can be
+	// claimed again.
+	Reserve(ctx context.Context, scope, key, fingerprint string, lease time.Duration) (Record, bool, error)
+	// Complete stores the response of the request that reserved key and
+	// keeps it for ttl. A key already completed keeps its first response.
+	Complete(ctx context.Context, scope, key string, response Response, ttl time.Duration) error
+	// Release forgets key so that the request can be retried.
+	Release(ctx context.Context, scope, key string) error
+}
+
+// PostgresStore keeps keys in the idempotency_keys table.
+type PostgresStore struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewPostgresStore(dbPool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{dbPool: dbPool}
+}
+
+// Reserve implements Store. An expired record, or a reservation whose lease
+// ran out, is replaced; a concurrent request with the same key loses the
+// insert and sees the winner's record.
+func (store *PostgresStore) Reserve(ctx context.Context, scope, key, fingerprint string, lease time.Duration) (Record, bool, error) {
+	_, err := store.dbPool.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2 AND expires_at < NOW()
+	`, scope, key)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to expire idempotency key: %w", err)
+	}
+
+	commandTag, err := store.dbPool.Exec(ctx, `
+		INSERT INTO idempotency_keys (scope, idempotency_key, fingerprint, expires_at)
+		VALUES ($1, $2, $3, NOW() + $4::DOUBLE PRECISION * INTERVAL '1 second')
+		ON CONFLICT (scope, idempotency_key) DO NOTHING
+	`, scope, key, fingerprint, lease.Seconds())
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if commandTag.RowsAffected() == 1 {
+		return Record{Fingerprint: fingerprint}, true, nil
+	}
+
+	var record Record
+	var statusCode *int
+	var headers []byte
+	var body []byte
+	err = store.dbPool.QueryRow(ctx, `
+		SELECT fingerprint, status_code, headers, body
+		FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2
+	`, scope, key).Scan(&record.Fingerprint, &statusCode, &headers, &body)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+	if statusCode != nil {
+		response := &Response{StatusCode: *statusCode, Body: body}
+		if err := json.Unmarshal(headers, &response.Header); err != nil {
+			return Record{}, false, fmt.Errorf("failed to decode stored headers: %w", err)
+		}
+		record.Response = response
+	}
+	return record, false, nil
+}
+
+// Complete implements Store.
+func (store *PostgresStore) Complete(ctx context.Context, scope, key string, response Response, ttl time.Duration) error {
+	headers, err := json.Marshal(response.Header)
+	if err != nil {
+		return err
+	}
+	_, err = store.dbPool.Exec(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = $3, headers = $4, body = $5,
+		    expires_at = NOW() + $6::DOUBLE PRECISION * INTERVAL '1 second'
+		WHERE scope = $1 AND idempotency_key = $2 AND status_code IS NULL
+	`, scope, key, response.StatusCode, headers, response.Body, ttl.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release implements Store.
+func (store *PostgresStore) Release(ctx context.Context, scope, key string) error {
+	_, err := store.dbPool.Exec(ctx, `
+		DELETE FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2
+	`, scope, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes expired keys and returns how many were removed.
+func (store *PostgresStore) Prune(ctx context.Context) (int64, error) {
+	commandTag, err := store.dbPool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+// StartPruning prunes every interval until ctx is cancelled.
+func (store *PostgresStore) StartPruning(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if deleted, err := store.Prune(ctx); err != nil {
+			log.Printf("idempotency key prune failed: %v", err)
+		} else if deleted > 0 {
+			log.Printf("pruned %d expired idempotency key(s)", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type memoryEntry struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// MemoryStore keeps keys in process memory. It suits tests and a single
+// instance; replicas need a shared store such as PostgresStore.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]memoryEntry{}}
+}
+
+// Reserve implements Store.
+func (store *MemoryStore) Reserve(ctx context.Context, scope, key, fingerprint string, lease time.Duration) (Record, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	id := scope + "\x00" + key
+	if entry, ok := store.entries[id]; ok && time.Now().Before(entry.expiresAt) {
+		return entry.record, false, nil
+	}
+	store.entries[id] = memoryEntry{record: Record{Fingerprint: fingerprint}, expiresAt: time.Now().Add(lease)}
+	return Record{Fingerprint: fingerprint}, true, nil
+}
+
+// Complete implements Store.
+func (store *MemoryStore) Complete(ctx context.Context, scope, key string, response Response, ttl time.Duration) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	id := scope + "\x00" + key
+	if entry, ok := store.entries[id]; ok && entry.record.Response == nil {
+		entry.record.Response = &response
+		entry.expiresAt = time.Now().Add(ttl)
+		store.entries[id] = entry
+	}
+	return nil
+}
+
+// Release implements Store.
+func (store *MemoryStore) Release(ctx context.Context, scope, key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.entries, scope+"\x00"+key)
+	return nil
+}
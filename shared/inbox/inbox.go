@@ -0,0 +1,88 @@
+// Package inbox makes Kafka consumers idempotent. Every handled message is
+// recorded in the processed_messages table inside the same transaction as
+// the handler's own writes, so a redelivered message is recognised and
+// skipped instead of being applied twice.
+//
+// Services using the package need the table:
+//
+//	CREATE TABLE processed_messages (
+//	    consumer_group TEXT NOT NULL,
+//	    message_id TEXT NOT NULL,
+//	    processed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+//	    PRIMARY KEY (consumer_group, message_id)
+//	);
+//	CREATE INDEX idx_processed_messages_processed_at ON processed_messages (processed_at);
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"product-app/shared/kafka"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// TxHandler processes a message using tx for all of its database writes.
+// Returning an error rolls back both the writes and the processed marker.
+type TxHandler func(ctx context.Context, tx pgx.Tx, message kafka.Message) error
+
+// MessageID identifies a message for deduplication: the event-id header
+// when the producer set one, otherwise its topic, partition and offset.
+func MessageID(message kafka.Message) string {
+	for _, header := range message.Headers {
+		if header.Key == kafka.HeaderEventID && len(header.Value) > 0 {
+			return string(header.Value)
+		}
+	}
+	return fmt.Sprintf("%s/%d/%d", message.Topic, message.Partition, message.Offset)
+}
+
+// NewIdempotentHandler wraps handler so that each message is applied at most
+// once per consumer group.
+func NewIdempotentHandler(dbPool *pgxpool.Pool, consumerGroup string, handler TxHandler) kafka.MessageHandler {
+	return func(ctx context.Context, message kafka.Message) error {
+		messageId := MessageID(message)
+
+		tx, err := dbPool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to start transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback(ctx) }()
+
+		firstDelivery, err := markProcessed(ctx, tx, consumerGroup, messageId)
+		if err != nil {
+			return err
+		}
+		if !firstDelivery {
+			log.Printf("skipping duplicate message %s for %s", messageId, consumerGroup)
+			return nil
+		}
+
+		if err := handler(ctx, tx, message); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	}
+}
+
+// markProcessed inserts the marker and reports whether it was new. A
+// concurrent delivery of the same message blocks on the primary key until
+// the first transaction finishes, then sees the row.
+func markProcessed(ctx context.Context, tx pgx.Tx, consumerGroup, messageId string) (bool, error) {
+	commandTag, err := tx.Exec(ctx, `
+		INSERT INTO processed_messages (consumer_group, message_id)
+		VALUES ($1, $2)
+		ON CONFLICT (consumer_group, message_id) DO NOTHING
+	`, consumerGroup, messageId)
+	if err != nil {
+		return false, fmt.Errorf("failed to record processed message: %w", err)
+	}
+	return commandTag.RowsAffected() == 1, nil
+}
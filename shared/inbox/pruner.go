@@ -0,0 +1,72 @@
+package inbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// PrunerConfig controls how long processed markers are kept. Retention must
+// exceed the longest time a message can be redelivered, e.g. the topic's
+// retention when consumers may be reset to the earliest offset.
+type PrunerConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+// DefaultPrunerConfig keeps markers for a week and prunes hourly.
+var DefaultPrunerConfig = PrunerConfig{
+	Retention: 7 * 24 * time.Hour,
+	Interval:  time.Hour,
+}
+
+// Pruner periodically deletes old processed_messages rows.
+type Pruner struct {
+	dbPool *pgxpool.Pool
+	config PrunerConfig
+}
+
+func NewPruner(dbPool *pgxpool.Pool, config PrunerConfig) *Pruner {
+	if config.Retention <= 0 {
+		config.Retention = DefaultPrunerConfig.Retention
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultPrunerConfig.Interval
+	}
+	return &Pruner{dbPool: dbPool, config: config}
+}
+
+// Start prunes every Interval until ctx is cancelled.
+func (pruner *Pruner) Start(ctx context.Context) {
+	ticker := time.NewTicker(pruner.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if deleted, err := pruner.Prune(ctx); err != nil {
+			log.Printf("processed messages prune failed: %v", err)
+		} else if deleted > 0 {
+			log.Printf("pruned %d processed message(s)", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes markers older than Retention and returns how many were
+// removed.
+func (pruner *Pruner) Prune(ctx context.Context) (int64, error) {
+	commandTag, err := pruner.dbPool.Exec(ctx, `
+		DELETE FROM processed_messages
+		WHERE processed_at < NOW() - $1::DOUBLE PRECISION * INTERVAL '1 second'
+	`, pruner.config.Retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
@@ -3,6 +3,7 @@ package kafka
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
@@ -12,10 +13,19 @@ import (
 // MessageHandler is a function that processes a Kafka message
 type MessageHandler func(ctx context.Context, message kafka.Message) error
 
+// DeadLetterPublisher receives messages whose retries are exhausted.
+type DeadLetterPublisher interface {
+	PublishRawMessage(ctx context.Context, message kafka.Message) error
+}
+
 // Consumer Kafka consumer wrapper
 type Consumer struct {
-	reader  *kafka.Reader
-	handler MessageHandler
+	reader      *kafka.Reader
+	handler     MessageHandler
+	retry       RetryPolicy
+	deadLetter  DeadLetterPublisher
+	concurrency int
+	orderBy     Ordering
 }
 
 // ConsumerConfig configuration for creating a consumer
@@ -23,6 +33,15 @@ type ConsumerConfig struct {
 	Brokers []string
 	Topic   string
 	GroupID string
+	// Retry defaults to DefaultRetryPolicy.
+	Retry RetryPolicy
+	// DeadLetterTopic defaults to Topic + ".dlq".
+	DeadLetterTopic string
+	// Concurrency above 1 handles messages on that many workers; see
+	// startConcurrent. Zero or one keeps the sequential loop.
+	Concurrency int
+	// OrderBy selects what must stay in order when Concurrency > 1.
+	OrderBy Ordering
 }
 
 // NewConsumer creates a new Kafka consumer
@@ -37,9 +56,18 @@ func NewConsumer(config ConsumerConfig, handler MessageHandler) *Consumer {
 		StartOffset:    kafka.LastOffset, // En son mesajdan başla
 	})
 
+	deadLetterTopic := config.DeadLetterTopic
+	if deadLetterTopic == "" {
+		deadLetterTopic = DeadLetterTopic(config.Topic)
+	}
+
 	return &Consumer{
-		reader:  reader,
-		handler: handler,
+		reader:      reader,
+		handler:     handler,
+		retry:       config.Retry.withDefaults(),
+		deadLetter:  NewProducer(config.Brokers, deadLetterTopic),
+		concurrency: config.Concurrency,
+		orderBy:     config.OrderBy,
 	}
 }
 
@@ -48,28 +76,36 @@ func (c *Consumer) Start(ctx context.Context) error {
 	log.Printf("Starting consumer for topic: %s, group: %s",
 		c.reader.Config().Topic, c.reader.Config().GroupID)
 
+	if c.concurrency > 1 {
+		return c.startConcurrent(ctx)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("Consumer stopped")
-			return c.reader.Close()
+			return c.Close()
 		default:
 			// Mesaj al
 			message, err := c.reader.FetchMessage(ctx)
 			if err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
 				log.Printf("Error fetching message: %v", err)
 				continue
 			}
 
 			log.Printf("Received message: offset=%d key=%s", message.Offset, string(message.Key))
+			observeLag(message, c.reader.Config().GroupID)
 
-			// Mesajı işle
-			if err := c.handler(ctx, message); err != nil {
-				log.Printf("Error handling message: %v", err)
-				continue // Hata olursa commit etme, tekrar işlensin
+			// Mesajı işle; başarısız olursa DLQ'ya taşınana kadar commit etme
+			if err := c.process(ctx, ctx, message); err != nil {
+				log.Printf("Stopping before commit of offset %d: %v", message.Offset, err)
+				continue
 			}
 
-			// Başarılı olursa commit et
+			// İşlendi ya da DLQ'ya taşındı, commit et
 			if err := c.reader.CommitMessages(ctx, message); err != nil {
 				log.Printf("Error committing message: %v", err)
 			}
@@ -77,8 +113,61 @@ func (c *Consumer) Start(ctx context.Context) error {
 	}
 }
 
-// Close closes the consumer
+// process calls the handler until it succeeds or the retry policy is
+// exhausted, then parks the message on the dead-letter topic. The handler
+// runs with handleCtx; backoff waits stop when ctx is cancelled, in which
+// case an error is returned and the message must not be committed.
+func (c *Consumer) process(ctx, handleCtx context.Context, message kafka.Message) error {
+	group := c.reader.Config().GroupID
+	var handleErr error
+	attempt := 1
+	for ; ; attempt++ {
+		start := time.Now()
+		handleErr = c.handler(handleCtx, message)
+		consumerHandlerDuration.WithLabelValues(message.Topic, group).Observe(time.Since(start).Seconds())
+		if handleErr == nil {
+			return nil
+		}
+		consumerHandlerErrorsTotal.WithLabelValues(message.Topic, group).Inc()
+		if attempt >= c.retry.MaxAttempts {
+			break
+		}
+		delay := c.retry.Backoff(attempt)
+		log.Printf("Error handling message: offset=%d attempt=%d/%d retry_in=%s: %v",
+			message.Offset, attempt, c.retry.MaxAttempts, delay, handleErr)
+		if !sleep(ctx, delay) {
+			return ctx.Err()
+		}
+	}
+
+	return c.sendToDeadLetter(ctx, handleCtx, message, attempt, handleErr)
+}
+
+// sendToDeadLetter keeps retrying the publish: committing a message that is
+// neither handled nor parked would lose it.
+func (c *Consumer) sendToDeadLetter(ctx, publishCtx context.Context, message kafka.Message, attempts int, cause error) error {
+	deadLetter := NewDeadLetterMessage(message, c.reader.Config().GroupID, attempts, cause)
+	for publishAttempt := 1; ; publishAttempt++ {
+		err := c.deadLetter.PublishRawMessage(publishCtx, deadLetter)
+		if err == nil {
+			consumerDeadLetteredTotal.WithLabelValues(message.Topic, c.reader.Config().GroupID).Inc()
+			log.Printf("Message moved to dead-letter topic: offset=%d key=%s attempts=%d: %v",
+				message.Offset, string(message.Key), attempts, cause)
+			return nil
+		}
+		log.Printf("Error publishing offset %d to dead-letter topic: %v", message.Offset, err)
+		if !sleep(ctx, c.retry.Backoff(publishAttempt)) {
+			return fmt.Errorf("dead-letter publish interrupted: %w", ctx.Err())
+		}
+	}
+}
+
+// Close closes the reader and the dead-letter producer. Start calls it on
+// shutdown.
 func (c *Consumer) Close() error {
+	if closer, ok := c.deadLetter.(interface{ Close() error }); ok {
+		closer.Close()
+	}
 	if c.reader != nil {
 		return c.reader.Close()
 	}
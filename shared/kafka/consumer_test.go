@@ -0,0 +1,94 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDeadLetterPublisher struct {
+	mu        sync.Mutex
+	published []kafka.Message
+	failures  int
+	closed    bool
+}
+
+func (publisher *fakeDeadLetterPublisher) PublishRawMessage(_ context.Context, message kafka.Message) error {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+	if publisher.failures > 0 {
+		publisher.failures--
+		return errors.New("broker unavailable")
+	}
+	publisher.published = append(publisher.published, message)
+	return nil
+}
+
+func (publisher *fakeDeadLetterPublisher) Close() error {
+	publisher.closed = true
+	return nil
+}
+
+// newTestConsumer returns a consumer whose reader never reaches a broker.
+func newTestConsumer(handler MessageHandler, deadLetter DeadLetterPublisher) *Consumer {
+	return &Consumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: []string{"127.0.0.1:1"},
+			Topic:   "test.events",
+			GroupID: "test-group",
+		}),
+		handler:    handler,
+		retry:      RetryPolicy{MaxAttempts: 3, BackoffMin: time.Millisecond, BackoffMax: time.Millisecond},
+		deadLetter: deadLetter,
+	}
+}
+
+func TestConsumer_StartClosesDeadLetterProducerOnShutdown(t *testing.T) {
+	deadLetter := &fakeDeadLetterPublisher{}
+	consumer := newTestConsumer(nil, deadLetter)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.NoError(t, consumer.Start(ctx))
+	assert.True(t, deadLetter.closed)
+}
+
+func TestConsumer_ProcessDeadLettersAfterRetries(t *testing.T) {
+	deadLetter := &fakeDeadLetterPublisher{failures: 1}
+	calls := 0
+	consumer := newTestConsumer(func(context.Context, kafka.Message) error {
+		calls++
+		return errors.New("boom")
+	}, deadLetter)
+	defer consumer.Close()
+
+	err := consumer.process(context.Background(), context.Background(), kafka.Message{Topic: "test.events", Offset: 4})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Len(t, deadLetter.published, 1, "the failed publish is retried")
+	parsed := ParseDeadLetter(deadLetter.published[0])
+	assert.Equal(t, 3, parsed.Attempts)
+	assert.Equal(t, int64(4), parsed.SourceOffset)
+}
+
+func TestConsumer_ProcessStopsRetryingOnCancel(t *testing.T) {
+	deadLetter := &fakeDeadLetterPublisher{}
+	consumer := newTestConsumer(func(context.Context, kafka.Message) error {
+		return errors.New("boom")
+	}, deadLetter)
+	consumer.retry.BackoffMin, consumer.retry.BackoffMax = time.Hour, time.Hour
+	defer consumer.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := consumer.process(ctx, context.Background(), kafka.Message{Topic: "test.events"})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, deadLetter.published, "an unfinished message is neither committed nor parked")
+}
@@ -0,0 +1,209 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// DeadLetterSuffix is appended to a topic name to form its dead-letter topic.
+const DeadLetterSuffix = ".dlq"
+
+// Headers added to every dead-lettered message. The original headers, key
+// and value are kept unchanged so the message can be redriven as-is.
+const (
+	HeaderDeadLetterError           = "x-dlq-error"
+	HeaderDeadLetterSourceTopic     = "x-dlq-source-topic"
+	HeaderDeadLetterSourcePartition = "x-dlq-source-partition"
+	HeaderDeadLetterSourceOffset    = "x-dlq-source-offset"
+	HeaderDeadLetterConsumerGroup   = "x-dlq-consumer-group"
+	HeaderDeadLetterAttempts        = "x-dlq-attempts"
+	HeaderDeadLetterFailedAt        = "x-dlq-failed-at"
+)
+
+const deadLetterHeaderPrefix = "x-dlq-"
+
+// DeadLetterTopic returns the dead-letter topic for topic.
+func DeadLetterTopic(topic string) string {
+	return topic + DeadLetterSuffix
+}
+
+// DeadLetter is a parsed dead-letter message.
+type DeadLetter struct {
+	Message         kafka.Message
+	SourceTopic     string
+	SourcePartition int
+	SourceOffset    int64
+	ConsumerGroup   string
+	Attempts        int
+	Error           string
+	FailedAt        time.Time
+}
+
+// NewDeadLetterMessage copies message for the dead-letter topic and records
+// why and where it failed in x-dlq-* headers.
+func NewDeadLetterMessage(message kafka.Message, groupID string, attempts int, cause error) kafka.Message {
+	headers := withoutDeadLetterHeaders(message.Headers)
+	headers = append(headers,
+		kafka.Header{Key: HeaderDeadLetterError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderDeadLetterSourceTopic, Value: []byte(message.Topic)},
+		kafka.Header{Key: HeaderDeadLetterSourcePartition, Value: []byte(strconv.Itoa(message.Partition))},
+		kafka.Header{Key: HeaderDeadLetterSourceOffset, Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		kafka.Header{Key: HeaderDeadLetterConsumerGroup, Value: []byte(groupID)},
+		kafka.Header{Key: HeaderDeadLetterAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: HeaderDeadLetterFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+	)
+
+	return kafka.Message{
+		Key:     message.Key,
+		Value:   message.Value,
+		Headers: headers,
+		Time:    time.Now(),
+	}
+}
+
+// ParseDeadLetter reads the x-dlq-* headers of a dead-letter message.
+func ParseDeadLetter(message kafka.Message) DeadLetter {
+	deadLetter := DeadLetter{Message: message}
+	for _, header := range message.Headers {
+		value := string(header.Value)
+		switch header.Key {
+		case HeaderDeadLetterError:
+			deadLetter.Error = value
+		case HeaderDeadLetterSourceTopic:
+			deadLetter.SourceTopic = value
+		case HeaderDeadLetterSourcePartition:
+			deadLetter.SourcePartition, _ = strconv.Atoi(value)
+		case HeaderDeadLetterSourceOffset:
+			deadLetter.SourceOffset, _ = strconv.ParseInt(value, 10, 64)
+		case HeaderDeadLetterConsumerGroup:
+			deadLetter.ConsumerGroup = value
+		case HeaderDeadLetterAttempts:
+			deadLetter.Attempts, _ = strconv.Atoi(value)
+		case HeaderDeadLetterFailedAt:
+			deadLetter.FailedAt, _ = time.Parse(time.RFC3339Nano, value)
+		}
+	}
+	if deadLetter.SourceTopic == "" {
+		deadLetter.SourceTopic = strings.TrimSuffix(message.Topic, DeadLetterSuffix)
+	}
+	return deadLetter
+}
+
+// RedriveMessage returns the message to publish back to the source topic,
+// with the dead-letter headers removed.
+func (deadLetter DeadLetter) RedriveMessage() kafka.Message {
+	return kafka.Message{
+		Topic:   deadLetter.SourceTopic,
+		Key:     deadLetter.Message.Key,
+		Value:   deadLetter.Message.Value,
+		Headers: withoutDeadLetterHeaders(deadLetter.Message.Headers),
+		Time:    time.Now(),
+	}
+}
+
+func withoutDeadLetterHeaders(headers []kafka.Header) []kafka.Header {
+	kept := make([]kafka.Header, 0, len(headers))
+	for _, header := range headers {
+		if !strings.HasPrefix(header.Key, deadLetterHeaderPrefix) {
+			kept = append(kept, header)
+		}
+	}
+	return kept
+}
+
+// ReadDeadLetters returns up to limit messages currently stored in the
+// dead-letter topic, oldest first per partition. It does not join a
+// consumer group, so nothing is committed.
+func ReadDeadLetters(ctx context.Context, brokers []string, topic string, limit int) ([]DeadLetter, error) {
+	partitions, err := readPartitionIDs(ctx, brokers, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	var deadLetters []DeadLetter
+	for _, partition := range partitions {
+		if limit > 0 && len(deadLetters) >= limit {
+			break
+		}
+		remaining := 0
+		if limit > 0 {
+			remaining = limit - len(deadLetters)
+		}
+		err := readPartition(ctx, brokers, topic, partition, remaining, func(message kafka.Message) error {
+			deadLetters = append(deadLetters, ParseDeadLetter(message))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return deadLetters, nil
+}
+
+// RedriveConfig configures Redrive.
+type RedriveConfig struct {
+	Brokers []string
+	// Topic is the dead-letter topic to drain.
+	Topic string
+	// GroupID tracks what has already been redriven, so running the
+	// command twice does not publish the same message twice.
+	GroupID string
+	// Limit stops after this many messages; zero means no limit.
+	Limit int
+	// IdleTimeout ends the run once no message arrived for this long.
+	IdleTimeout time.Duration
+}
+
+// Redrive publishes dead-lettered messages back to their source topic and
+// commits each one only after the publish succeeded. It returns the number
+// of messages redriven.
+func Redrive(ctx context.Context, config RedriveConfig) (int, error) {
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = 5 * time.Second
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     config.Brokers,
+		Topic:       config.Topic,
+		GroupID:     config.GroupID,
+		MaxBytes:    10e6,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(config.Brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}
+	defer writer.Close()
+
+	redriven := 0
+	for config.Limit <= 0 || redriven < config.Limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, config.IdleTimeout)
+		message, err := reader.FetchMessage(fetchCtx)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return redriven, nil
+		}
+		if err != nil {
+			return redriven, fmt.Errorf("failed to fetch from %s: %w", config.Topic, err)
+		}
+
+		deadLetter := ParseDeadLetter(message)
+		if err := writer.WriteMessages(ctx, deadLetter.RedriveMessage()); err != nil {
+			return redriven, fmt.Errorf("failed to redrive offset %d to %s: %w", message.Offset, deadLetter.SourceTopic, err)
+		}
+		if err := reader.CommitMessages(ctx, message); err != nil {
+			return redriven, fmt.Errorf("failed to commit offset %d: %w", message.Offset, err)
+		}
+		redriven++
+	}
+	return redriven, nil
+}
@@ -0,0 +1,60 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetter_HeaderRoundTrip(t *testing.T) {
+	original := kafka.Message{
+		Topic:     "order.events",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("7"),
+		Value:     []byte(`{"id":"e1"}`),
+		Headers:   []kafka.Header{{Key: "trace-id", Value: []byte("abc")}},
+	}
+
+	message := NewDeadLetterMessage(original, "product-service", 5, errors.New("boom"))
+	message.Topic = DeadLetterTopic(original.Topic)
+	deadLetter := ParseDeadLetter(message)
+
+	assert.Equal(t, "order.events", deadLetter.SourceTopic)
+	assert.Equal(t, 3, deadLetter.SourcePartition)
+	assert.Equal(t, int64(42), deadLetter.SourceOffset)
+	assert.Equal(t, "product-service", deadLetter.ConsumerGroup)
+	assert.Equal(t, 5, deadLetter.Attempts)
+	assert.Equal(t, "boom", deadLetter.Error)
+	assert.WithinDuration(t, time.Now(), deadLetter.FailedAt, time.Minute)
+
+	redrive := deadLetter.RedriveMessage()
+	assert.Equal(t, "order.events", redrive.Topic)
+	assert.Equal(t, original.Key, redrive.Key)
+	assert.Equal(t, original.Value, redrive.Value)
+	assert.Equal(t, original.Headers, redrive.Headers, "only the x-dlq-* headers are removed")
+}
+
+func TestDeadLetter_ReplacesHeadersOfEarlierFailure(t *testing.T) {
+	first := NewDeadLetterMessage(kafka.Message{Topic: "order.events", Offset: 1}, "product-service", 5, errors.New("first"))
+	first.Topic = "order.events"
+	first.Offset = 9
+
+	second := ParseDeadLetter(NewDeadLetterMessage(first, "product-service", 2, errors.New("second")))
+
+	assert.Equal(t, "second", second.Error)
+	assert.Equal(t, int64(9), second.SourceOffset)
+	assert.Equal(t, 2, second.Attempts)
+	assert.Len(t, second.Message.Headers, 7, "one set of x-dlq-* headers")
+}
+
+func TestParseDeadLetter_FallsBackToTopicName(t *testing.T) {
+	deadLetter := ParseDeadLetter(kafka.Message{Topic: "product.events.dlq"})
+
+	assert.Equal(t, "product.events", deadLetter.SourceTopic)
+	assert.Zero(t, deadLetter.Attempts)
+	assert.True(t, deadLetter.FailedAt.IsZero())
+}
@@ -0,0 +1,164 @@
+package kafka
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidEnvelope is returned when a message is not a valid envelope.
+	ErrInvalidEnvelope = errors.New("invalid event envelope")
+	// ErrUnsupportedSchemaVersion is returned for envelopes whose major
+	// schema version differs from the one the consumer understands.
+	ErrUnsupportedSchemaVersion = errors.New("unsupported event schema version")
+	// ErrUnexpectedEventType is returned when the payload is decoded into a
+	// struct for a different event type.
+	ErrUnexpectedEventType = errors.New("unexpected event type")
+)
+
+// Event is implemented by every typed event payload. SchemaVersion uses
+// "major.minor"; minor versions only add optional fields, a new major
+// version is a breaking change. AggregateID identifies the entity the event
+// is about and is used as the message key, so all events of one entity land
+// on one partition and are consumed in the order they were published.
+type Event interface {
+	EventType() string
+	SchemaVersion() string
+	AggregateID() string
+}
+
+// Envelope wraps every event published to Kafka. The fields follow the
+// CloudEvents attribute names where one exists.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Source        string          `json:"source"`
+	SchemaVersion string          `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// EnvelopeOption customises a new envelope.
+type EnvelopeOption func(*Envelope)
+
+// WithCorrelationID links the event to the request or event that caused it.
+// Without it the event starts a new chain and its own id is used.
+func WithCorrelationID(correlationID string) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.CorrelationID = correlationID
+	}
+}
+
+// WithOccurredAt overrides the event time, which defaults to now.
+func WithOccurredAt(occurredAt time.Time) EnvelopeOption {
+	return func(envelope *Envelope) {
+		envelope.OccurredAt = occurredAt.UTC()
+	}
+}
+
+// HeaderEventID carries the envelope id as a message header, so consumers
+// can deduplicate without decoding the payload.
+const HeaderEventID = "event-id"
+
+// NewEnvelope wraps event. source identifies the producing service, e.g.
+// "product-service".
+func NewEnvelope(source string, event Event, opts ...EnvelopeOption) (Envelope, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to marshal %s payload: %w", event.EventType(), err)
+	}
+
+	envelope := Envelope{
+		ID:            newEventID(),
+		Type:          event.EventType(),
+		Source:        source,
+		SchemaVersion: event.SchemaVersion(),
+		OccurredAt:    time.Now().UTC(),
+		Data:          data,
+	}
+	for _, opt := range opts {
+		opt(&envelope)
+	}
+	if envelope.CorrelationID == "" {
+		envelope.CorrelationID = envelope.ID
+	}
+	return envelope, nil
+}
+
+// EncodeEvent wraps event in an envelope and returns the JSON message value.
+func EncodeEvent(source string, event Event, opts ...EnvelopeOption) ([]byte, error) {
+	envelope, err := NewEnvelope(source, event, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope)
+}
+
+// DecodeEnvelope parses a message value and checks the required fields. The
+// payload stays raw until DecodeData is called.
+func DecodeEnvelope(value []byte) (Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(value, &envelope); err != nil {
+		return Envelope{}, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	if envelope.ID == "" || envelope.Type == "" || envelope.SchemaVersion == "" || len(envelope.Data) == 0 {
+		return Envelope{}, fmt.Errorf("%w: id, type, schema_version and data are required", ErrInvalidEnvelope)
+	}
+	if _, err := majorVersion(envelope.SchemaVersion); err != nil {
+		return Envelope{}, err
+	}
+	return envelope, nil
+}
+
+// DecodeData unmarshals the payload into event after checking that the type
+// matches and the major schema version is the one event implements.
+func (envelope Envelope) DecodeData(event Event) error {
+	if envelope.Type != event.EventType() {
+		return fmt.Errorf("%w: got %s, want %s", ErrUnexpectedEventType, envelope.Type, event.EventType())
+	}
+
+	got, err := majorVersion(envelope.SchemaVersion)
+	if err != nil {
+		return err
+	}
+	want, err := majorVersion(event.SchemaVersion())
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("%w: %s %s (supported major version %d)",
+			ErrUnsupportedSchemaVersion, envelope.Type, envelope.SchemaVersion, want)
+	}
+
+	if err := json.Unmarshal(envelope.Data, event); err != nil {
+		return fmt.Errorf("%w: %s payload: %v", ErrInvalidEnvelope, envelope.Type, err)
+	}
+	return nil
+}
+
+func majorVersion(schemaVersion string) (int, error) {
+	major, _, _ := strings.Cut(schemaVersion, ".")
+	parsed, err := strconv.Atoi(major)
+	if err != nil || parsed < 1 {
+		return 0, fmt.Errorf("%w: malformed schema_version %q", ErrInvalidEnvelope, schemaVersion)
+	}
+	return parsed, nil
+}
+
+// newEventID returns a random UUID (version 4).
+func newEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	id := hex.EncodeToString(b[:])
+	return id[0:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:32]
+}
@@ -0,0 +1,213 @@
+package kafka
+
+import (
+	"strconv"
+	"time"
+)
+
+// Event types published on the product.events and order.events topics.
+// Shipment events are published on order.events.
+const (
+	EventTypeProductCreated     = "product.created"
+	EventTypeProductUpdated     = "product.updated"
+	EventTypeProductDeleted     = "product.deleted"
+	EventTypeOrderCreated       = "order.created"
+	EventTypeOrderCancelled     = "order.cancelled"
+	EventTypeOrderStatusChanged = "order.status_changed"
+	EventTypeOrderRefunded      = "order.refunded"
+	EventTypeShipmentCreated    = "shipment.created"
+	EventTypeShipmentUpdated    = "shipment.updated"
+	EventTypeShipmentDelivered  = "shipment.delivered"
+)
+
+// ProductCreated is published by the product service after a product is
+// stored.
+type ProductCreated struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Price       float32  `json:"price"`
+	Description string   `json:"description"`
+	Discount    float32  `json:"discount"`
+	StoreID     int64    `json:"store_id"`
+	StoreSlug   string   `json:"store_slug"`
+	Store       string   `json:"store"`
+	ImageUrls   []string `json:"image_urls"`
+	CategoryID  int64    `json:"category_id"`
+}
+
+func (ProductCreated) EventType() string         { return EventTypeProductCreated }
+func (ProductCreated) SchemaVersion() string     { return "1.0" }
+func (event ProductCreated) AggregateID() string { return strconv.FormatInt(event.ID, 10) }
+
+// ProductUpdated is published by the product service after a stored product
+// changes. It carries the full product so consumers can replace their copy.
+type ProductUpdated struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Price       float32  `json:"price"`
+	Description string   `json:"description"`
+	Discount    float32  `json:"discount"`
+	StoreID     int64    `json:"store_id"`
+	StoreSlug   string   `json:"store_slug"`
+	Store       string   `json:"store"`
+	ImageUrls   []string `json:"image_urls"`
+	CategoryID  int64    `json:"category_id"`
+}
+
+func (ProductUpdated) EventType() string         { return EventTypeProductUpdated }
+func (ProductUpdated) SchemaVersion() string     { return "1.0" }
+func (event ProductUpdated) AggregateID() string { return strconv.FormatInt(event.ID, 10) }
+
+// ProductDeleted is published by the product service after a product is
+// removed.
+type ProductDeleted struct {
+	ID         int64 `json:"id"`
+	CategoryID int64 `json:"category_id"`
+}
+
+func (ProductDeleted) EventType() string         { return EventTypeProductDeleted }
+func (ProductDeleted) SchemaVersion() string     { return "1.0" }
+func (event ProductDeleted) AggregateID() string { return strconv.FormatInt(event.ID, 10) }
+
+// OrderLine is one item of an order as carried by order events.
+type OrderLine struct {
+	ProductID int64   `json:"product_id"`
+	Variant   string  `json:"variant,omitempty"`
+	Quantity  int32   `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Discount  float64 `json:"discount"`
+	LineTotal float64 `json:"line_total"`
+}
+
+// OrderCreated is published by the order service after an order is placed.
+// Version 2 replaced the single product_id and quantity with items and
+// totals.
+type OrderCreated struct {
+	ID             int64       `json:"id"`
+	CustomerNumber string      `json:"customer_number"`
+	Items          []OrderLine `json:"items"`
+	Subtotal       float64     `json:"subtotal"`
+	DiscountTotal  float64     `json:"discount_total"`
+	GrandTotal     float64     `json:"grand_total"`
+	OrderTime      time.Time   `json:"order_time"`
+}
+
+func (OrderCreated) EventType() string         { return EventTypeOrderCreated }
+func (OrderCreated) SchemaVersion() string     { return "2.0" }
+func (event OrderCreated) AggregateID() string { return strconv.FormatInt(event.ID, 10) }
+
+// OrderCancelled is published by the order service after an order is
+// cancelled. Consumers holding stock for the items should release it.
+// Version 2.1 added the reason code.
+type OrderCancelled struct {
+	ID             int64       `json:"id"`
+	CustomerNumber string      `json:"customer_number"`
+	Items          []OrderLine `json:"items"`
+	Subtotal       float64     `json:"subtotal"`
+	DiscountTotal  float64     `json:"discount_total"`
+	GrandTotal     float64     `json:"grand_total"`
+	OrderTime      time.Time   `json:"order_time"`
+	ReasonCode     string      `json:"reason_code,omitempty"`
+	CancelledAt    time.Time   `json:"cancelled_at"`
+}
+
+func (OrderCancelled) EventType() string         { return EventTypeOrderCancelled }
+func (OrderCancelled) SchemaVersion() string     { return "2.1" }
+func (event OrderCancelled) AggregateID() string { return strconv.FormatInt(event.ID, 10) }
+
+// OrderRefunded is published by the order service after part or all of an
+// order's payment is refunded. RefundedTotal includes Amount.
+type OrderRefunded struct {
+	ID             int64     `json:"id"`
+	CustomerNumber string    `json:"customer_number"`
+	RefundID       int64     `json:"refund_id"`
+	Amount         float64   `json:"amount"`
+	RefundedTotal  float64   `json:"refunded_total"`
+	GrandTotal     float64   `json:"grand_total"`
+	Reason         string    `json:"reason,omitempty"`
+	RefundedAt     time.Time `json:"refunded_at"`
+}
+
+func (OrderRefunded) EventType() string         { return EventTypeOrderRefunded }
+func (OrderRefunded) SchemaVersion() string     { return "1.0" }
+func (event OrderRefunded) AggregateID() string { return strconv.FormatInt(event.ID, 10) }
+
+// OrderStatusChanged is published by the order service whenever an order
+// moves from one status to another.
+type OrderStatusChanged struct {
+	ID             int64       `json:"id"`
+	CustomerNumber string      `json:"customer_number"`
+	Items          []OrderLine `json:"items"`
+	Subtotal       float64     `json:"subtotal"`
+	DiscountTotal  float64     `json:"discount_total"`
+	GrandTotal     float64     `json:"grand_total"`
+	OrderTime      time.Time   `json:"order_time"`
+	PreviousStatus string      `json:"previous_status"`
+	Status         string      `json:"status"`
+	ChangedAt      time.Time   `json:"changed_at"`
+}
+
+func (OrderStatusChanged) EventType() string         { return EventTypeOrderStatusChanged }
+func (OrderStatusChanged) SchemaVersion() string     { return "2.0" }
+func (event OrderStatusChanged) AggregateID() string { return strconv.FormatInt(event.ID, 10) }
+
+// ShipmentLine is one item of a shipment as carried by shipment events.
+type ShipmentLine struct {
+	ProductID int64  `json:"product_id"`
+	Variant   string `json:"variant,omitempty"`
+	Quantity  int32  `json:"quantity"`
+}
+
+// ShipmentCreated is published by the order service after a shipment of an
+// order is handed to the carrier. Shipment events are keyed by their order,
+// so they stay in order with the order's own events.
+type ShipmentCreated struct {
+	ID             int64          `json:"id"`
+	OrderID        int64          `json:"order_id"`
+	CustomerNumber string         `json:"customer_number"`
+	Carrier        string         `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number"`
+	Status         string         `json:"status"`
+	Items          []ShipmentLine `json:"items"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+func (ShipmentCreated) EventType() string         { return EventTypeShipmentCreated }
+func (ShipmentCreated) SchemaVersion() string     { return "1.0" }
+func (event ShipmentCreated) AggregateID() string { return strconv.FormatInt(event.OrderID, 10) }
+
+// ShipmentUpdated is published by the order service for every tracking
+// event recorded for a shipment. PreviousStatus equals Status when the event
+// only reports progress, such as arrival at another hub.
+type ShipmentUpdated struct {
+	ID             int64     `json:"id"`
+	OrderID        int64     `json:"order_id"`
+	CustomerNumber string    `json:"customer_number"`
+	Carrier        string    `json:"carrier"`
+	TrackingNumber string    `json:"tracking_number"`
+	PreviousStatus string    `json:"previous_status"`
+	Status         string    `json:"status"`
+	Location       string    `json:"location,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+func (ShipmentUpdated) EventType() string         { return EventTypeShipmentUpdated }
+func (ShipmentUpdated) SchemaVersion() string     { return "1.0" }
+func (event ShipmentUpdated) AggregateID() string { return strconv.FormatInt(event.OrderID, 10) }
+
+// ShipmentDelivered is published by the order service, after
+// shipment.updated, when a shipment is delivered.
+type ShipmentDelivered struct {
+	ID             int64          `json:"id"`
+	OrderID        int64          `json:"order_id"`
+	CustomerNumber string         `json:"customer_number"`
+	Carrier        string         `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number"`
+	Items          []ShipmentLine `json:"items"`
+	DeliveredAt    time.Time      `json:"delivered_at"`
+}
+
+func (ShipmentDelivered) EventType() string         { return EventTypeShipmentDelivered }
+func (ShipmentDelivered) SchemaVersion() string     { return "1.0" }
+func (event ShipmentDelivered) AggregateID() string { return strconv.FormatInt(event.OrderID, 10) }
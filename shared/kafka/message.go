@@ -3,3 +3,5 @@ package kafka
 import "github.com/segmentio/kafka-go"
 
 type Message = kafka.Message
+
+type Header = kafka.Header
@@ -0,0 +1,62 @@
+package kafka
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/segmentio/kafka-go"
+)
+
+// Collectors are registered on the default registry, which every service
+// already serves on /metrics.
+var (
+	producerMessagesWrittenTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_producer_messages_written_total",
+		Help: "Messages successfully written to Kafka.",
+	}, []string{"topic"})
+
+	producerMessagesFailedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_producer_messages_failed_total",
+		Help: "Messages whose write to Kafka failed.",
+	}, []string{"topic"})
+
+	producerWriteDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_producer_write_duration_seconds",
+		Help:    "Time spent in a single write call, including retries.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic"})
+
+	producerBatchSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_producer_batch_size",
+		Help:    "Messages passed to a single write call.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 8),
+	}, []string{"topic"})
+
+	consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_consumer_lag",
+		Help: "Messages between the last fetched offset and the partition high watermark.",
+	}, []string{"topic", "group", "partition"})
+
+	consumerHandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_consumer_handler_duration_seconds",
+		Help:    "Duration of a single handler attempt.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic", "group"})
+
+	consumerHandlerErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumer_handler_errors_total",
+		Help: "Handler attempts that returned an error.",
+	}, []string{"topic", "group"})
+
+	consumerDeadLetteredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumer_dead_lettered_total",
+		Help: "Messages moved to the dead-letter topic after exhausting retries.",
+	}, []string{"topic", "group"})
+)
+
+// observeLag records how far the consumer is behind on message's partition.
+func observeLag(message kafka.Message, group string) {
+	lag := max(message.HighWaterMark-message.Offset-1, 0)
+	consumerLag.WithLabelValues(message.Topic, group, strconv.Itoa(message.Partition)).Set(float64(lag))
+}
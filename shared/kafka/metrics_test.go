@@ -0,0 +1,106 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWriter struct {
+	err     error
+	written []kafka.Message
+}
+
+func (writer *fakeWriter) WriteMessages(_ context.Context, messages ...kafka.Message) error {
+	if writer.err != nil {
+		return writer.err
+	}
+	writer.written = append(writer.written, messages...)
+	return nil
+}
+
+func (writer *fakeWriter) Stats() kafka.WriterStats { return kafka.WriterStats{} }
+
+func (writer *fakeWriter) Close() error { return nil }
+
+// histogram returns the sample count and sum of one histogram series.
+func histogram(t *testing.T, observer prometheus.Observer) (uint64, float64) {
+	t.Helper()
+	metric := &dto.Metric{}
+	assert.NoError(t, observer.(prometheus.Metric).Write(metric))
+	return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+}
+
+func TestProducer_WriteRecordsMetrics(t *testing.T) {
+	writer := &fakeWriter{}
+	producer := &Producer{topic: "metrics.written", writer: writer}
+
+	assert.NoError(t, producer.PublishMessage(context.Background(), "1", map[string]int{"id": 1}))
+	assert.NoError(t, producer.PublishBatch(context.Background(), []kafka.Message{{Key: []byte("2")}, {Key: []byte("3")}}))
+
+	assert.Len(t, writer.written, 3)
+	assert.Equal(t, 3.0, testutil.ToFloat64(producerMessagesWrittenTotal.WithLabelValues("metrics.written")))
+	assert.Zero(t, testutil.ToFloat64(producerMessagesFailedTotal.WithLabelValues("metrics.written")))
+	count, sum := histogram(t, producerBatchSize.WithLabelValues("metrics.written"))
+	assert.Equal(t, uint64(2), count, "one observation per write call")
+	assert.Equal(t, 3.0, sum)
+	count, _ = histogram(t, producerWriteDuration.WithLabelValues("metrics.written"))
+	assert.Equal(t, uint64(2), count)
+}
+
+func TestProducer_WriteCountsFailedMessages(t *testing.T) {
+	producer := &Producer{topic: "metrics.failed", writer: &fakeWriter{err: errors.New("broker down")}}
+
+	err := producer.PublishBatch(context.Background(), []kafka.Message{{}, {}})
+
+	assert.Error(t, err)
+	assert.Equal(t, 2.0, testutil.ToFloat64(producerMessagesFailedTotal.WithLabelValues("metrics.failed")))
+	assert.Zero(t, testutil.ToFloat64(producerMessagesWrittenTotal.WithLabelValues("metrics.failed")))
+	count, _ := histogram(t, producerWriteDuration.WithLabelValues("metrics.failed"))
+	assert.Equal(t, uint64(1), count, "failed writes are timed too")
+}
+
+func TestProducer_WriteLabelsByMessageTopicWithoutWriterTopic(t *testing.T) {
+	producer := &Producer{writer: &fakeWriter{}}
+
+	assert.NoError(t, producer.PublishRawMessage(context.Background(), kafka.Message{Topic: "metrics.raw"}))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(producerMessagesWrittenTotal.WithLabelValues("metrics.raw")))
+}
+
+func TestObserveLag(t *testing.T) {
+	observeLag(kafka.Message{Topic: "metrics.lag", Partition: 2, Offset: 10, HighWaterMark: 15}, "group")
+	assert.Equal(t, 4.0, testutil.ToFloat64(consumerLag.WithLabelValues("metrics.lag", "group", "2")))
+
+	observeLag(kafka.Message{Topic: "metrics.lag", Partition: 2, Offset: 14, HighWaterMark: 15}, "group")
+	assert.Zero(t, testutil.ToFloat64(consumerLag.WithLabelValues("metrics.lag", "group", "2")), "caught up")
+}
+
+func TestConsumer_ProcessRecordsMetrics(t *testing.T) {
+	calls := 0
+	consumer := newTestConsumer(func(context.Context, kafka.Message) error {
+		calls++
+		if calls < 3 {
+			return errors.New("boom")
+		}
+		return nil
+	}, &fakeDeadLetterPublisher{})
+	defer consumer.Close()
+
+	assert.NoError(t, consumer.process(context.Background(), context.Background(), kafka.Message{Topic: "metrics.consumed"}))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(consumerHandlerErrorsTotal.WithLabelValues("metrics.consumed", "test-group")))
+	assert.Zero(t, testutil.ToFloat64(consumerDeadLetteredTotal.WithLabelValues("metrics.consumed", "test-group")))
+	count, _ := histogram(t, consumerHandlerDuration.WithLabelValues("metrics.consumed", "test-group"))
+	assert.Equal(t, uint64(3), count, "every attempt is timed")
+
+	consumer.handler = func(context.Context, kafka.Message) error { return errors.New("boom") }
+	assert.NoError(t, consumer.process(context.Background(), context.Background(), kafka.Message{Topic: "metrics.consumed"}))
+	assert.Equal(t, 1.0, testutil.ToFloat64(consumerDeadLetteredTotal.WithLabelValues("metrics.consumed", "test-group")))
+}
@@ -12,7 +12,15 @@ import (
 
 // Producer Kafka producer wrapper
 type Producer struct {
-	writer *kafka.Writer
+	topic  string
+	writer messageWriter
+}
+
+// messageWriter is the part of *kafka.Writer the producer uses.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, messages ...kafka.Message) error
+	Stats() kafka.WriterStats
+	Close() error
 }
 
 // NewProducer creates a new Kafka producer
@@ -20,7 +28,7 @@ func NewProducer(brokers []string, topic string) *Producer {
 	writer := &kafka.Writer{
 		Addr:     kafka.TCP(brokers...),
 		Topic:    topic,
-		Balancer: &kafka.LeastBytes{}, // Load balancing strategy
+		Balancer: &kafka.Hash{}, // Same key, same partition: keeps per-aggregate order
 
 		// Performance tuning
 		BatchSize:    100,
@@ -39,7 +47,7 @@ func NewProducer(brokers []string, topic string) *Producer {
 		WriteBackoffMax: 1 * time.Second,
 	}
 
-	return &Producer{writer: writer}
+	return &Producer{topic: topic, writer: writer}
 }
 
 // PublishMessage sends a single message to Kafka
@@ -57,11 +65,36 @@ func (p *Producer) PublishMessage(ctx context.Context, key string, value interfa
 	}
 
 	// Write message
-	err = p.writer.WriteMessages(ctx, message)
+	err = p.write(ctx, message)
 	if err != nil {
-		return fmt.Errorf("failed to write message to topic %s: %w", p.writer.Topic, err)
+		return fmt.Errorf("failed to write message to topic %s: %w", p.topic, err)
+	}
+
+	return nil
+}
+
+// PublishEvent wraps event in an Envelope and sends it keyed by its
+// aggregate id.
+func (p *Producer) PublishEvent(ctx context.Context, source string, event Event, opts ...EnvelopeOption) error {
+	envelope, err := NewEnvelope(source, event, opts...)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s envelope: %w", event.EventType(), err)
+	}
+
+	message := kafka.Message{
+		Key:     []byte(event.AggregateID()),
+		Value:   valueBytes,
+		Headers: []kafka.Header{{Key: HeaderEventID, Value: []byte(envelope.ID)}},
+		Time:    time.Now(),
 	}
 
+	if err := p.write(ctx, message); err != nil {
+		return fmt.Errorf("failed to write %s to topic %s: %w", event.EventType(), p.topic, err)
+	}
 	return nil
 }
 
@@ -88,7 +121,7 @@ func (p *Producer) PublishMessageWithHeaders(ctx context.Context, key string, va
 		Time:    time.Now(),
 	}
 
-	err = p.writer.WriteMessages(ctx, message)
+	err = p.write(ctx, message)
 	if err != nil {
 		return fmt.Errorf("failed to write message: %w", err)
 	}
@@ -102,7 +135,7 @@ func (p *Producer) PublishBatch(ctx context.Context, messages []kafka.Message) e
 		return nil
 	}
 
-	err := p.writer.WriteMessages(ctx, messages...)
+	err := p.write(ctx, messages...)
 	if err != nil {
 		return fmt.Errorf("failed to write batch messages: %w", err)
 	}
@@ -112,13 +145,33 @@ func (p *Producer) PublishBatch(ctx context.Context, messages []kafka.Message) e
 
 // PublishRawMessage sends a pre-constructed Kafka message
 func (p *Producer) PublishRawMessage(ctx context.Context, message kafka.Message) error {
-	err := p.writer.WriteMessages(ctx, message)
+	err := p.write(ctx, message)
 	if err != nil {
 		return fmt.Errorf("failed to write raw message: %w", err)
 	}
 	return nil
 }
 
+// write sends messages and records the producer metrics.
+func (p *Producer) write(ctx context.Context, messages ...kafka.Message) error {
+	topic := p.topic
+	if topic == "" && len(messages) > 0 {
+		topic = messages[0].Topic
+	}
+
+	start := time.Now()
+	err := p.writer.WriteMessages(ctx, messages...)
+	producerWriteDuration.WithLabelValues(topic).Observe(time.Since(start).Seconds())
+	producerBatchSize.WithLabelValues(topic).Observe(float64(len(messages)))
+
+	if err != nil {
+		producerMessagesFailedTotal.WithLabelValues(topic).Add(float64(len(messages)))
+		return err
+	}
+	producerMessagesWrittenTotal.WithLabelValues(topic).Add(float64(len(messages)))
+	return nil
+}
+
 // Close closes the producer and releases resources
 func (p *Producer) Close() error {
 	if p.writer != nil {
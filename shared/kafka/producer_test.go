@@ -0,0 +1,23 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProducer_RoutesSameKeyToSamePartition(t *testing.T) {
+	producer := NewProducer([]string{"localhost:9092"}, "order.events")
+	writer, ok := producer.writer.(*kafka.Writer)
+	assert.True(t, ok)
+
+	partitions := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	for _, key := range []string{"1", "42", "order-7"} {
+		first := writer.Balancer.Balance(kafka.Message{Key: []byte(key), Value: []byte("created")}, partitions...)
+		for i := 0; i < 10; i++ {
+			partition := writer.Balancer.Balance(kafka.Message{Key: []byte(key), Value: []byte("updated")}, partitions...)
+			assert.Equal(t, first, partition, "key %s", key)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Replay hands every message currently in topic to handler, partition by
+// partition from the oldest retained offset up to the end offset observed
+// when the partition is opened. It uses no consumer group and commits
+// nothing, so it does not disturb running consumers. Messages produced
+// while Replay runs may or may not be included; handlers should therefore
+// be idempotent. It returns the number of messages handled and stops at the
+// first handler error.
+func Replay(ctx context.Context, brokers []string, topic string, handler MessageHandler) (int, error) {
+	partitions, err := readPartitionIDs(ctx, brokers, topic)
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, partition := range partitions {
+		err := readPartition(ctx, brokers, topic, partition, 0, func(message kafka.Message) error {
+			if err := handler(ctx, message); err != nil {
+				return fmt.Errorf("failed to replay %s/%d offset %d: %w", topic, partition, message.Offset, err)
+			}
+			replayed++
+			return nil
+		})
+		if err != nil {
+			return replayed, err
+		}
+	}
+	return replayed, nil
+}
+
+func readPartitionIDs(ctx context.Context, brokers []string, topic string) ([]int, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("at least one broker is required")
+	}
+
+	conn, err := kafka.DialContext(ctx, "tcp", brokers[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", brokers[0], err)
+	}
+	partitions, err := conn.ReadPartitions(topic)
+	conn.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partitions of %s: %w", topic, err)
+	}
+
+	ids := make([]int, 0, len(partitions))
+	for _, partition := range partitions {
+		ids = append(ids, partition.ID)
+	}
+	return ids, nil
+}
+
+// readPartition calls visit for the messages of one partition between its
+// first and last offset at the time of the call, stopping after limit
+// messages when limit is positive.
+func readPartition(ctx context.Context, brokers []string, topic string, partition, limit int, visit func(kafka.Message) error) error {
+	leader, err := kafka.DialLeader(ctx, "tcp", brokers[0], topic, partition)
+	if err != nil {
+		return fmt.Errorf("failed to dial leader of %s/%d: %w", topic, partition, err)
+	}
+	first, last, err := leader.ReadOffsets()
+	leader.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read offsets of %s/%d: %w", topic, partition, err)
+	}
+	if first >= last {
+		return nil
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   brokers,
+		Topic:     topic,
+		Partition: partition,
+		MaxBytes:  10e6,
+	})
+	defer reader.Close()
+	if err := reader.SetOffset(first); err != nil {
+		return err
+	}
+
+	read := 0
+	for offset := first; offset < last; {
+		if limit > 0 && read >= limit {
+			break
+		}
+		message, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read %s/%d: %w", topic, partition, err)
+		}
+		if err := visit(message); err != nil {
+			return err
+		}
+		read++
+		offset = message.Offset + 1
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package kafka
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy controls how often a failing message is handed back to the
+// handler before it is routed to the dead-letter topic.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of handler calls, including the first.
+	MaxAttempts int
+	BackoffMin  time.Duration
+	BackoffMax  time.Duration
+}
+
+// DefaultRetryPolicy is used when ConsumerConfig.Retry is left empty.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BackoffMin:  200 * time.Millisecond,
+	BackoffMax:  10 * time.Second,
+}
+
+func (policy RetryPolicy) withDefaults() RetryPolicy {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if policy.BackoffMin <= 0 {
+		policy.BackoffMin = DefaultRetryPolicy.BackoffMin
+	}
+	if policy.BackoffMax < policy.BackoffMin {
+		policy.BackoffMax = max(DefaultRetryPolicy.BackoffMax, policy.BackoffMin)
+	}
+	return policy
+}
+
+// Backoff returns the delay before retry number attempt (1-based), doubling
+// from BackoffMin and capped at BackoffMax.
+func (policy RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := policy.BackoffMin
+	for i := 1; i < attempt && delay < policy.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, policy.BackoffMax)
+}
+
+// sleep waits for delay and reports false if ctx was cancelled first.
+func sleep(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,56 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BackoffMin: 100 * time.Millisecond, BackoffMax: time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{60, time.Second},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, policy.Backoff(test.attempt), "attempt %d", test.attempt)
+	}
+}
+
+func TestRetryPolicy_WithDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		want   RetryPolicy
+	}{
+		{"empty", RetryPolicy{}, DefaultRetryPolicy},
+		{"kept", RetryPolicy{MaxAttempts: 2, BackoffMin: time.Second, BackoffMax: time.Minute},
+			RetryPolicy{MaxAttempts: 2, BackoffMin: time.Second, BackoffMax: time.Minute}},
+		{"max below min", RetryPolicy{MaxAttempts: 1, BackoffMin: time.Minute, BackoffMax: time.Second},
+			RetryPolicy{MaxAttempts: 1, BackoffMin: time.Minute, BackoffMax: time.Minute}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.policy.withDefaults())
+		})
+	}
+}
+
+func TestSleep_StopsOnCancel(t *testing.T) {
+	assert.True(t, sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, sleep(ctx, time.Hour))
+}
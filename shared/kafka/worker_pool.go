@@ -0,0 +1,204 @@
+package kafka
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Ordering decides which messages a concurrent consumer keeps in order. Its
+// values are the names accepted by KAFKA_CONSUMER_ORDER_BY; the zero value
+// orders by key.
+type Ordering string
+
+const (
+	// OrderByKey routes every message with the same key to the same worker,
+	// so different keys of one partition are handled in parallel.
+	OrderByKey Ordering = "key"
+	// OrderByPartition keeps whole partitions in order.
+	OrderByPartition Ordering = "partition"
+)
+
+const workerQueueSize = 64
+
+// startConcurrent fetches messages on the calling goroutine and hands them
+// to c.concurrency workers. A message always lands on the worker chosen by
+// its key (or partition), so those stay in fetch order. Offsets are
+// committed only up to the highest offset below which every fetched message
+// of the partition has completed, so a crash never skips unfinished work.
+//
+// When ctx is cancelled fetching stops, messages already being handled run
+// to completion, queued ones are left uncommitted for redelivery, and the
+// final contiguous offsets are committed before the consumer closes.
+func (c *Consumer) startConcurrent(ctx context.Context) error {
+	// Commits of work finished after ctx is cancelled must still go out.
+	commitCtx := context.WithoutCancel(ctx)
+	tracker := newOffsetTracker()
+	pool := c.startWorkers(ctx, tracker, func(commit kafka.Message) error {
+		return c.reader.CommitMessages(commitCtx, commit)
+	})
+
+	log.Printf("Consumer running with %d workers", c.concurrency)
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("Error fetching message: %v", err)
+			continue
+		}
+
+		observeLag(message, c.reader.Config().GroupID)
+		tracker.track(message)
+		if !pool.dispatch(ctx, c.workerFor(message), message) {
+			break
+		}
+	}
+
+	pool.stop()
+	log.Println("Consumer stopped")
+	return c.Close()
+}
+
+// workerPool is the set of workers of a concurrent consumer, one queue each.
+type workerPool struct {
+	queues  []chan kafka.Message
+	workers sync.WaitGroup
+}
+
+// startWorkers starts c.concurrency workers. Each handles its queue in
+// order and passes the offset tracker's commit point to commit.
+func (c *Consumer) startWorkers(ctx context.Context, tracker *offsetTracker, commit func(kafka.Message) error) *workerPool {
+	// Handlers keep running after ctx is cancelled so in-flight work can
+	// finish; retry and dead-letter backoff still stop on ctx.
+	handleCtx := context.WithoutCancel(ctx)
+	pool := &workerPool{queues: make([]chan kafka.Message, c.concurrency)}
+	for i := range pool.queues {
+		pool.queues[i] = make(chan kafka.Message, workerQueueSize)
+		pool.workers.Add(1)
+		go func(queue <-chan kafka.Message) {
+			defer pool.workers.Done()
+			for message := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := c.process(ctx, handleCtx, message); err != nil {
+					log.Printf("Stopping before commit of offset %d: %v", message.Offset, err)
+					continue
+				}
+				if next, ok := tracker.complete(message); ok {
+					if err := commit(next); err != nil {
+						log.Printf("Error committing offset %d: %v", next.Offset, err)
+					}
+				}
+			}
+		}(pool.queues[i])
+	}
+	return pool
+}
+
+// dispatch queues message on worker and reports false if ctx was cancelled
+// first.
+func (pool *workerPool) dispatch(ctx context.Context, worker int, message kafka.Message) bool {
+	select {
+	case pool.queues[worker] <- message:
+	case <-ctx.Done():
+	}
+	return ctx.Err() == nil
+}
+
+// stop closes the queues and waits for the workers to drain them.
+func (pool *workerPool) stop() {
+	for _, queue := range pool.queues {
+		close(queue)
+	}
+	pool.workers.Wait()
+}
+
+func (c *Consumer) workerFor(message kafka.Message) int {
+	hash := fnv.New32a()
+	if c.orderBy == OrderByPartition || len(message.Key) == 0 {
+		hash.Write([]byte(strconv.Itoa(message.Partition)))
+	} else {
+		hash.Write(message.Key)
+	}
+	return int(hash.Sum32() % uint32(c.concurrency))
+}
+
+// offsetTracker remembers fetched but uncommitted offsets per partition, in
+// fetch order.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	pending []pendingOffset
+}
+
+type pendingOffset struct {
+	offset int64
+	done   bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: map[int]*partitionOffsets{}}
+}
+
+// track records a fetched message. An offset at or below the last tracked
+// one means the partition was rewound after a rebalance, so its stale
+// entries are dropped.
+func (tracker *offsetTracker) track(message kafka.Message) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	partition, ok := tracker.partitions[message.Partition]
+	if !ok {
+		partition = &partitionOffsets{}
+		tracker.partitions[message.Partition] = partition
+	}
+	if n := len(partition.pending); n > 0 && message.Offset <= partition.pending[n-1].offset {
+		partition.pending = nil
+	}
+	partition.pending = append(partition.pending, pendingOffset{offset: message.Offset})
+}
+
+// complete marks message done and, when that extends the finished prefix of
+// its partition, returns the message to commit (the last offset of that
+// prefix).
+func (tracker *offsetTracker) complete(message kafka.Message) (kafka.Message, bool) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	partition, ok := tracker.partitions[message.Partition]
+	if !ok {
+		return kafka.Message{}, false
+	}
+	for i := range partition.pending {
+		if partition.pending[i].offset == message.Offset {
+			partition.pending[i].done = true
+			break
+		}
+	}
+
+	committed := -1
+	for committed+1 < len(partition.pending) && partition.pending[committed+1].done {
+		committed++
+	}
+	if committed < 0 {
+		return kafka.Message{}, false
+	}
+
+	commit := kafka.Message{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    partition.pending[committed].offset,
+	}
+	partition.pending = partition.pending[committed+1:]
+	return commit, true
+}
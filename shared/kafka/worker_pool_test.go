@@ -0,0 +1,118 @@
+package kafka
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func message(partition int, offset int64, key string) kafka.Message {
+	return kafka.Message{Topic: "test.events", Partition: partition, Offset: offset, Key: []byte(key)}
+}
+
+func TestOffsetTracker_CommitsOnlyTheContiguousPrefix(t *testing.T) {
+	tracker := newOffsetTracker()
+	for offset := int64(10); offset < 14; offset++ {
+		tracker.track(message(0, offset, ""))
+	}
+	tracker.track(message(1, 5, ""))
+
+	_, ok := tracker.complete(message(0, 12, ""))
+	assert.False(t, ok, "10 and 11 are still running")
+	_, ok = tracker.complete(message(0, 11, ""))
+	assert.False(t, ok)
+
+	commit, ok := tracker.complete(message(0, 10, ""))
+	assert.True(t, ok)
+	assert.Equal(t, int64(12), commit.Offset, "10 completes the prefix up to 12")
+	assert.Equal(t, 0, commit.Partition)
+
+	commit, ok = tracker.complete(message(1, 5, ""))
+	assert.True(t, ok, "partitions are tracked separately")
+	assert.Equal(t, int64(5), commit.Offset)
+
+	commit, ok = tracker.complete(message(0, 13, ""))
+	assert.True(t, ok)
+	assert.Equal(t, int64(13), commit.Offset)
+}
+
+func TestOffsetTracker_DropsStaleOffsetsAfterRewind(t *testing.T) {
+	tracker := newOffsetTracker()
+	tracker.track(message(0, 10, ""))
+	tracker.track(message(0, 11, ""))
+	tracker.track(message(0, 7, ""))
+
+	commit, ok := tracker.complete(message(0, 7, ""))
+	assert.True(t, ok, "10 and 11 belong to the previous assignment")
+	assert.Equal(t, int64(7), commit.Offset)
+
+	_, ok = tracker.complete(message(3, 1, ""))
+	assert.False(t, ok, "untracked partition")
+}
+
+func TestConsumer_WorkerFor(t *testing.T) {
+	consumer := &Consumer{concurrency: 8, orderBy: OrderByKey}
+	assert.Equal(t, consumer.workerFor(message(0, 1, "order-7")), consumer.workerFor(message(3, 9, "order-7")),
+		"a key always lands on the same worker")
+	assert.Equal(t, consumer.workerFor(message(2, 1, "")), consumer.workerFor(message(2, 5, "")),
+		"keyless messages fall back to their partition")
+
+	consumer.orderBy = OrderByPartition
+	assert.Equal(t, consumer.workerFor(message(4, 1, "a")), consumer.workerFor(message(4, 2, "b")))
+}
+
+func TestConsumer_WorkersSerialiseMessagesOfOneKey(t *testing.T) {
+	var mu sync.Mutex
+	inFlight := map[string]int{}
+	handled := map[string][]int64{}
+	overlapped := false
+	handler := func(_ context.Context, message kafka.Message) error {
+		key := string(message.Key)
+		mu.Lock()
+		inFlight[key]++
+		overlapped = overlapped || inFlight[key] > 1
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		inFlight[key]--
+		handled[key] = append(handled[key], message.Offset)
+		mu.Unlock()
+		return nil
+	}
+	consumer := newTestConsumer(handler, &fakeDeadLetterPublisher{})
+	defer consumer.Close()
+	consumer.concurrency = 4
+
+	var commits []int64
+	tracker := newOffsetTracker()
+	pool := consumer.startWorkers(context.Background(), tracker, func(commit kafka.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		commits = append(commits, commit.Offset)
+		return nil
+	})
+	keys := []string{"a", "b", "c", "d"}
+	for offset := int64(0); offset < 40; offset++ {
+		next := message(0, offset, keys[offset%4])
+		tracker.track(next)
+		assert.True(t, pool.dispatch(context.Background(), consumer.workerFor(next), next))
+	}
+	pool.stop()
+
+	assert.False(t, overlapped, "two messages of one key never run at once")
+	for i, key := range keys {
+		assert.Len(t, handled[key], 10)
+		for j, offset := range handled[key] {
+			assert.Equal(t, int64(i+4*j), offset, "key %s is handled in fetch order", key)
+		}
+	}
+	assert.NotEmpty(t, commits)
+	assert.Equal(t, int64(39), slices.Max(commits), "everything handled is committed")
+}
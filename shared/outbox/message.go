@@ -0,0 +1,91 @@
+// Package outbox implements the transactional outbox pattern. State changes
+// and the events describing them are written in the same database
+// transaction; a Relay later publishes pending rows to Kafka and marks them
+// sent, so an unavailable broker delays events instead of losing them.
+//
+// Services using the package need the outbox table:
+//
+//	CREATE TABLE outbox (
+//	    id BIGSERIAL PRIMARY KEY,
+//	    topic TEXT NOT NULL,
+//	    message_key TEXT NOT NULL,
+//	    payload JSON NOT NULL,
+//	    headers JSONB NOT NULL DEFAULT '{}',
+//	    attempts INT NOT NULL DEFAULT 0,
+//	    last_error TEXT,
+//	    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+//	    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+//	    sent_at TIMESTAMP
+//	);
+//	CREATE INDEX idx_outbox_pending ON outbox (topic, id) WHERE sent_at IS NULL;
+//	CREATE INDEX idx_outbox_pending_key ON outbox (topic, message_key, id) WHERE sent_at IS NULL;
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"product-app/shared/kafka"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Message is a Kafka message waiting in the outbox.
+type Message struct {
+	Topic   string
+	Key     string
+	Value   []byte
+	Headers map[string]string
+}
+
+// NewMessage JSON-encodes value into a Message for the given topic.
+func NewMessage(topic, key string, value interface{}) (Message, error) {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to marshal outbox message: %w", err)
+	}
+	return Message{Topic: topic, Key: key, Value: valueBytes}, nil
+}
+
+// NewEventMessage wraps event in a versioned envelope (see
+// shared/kafka.Envelope) keyed by its aggregate id. The envelope id is also
+// sent as the event-id header.
+func NewEventMessage(topic, source string, event kafka.Event, opts ...kafka.EnvelopeOption) (Message, error) {
+	envelope, err := kafka.NewEnvelope(source, event, opts...)
+	if err != nil {
+		return Message{}, err
+	}
+	valueBytes, err := json.Marshal(envelope)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to marshal %s envelope: %w", event.EventType(), err)
+	}
+	return Message{
+		Topic:   topic,
+		Key:     event.AggregateID(),
+		Value:   valueBytes,
+		Headers: map[string]string{kafka.HeaderEventID: envelope.ID},
+	}, nil
+}
+
+// Enqueue stores the message inside tx. It becomes visible to the relay only
+// when the caller commits.
+func Enqueue(ctx context.Context, tx pgx.Tx, message Message) error {
+	headers := message.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headerBytes, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox headers: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO outbox (topic, message_key, payload, headers)
+		VALUES ($1, $2, $3, $4)
+	`, message.Topic, message.Key, message.Value, headerBytes)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue outbox message for topic %s: %w", message.Topic, err)
+	}
+	return nil
+}
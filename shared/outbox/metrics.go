@@ -0,0 +1,28 @@
+package outbox
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	pendingMessages = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "outbox_pending_messages",
+		Help: "Number of outbox rows not yet published.",
+	}, []string{"topic"})
+
+	lagSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "outbox_lag_seconds",
+		Help: "Age of the oldest unpublished outbox row.",
+	}, []string{"topic"})
+
+	publishedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbox_published_total",
+		Help: "Outbox rows published to Kafka.",
+	}, []string{"topic"})
+
+	failedAttemptsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbox_failed_attempts_total",
+		Help: "Failed attempts to publish an outbox row.",
+	}, []string{"topic"})
+)
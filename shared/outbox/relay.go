@@ -0,0 +1,269 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/segmentio/kafka-go"
+)
+
+// Publisher writes a prepared message to Kafka. *shared/kafka.Producer
+// satisfies it.
+type Publisher interface {
+	PublishRawMessage(ctx context.Context, message kafka.Message) error
+}
+
+// RelayConfig configures a Relay. Zero values fall back to the defaults.
+type RelayConfig struct {
+	// Topic is the outbox topic handled by this relay. The publisher must
+	// write to the same topic.
+	Topic        string
+	BatchSize    int
+	PollInterval time.Duration
+	// BackoffMin and BackoffMax bound the exponential delay between
+	// attempts for a row that failed to publish.
+	BackoffMin time.Duration
+	BackoffMax time.Duration
+	// Retention is how long sent rows are kept before they are deleted.
+	Retention time.Duration
+}
+
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = time.Second
+	defaultBackoffMin   = time.Second
+	defaultBackoffMax   = 5 * time.Minute
+	defaultRetention    = 7 * 24 * time.Hour
+	cleanupInterval     = time.Hour
+)
+
+// Relay polls the outbox table and publishes pending rows in id order.
+// Rows sharing a key are published strictly in order: while a row waits for
+// its next attempt, the later rows with its key wait behind it, and rows
+// with other keys go ahead. Several relay instances may run at once; a
+// transaction-scoped advisory lock lets only one of them relay a topic at a
+// time.
+type Relay struct {
+	dbPool    *pgxpool.Pool
+	publisher Publisher
+	config    RelayConfig
+}
+
+// NewRelay creates a relay for config.Topic.
+func NewRelay(dbPool *pgxpool.Pool, publisher Publisher, config RelayConfig) *Relay {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	if config.BackoffMin <= 0 {
+		config.BackoffMin = defaultBackoffMin
+	}
+	if config.BackoffMax < config.BackoffMin {
+		config.BackoffMax = defaultBackoffMax
+	}
+	if config.Retention <= 0 {
+		config.Retention = defaultRetention
+	}
+	return &Relay{dbPool: dbPool, publisher: publisher, config: config}
+}
+
+// Start runs the relay until ctx is cancelled.
+func (r *Relay) Start(ctx context.Context) error {
+	log.Printf("Starting outbox relay for topic: %s", r.config.Topic)
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+	lastCleanup := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Outbox relay for topic %s stopped", r.config.Topic)
+			return nil
+		case <-ticker.C:
+			// Drain full batches back to back so a backlog clears quickly.
+			for {
+				published, err := r.RelayBatch(ctx)
+				if err != nil {
+					log.Printf("Outbox relay error for topic %s: %v", r.config.Topic, err)
+					break
+				}
+				if published < r.config.BatchSize {
+					break
+				}
+			}
+			r.updateLagMetrics(ctx)
+
+			if time.Since(lastCleanup) >= cleanupInterval {
+				r.deleteSent(ctx)
+				lastCleanup = time.Now()
+			}
+		}
+	}
+}
+
+type pendingRow struct {
+	id       int64
+	key      string
+	payload  []byte
+	headers  []byte
+	attempts int
+}
+
+// RelayBatch publishes up to BatchSize due rows and returns how many were
+// sent. It stops at the first failure; the failed row is retried after a
+// backoff and blocks the later rows with its key until it is sent. It
+// returns 0 without publishing while another relay holds the topic.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('outbox:' || $1))`, r.config.Topic).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to lock outbox topic %s: %w", r.config.Topic, err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	rows, err := r.claimPending(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, row := range rows {
+		if err := r.publish(ctx, row); err != nil {
+			failedAttemptsTotal.WithLabelValues(r.config.Topic).Inc()
+			if markErr := r.markFailed(ctx, tx, row, err); markErr != nil {
+				return published, markErr
+			}
+			log.Printf("Outbox message %d for topic %s failed (attempt %d): %v",
+				row.id, r.config.Topic, row.attempts+1, err)
+			break
+		}
+		if _, err := tx.Exec(ctx, `UPDATE outbox SET sent_at = NOW(), attempts = attempts + 1 WHERE id = $1`, row.id); err != nil {
+			return published, fmt.Errorf("failed to mark outbox message %d as sent: %w", row.id, err)
+		}
+		published++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox batch: %w", err)
+	}
+	publishedTotal.WithLabelValues(r.config.Topic).Add(float64(published))
+	return published, nil
+}
+
+// claimPending locks the due rows of the topic, leaving out rows whose key
+// has an earlier row still waiting for its next attempt.
+func (r *Relay) claimPending(ctx context.Context, tx pgx.Tx) ([]pendingRow, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, message_key, payload, headers, attempts
+		FROM outbox
+		WHERE topic = $1 AND sent_at IS NULL AND next_attempt_at <= NOW()
+		  AND NOT EXISTS (
+		    SELECT 1 FROM outbox earlier
+		    WHERE earlier.topic = outbox.topic
+		      AND earlier.message_key = outbox.message_key
+		      AND earlier.sent_at IS NULL
+		      AND earlier.id < outbox.id
+		      AND earlier.next_attempt_at > NOW()
+		  )
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, r.config.Topic, r.config.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []pendingRow
+	for rows.Next() {
+		var row pendingRow
+		if err := rows.Scan(&row.id, &row.key, &row.payload, &row.headers, &row.attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox row: %w", err)
+		}
+		pending = append(pending, row)
+	}
+	return pending, rows.Err()
+}
+
+func (r *Relay) publish(ctx context.Context, row pendingRow) error {
+	var headers map[string]string
+	if err := json.Unmarshal(row.headers, &headers); err != nil {
+		return fmt.Errorf("invalid headers: %w", err)
+	}
+
+	kafkaHeaders := make([]kafka.Header, 0, len(headers))
+	for key, value := range headers {
+		kafkaHeaders = append(kafkaHeaders, kafka.Header{Key: key, Value: []byte(value)})
+	}
+
+	return r.publisher.PublishRawMessage(ctx, kafka.Message{
+		Key:     []byte(row.key),
+		Value:   row.payload,
+		Headers: kafkaHeaders,
+		Time:    time.Now(),
+	})
+}
+
+func (r *Relay) markFailed(ctx context.Context, tx pgx.Tx, row pendingRow, publishErr error) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE outbox
+		SET attempts = attempts + 1,
+		    last_error = $2,
+		    next_attempt_at = NOW() + $3::DOUBLE PRECISION * INTERVAL '1 millisecond'
+		WHERE id = $1
+	`, row.id, publishErr.Error(), r.backoff(row.attempts+1).Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to record outbox failure for message %d: %w", row.id, err)
+	}
+	return nil
+}
+
+// backoff doubles the delay for every failed attempt up to BackoffMax.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.config.BackoffMin
+	for i := 1; i < attempts && delay < r.config.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.BackoffMax)
+}
+
+func (r *Relay) updateLagMetrics(ctx context.Context) {
+	var pending int64
+	var lag float64
+	err := r.dbPool.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at)), 0)
+		FROM outbox
+		WHERE topic = $1 AND sent_at IS NULL
+	`, r.config.Topic).Scan(&pending, &lag)
+	if err != nil {
+		log.Printf("Failed to read outbox lag for topic %s: %v", r.config.Topic, err)
+		return
+	}
+	pendingMessages.WithLabelValues(r.config.Topic).Set(float64(pending))
+	lagSeconds.WithLabelValues(r.config.Topic).Set(lag)
+}
+
+func (r *Relay) deleteSent(ctx context.Context) {
+	_, err := r.dbPool.Exec(ctx, `
+		DELETE FROM outbox
+		WHERE topic = $1 AND sent_at < NOW() - $2::DOUBLE PRECISION * INTERVAL '1 second'
+	`, r.config.Topic, int64(r.config.Retention.Seconds()))
+	if err != nil {
+		log.Printf("Failed to delete sent outbox rows for topic %s: %v", r.config.Topic, err)
+	}
+}
@@ -0,0 +1,11 @@
+// Package proto holds the gRPC contracts of the services. The generated Go
+// packages under product/v1 and user/v1 are committed so other services can
+// import the clients without running the generators.
+//
+// Regenerate after editing a .proto file (requires buf, protoc-gen-go and
+// protoc-gen-go-grpc on PATH):
+//
+//	go generate ./shared/proto
+package proto
+
+//go:generate buf generate